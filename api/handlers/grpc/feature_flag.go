@@ -0,0 +1,425 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ff/api/middlewares"
+	featureflagv1 "ff/api/proto/featureflag/v1"
+	a_entity "ff/internal/assignment/entity"
+	"ff/internal/db/model"
+	e_entity "ff/internal/evaluation/entity"
+	ff_entity "ff/internal/feature_flag/entity"
+	p_entity "ff/internal/person/entity"
+	"ff/internal/stream"
+	st_entity "ff/internal/stream/entity"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// bulkPageSize is the page size used to list every flag of the project in a bulk evaluation
+const bulkPageSize = 100
+
+type EvaluationService interface {
+	Evaluate(request e_entity.EvaluationRequest) (e_entity.EvaluationResponse, error)
+}
+
+type FeatureFlagService interface {
+	GetFeatureFlag(pagination model.Pagination, filters ff_entity.FeatureFlagFilters) ([]ff_entity.FeatureFlagResponse, int64, error)
+}
+
+type PersonService interface {
+	GetAssignedFeatureFlagsByPersonId(id uint, projectId uint, environment string) ([]p_entity.AssignedFeatureFlagResponse, error)
+}
+
+type AssignmentService interface {
+	ApplyAssignment(request a_entity.Assignment, personId uint) error
+	DeleteAssignment(request a_entity.Assignment, personId uint) error
+}
+
+type StreamService interface {
+	Subscribe(projectId uint, environment string, lastEventId uint) (*stream.Subscription, []st_entity.Event, error)
+	Unsubscribe(subscription *stream.Subscription)
+}
+
+type FeatureFlagGrpcHandler struct {
+	featureflagv1.UnimplementedFeatureFlagServiceServer
+
+	EvaluationService  EvaluationService
+	FeatureFlagService FeatureFlagService
+	PersonService      PersonService
+	AssignmentService  AssignmentService
+	StreamService      StreamService
+}
+
+func NewFeatureFlagGrpcHandler(evaluation EvaluationService, featureFlag FeatureFlagService, person PersonService, assignment AssignmentService, stream StreamService, s *grpc.Server) {
+	handler := &FeatureFlagGrpcHandler{
+		EvaluationService:  evaluation,
+		FeatureFlagService: featureFlag,
+		PersonService:      person,
+		AssignmentService:  assignment,
+		StreamService:      stream,
+	}
+
+	featureflagv1.RegisterFeatureFlagServiceServer(s, handler)
+}
+
+func (h *FeatureFlagGrpcHandler) Evaluate(ctx context.Context, request *featureflagv1.EvaluateRequest) (*featureflagv1.Evaluation, error) {
+	scope := middlewares.GetGrpcScope(ctx)
+
+	evaluation, err := h.EvaluationService.Evaluate(e_entity.EvaluationRequest{
+		FlagName:    request.GetFlagName(),
+		Context:     evaluationContextFromProto(request.GetContext()),
+		Environment: scope.Environment,
+		ProjectID:   scope.ProjectID,
+	})
+	if err != nil {
+		if err.Error() == "FlagName|Flag name is required" {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return evaluationToProto(evaluation)
+}
+
+func (h *FeatureFlagGrpcHandler) BulkEvaluate(ctx context.Context, request *featureflagv1.BulkEvaluateRequest) (*featureflagv1.BulkEvaluateResponse, error) {
+	scope := middlewares.GetGrpcScope(ctx)
+
+	flagNames := request.GetFlagNames()
+	if len(flagNames) == 0 {
+		var err error
+		if flagNames, err = h.projectFlagNames(scope); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	evaluationContext := evaluationContextFromProto(request.GetContext())
+
+	response := &featureflagv1.BulkEvaluateResponse{}
+	for _, flagName := range flagNames {
+		evaluation, err := h.EvaluationService.Evaluate(e_entity.EvaluationRequest{
+			FlagName:    flagName,
+			Context:     evaluationContext,
+			Environment: scope.Environment,
+			ProjectID:   scope.ProjectID,
+		})
+		if err != nil {
+			if err.Error() == "FlagName|Flag name is required" {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		protoEvaluation, err := evaluationToProto(evaluation)
+		if err != nil {
+			return nil, err
+		}
+		response.Evaluations = append(response.Evaluations, protoEvaluation)
+	}
+
+	return response, nil
+}
+
+// projectFlagNames lists the names of every flag of the project, page by page
+func (h *FeatureFlagGrpcHandler) projectFlagNames(scope middlewares.GrpcScope) ([]string, error) {
+	var names []string
+
+	for page := 1; ; page++ {
+		featureFlags, total, err := h.FeatureFlagService.GetFeatureFlag(
+			model.Pagination{Page: page, Limit: bulkPageSize},
+			ff_entity.FeatureFlagFilters{ProjectID: scope.ProjectID, Environment: scope.Environment},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, featureFlag := range featureFlags {
+			names = append(names, featureFlag.Name)
+		}
+
+		if len(featureFlags) == 0 || int64(len(names)) >= total {
+			return names, nil
+		}
+	}
+}
+
+func (h *FeatureFlagGrpcHandler) ListFeatureFlags(ctx context.Context, request *featureflagv1.ListFeatureFlagsRequest) (*featureflagv1.ListFeatureFlagsResponse, error) {
+	scope := middlewares.GetGrpcScope(ctx)
+
+	page := int(request.GetPage())
+	if page <= 1 {
+		page = 1 // Default page
+	}
+	limit := int(request.GetLimit())
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	featureFlags, totalCount, err := h.FeatureFlagService.GetFeatureFlag(
+		model.Pagination{Page: page, Limit: limit},
+		ff_entity.FeatureFlagFilters{
+			Name:        request.GetName(),
+			IsActive:    request.IsActive,
+			IsGlobal:    request.IsGlobal,
+			ProjectID:   scope.ProjectID,
+			Environment: scope.Environment,
+		},
+	)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response := &featureflagv1.ListFeatureFlagsResponse{Total: totalCount}
+	for _, featureFlag := range featureFlags {
+		response.Items = append(response.Items, featureFlagToProto(featureFlag))
+	}
+
+	return response, nil
+}
+
+func (h *FeatureFlagGrpcHandler) GetAssignedFeatureFlags(ctx context.Context, request *featureflagv1.GetAssignedFeatureFlagsRequest) (*featureflagv1.GetAssignedFeatureFlagsResponse, error) {
+	scope := middlewares.GetGrpcScope(ctx)
+
+	if request.GetPersonId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "person id is required")
+	}
+
+	featureFlags, err := h.PersonService.GetAssignedFeatureFlagsByPersonId(uint(request.GetPersonId()), scope.ProjectID, scope.Environment)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response := &featureflagv1.GetAssignedFeatureFlagsResponse{Total: int64(len(featureFlags))}
+	for _, featureFlag := range featureFlags {
+		variantValue, err := variantValueToProto(featureFlag.VariantValue)
+		if err != nil {
+			return nil, err
+		}
+
+		response.Items = append(response.Items, &featureflagv1.AssignedFeatureFlag{
+			Id:           uint64(featureFlag.ID),
+			Name:         featureFlag.Name,
+			IsActive:     featureFlag.IsActive,
+			IsAssigned:   featureFlag.IsAssigned,
+			ViaSegment:   featureFlag.ViaSegment,
+			Variant:      featureFlag.Variant,
+			VariantValue: variantValue,
+		})
+	}
+
+	return response, nil
+}
+
+func (h *FeatureFlagGrpcHandler) ApplyAssignment(ctx context.Context, request *featureflagv1.AssignmentRequest) (*featureflagv1.AssignmentResponse, error) {
+	input := assignmentFromProto(ctx, request)
+
+	if err := h.AssignmentService.ApplyAssignment(input, 0); err != nil {
+		alreadyAssignedError := fmt.Sprintf("Person %d is already assigned to the feature flag %d", input.PersonID, input.FeatureFlagID)
+		alreadyExcludedError := fmt.Sprintf("Person %d is already excluded from the feature flag %d", input.PersonID, input.FeatureFlagID)
+		if err.Error() == alreadyAssignedError || err.Error() == alreadyExcludedError {
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+		if err.Error() == "feature flag not found" {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		if isAssignmentValidationError(err) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &featureflagv1.AssignmentResponse{Message: "Assignment Applied"}, nil
+}
+
+func (h *FeatureFlagGrpcHandler) DeleteAssignment(ctx context.Context, request *featureflagv1.AssignmentRequest) (*featureflagv1.AssignmentResponse, error) {
+	input := assignmentFromProto(ctx, request)
+
+	if err := h.AssignmentService.DeleteAssignment(input, 0); err != nil {
+		if err.Error() == fmt.Sprintf("Person %d is not assigned to the feature flag %d", input.PersonID, input.FeatureFlagID) || err.Error() == "feature flag not found" {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		if isAssignmentValidationError(err) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &featureflagv1.AssignmentResponse{Message: "Assignment Removed"}, nil
+}
+
+// Watch works as the REST stream, the missed events are sent first and an event already
+// sent is skipped when the subscription delivers it again
+func (h *FeatureFlagGrpcHandler) Watch(request *featureflagv1.WatchRequest, server featureflagv1.FeatureFlagService_WatchServer) error {
+	scope := middlewares.GetGrpcScope(server.Context())
+
+	subscription, missed, err := h.StreamService.Subscribe(scope.ProjectID, scope.Environment, uint(request.GetLastEventId()))
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer h.StreamService.Unsubscribe(subscription)
+
+	// missed events can be sent again by the subscription, a lower id than the last one sent
+	// is not a duplicate, the event may have been stored after it
+	replayed := map[uint]struct{}{}
+	for _, event := range missed {
+		if err := sendEvent(server, event); err != nil {
+			return err
+		}
+		replayed[event.ID] = struct{}{}
+	}
+
+	for {
+		select {
+		case <-server.Context().Done():
+			return nil
+		case event, ok := <-subscription.Events():
+			if !ok {
+				// dropped for being too slow, the client resumes from the last id it got
+				return status.Error(codes.Unavailable, "the subscription fell behind the events")
+			}
+			if _, ok := replayed[event.ID]; ok {
+				continue
+			}
+			if err := sendEvent(server, event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func sendEvent(server featureflagv1.FeatureFlagService_WatchServer, event st_entity.Event) error {
+	protoEvent := &featureflagv1.FlagEvent{
+		Id:          uint64(event.ID),
+		Type:        event.Type,
+		ProjectId:   uint64(event.ProjectID),
+		Environment: event.Environment,
+		CreatedAt:   event.CreatedAt,
+	}
+
+	if event.FeatureFlag != nil {
+		// the flag goes as its JSON form, the same the REST stream sends
+		data, err := json.Marshal(event.FeatureFlag)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+
+		var featureFlag map[string]interface{}
+		if err := json.Unmarshal(data, &featureFlag); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+
+		if protoEvent.FeatureFlag, err = structpb.NewStruct(featureFlag); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return server.Send(protoEvent)
+}
+
+func isAssignmentValidationError(err error) bool {
+	return err.Error() == "person id is required" || err.Error() == "feature flag id is required" || err.Error() == "kind must be include or exclude" ||
+		strings.HasPrefix(err.Error(), "variant ")
+}
+
+func assignmentFromProto(ctx context.Context, request *featureflagv1.AssignmentRequest) a_entity.Assignment {
+	scope := middlewares.GetGrpcScope(ctx)
+
+	return a_entity.Assignment{
+		PersonID:      uint(request.GetPersonId()),
+		FeatureFlagID: uint(request.GetFeatureFlagId()),
+		Variant:       request.GetVariant(),
+		Kind:          request.GetKind(),
+		Environment:   scope.Environment,
+		ProjectID:     scope.ProjectID,
+	}
+}
+
+func evaluationContextFromProto(context *featureflagv1.EvaluationContext) e_entity.EvaluationContext {
+	return e_entity.EvaluationContext{
+		PersonID:   uint(context.GetPersonId()),
+		Email:      context.GetEmail(),
+		Attributes: context.GetAttributes().AsMap(),
+	}
+}
+
+func evaluationToProto(evaluation e_entity.EvaluationResponse) (*featureflagv1.Evaluation, error) {
+	variantValue, err := variantValueToProto(evaluation.VariantValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &featureflagv1.Evaluation{
+		FlagName:     evaluation.FlagName,
+		Value:        evaluation.Value,
+		Reason:       evaluation.Reason,
+		Variant:      evaluation.Variant,
+		VariantValue: variantValue,
+	}, nil
+}
+
+func variantValueToProto(value interface{}) (*structpb.Value, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	protoValue, err := structpb.NewValue(value)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return protoValue, nil
+}
+
+func featureFlagToProto(featureFlag ff_entity.FeatureFlagResponse) *featureflagv1.FeatureFlag {
+	// the REST response carries the id as a string
+	id, _ := strconv.ParseUint(featureFlag.ID, 10, 64)
+
+	protoFlag := &featureflagv1.FeatureFlag{
+		Id:                id,
+		Name:              featureFlag.Name,
+		Description:       featureFlag.Description,
+		IsActive:          featureFlag.IsActive,
+		IsGlobal:          featureFlag.IsGlobal,
+		ExpirationDate:    featureFlag.ExpirationDate,
+		RolloutPercentage: int32(featureFlag.RolloutPercentage),
+		Type:              featureFlag.Type,
+		DefaultVariant:    featureFlag.DefaultVariant,
+		Environment:       featureFlag.Environment,
+		ProjectId:         uint64(featureFlag.ProjectID),
+		CreatedAt:         featureFlag.CreatedAt,
+		UpdatedAt:         featureFlag.UpdatedAt,
+	}
+
+	for _, rule := range featureFlag.Rules {
+		protoFlag.Rules = append(protoFlag.Rules, &featureflagv1.Rule{
+			Attribute: rule.Attribute,
+			Operator:  rule.Operator,
+			Values:    rule.Values,
+			Result:    rule.Result,
+		})
+	}
+
+	for _, variant := range featureFlag.Variants {
+		protoFlag.Variants = append(protoFlag.Variants, &featureflagv1.Variant{
+			Name:   variant.Name,
+			Value:  variant.Value,
+			Weight: int32(variant.Weight),
+		})
+	}
+
+	for _, prerequisite := range featureFlag.Prerequisites {
+		protoFlag.Prerequisites = append(protoFlag.Prerequisites, &featureflagv1.Prerequisite{
+			FlagName: prerequisite.FlagName,
+			Variant:  prerequisite.Variant,
+		})
+	}
+
+	return protoFlag
+}
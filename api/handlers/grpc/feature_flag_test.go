@@ -0,0 +1,336 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"ff/api/middlewares"
+	featureflagv1 "ff/api/proto/featureflag/v1"
+	a_entity "ff/internal/assignment/entity"
+	"ff/internal/db/model"
+	e_entity "ff/internal/evaluation/entity"
+	ff_entity "ff/internal/feature_flag/entity"
+	p_entity "ff/internal/person/entity"
+	project_entity "ff/internal/project/entity"
+	"ff/internal/stream"
+	st_entity "ff/internal/stream/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type MockEvaluationService struct {
+	mock.Mock
+}
+
+func (m *MockEvaluationService) Evaluate(request e_entity.EvaluationRequest) (e_entity.EvaluationResponse, error) {
+	args := m.Called(request)
+	return args.Get(0).(e_entity.EvaluationResponse), args.Error(1)
+}
+
+type MockFeatureFlagService struct {
+	mock.Mock
+}
+
+func (m *MockFeatureFlagService) GetFeatureFlag(pagination model.Pagination, filters ff_entity.FeatureFlagFilters) ([]ff_entity.FeatureFlagResponse, int64, error) {
+	args := m.Called(pagination, filters)
+	return args.Get(0).([]ff_entity.FeatureFlagResponse), args.Get(1).(int64), args.Error(2)
+}
+
+type MockPersonService struct {
+	mock.Mock
+}
+
+func (m *MockPersonService) GetAssignedFeatureFlagsByPersonId(id uint, projectId uint, environment string) ([]p_entity.AssignedFeatureFlagResponse, error) {
+	args := m.Called(id, projectId, environment)
+	return args.Get(0).([]p_entity.AssignedFeatureFlagResponse), args.Error(1)
+}
+
+type MockAssignmentService struct {
+	mock.Mock
+}
+
+func (m *MockAssignmentService) ApplyAssignment(request a_entity.Assignment, personId uint) error {
+	args := m.Called(request, personId)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentService) DeleteAssignment(request a_entity.Assignment, personId uint) error {
+	args := m.Called(request, personId)
+	return args.Error(0)
+}
+
+type MockStreamService struct {
+	mock.Mock
+}
+
+func (m *MockStreamService) Subscribe(projectId uint, environment string, lastEventId uint) (*stream.Subscription, []st_entity.Event, error) {
+	args := m.Called(projectId, environment, lastEventId)
+	return args.Get(0).(*stream.Subscription), args.Get(1).([]st_entity.Event), args.Error(2)
+}
+
+func (m *MockStreamService) Unsubscribe(subscription *stream.Subscription) {
+	m.Called(subscription)
+}
+
+// MockScope authenticates the key "ffk_test" for the project 1 and knows the production environment
+type MockScope struct{}
+
+func (m *MockScope) GetProjectByKey(key string) (project_entity.ProjectResponse, error) {
+	return project_entity.ProjectResponse{}, errors.New("project not found")
+}
+
+func (m *MockScope) AuthenticateApiKey(key string) (project_entity.ProjectResponse, error) {
+	if key != "ffk_test" {
+		return project_entity.ProjectResponse{}, errors.New("invalid api key")
+	}
+	return project_entity.ProjectResponse{ID: 1, Key: "default"}, nil
+}
+
+func (m *MockScope) CheckPermission(projectId uint, personId uint, role string) error {
+	return nil
+}
+
+func (m *MockScope) EnvironmentExists(name string) (bool, error) {
+	return name == model.DefaultEnvironment, nil
+}
+
+type testServer struct {
+	client     featureflagv1.FeatureFlagServiceClient
+	evaluation *MockEvaluationService
+	flags      *MockFeatureFlagService
+	person     *MockPersonService
+	assignment *MockAssignmentService
+	stream     *MockStreamService
+}
+
+func newTestServer(t *testing.T) *testServer {
+	ts := &testServer{
+		evaluation: new(MockEvaluationService),
+		flags:      new(MockFeatureFlagService),
+		person:     new(MockPersonService),
+		assignment: new(MockAssignmentService),
+		stream:     new(MockStreamService),
+	}
+
+	unaryScope, streamScope := middlewares.ResolveGrpcScope(&MockScope{}, &MockScope{})
+	server := grpc.NewServer(grpc.UnaryInterceptor(unaryScope), grpc.StreamInterceptor(streamScope))
+	NewFeatureFlagGrpcHandler(ts.evaluation, ts.flags, ts.person, ts.assignment, ts.stream, server)
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	ts.client = featureflagv1.NewFeatureFlagServiceClient(conn)
+
+	return ts
+}
+
+func withApiKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "x-api-key", key)
+}
+
+func TestScope(t *testing.T) {
+	ts := newTestServer(t)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		code codes.Code
+	}{
+		{name: "Missing api key", ctx: context.Background(), code: codes.Unauthenticated},
+		{name: "Invalid api key", ctx: withApiKey("ffk_wrong"), code: codes.Unauthenticated},
+		{name: "Api key of another project", ctx: metadata.AppendToOutgoingContext(withApiKey("ffk_test"), "x-project", "other"), code: codes.PermissionDenied},
+		{name: "Unknown environment", ctx: metadata.AppendToOutgoingContext(withApiKey("ffk_test"), "x-environment", "qa"), code: codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ts.client.Evaluate(tt.ctx, &featureflagv1.EvaluateRequest{FlagName: "NEW_CHECKOUT"})
+
+			assert.Equal(t, tt.code, status.Code(err))
+			ts.evaluation.AssertNotCalled(t, "Evaluate", mock.Anything)
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Run("Evaluate a flag in the scope of the api key", func(t *testing.T) {
+		ts := newTestServer(t)
+
+		attributes, _ := structpb.NewStruct(map[string]interface{}{"country": "BR"})
+		ts.evaluation.On("Evaluate", e_entity.EvaluationRequest{
+			FlagName:    "BUTTON_COLOR",
+			Context:     e_entity.EvaluationContext{PersonID: 7, Email: "jane@mail.com", Attributes: map[string]interface{}{"country": "BR"}},
+			Environment: model.DefaultEnvironment,
+			ProjectID:   1,
+		}).Return(e_entity.EvaluationResponse{FlagName: "BUTTON_COLOR", Value: true, Reason: e_entity.ReasonTargeting, Variant: "green", VariantValue: "#0f0"}, nil)
+
+		evaluation, err := ts.client.Evaluate(withApiKey("ffk_test"), &featureflagv1.EvaluateRequest{
+			FlagName: "BUTTON_COLOR",
+			Context:  &featureflagv1.EvaluationContext{PersonId: 7, Email: "jane@mail.com", Attributes: attributes},
+		})
+
+		require.NoError(t, err)
+		assert.True(t, evaluation.GetValue())
+		assert.Equal(t, e_entity.ReasonTargeting, evaluation.GetReason())
+		assert.Equal(t, "green", evaluation.GetVariant())
+		assert.Equal(t, "#0f0", evaluation.GetVariantValue().GetStringValue())
+	})
+
+	t.Run("Flag name is required", func(t *testing.T) {
+		ts := newTestServer(t)
+
+		ts.evaluation.On("Evaluate", mock.Anything).Return(e_entity.EvaluationResponse{}, errors.New("FlagName|Flag name is required"))
+
+		_, err := ts.client.Evaluate(withApiKey("ffk_test"), &featureflagv1.EvaluateRequest{})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestBulkEvaluate(t *testing.T) {
+	t.Run("Evaluate the informed flags", func(t *testing.T) {
+		ts := newTestServer(t)
+
+		ts.evaluation.On("Evaluate", mock.MatchedBy(func(request e_entity.EvaluationRequest) bool { return request.FlagName == "A" })).Return(e_entity.EvaluationResponse{FlagName: "A", Value: true}, nil)
+		ts.evaluation.On("Evaluate", mock.MatchedBy(func(request e_entity.EvaluationRequest) bool { return request.FlagName == "B" })).Return(e_entity.EvaluationResponse{FlagName: "B", Reason: e_entity.ReasonNotFound}, nil)
+
+		response, err := ts.client.BulkEvaluate(withApiKey("ffk_test"), &featureflagv1.BulkEvaluateRequest{FlagNames: []string{"A", "B"}})
+
+		require.NoError(t, err)
+		require.Equal(t, 2, len(response.GetEvaluations()))
+		assert.True(t, response.GetEvaluations()[0].GetValue())
+		assert.Equal(t, e_entity.ReasonNotFound, response.GetEvaluations()[1].GetReason())
+		ts.flags.AssertNotCalled(t, "GetFeatureFlag", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Evaluate every flag of the project", func(t *testing.T) {
+		ts := newTestServer(t)
+
+		filters := ff_entity.FeatureFlagFilters{ProjectID: 1, Environment: model.DefaultEnvironment}
+		ts.flags.On("GetFeatureFlag", model.Pagination{Page: 1, Limit: bulkPageSize}, filters).Return([]ff_entity.FeatureFlagResponse{{Name: "A"}}, int64(1), nil)
+		ts.evaluation.On("Evaluate", mock.Anything).Return(e_entity.EvaluationResponse{FlagName: "A", Value: true}, nil)
+
+		response, err := ts.client.BulkEvaluate(withApiKey("ffk_test"), &featureflagv1.BulkEvaluateRequest{})
+
+		require.NoError(t, err)
+		require.Equal(t, 1, len(response.GetEvaluations()))
+		assert.Equal(t, "A", response.GetEvaluations()[0].GetFlagName())
+	})
+}
+
+func TestListFeatureFlags(t *testing.T) {
+	ts := newTestServer(t)
+
+	isActive := true
+	ts.flags.On("GetFeatureFlag", model.Pagination{Page: 1, Limit: 10}, ff_entity.FeatureFlagFilters{
+		Name:        "NEW",
+		IsActive:    &isActive,
+		ProjectID:   1,
+		Environment: model.DefaultEnvironment,
+	}).Return([]ff_entity.FeatureFlagResponse{{
+		ID:       "3",
+		Name:     "NEW_CHECKOUT",
+		IsActive: true,
+		Variants: []ff_entity.Variant{{Name: "green", Value: "#0f0", Weight: 100}},
+	}}, int64(1), nil)
+
+	response, err := ts.client.ListFeatureFlags(withApiKey("ffk_test"), &featureflagv1.ListFeatureFlagsRequest{Name: "NEW", IsActive: &isActive})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), response.GetTotal())
+	require.Equal(t, 1, len(response.GetItems()))
+	assert.Equal(t, uint64(3), response.GetItems()[0].GetId())
+	assert.Equal(t, "green", response.GetItems()[0].GetVariants()[0].GetName())
+}
+
+func TestGetAssignedFeatureFlags(t *testing.T) {
+	ts := newTestServer(t)
+
+	ts.person.On("GetAssignedFeatureFlagsByPersonId", uint(7), uint(1), model.DefaultEnvironment).Return([]p_entity.AssignedFeatureFlagResponse{
+		{ID: 3, Name: "NEW_CHECKOUT", IsActive: true, IsAssigned: true},
+	}, nil)
+
+	response, err := ts.client.GetAssignedFeatureFlags(withApiKey("ffk_test"), &featureflagv1.GetAssignedFeatureFlagsRequest{PersonId: 7})
+
+	require.NoError(t, err)
+	require.Equal(t, 1, len(response.GetItems()))
+	assert.Equal(t, "NEW_CHECKOUT", response.GetItems()[0].GetName())
+
+	_, err = ts.client.GetAssignedFeatureFlags(withApiKey("ffk_test"), &featureflagv1.GetAssignedFeatureFlagsRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAssignments(t *testing.T) {
+	t.Run("Apply an assignment in the environment of the call", func(t *testing.T) {
+		ts := newTestServer(t)
+
+		ts.assignment.On("ApplyAssignment", a_entity.Assignment{PersonID: 7, FeatureFlagID: 3, Environment: model.DefaultEnvironment, ProjectID: 1}, uint(0)).Return(nil)
+
+		response, err := ts.client.ApplyAssignment(withApiKey("ffk_test"), &featureflagv1.AssignmentRequest{PersonId: 7, FeatureFlagId: 3})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Assignment Applied", response.GetMessage())
+	})
+
+	t.Run("Person already assigned", func(t *testing.T) {
+		ts := newTestServer(t)
+
+		ts.assignment.On("ApplyAssignment", mock.Anything, uint(0)).Return(errors.New("Person 7 is already assigned to the feature flag 3"))
+
+		_, err := ts.client.ApplyAssignment(withApiKey("ffk_test"), &featureflagv1.AssignmentRequest{PersonId: 7, FeatureFlagId: 3})
+
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("Delete a missing assignment", func(t *testing.T) {
+		ts := newTestServer(t)
+
+		ts.assignment.On("DeleteAssignment", mock.Anything, uint(0)).Return(errors.New("Person 7 is not assigned to the feature flag 3"))
+
+		_, err := ts.client.DeleteAssignment(withApiKey("ffk_test"), &featureflagv1.AssignmentRequest{PersonId: 7, FeatureFlagId: 3})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestWatch(t *testing.T) {
+	ts := newTestServer(t)
+
+	subscription := &stream.Subscription{ProjectID: 1, Environment: model.DefaultEnvironment}
+	missed := []st_entity.Event{
+		{ID: 3, Type: st_entity.EventReset, ProjectID: 1, Environment: model.DefaultEnvironment},
+	}
+	ts.stream.On("Subscribe", uint(1), model.DefaultEnvironment, uint(2)).Return(subscription, missed, nil)
+	ts.stream.On("Unsubscribe", subscription).Return()
+
+	ctx, cancel := context.WithTimeout(withApiKey("ffk_test"), time.Second)
+	defer cancel()
+
+	watch, err := ts.client.Watch(ctx, &featureflagv1.WatchRequest{LastEventId: 2})
+	require.NoError(t, err)
+
+	event, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), event.GetId())
+	assert.Equal(t, st_entity.EventReset, event.GetType())
+}
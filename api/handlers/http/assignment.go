@@ -6,6 +6,7 @@ import (
 	"ff/pkg/utils"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 )
@@ -30,8 +31,10 @@ func NewAssignmentEchoHandler(assignment AssignmentService, e *echo.Echo) {
 func LoadAssignmentRoutes(e *echo.Echo, handler *AssignmentEchoHandler) {
 	group := e.Group("/api/feature-flags", middlewares.ValidateCookie)
 
-	group.POST("/v1/assignments", handler.applyAssignmentsHandler)
-	group.DELETE("/v1/assignments", handler.removeAssignmentsHandler)
+	for _, prefix := range scopedPrefixes {
+		group.POST(prefix+"/assignments", handler.applyAssignmentsHandler)
+		group.DELETE(prefix+"/assignments", handler.removeAssignmentsHandler)
+	}
 }
 
 func (e *AssignmentEchoHandler) applyAssignmentsHandler(c echo.Context) error {
@@ -47,11 +50,24 @@ func (e *AssignmentEchoHandler) applyAssignmentsHandler(c echo.Context) error {
 		return response.ErrorHandler(http.StatusUnauthorized, err)
 	}
 
+	input.Environment = utils.GetEnvironment(c)
+	input.ProjectID = utils.GetProject(c)
+
 	if err := e.AssignmentService.ApplyAssignment(input, uint(personId)); err != nil {
 		alreadyAssignedError := fmt.Sprintf("Person %d is already assigned to the feature flag %d", input.PersonID, input.FeatureFlagID)
 		if err.Error() == alreadyAssignedError {
 			return response.SuccessHandlerMessage(http.StatusConflict, alreadyAssignedError)
 		}
+		alreadyExcludedError := fmt.Sprintf("Person %d is already excluded from the feature flag %d", input.PersonID, input.FeatureFlagID)
+		if err.Error() == alreadyExcludedError {
+			return response.SuccessHandlerMessage(http.StatusConflict, alreadyExcludedError)
+		}
+		if err.Error() == "kind must be include or exclude" || strings.HasPrefix(err.Error(), "variant ") {
+			return response.ErrorHandler(http.StatusBadRequest, err)
+		}
+		if err.Error() == "feature flag not found" {
+			return response.ErrorHandler(http.StatusNotFound, err)
+		}
 		return response.ErrorHandler(http.StatusInternalServerError, err)
 	}
 
@@ -71,11 +87,17 @@ func (e *AssignmentEchoHandler) removeAssignmentsHandler(c echo.Context) error {
 		return response.ErrorHandler(http.StatusUnauthorized, err)
 	}
 
+	input.Environment = utils.GetEnvironment(c)
+	input.ProjectID = utils.GetProject(c)
+
 	if err := e.AssignmentService.DeleteAssignment(input, uint(personId)); err != nil {
 		featureFlagNotAssigned := fmt.Sprintf("Person %d is not assigned to the feature flag %d", input.PersonID, input.FeatureFlagID)
 		if err.Error() == featureFlagNotAssigned {
 			return response.SuccessHandlerMessage(http.StatusConflict, featureFlagNotAssigned)
 		}
+		if err.Error() == "feature flag not found" {
+			return response.ErrorHandler(http.StatusNotFound, err)
+		}
 		return response.ErrorHandler(http.StatusInternalServerError, err)
 	}
 
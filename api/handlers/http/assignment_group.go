@@ -0,0 +1,242 @@
+package http
+
+import (
+	"errors"
+	"ff/api/middlewares"
+	ag_entity "ff/internal/assignment_group/entity"
+	"ff/internal/db/model"
+	"ff/pkg/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AssignmentGroupService interface {
+	CreateAssignmentGroup(request ag_entity.AssignmentGroup, personId uint) error
+	GetAssignmentGroups(pagination model.Pagination, filters ag_entity.AssignmentGroupFilters) ([]ag_entity.AssignmentGroupResponse, int64, error)
+	UpdateAssignmentGroupById(id uint, request ag_entity.AssignmentGroup) error
+	DeleteAssignmentGroup(id uint) error
+	AddAssignmentGroupMembers(id uint, request ag_entity.AssignmentGroupMembers) error
+	RemoveAssignmentGroupMember(id uint, personId uint) error
+	ApplyGroupAssignment(id uint, request ag_entity.GroupAssignment) error
+	DeleteGroupAssignment(id uint, featureFlagId uint) error
+}
+
+type AssignmentGroupEchoHandler struct {
+	AssignmentGroupService AssignmentGroupService
+}
+
+func NewAssignmentGroupEchoHandler(assignmentGroup AssignmentGroupService, e *echo.Echo) {
+	handler := &AssignmentGroupEchoHandler{
+		AssignmentGroupService: assignmentGroup,
+	}
+
+	LoadAssignmentGroupRoutes(e, handler)
+}
+
+// LoadAssignmentGroupRoutes exposes the assignment groups, also known as segments
+func LoadAssignmentGroupRoutes(e *echo.Echo, handler *AssignmentGroupEchoHandler) {
+	group := e.Group("/api/feature-flags", middlewares.ValidateCookie)
+
+	group.POST("/v1/assignment-groups", handler.createAssignmentGroupHandler)
+	group.GET("/v1/assignment-groups", handler.getAssignmentGroupsHandler)
+	group.PUT("/v1/assignment-groups/:id", handler.updateAssignmentGroupByIdHandler)
+	group.DELETE("/v1/assignment-groups/:id", handler.deleteAssignmentGroupHandler)
+	group.POST("/v1/assignment-groups/:id/members", handler.addAssignmentGroupMembersHandler)
+	group.DELETE("/v1/assignment-groups/:id/members/:personId", handler.removeAssignmentGroupMemberHandler)
+	group.POST("/v1/assignment-groups/:id/feature-flags", handler.applyGroupAssignmentHandler)
+	group.DELETE("/v1/assignment-groups/:id/feature-flags/:featureFlagId", handler.deleteGroupAssignmentHandler)
+}
+
+func (e *AssignmentGroupEchoHandler) createAssignmentGroupHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	var input ag_entity.AssignmentGroup
+	if err := utils.GetBodyFromRequest(c, &input); err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+
+	var personId int
+	if err := utils.GetAuthenticatedPerson(c, &personId); err != nil {
+		return response.ErrorHandler(http.StatusUnauthorized, err)
+	}
+
+	if err := e.AssignmentGroupService.CreateAssignmentGroup(input, uint(personId)); err != nil {
+		if err.Error() == "assignment group already exists" {
+			return response.ErrorHandler(http.StatusConflict, err)
+		}
+		if strings.HasPrefix(err.Error(), "Name|") {
+			return response.ErrorHandler(http.StatusBadRequest, err)
+		}
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusCreated, "Assignment Group Created")
+}
+
+func (e *AssignmentGroupEchoHandler) getAssignmentGroupsHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	id, _ := strconv.Atoi(c.QueryParam("id"))
+
+	if page <= 1 {
+		page = 1 // Default page
+	}
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	assignmentGroups, totalCount, err := e.AssignmentGroupService.GetAssignmentGroups(model.Pagination{
+		Page:  page,
+		Limit: limit,
+	}, ag_entity.AssignmentGroupFilters{
+		ID:   uint(id),
+		Name: c.QueryParam("name"),
+	})
+	if err != nil {
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	interfaceSlice := make([]interface{}, len(assignmentGroups))
+	for i, v := range assignmentGroups {
+		interfaceSlice[i] = v
+	}
+
+	return response.PaginationHandler(interfaceSlice, totalCount)
+}
+
+func (e *AssignmentGroupEchoHandler) updateAssignmentGroupByIdHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	var input ag_entity.AssignmentGroup
+	if err := utils.GetBodyFromRequest(c, &input); err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("assignment group id is not a number"))
+	}
+
+	if err := e.AssignmentGroupService.UpdateAssignmentGroupById(uint(id), input); err != nil {
+		return assignmentGroupErrorHandler(response, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Assignment Group Updated")
+}
+
+func (e *AssignmentGroupEchoHandler) deleteAssignmentGroupHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("assignment group id is not a number"))
+	}
+
+	if err := e.AssignmentGroupService.DeleteAssignmentGroup(uint(id)); err != nil {
+		return assignmentGroupErrorHandler(response, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Assignment Group Deleted")
+}
+
+func (e *AssignmentGroupEchoHandler) addAssignmentGroupMembersHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	var input ag_entity.AssignmentGroupMembers
+	if err := utils.GetBodyFromRequest(c, &input); err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("assignment group id is not a number"))
+	}
+
+	if err := e.AssignmentGroupService.AddAssignmentGroupMembers(uint(id), input); err != nil {
+		return assignmentGroupErrorHandler(response, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Assignment Group Members Added")
+}
+
+func (e *AssignmentGroupEchoHandler) removeAssignmentGroupMemberHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("assignment group id is not a number"))
+	}
+
+	personId, err := strconv.Atoi(c.Param("personId"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("person id is not a number"))
+	}
+
+	if err := e.AssignmentGroupService.RemoveAssignmentGroupMember(uint(id), uint(personId)); err != nil {
+		return assignmentGroupErrorHandler(response, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Assignment Group Member Removed")
+}
+
+func (e *AssignmentGroupEchoHandler) applyGroupAssignmentHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	var input ag_entity.GroupAssignment
+	if err := utils.GetBodyFromRequest(c, &input); err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("assignment group id is not a number"))
+	}
+
+	if err := e.AssignmentGroupService.ApplyGroupAssignment(uint(id), input); err != nil {
+		return assignmentGroupErrorHandler(response, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Feature Flag Assigned to the Assignment Group")
+}
+
+func (e *AssignmentGroupEchoHandler) deleteGroupAssignmentHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("assignment group id is not a number"))
+	}
+
+	featureFlagId, err := strconv.Atoi(c.Param("featureFlagId"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
+	}
+
+	if err := e.AssignmentGroupService.DeleteGroupAssignment(uint(id), uint(featureFlagId)); err != nil {
+		return assignmentGroupErrorHandler(response, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Feature Flag Removed from the Assignment Group")
+}
+
+func assignmentGroupErrorHandler(response ResponseJSON, err error) error {
+	if err.Error() == "assignment group already exists" {
+		return response.ErrorHandler(http.StatusConflict, err)
+	}
+	if err.Error() == "assignment group not found" ||
+		err.Error() == "person is not a member of the assignment group" ||
+		err.Error() == "feature flag is not assigned to the assignment group" {
+		return response.ErrorHandler(http.StatusNotFound, err)
+	}
+	if err.Error() == "feature flag id is required" ||
+		strings.HasPrefix(err.Error(), "Name|") ||
+		strings.HasPrefix(err.Error(), "PersonIDs|") {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+	return response.ErrorHandler(http.StatusInternalServerError, err)
+}
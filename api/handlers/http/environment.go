@@ -0,0 +1,46 @@
+package http
+
+import (
+	env_entity "ff/internal/environment/entity"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type EnvironmentService interface {
+	GetEnvironments() ([]env_entity.EnvironmentResponse, error)
+}
+
+type EnvironmentEchoHandler struct {
+	EnvironmentService EnvironmentService
+}
+
+func NewEnvironmentEchoHandler(environment EnvironmentService, e *echo.Echo) {
+	handler := &EnvironmentEchoHandler{
+		EnvironmentService: environment,
+	}
+
+	LoadEnvironmentRoutes(e, handler)
+}
+
+func LoadEnvironmentRoutes(e *echo.Echo, handler *EnvironmentEchoHandler) {
+	group := e.Group("/api/feature-flags")
+
+	group.GET("/v1/environments", handler.getEnvironmentsHandler)
+}
+
+func (e *EnvironmentEchoHandler) getEnvironmentsHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	environments, err := e.EnvironmentService.GetEnvironments()
+	if err != nil {
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	interfaceSlice := make([]interface{}, len(environments))
+	for i, v := range environments {
+		interfaceSlice[i] = v
+	}
+
+	return response.PaginationHandler(interfaceSlice, int64(len(environments)))
+}
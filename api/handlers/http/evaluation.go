@@ -0,0 +1,56 @@
+package http
+
+import (
+	"ff/api/middlewares"
+	e_entity "ff/internal/evaluation/entity"
+	"ff/pkg/utils"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type EvaluationService interface {
+	Evaluate(request e_entity.EvaluationRequest) (e_entity.EvaluationResponse, error)
+}
+
+type EvaluationEchoHandler struct {
+	EvaluationService EvaluationService
+}
+
+func NewEvaluationEchoHandler(evaluation EvaluationService, e *echo.Echo) {
+	handler := &EvaluationEchoHandler{
+		EvaluationService: evaluation,
+	}
+
+	LoadEvaluationRoutes(e, handler)
+}
+
+func LoadEvaluationRoutes(e *echo.Echo, handler *EvaluationEchoHandler) {
+	group := e.Group("/api/feature-flags", middlewares.ValidateApiKeyOrCookie)
+
+	for _, prefix := range scopedPrefixes {
+		group.POST(prefix+"/evaluate", handler.evaluateHandler)
+	}
+}
+
+func (e *EvaluationEchoHandler) evaluateHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	var input e_entity.EvaluationRequest
+	if err := utils.GetBodyFromRequest(c, &input); err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+
+	input.Environment = utils.GetEnvironment(c)
+	input.ProjectID = utils.GetProject(c)
+
+	evaluation, err := e.EvaluationService.Evaluate(input)
+	if err != nil {
+		if err.Error() == "FlagName|Flag name is required" {
+			return response.ErrorHandler(http.StatusBadRequest, err)
+		}
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	return response.SuccessHandler(http.StatusOK, evaluation)
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	e_entity "ff/internal/evaluation/entity"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEvaluationService is a mock of EvaluationService
+type MockEvaluationService struct {
+	mock.Mock
+}
+
+func (m *MockEvaluationService) Evaluate(request e_entity.EvaluationRequest) (e_entity.EvaluationResponse, error) {
+	args := m.Called(request)
+	return args.Get(0).(e_entity.EvaluationResponse), args.Error(1)
+}
+
+// Evaluate Tests Cases
+func TestEvaluateHandler(t *testing.T) {
+	t.Run("Without api key or cookie", func(t *testing.T) {
+		e := echo.New()
+		evaluationService := new(MockEvaluationService)
+		LoadEvaluationRoutes(e, &EvaluationEchoHandler{EvaluationService: evaluationService})
+
+		for _, path := range []string{"/api/feature-flags/v1/evaluate", "/api/feature-flags/v1/projects/default/environments/staging/evaluate"} {
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"flagName":"TEST_FLAG_NAME"}`))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusUnauthorized, rec.Code, path)
+		}
+		evaluationService.AssertNotCalled(t, "Evaluate", mock.Anything)
+	})
+}
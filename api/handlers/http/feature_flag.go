@@ -6,8 +6,10 @@ import (
 	"ff/internal/db/model"
 	ff_entity "ff/internal/feature_flag/entity"
 	"ff/pkg/utils"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 )
@@ -16,6 +18,12 @@ type FeatureFlagService interface {
 	CreateFeatureFlag(request ff_entity.FeatureFlag, personId uint) error
 	GetFeatureFlag(pagination model.Pagination, filters ff_entity.FeatureFlagFilters) ([]ff_entity.FeatureFlagResponse, int64, error)
 	UpdateFeatureFlagById(id uint, request ff_entity.UpdateFeatureFlag) error
+	UpdateFeatureFlagRules(id uint, request ff_entity.UpdateFeatureFlagRules) error
+	UpdateFeatureFlagVariants(id uint, request ff_entity.UpdateFeatureFlagVariants) error
+	UpdateFeatureFlagPrerequisites(id uint, request ff_entity.UpdateFeatureFlagPrerequisites) error
+	ArchiveFeatureFlag(id uint, request ff_entity.ArchiveFeatureFlag) error
+	RestoreFeatureFlag(id uint, projectId uint, personId uint) error
+	DeleteFeatureFlag(id uint, projectId uint, personId uint) error
 }
 
 type FeatureFlagEchoHandler struct {
@@ -33,9 +41,18 @@ func NewFeatureFlagEchoHandler(featureflag FeatureFlagService, e *echo.Echo) {
 func LoadFeatureFlagsRoutes(e *echo.Echo, handler *FeatureFlagEchoHandler) {
 	group := e.Group("/api/feature-flags", middlewares.ValidateCookie)
 
-	group.POST("/v1/feature-flags", handler.createFeatureFlagHandler)
-	group.GET("/v1/feature-flags", handler.getFeatureFlagHandler)
-	group.PUT("/v1/feature-flags/:id", handler.updateFeatureFlagByIdHandler)
+	for _, prefix := range scopedPrefixes {
+		group.POST(prefix+"/feature-flags", handler.createFeatureFlagHandler)
+		group.GET(prefix+"/feature-flags", handler.getFeatureFlagHandler)
+		group.GET(prefix+"/feature-flags/:id", handler.getFeatureFlagByIdHandler)
+		group.PUT(prefix+"/feature-flags/:id", handler.updateFeatureFlagByIdHandler)
+		group.PUT(prefix+"/feature-flags/:id/rules", handler.updateFeatureFlagRulesHandler)
+		group.PUT(prefix+"/feature-flags/:id/variants", handler.updateFeatureFlagVariantsHandler)
+		group.PUT(prefix+"/feature-flags/:id/prerequisites", handler.updateFeatureFlagPrerequisitesHandler)
+		group.POST(prefix+"/feature-flags/:id/archive", handler.archiveFeatureFlagHandler)
+		group.POST(prefix+"/feature-flags/:id/restore", handler.restoreFeatureFlagHandler)
+		group.DELETE(prefix+"/feature-flags/:id", handler.deleteFeatureFlagHandler)
+	}
 }
 
 func (e *FeatureFlagEchoHandler) createFeatureFlagHandler(c echo.Context) error {
@@ -51,6 +68,9 @@ func (e *FeatureFlagEchoHandler) createFeatureFlagHandler(c echo.Context) error
 		return response.ErrorHandler(http.StatusUnauthorized, err)
 	}
 
+	input.Environment = utils.GetEnvironment(c)
+	input.ProjectID = utils.GetProject(c)
+
 	if err := e.FeatureFlagService.CreateFeatureFlag(input, uint(personId)); err != nil {
 		if err.Error() == "feature flag already exists" {
 			return response.ErrorHandler(http.StatusConflict, err)
@@ -58,7 +78,12 @@ func (e *FeatureFlagEchoHandler) createFeatureFlagHandler(c echo.Context) error
 		if err.Error() == "Name|Name is required" ||
 			err.Error() == "Name|Name must be uppercase and contain only letters, numbers, underscores" ||
 			err.Error() == "Description|Description is required" ||
-			err.Error() == "ExpirationDate|Expiration date must be in YYYY-MM-DD format" {
+			err.Error() == "ExpirationDate|Expiration date must be in YYYY-MM-DD format" ||
+			err.Error() == "RolloutPercentage|Rollout percentage must be between 0 and 100" ||
+			strings.HasPrefix(err.Error(), "Rules|") ||
+			strings.HasPrefix(err.Error(), "Type|") ||
+			strings.HasPrefix(err.Error(), "Variants|") ||
+			strings.HasPrefix(err.Error(), "Prerequisites|") {
 			return response.ErrorHandler(http.StatusBadRequest, err)
 		}
 		return response.ErrorHandler(http.StatusInternalServerError, err)
@@ -102,6 +127,12 @@ func (e *FeatureFlagEchoHandler) getFeatureFlagHandler(c echo.Context) error {
 		return response.ErrorHandler(http.StatusBadRequest, errors.New("invalid isGlobalStr value"))
 	}
 
+	// archived flags are hidden unless asked for
+	archivedStr := c.QueryParam("archived")
+	if archivedStr != "" && archivedStr != "true" && archivedStr != "false" {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("invalid archived value"))
+	}
+
 	if page <= 1 {
 		page = 1 // Default page
 	}
@@ -115,11 +146,14 @@ func (e *FeatureFlagEchoHandler) getFeatureFlagHandler(c echo.Context) error {
 	}
 
 	filters := ff_entity.FeatureFlagFilters{
-		ID:       uint(id),
-		Name:     name,
-		IsActive: isActive,
-		IsGlobal: isGlobal,
-		PersonID: uint(personId),
+		ID:          uint(id),
+		Name:        name,
+		IsActive:    isActive,
+		IsGlobal:    isGlobal,
+		PersonID:    uint(personId),
+		ProjectID:   utils.GetProject(c),
+		Environment: utils.GetEnvironment(c),
+		Archived:    archivedStr == "true",
 	}
 
 	featureFlag, totalCount, err := e.FeatureFlagService.GetFeatureFlag(pagination, filters)
@@ -137,6 +171,28 @@ func (e *FeatureFlagEchoHandler) getFeatureFlagHandler(c echo.Context) error {
 	return response.PaginationHandler(interfaceSlice, totalCount)
 }
 
+// getFeatureFlagByIdHandler returns the flag with its version as the ETag, the update sends it
+// back in If-Match
+func (e *FeatureFlagEchoHandler) getFeatureFlagByIdHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
+	}
+
+	featureFlag, found, err := e.currentFeatureFlag(c, uint(id))
+	if err != nil {
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+	if !found {
+		return response.ErrorHandler(http.StatusNotFound, errors.New("feature flag not found"))
+	}
+
+	c.Response().Header().Set("ETag", featureFlagETag(featureFlag.Version))
+	return response.SuccessHandler(http.StatusOK, featureFlag)
+}
+
 func (e *FeatureFlagEchoHandler) updateFeatureFlagByIdHandler(c echo.Context) error {
 	response := ResponseJSON{c: c}
 
@@ -150,26 +206,305 @@ func (e *FeatureFlagEchoHandler) updateFeatureFlagByIdHandler(c echo.Context) er
 		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
 	}
 
-	// TODO: get personId to audit
+	// the person is kept in the revision of the flag
 	var personId int
 	if err = utils.GetAuthenticatedPerson(c, &personId); err != nil {
 		return response.ErrorHandler(http.StatusUnauthorized, err)
 	}
 
+	// the update has to say which version it is based on, If-Match wins over the body
+	if ifMatch := c.Request().Header.Get("If-Match"); ifMatch != "" {
+		version, err := parseFeatureFlagETag(ifMatch)
+		if err != nil {
+			return response.ErrorHandler(http.StatusBadRequest, err)
+		}
+		input.Version = version
+	}
+	if input.Version == 0 {
+		return response.ErrorHandler(http.StatusPreconditionRequired, errors.New("feature flag version is required, send it in the If-Match header or the version field"))
+	}
+
+	input.Environment = utils.GetEnvironment(c)
+	input.ProjectID = utils.GetProject(c)
+	input.PersonID = uint(personId)
+
 	if err := e.FeatureFlagService.UpdateFeatureFlagById(uint(id), input); err != nil {
 		if err.Error() == "no feature flag updated" {
 			return response.SuccessHandlerMessage(http.StatusOK, "no feature flag updated")
 		}
 		if err.Error() == "Name|Name must be uppercase and contain only letters, numbers, underscores" ||
 			err.Error() == "Description|Description is required" ||
-			err.Error() == "ExpirationDate|Expiration date must be in YYYY-MM-DD format" {
+			err.Error() == "ExpirationDate|Expiration date must be in YYYY-MM-DD format" ||
+			err.Error() == "RolloutPercentage|Rollout percentage must be between 0 and 100" {
 			return response.ErrorHandler(http.StatusBadRequest, err)
 		}
 		if err.Error() == "feature flag not found" {
 			return response.ErrorHandler(http.StatusNotFound, err)
 		}
+		if err.Error() == "feature flag was changed by someone else" {
+			return e.conflictHandler(c, uint(id), err)
+		}
+		if strings.HasPrefix(err.Error(), "feature flag is a prerequisite of") ||
+			err.Error() == "feature flag is archived" {
+			return response.ErrorHandler(http.StatusConflict, err)
+		}
 		return response.ErrorHandler(http.StatusInternalServerError, err)
 	}
 
 	return response.SuccessHandlerMessage(http.StatusOK, "Feature Flag Updated")
 }
+
+// conflictHandler answers a stale update with the flag as it is now, the client merges its
+// change into it and sends it again with the new version
+func (e *FeatureFlagEchoHandler) conflictHandler(c echo.Context, id uint, conflict error) error {
+	response := ResponseJSON{c: c}
+
+	featureFlag, found, err := e.currentFeatureFlag(c, id)
+	if err != nil {
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+	if !found {
+		return response.ErrorHandler(http.StatusNotFound, errors.New("feature flag not found"))
+	}
+
+	c.Response().Header().Set("ETag", featureFlagETag(featureFlag.Version))
+	return response.SuccessHandler(http.StatusConflict, map[string]interface{}{
+		"error":       conflict.Error(),
+		"featureFlag": featureFlag,
+	})
+}
+
+// currentFeatureFlag reads the flag in the project and environment of the request
+func (e *FeatureFlagEchoHandler) currentFeatureFlag(c echo.Context, id uint) (ff_entity.FeatureFlagResponse, bool, error) {
+	featureFlags, _, err := e.FeatureFlagService.GetFeatureFlag(model.Pagination{
+		Page:  1,
+		Limit: 1,
+	}, ff_entity.FeatureFlagFilters{
+		ID:          id,
+		ProjectID:   utils.GetProject(c),
+		Environment: utils.GetEnvironment(c),
+	})
+	if err != nil || len(featureFlags) == 0 {
+		return ff_entity.FeatureFlagResponse{}, false, err
+	}
+
+	return featureFlags[0], true, nil
+}
+
+// featureFlagETag is strong, the version changes with every change of the flag
+func featureFlagETag(version uint) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+func parseFeatureFlagETag(ifMatch string) (uint, error) {
+	version, err := strconv.ParseUint(strings.Trim(strings.TrimPrefix(strings.TrimSpace(ifMatch), "W/"), `"`), 10, 64)
+	if err != nil || version == 0 {
+		return 0, errors.New("invalid If-Match value, it must be the ETag of the feature flag")
+	}
+
+	return uint(version), nil
+}
+
+func (e *FeatureFlagEchoHandler) updateFeatureFlagRulesHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	var input ff_entity.UpdateFeatureFlagRules
+	if err := utils.GetBodyFromRequest(c, &input); err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
+	}
+
+	var personId int
+	if err = utils.GetAuthenticatedPerson(c, &personId); err != nil {
+		return response.ErrorHandler(http.StatusUnauthorized, err)
+	}
+
+	input.ProjectID = utils.GetProject(c)
+	input.PersonID = uint(personId)
+
+	if err := e.FeatureFlagService.UpdateFeatureFlagRules(uint(id), input); err != nil {
+		if strings.HasPrefix(err.Error(), "Rules|") {
+			return response.ErrorHandler(http.StatusBadRequest, err)
+		}
+		if err.Error() == "feature flag not found" {
+			return response.ErrorHandler(http.StatusNotFound, err)
+		}
+		if err.Error() == "feature flag is archived" {
+			return response.ErrorHandler(http.StatusConflict, err)
+		}
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Feature Flag Rules Updated")
+}
+
+func (e *FeatureFlagEchoHandler) updateFeatureFlagVariantsHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	var input ff_entity.UpdateFeatureFlagVariants
+	if err := utils.GetBodyFromRequest(c, &input); err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
+	}
+
+	var personId int
+	if err = utils.GetAuthenticatedPerson(c, &personId); err != nil {
+		return response.ErrorHandler(http.StatusUnauthorized, err)
+	}
+
+	input.ProjectID = utils.GetProject(c)
+	input.PersonID = uint(personId)
+
+	if err := e.FeatureFlagService.UpdateFeatureFlagVariants(uint(id), input); err != nil {
+		if strings.HasPrefix(err.Error(), "Variants|") {
+			return response.ErrorHandler(http.StatusBadRequest, err)
+		}
+		if err.Error() == "feature flag not found" {
+			return response.ErrorHandler(http.StatusNotFound, err)
+		}
+		if err.Error() == "feature flag is archived" {
+			return response.ErrorHandler(http.StatusConflict, err)
+		}
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Feature Flag Variants Updated")
+}
+
+func (e *FeatureFlagEchoHandler) updateFeatureFlagPrerequisitesHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	var input ff_entity.UpdateFeatureFlagPrerequisites
+	if err := utils.GetBodyFromRequest(c, &input); err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
+	}
+
+	var personId int
+	if err = utils.GetAuthenticatedPerson(c, &personId); err != nil {
+		return response.ErrorHandler(http.StatusUnauthorized, err)
+	}
+
+	input.ProjectID = utils.GetProject(c)
+	input.PersonID = uint(personId)
+
+	if err := e.FeatureFlagService.UpdateFeatureFlagPrerequisites(uint(id), input); err != nil {
+		if strings.HasPrefix(err.Error(), "Prerequisites|") {
+			return response.ErrorHandler(http.StatusBadRequest, err)
+		}
+		if err.Error() == "feature flag not found" {
+			return response.ErrorHandler(http.StatusNotFound, err)
+		}
+		if err.Error() == "feature flag is archived" {
+			return response.ErrorHandler(http.StatusConflict, err)
+		}
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Feature Flag Prerequisites Updated")
+}
+
+func (e *FeatureFlagEchoHandler) archiveFeatureFlagHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	// the body is optional, it only carries the force option
+	var input ff_entity.ArchiveFeatureFlag
+	if c.Request().ContentLength != 0 {
+		if err := utils.GetBodyFromRequest(c, &input); err != nil {
+			return response.ErrorHandler(http.StatusBadRequest, err)
+		}
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
+	}
+
+	// the person is kept in the revision of the flag
+	var personId int
+	if err = utils.GetAuthenticatedPerson(c, &personId); err != nil {
+		return response.ErrorHandler(http.StatusUnauthorized, err)
+	}
+
+	input.ProjectID = utils.GetProject(c)
+	input.PersonID = uint(personId)
+
+	if err := e.FeatureFlagService.ArchiveFeatureFlag(uint(id), input); err != nil {
+		if err.Error() == "feature flag not found" {
+			return response.ErrorHandler(http.StatusNotFound, err)
+		}
+		if err.Error() == "feature flag is already archived" ||
+			strings.HasPrefix(err.Error(), "feature flag is a prerequisite of") {
+			return response.ErrorHandler(http.StatusConflict, err)
+		}
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Feature Flag Archived")
+}
+
+func (e *FeatureFlagEchoHandler) restoreFeatureFlagHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
+	}
+
+	// the person is kept in the revision of the flag
+	var personId int
+	if err = utils.GetAuthenticatedPerson(c, &personId); err != nil {
+		return response.ErrorHandler(http.StatusUnauthorized, err)
+	}
+
+	if err := e.FeatureFlagService.RestoreFeatureFlag(uint(id), utils.GetProject(c), uint(personId)); err != nil {
+		if err.Error() == "feature flag not found" {
+			return response.ErrorHandler(http.StatusNotFound, err)
+		}
+		if err.Error() == "feature flag is not archived" {
+			return response.ErrorHandler(http.StatusConflict, err)
+		}
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Feature Flag Restored")
+}
+
+func (e *FeatureFlagEchoHandler) deleteFeatureFlagHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
+	}
+
+	// the person is kept in the revision of the flag
+	var personId int
+	if err = utils.GetAuthenticatedPerson(c, &personId); err != nil {
+		return response.ErrorHandler(http.StatusUnauthorized, err)
+	}
+
+	if err := e.FeatureFlagService.DeleteFeatureFlag(uint(id), utils.GetProject(c), uint(personId)); err != nil {
+		if err.Error() == "feature flag not found" {
+			return response.ErrorHandler(http.StatusNotFound, err)
+		}
+		if err.Error() == "feature flag must be archived before it is deleted" {
+			return response.ErrorHandler(http.StatusConflict, err)
+		}
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Feature Flag Deleted")
+}
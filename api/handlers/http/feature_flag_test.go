@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"ff/internal/auth"
 	"ff/internal/db/model"
 	featureflag "ff/internal/feature_flag"
 	featureFlagEntity "ff/internal/feature_flag/entity"
@@ -43,6 +44,56 @@ func (m *MockRepository) UpdateFeatureFlagById(id uint, featureFlag model.Update
 	return args.Error(0)
 }
 
+func (m *MockRepository) GetFeatureFlagByName(projectId uint, name string, environment string) (model.FeatureFlag, error) {
+	args := m.Called(projectId, name, environment)
+	return args.Get(0).(model.FeatureFlag), args.Error(1)
+}
+
+func (m *MockRepository) ReplaceRules(featureFlagId uint, rules []model.Rule) error {
+	args := m.Called(featureFlagId, rules)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ReplaceVariants(featureFlagId uint, defaultVariant string, variants []model.Variant) error {
+	args := m.Called(featureFlagId, defaultVariant, variants)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ReplacePrerequisites(featureFlagId uint, prerequisites []model.Prerequisite) error {
+	args := m.Called(featureFlagId, prerequisites)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetDependentFeatureFlags(featureFlagId uint) ([]model.FeatureFlag, error) {
+	args := m.Called(featureFlagId)
+	return args.Get(0).([]model.FeatureFlag), args.Error(1)
+}
+
+func (m *MockRepository) GetExpiredFeatureFlags(date string) ([]model.FeatureFlag, error) {
+	args := m.Called(date)
+	return args.Get(0).([]model.FeatureFlag), args.Error(1)
+}
+
+func (m *MockRepository) ExpireFeatureFlag(id uint, deactivate bool, reason string, expiredAt time.Time) (bool, error) {
+	args := m.Called(id, deactivate, reason, expiredAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) RollbackFeatureFlag(id uint, rollback model.RollbackFeatureFlag) error {
+	args := m.Called(id, rollback)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ArchiveFeatureFlag(id uint, archivedAt *time.Time) error {
+	args := m.Called(id, archivedAt)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteFeatureFlag(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 // Create Feature Flag Tests Cases
 func TestCreateFeatureFlagHandler(t *testing.T) {
 	validFeatureFlagBody := featureFlagEntity.FeatureFlag{
@@ -56,26 +107,20 @@ func TestCreateFeatureFlagHandler(t *testing.T) {
 		e := echo.New()
 		req := httptest.NewRequest(http.MethodPost, "/api/feature-flags/v1/feature-flags", nil)
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Personid", "123")
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
+		c.Set("auth_info", auth.AuthUserResponse{PersonID: 123})
 
-		filtersMock := mock.MatchedBy(func(filters model.FeatureFlagFilters) bool {
-			return filters.Name == "TEST_FLAG_NAME"
-		})
-		paginationMock := mock.MatchedBy(func(pagination model.Pagination) bool {
-			return pagination.Page == 1 && pagination.Limit == 1
-		})
 		featureFlagMock := mock.AnythingOfType("model.FeatureFlag")
 
 		mockRepository := new(MockRepository)
-		mockRepository.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{}, 0, nil)
+		mockRepository.On("GetFeatureFlagByName", uint(0), "TEST_FLAG_NAME", "").Return(model.FeatureFlag{}, nil)
 		mockRepository.On("AddFeatureFlag", featureFlagMock).Return(nil)
 
 		mockLogger := zerolog.New(os.Stdout)
 
 		handler := &FeatureFlagEchoHandler{
-			FeatureFlagService: featureflag.FeatureFlagService{
+			FeatureFlagService: &featureflag.FeatureFlagService{
 				Repository: mockRepository,
 				Logger:     &mockLogger,
 			},
@@ -92,92 +137,24 @@ func TestCreateFeatureFlagHandler(t *testing.T) {
 		assert.Equal(t, http.StatusCreated, rec.Code)
 		assert.JSONEq(t, `{"message":"Feature Flag Created"}`, rec.Body.String())
 
-		mockRepository.AssertCalled(t, "GetFeatureFlag", filtersMock, paginationMock)
+		mockRepository.AssertCalled(t, "GetFeatureFlagByName", uint(0), "TEST_FLAG_NAME", "")
 		mockRepository.AssertCalled(t, "AddFeatureFlag", featureFlagMock)
 	})
 
-	t.Run("Missing PersonId", func(t *testing.T) {
-		// Setup
-		e := echo.New()
-		req := httptest.NewRequest(http.MethodPost, "/api/feature-flags/v1/feature-flags", nil)
-		req.Header.Set("Content-Type", "application/json")
-		// Intentionally not setting PersonId header
-		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
-
-		mockRepository := new(MockRepository)
-		mockLogger := zerolog.New(os.Stdout)
-
-		handler := &FeatureFlagEchoHandler{
-			FeatureFlagService: featureflag.FeatureFlagService{
-				Repository: mockRepository,
-				Logger:     &mockLogger,
-			},
-		}
-
-		inputJSON, _ := json.Marshal(validFeatureFlagBody)
-		c.Request().Body = io.NopCloser(bytes.NewBuffer(inputJSON))
-
-		// Perform request
-		err := handler.createFeatureFlagHandler(c)
-
-		// Assertions
-		assert.NoError(t, err)
-		assert.Equal(t, http.StatusUnauthorized, rec.Code)
-		assert.JSONEq(t, `{"error":"missing Personid header"}`, rec.Body.String())
-
-		mockRepository.AssertNotCalled(t, "GetFeatureFlag")
-		mockRepository.AssertNotCalled(t, "AddFeatureFlag")
-	})
-
-	t.Run("Invalid PersonId", func(t *testing.T) {
-		// Setup
-		e := echo.New()
-		req := httptest.NewRequest(http.MethodPost, "/api/feature-flags/v1/feature-flags", nil)
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Personid", "invalid")
-		rec := httptest.NewRecorder()
-		c := e.NewContext(req, rec)
-
-		mockRepository := new(MockRepository)
-		mockLogger := zerolog.New(os.Stdout)
-
-		handler := &FeatureFlagEchoHandler{
-			FeatureFlagService: featureflag.FeatureFlagService{
-				Repository: mockRepository,
-				Logger:     &mockLogger,
-			},
-		}
-
-		inputJSON, _ := json.Marshal(validFeatureFlagBody)
-		c.Request().Body = io.NopCloser(bytes.NewBuffer(inputJSON))
-
-		// Perform request
-		err := handler.createFeatureFlagHandler(c)
-
-		// Assertions
-		assert.NoError(t, err)
-		assert.Equal(t, http.StatusUnauthorized, rec.Code)
-		assert.JSONEq(t, `{"error":"invalid Personid format"}`, rec.Body.String())
-
-		mockRepository.AssertNotCalled(t, "GetFeatureFlag")
-		mockRepository.AssertNotCalled(t, "AddFeatureFlag")
-	})
-
 	t.Run("PersonId zero (not logged)", func(t *testing.T) {
 		// Setup
 		e := echo.New()
 		req := httptest.NewRequest(http.MethodPost, "/api/feature-flags/v1/feature-flags", nil)
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Personid", "0")
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
+		c.Set("auth_info", auth.AuthUserResponse{PersonID: 0})
 
 		mockRepository := new(MockRepository)
 		mockLogger := zerolog.New(os.Stdout)
 
 		handler := &FeatureFlagEchoHandler{
-			FeatureFlagService: featureflag.FeatureFlagService{
+			FeatureFlagService: &featureflag.FeatureFlagService{
 				Repository: mockRepository,
 				Logger:     &mockLogger,
 			},
@@ -194,7 +171,7 @@ func TestCreateFeatureFlagHandler(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, rec.Code)
 		assert.JSONEq(t, `{"error":"you are not logged in"}`, rec.Body.String())
 
-		mockRepository.AssertNotCalled(t, "GetFeatureFlag")
+		mockRepository.AssertNotCalled(t, "GetFeatureFlagByName")
 		mockRepository.AssertNotCalled(t, "AddFeatureFlag")
 	})
 
@@ -203,26 +180,20 @@ func TestCreateFeatureFlagHandler(t *testing.T) {
 		e := echo.New()
 		req := httptest.NewRequest(http.MethodPost, "/api/feature-flags/v1/feature-flags", nil)
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Personid", "123")
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
+		c.Set("auth_info", auth.AuthUserResponse{PersonID: 123})
 
-		filtersMock := mock.MatchedBy(func(filters model.FeatureFlagFilters) bool {
-			return filters.Name == "TEST_FLAG_NAME"
-		})
-		paginationMock := mock.MatchedBy(func(pagination model.Pagination) bool {
-			return pagination.Page == 1 && pagination.Limit == 1
-		})
 		featureFlagMock := mock.AnythingOfType("model.FeatureFlag")
 
 		mockRepository := new(MockRepository)
-		mockRepository.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{}, 0, nil)
+		mockRepository.On("GetFeatureFlagByName", uint(0), "TEST_FLAG_NAME", "").Return(model.FeatureFlag{}, nil)
 		mockRepository.On("AddFeatureFlag", featureFlagMock).Return(errors.New("add repository error"))
 
 		mockLogger := zerolog.New(os.Stdout)
 
 		handler := &FeatureFlagEchoHandler{
-			FeatureFlagService: featureflag.FeatureFlagService{
+			FeatureFlagService: &featureflag.FeatureFlagService{
 				Repository: mockRepository,
 				Logger:     &mockLogger,
 			},
@@ -239,7 +210,7 @@ func TestCreateFeatureFlagHandler(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 		assert.JSONEq(t, `{"error":"add repository error"}`, rec.Body.String())
 
-		mockRepository.AssertCalled(t, "GetFeatureFlag", filtersMock, paginationMock)
+		mockRepository.AssertCalled(t, "GetFeatureFlagByName", uint(0), "TEST_FLAG_NAME", "")
 		mockRepository.AssertCalled(t, "AddFeatureFlag", featureFlagMock)
 	})
 
@@ -248,24 +219,17 @@ func TestCreateFeatureFlagHandler(t *testing.T) {
 		e := echo.New()
 		req := httptest.NewRequest(http.MethodPost, "/api/feature-flags/v1/feature-flags", nil)
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Personid", "123")
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
-
-		filtersMock := mock.MatchedBy(func(filters model.FeatureFlagFilters) bool {
-			return filters.Name == "TEST_FLAG_NAME"
-		})
-		paginationMock := mock.MatchedBy(func(pagination model.Pagination) bool {
-			return pagination.Page == 1 && pagination.Limit == 1
-		})
+		c.Set("auth_info", auth.AuthUserResponse{PersonID: 123})
 
 		mockRepository := new(MockRepository)
-		mockRepository.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{}, 0, errors.New("get repository error"))
+		mockRepository.On("GetFeatureFlagByName", uint(0), "TEST_FLAG_NAME", "").Return(model.FeatureFlag{}, errors.New("get repository error"))
 
 		mockLogger := zerolog.New(os.Stdout)
 
 		handler := &FeatureFlagEchoHandler{
-			FeatureFlagService: featureflag.FeatureFlagService{
+			FeatureFlagService: &featureflag.FeatureFlagService{
 				Repository: mockRepository,
 				Logger:     &mockLogger,
 			},
@@ -282,7 +246,7 @@ func TestCreateFeatureFlagHandler(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 		assert.JSONEq(t, `{"error":"get repository error"}`, rec.Body.String())
 
-		mockRepository.AssertCalled(t, "GetFeatureFlag", filtersMock, paginationMock)
+		mockRepository.AssertCalled(t, "GetFeatureFlagByName", uint(0), "TEST_FLAG_NAME", "")
 		mockRepository.AssertNotCalled(t, "AddFeatureFlag")
 	})
 }
@@ -319,7 +283,7 @@ func TestGetFeatureFlagHandler(t *testing.T) {
 	}}
 
 	featureFlagResponse := []featureFlagEntity.FeatureFlagResponse{{
-		ID:             4,
+		ID:             "4",
 		Name:           "TEST_FLAG_NAME",
 		Description:    "This is an example feature flag",
 		IsActive:       false,
@@ -351,9 +315,9 @@ func TestGetFeatureFlagHandler(t *testing.T) {
 		e := echo.New()
 		req := httptest.NewRequest(http.MethodGet, "/api/feature-flags/v1/feature-flags?"+queryParams.Encode(), nil)
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Personid", "123")
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
+		c.Set("auth_info", auth.AuthUserResponse{PersonID: 123})
 
 		filtersMock := mock.MatchedBy(func(filters model.FeatureFlagFilters) bool {
 			return filters.Name == "TEST_FLAG_NAME"
@@ -368,7 +332,7 @@ func TestGetFeatureFlagHandler(t *testing.T) {
 		mockLogger := zerolog.New(os.Stdout)
 
 		handler := &FeatureFlagEchoHandler{
-			FeatureFlagService: featureflag.FeatureFlagService{
+			FeatureFlagService: &featureflag.FeatureFlagService{
 				Repository: mockRepository,
 				Logger:     &mockLogger,
 			},
@@ -408,11 +372,12 @@ func TestUpdateFeatureFlagByIdHandler(t *testing.T) {
 		// Setup
 		e := echo.New()
 		url := fmt.Sprintf("/api/feature-flags/v1/feature-flags/%d", featureFlagId)
-		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req := httptest.NewRequest(http.MethodPut, url, nil)
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Personid", "123")
+		req.Header.Set("If-Match", `"1"`)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
+		c.Set("auth_info", auth.AuthUserResponse{PersonID: 123})
 
 		filtersMock := mock.MatchedBy(func(filters model.FeatureFlagFilters) bool {
 			return filters.ID == uint(featureFlagId)
@@ -425,16 +390,18 @@ func TestUpdateFeatureFlagByIdHandler(t *testing.T) {
 			Description:    "This is an example feature flag",
 			IsActive:       false,
 			ExpirationDate: "2024-05-09",
+			Environment:    model.DefaultEnvironment,
+			Version:        1,
 		}
 
 		mockRepository := new(MockRepository)
-		mockRepository.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{}, 1, nil)
+		mockRepository.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{{ID: uint(featureFlagId), Version: 1}}, 1, nil)
 		mockRepository.On("UpdateFeatureFlagById", uint(featureFlagId), featureFlagToUpdate).Return(nil)
 
 		mockLogger := zerolog.New(os.Stdout)
 
 		handler := &FeatureFlagEchoHandler{
-			FeatureFlagService: featureflag.FeatureFlagService{
+			FeatureFlagService: &featureflag.FeatureFlagService{
 				Repository: mockRepository,
 				Logger:     &mockLogger,
 			},
@@ -467,11 +434,12 @@ func TestUpdateFeatureFlagByIdHandler(t *testing.T) {
 		// Setup
 		e := echo.New()
 		url := fmt.Sprintf("/api/feature-flags/v1/feature-flags/%d", featureFlagId)
-		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req := httptest.NewRequest(http.MethodPut, url, nil)
 		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Personid", "123")
+		req.Header.Set("If-Match", `"1"`)
 		rec := httptest.NewRecorder()
 		c := e.NewContext(req, rec)
+		c.Set("auth_info", auth.AuthUserResponse{PersonID: 123})
 
 		filtersMock := mock.MatchedBy(func(filters model.FeatureFlagFilters) bool {
 			return filters.ID == uint(featureFlagId)
@@ -486,7 +454,7 @@ func TestUpdateFeatureFlagByIdHandler(t *testing.T) {
 		mockLogger := zerolog.New(os.Stdout)
 
 		handler := &FeatureFlagEchoHandler{
-			FeatureFlagService: featureflag.FeatureFlagService{
+			FeatureFlagService: &featureflag.FeatureFlagService{
 				Repository: mockRepository,
 				Logger:     &mockLogger,
 			},
@@ -512,4 +480,39 @@ func TestUpdateFeatureFlagByIdHandler(t *testing.T) {
 		mockRepository.AssertNotCalled(t, "AddFeatureFlag")
 		mockRepository.AssertNotCalled(t, "GetFeatureFlag")
 	})
+
+	t.Run("Missing version", func(t *testing.T) {
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, "/api/feature-flags/v1/feature-flags/1", nil)
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("auth_info", auth.AuthUserResponse{PersonID: 123})
+
+		mockRepository := new(MockRepository)
+		mockLogger := zerolog.New(os.Stdout)
+
+		handler := &FeatureFlagEchoHandler{
+			FeatureFlagService: &featureflag.FeatureFlagService{
+				Repository: mockRepository,
+				Logger:     &mockLogger,
+			},
+		}
+
+		inputJSON, _ := json.Marshal(featureFlagBody)
+		c.Request().Body = io.NopCloser(bytes.NewBuffer(inputJSON))
+
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		// Perform request
+		err := handler.updateFeatureFlagByIdHandler(c)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+
+		mockRepository.AssertNotCalled(t, "UpdateFeatureFlagById", mock.Anything, mock.Anything)
+	})
 }
@@ -6,6 +6,15 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// scopedPrefixes are the prefixes the project and environment scoped routes are served under,
+// without them in the path they come from the X-Project and X-Environment headers
+var scopedPrefixes = []string{
+	"/v1",
+	"/v1/environments/:environment",
+	"/v1/projects/:project",
+	"/v1/projects/:project/environments/:environment",
+}
+
 type ResponseJSON struct {
 	c echo.Context
 }
@@ -5,6 +5,7 @@ import (
 	middlewares "ff/api/middlewares"
 	"ff/internal/db/model"
 	p_entity "ff/internal/person/entity"
+	"ff/pkg/utils"
 	"net/http"
 	"strconv"
 
@@ -13,7 +14,7 @@ import (
 
 type PersonService interface {
 	GetPeopleAssignmentByFeatureFlag(pagination model.Pagination, filters p_entity.PersonFilters) ([]p_entity.PersonWithAssignmentResponse, int64, error)
-	GetAssignedFeatureFlagsByPersonId(id uint) ([]p_entity.AssignedFeatureFlagResponse, error)
+	GetAssignedFeatureFlagsByPersonId(id uint, projectId uint, environment string) ([]p_entity.AssignedFeatureFlagResponse, error)
 }
 
 type PeopleEchoHandler struct {
@@ -31,8 +32,10 @@ func NewPersonEchoHandler(person PersonService, e *echo.Echo) {
 func LoadPeopleRoutes(e *echo.Echo, handler *PeopleEchoHandler) {
 	group := e.Group("/api/feature-flags")
 
-	group.GET("/v1/people/feature-flags/:id", handler.getPersonWithAssignmentHandler, middlewares.ValidateCookie)
-	group.GET("/v1/people/:id/assigned-feature-flags", handler.getAssignedFeatureFlagsByPersonIdHandler)
+	for _, prefix := range scopedPrefixes {
+		group.GET(prefix+"/people/feature-flags/:id", handler.getPersonWithAssignmentHandler, middlewares.ValidateCookie)
+		group.GET(prefix+"/people/:id/assigned-feature-flags", handler.getAssignedFeatureFlagsByPersonIdHandler)
+	}
 	// TODO: get feature flag / 1/ person / 1/ to get a single register? make sense?
 }
 
@@ -77,6 +80,7 @@ func (e *PeopleEchoHandler) getPersonWithAssignmentHandler(c echo.Context) error
 		FeatureFlagID: uint(id),
 		Name:          name,
 		IsAssigned:    isAssigned,
+		Environment:   utils.GetEnvironment(c),
 	}
 
 	people, totalCount, err := e.PeopleService.GetPeopleAssignmentByFeatureFlag(pagination, filters)
@@ -101,7 +105,7 @@ func (e *PeopleEchoHandler) getAssignedFeatureFlagsByPersonIdHandler(c echo.Cont
 		return response.ErrorHandler(http.StatusBadRequest, errors.New("person id is not a number"))
 	}
 
-	featureFlags, err := e.PeopleService.GetAssignedFeatureFlagsByPersonId(uint(id))
+	featureFlags, err := e.PeopleService.GetAssignedFeatureFlagsByPersonId(uint(id), utils.GetProject(c), utils.GetEnvironment(c))
 	if err != nil {
 		return response.ErrorHandler(http.StatusInternalServerError, err)
 	}
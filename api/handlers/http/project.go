@@ -0,0 +1,233 @@
+package http
+
+import (
+	"errors"
+	"ff/api/middlewares"
+	"ff/internal/db/model"
+	project_entity "ff/internal/project/entity"
+	"ff/pkg/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ProjectService interface {
+	CreateProject(request project_entity.Project, personId uint) error
+	GetProjects(pagination model.Pagination, filters project_entity.ProjectFilters) ([]project_entity.ProjectResponse, int64, error)
+	GetProjectMembers(projectId uint) ([]project_entity.ProjectMemberResponse, error)
+	SetProjectMember(projectId uint, request project_entity.ProjectMember) error
+	RemoveProjectMember(projectId uint, personId uint) error
+	CheckPermission(projectId uint, personId uint, role string) error
+	CreateApiKey(projectId uint, request project_entity.ApiKey) (project_entity.ApiKeyResponse, error)
+	GetApiKeys(projectId uint) ([]project_entity.ApiKeyResponse, error)
+	RevokeApiKey(projectId uint, id uint) error
+}
+
+type ProjectEchoHandler struct {
+	ProjectService ProjectService
+}
+
+func NewProjectEchoHandler(project ProjectService, e *echo.Echo) {
+	handler := &ProjectEchoHandler{
+		ProjectService: project,
+	}
+
+	LoadProjectRoutes(e, handler)
+}
+
+// LoadProjectRoutes exposes the projects, the members and api keys are managed by the project admins
+func LoadProjectRoutes(e *echo.Echo, handler *ProjectEchoHandler) {
+	group := e.Group("/api/feature-flags", middlewares.ValidateCookie)
+
+	group.POST("/v1/projects", handler.createProjectHandler)
+	group.GET("/v1/projects", handler.getProjectsHandler)
+	group.GET("/v1/projects/:project/members", handler.getProjectMembersHandler, handler.requireProjectAdmin)
+	group.PUT("/v1/projects/:project/members", handler.setProjectMemberHandler, handler.requireProjectAdmin)
+	group.DELETE("/v1/projects/:project/members/:personId", handler.removeProjectMemberHandler, handler.requireProjectAdmin)
+	group.POST("/v1/projects/:project/api-keys", handler.createApiKeyHandler, handler.requireProjectAdmin)
+	group.GET("/v1/projects/:project/api-keys", handler.getApiKeysHandler, handler.requireProjectAdmin)
+	group.DELETE("/v1/projects/:project/api-keys/:id", handler.revokeApiKeyHandler, handler.requireProjectAdmin)
+}
+
+func (e *ProjectEchoHandler) requireProjectAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		response := ResponseJSON{c: c}
+
+		var personId int
+		if err := utils.GetAuthenticatedPerson(c, &personId); err != nil {
+			return response.ErrorHandler(http.StatusUnauthorized, err)
+		}
+
+		if err := e.ProjectService.CheckPermission(utils.GetProject(c), uint(personId), project_entity.RoleAdmin); err != nil {
+			return response.ErrorHandler(http.StatusForbidden, err)
+		}
+
+		return next(c)
+	}
+}
+
+func (e *ProjectEchoHandler) createProjectHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	var input project_entity.Project
+	if err := utils.GetBodyFromRequest(c, &input); err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+
+	var personId int
+	if err := utils.GetAuthenticatedPerson(c, &personId); err != nil {
+		return response.ErrorHandler(http.StatusUnauthorized, err)
+	}
+
+	if err := e.ProjectService.CreateProject(input, uint(personId)); err != nil {
+		return projectErrorHandler(response, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusCreated, "Project Created")
+}
+
+func (e *ProjectEchoHandler) getProjectsHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	id, _ := strconv.Atoi(c.QueryParam("id"))
+
+	if page <= 1 {
+		page = 1 // Default page
+	}
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	projects, totalCount, err := e.ProjectService.GetProjects(model.Pagination{
+		Page:  page,
+		Limit: limit,
+	}, project_entity.ProjectFilters{
+		ID:  uint(id),
+		Key: c.QueryParam("key"),
+	})
+	if err != nil {
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	interfaceSlice := make([]interface{}, len(projects))
+	for i, v := range projects {
+		interfaceSlice[i] = v
+	}
+
+	return response.PaginationHandler(interfaceSlice, totalCount)
+}
+
+func (e *ProjectEchoHandler) getProjectMembersHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	members, err := e.ProjectService.GetProjectMembers(utils.GetProject(c))
+	if err != nil {
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	interfaceSlice := make([]interface{}, len(members))
+	for i, v := range members {
+		interfaceSlice[i] = v
+	}
+
+	return response.PaginationHandler(interfaceSlice, int64(len(members)))
+}
+
+func (e *ProjectEchoHandler) setProjectMemberHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	var input project_entity.ProjectMember
+	if err := utils.GetBodyFromRequest(c, &input); err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+
+	if err := e.ProjectService.SetProjectMember(utils.GetProject(c), input); err != nil {
+		return projectErrorHandler(response, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Project Member Updated")
+}
+
+func (e *ProjectEchoHandler) removeProjectMemberHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	personId, err := strconv.Atoi(c.Param("personId"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("person id is not a number"))
+	}
+
+	if err := e.ProjectService.RemoveProjectMember(utils.GetProject(c), uint(personId)); err != nil {
+		return projectErrorHandler(response, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Project Member Removed")
+}
+
+func (e *ProjectEchoHandler) createApiKeyHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	var input project_entity.ApiKey
+	if err := utils.GetBodyFromRequest(c, &input); err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+
+	apiKey, err := e.ProjectService.CreateApiKey(utils.GetProject(c), input)
+	if err != nil {
+		return projectErrorHandler(response, err)
+	}
+
+	return response.SuccessHandler(http.StatusCreated, apiKey)
+}
+
+func (e *ProjectEchoHandler) getApiKeysHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	apiKeys, err := e.ProjectService.GetApiKeys(utils.GetProject(c))
+	if err != nil {
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	interfaceSlice := make([]interface{}, len(apiKeys))
+	for i, v := range apiKeys {
+		interfaceSlice[i] = v
+	}
+
+	return response.PaginationHandler(interfaceSlice, int64(len(apiKeys)))
+}
+
+func (e *ProjectEchoHandler) revokeApiKeyHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("api key id is not a number"))
+	}
+
+	if err := e.ProjectService.RevokeApiKey(utils.GetProject(c), uint(id)); err != nil {
+		return projectErrorHandler(response, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "API Key Revoked")
+}
+
+func projectErrorHandler(response ResponseJSON, err error) error {
+	if err.Error() == "project already exists" {
+		return response.ErrorHandler(http.StatusConflict, err)
+	}
+	if err.Error() == "project not found" ||
+		err.Error() == "person is not a member of the project" ||
+		err.Error() == "api key not found" {
+		return response.ErrorHandler(http.StatusNotFound, err)
+	}
+	if strings.HasPrefix(err.Error(), "Key|") ||
+		strings.HasPrefix(err.Error(), "Name|") ||
+		strings.HasPrefix(err.Error(), "PersonID|") ||
+		strings.HasPrefix(err.Error(), "Role|") {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+	return response.ErrorHandler(http.StatusInternalServerError, err)
+}
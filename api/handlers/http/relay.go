@@ -0,0 +1,162 @@
+package http
+
+import (
+	"errors"
+	"ff/api/middlewares"
+	"ff/internal/db/model"
+	ff_entity "ff/internal/feature_flag/entity"
+	p_entity "ff/internal/person/entity"
+	"ff/internal/relay"
+	r_entity "ff/internal/relay/entity"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RelayService interface {
+	Serves(project string, environment string) bool
+	Status() r_entity.Status
+	GetFeatureFlags(pagination model.Pagination, filters ff_entity.FeatureFlagFilters) ([]ff_entity.FeatureFlagResponse, int64, error)
+	GetAssignedFeatureFlagsByPersonId(id uint) ([]p_entity.AssignedFeatureFlagResponse, error)
+}
+
+type RelayEchoHandler struct {
+	RelayService RelayService
+}
+
+func NewRelayEchoHandler(relay RelayService, e *echo.Echo) {
+	handler := &RelayEchoHandler{
+		RelayService: relay,
+	}
+
+	LoadRelayRoutes(e, handler)
+}
+
+// LoadRelayRoutes serves the read-only routes of the consumers from the relayed snapshot,
+// under the same paths as the API so a consumer only changes its base url
+func LoadRelayRoutes(e *echo.Echo, handler *RelayEchoHandler) {
+	group := e.Group("/api/feature-flags", handler.relayScope)
+
+	for _, prefix := range scopedPrefixes {
+		group.GET(prefix+"/feature-flags", handler.getFeatureFlagsHandler)
+		group.GET(prefix+"/people/:id/assigned-feature-flags", handler.getAssignedFeatureFlagsByPersonIdHandler)
+	}
+
+	e.GET("/api/feature-flags/relay/status", handler.getStatusHandler)
+}
+
+// relayScope rejects the requests for a project or an environment the relay does not serve
+func (e *RelayEchoHandler) relayScope(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		project := c.Param("project")
+		if project == "" {
+			project = c.Request().Header.Get(middlewares.ProjectHeader)
+		}
+
+		environment := c.Param("environment")
+		if environment == "" {
+			environment = c.Request().Header.Get(middlewares.EnvironmentHeader)
+		}
+
+		if !e.RelayService.Serves(project, environment) {
+			response := ResponseJSON{c: c}
+			return response.ErrorHandler(http.StatusNotFound, errors.New("project or environment not served by the relay"))
+		}
+
+		return next(c)
+	}
+}
+
+// getStatusHandler answers 503 until the first snapshot is synced, so it can back a readiness probe
+func (e *RelayEchoHandler) getStatusHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	status := e.RelayService.Status()
+	if status.SyncedAt == "" {
+		return response.SuccessHandler(http.StatusServiceUnavailable, status)
+	}
+
+	return response.SuccessHandler(http.StatusOK, status)
+}
+
+func (e *RelayEchoHandler) getFeatureFlagsHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	id, _ := strconv.Atoi(c.QueryParam("id"))
+
+	var isActive *bool
+	if isActiveStr := c.QueryParam("isActive"); isActiveStr != "" {
+		value, err := strconv.ParseBool(isActiveStr)
+		if err != nil {
+			return response.ErrorHandler(http.StatusBadRequest, errors.New("invalid isActive value"))
+		}
+		isActive = &value
+	}
+
+	var isGlobal *bool
+	if isGlobalStr := c.QueryParam("isGlobal"); isGlobalStr != "" {
+		value, err := strconv.ParseBool(isGlobalStr)
+		if err != nil {
+			return response.ErrorHandler(http.StatusBadRequest, errors.New("invalid isGlobal value"))
+		}
+		isGlobal = &value
+	}
+
+	if page <= 1 {
+		page = 1 // Default page
+	}
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	featureFlags, totalCount, err := e.RelayService.GetFeatureFlags(model.Pagination{
+		Page:  page,
+		Limit: limit,
+	}, ff_entity.FeatureFlagFilters{
+		ID:       uint(id),
+		Name:     c.QueryParam("name"),
+		IsActive: isActive,
+		IsGlobal: isGlobal,
+	})
+	if err != nil {
+		return relayErrorHandler(response, err)
+	}
+
+	interfaceSlice := make([]interface{}, len(featureFlags))
+	for i, v := range featureFlags {
+		interfaceSlice[i] = v
+	}
+
+	return response.PaginationHandler(interfaceSlice, totalCount)
+}
+
+func (e *RelayEchoHandler) getAssignedFeatureFlagsByPersonIdHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("person id is not a number"))
+	}
+
+	featureFlags, err := e.RelayService.GetAssignedFeatureFlagsByPersonId(uint(id))
+	if err != nil {
+		return relayErrorHandler(response, err)
+	}
+
+	interfaceSlice := make([]interface{}, len(featureFlags))
+	for i, v := range featureFlags {
+		interfaceSlice[i] = v
+	}
+
+	return response.PaginationHandler(interfaceSlice, int64(len(featureFlags)))
+}
+
+func relayErrorHandler(response ResponseJSON, err error) error {
+	if errors.Is(err, relay.ErrNotSynced) {
+		return response.ErrorHandler(http.StatusServiceUnavailable, err)
+	}
+	return response.ErrorHandler(http.StatusInternalServerError, err)
+}
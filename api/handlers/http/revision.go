@@ -0,0 +1,156 @@
+package http
+
+import (
+	"errors"
+	"ff/api/middlewares"
+	"ff/internal/db/model"
+	rv_entity "ff/internal/revision/entity"
+	"ff/pkg/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RevisionService interface {
+	GetRevisions(featureFlagId uint, projectId uint, pagination model.Pagination, filters rv_entity.RevisionFilters) ([]rv_entity.RevisionResponse, int64, error)
+	DiffRevisions(featureFlagId uint, projectId uint, fromId uint, toId uint) (rv_entity.DiffResponse, error)
+	RollbackRevision(featureFlagId uint, id uint, request rv_entity.Rollback) error
+}
+
+type RevisionEchoHandler struct {
+	RevisionService RevisionService
+}
+
+func NewRevisionEchoHandler(revision RevisionService, e *echo.Echo) {
+	handler := &RevisionEchoHandler{
+		RevisionService: revision,
+	}
+
+	LoadRevisionRoutes(e, handler)
+}
+
+func LoadRevisionRoutes(e *echo.Echo, handler *RevisionEchoHandler) {
+	group := e.Group("/api/feature-flags", middlewares.ValidateCookie)
+
+	for _, prefix := range scopedPrefixes {
+		group.GET(prefix+"/feature-flags/:id/revisions", handler.getRevisionsHandler)
+		group.GET(prefix+"/feature-flags/:id/revisions/diff", handler.diffRevisionsHandler)
+		group.POST(prefix+"/feature-flags/:id/revisions/:revision/rollback", handler.rollbackRevisionHandler)
+	}
+}
+
+// getRevisionsHandler lists the revisions of every environment, newest first, the environment
+// query parameter keeps the ones of a single environment
+func (e *RevisionEchoHandler) getRevisionsHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	if page <= 1 {
+		page = 1 // Default page
+	}
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	revisions, totalCount, err := e.RevisionService.GetRevisions(uint(id), utils.GetProject(c), model.Pagination{
+		Page:  page,
+		Limit: limit,
+	}, rv_entity.RevisionFilters{
+		Environment: c.QueryParam("environment"),
+	})
+	if err != nil {
+		return revisionErrorHandler(response, err)
+	}
+
+	interfaceSlice := make([]interface{}, len(revisions))
+	for i, v := range revisions {
+		interfaceSlice[i] = v
+	}
+
+	return response.PaginationHandler(interfaceSlice, totalCount)
+}
+
+func (e *RevisionEchoHandler) diffRevisionsHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
+	}
+
+	from, errFrom := strconv.Atoi(c.QueryParam("from"))
+	to, errTo := strconv.Atoi(c.QueryParam("to"))
+	if errFrom != nil || errTo != nil || from <= 0 || to <= 0 {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("from and to must be revision ids"))
+	}
+
+	diff, err := e.RevisionService.DiffRevisions(uint(id), utils.GetProject(c), uint(from), uint(to))
+	if err != nil {
+		return revisionErrorHandler(response, err)
+	}
+
+	return response.SuccessHandler(http.StatusOK, diff)
+}
+
+func (e *RevisionEchoHandler) rollbackRevisionHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	// the body is optional, it only carries the force option
+	var input rv_entity.Rollback
+	if c.Request().ContentLength != 0 {
+		if err := utils.GetBodyFromRequest(c, &input); err != nil {
+			return response.ErrorHandler(http.StatusBadRequest, err)
+		}
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
+	}
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("revision id is not a number"))
+	}
+
+	var personId int
+	if err := utils.GetAuthenticatedPerson(c, &personId); err != nil {
+		return response.ErrorHandler(http.StatusUnauthorized, err)
+	}
+
+	input.ProjectID = utils.GetProject(c)
+	input.PersonID = uint(personId)
+
+	if err := e.RevisionService.RollbackRevision(uint(id), uint(revision), input); err != nil {
+		return revisionErrorHandler(response, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Feature Flag Rolled Back")
+}
+
+func revisionErrorHandler(response ResponseJSON, err error) error {
+	if err.Error() == "feature flag not found" || err.Error() == "revision not found" {
+		return response.ErrorHandler(http.StatusNotFound, err)
+	}
+	// the revision goes through the validation of the updates, it may not fit the flag anymore
+	if strings.Contains(err.Error(), "|") {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+	if err.Error() == "feature flag is archived" ||
+		err.Error() == "feature flag was changed by someone else" ||
+		err.Error() == "feature flag already matches the revision" ||
+		strings.HasPrefix(err.Error(), "feature flag is a prerequisite of") ||
+		strings.HasPrefix(err.Error(), "variant ") {
+		return response.ErrorHandler(http.StatusConflict, err)
+	}
+	return response.ErrorHandler(http.StatusInternalServerError, err)
+}
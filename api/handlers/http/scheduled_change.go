@@ -0,0 +1,128 @@
+package http
+
+import (
+	"errors"
+	"ff/api/middlewares"
+	sc_entity "ff/internal/scheduled_change/entity"
+	"ff/pkg/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ScheduledChangeService interface {
+	CreateScheduledChange(featureFlagId uint, request sc_entity.ScheduledChange, personId uint) error
+	GetScheduledChanges(featureFlagId uint, projectId uint, filters sc_entity.ScheduledChangeFilters) ([]sc_entity.ScheduledChangeResponse, error)
+	CancelScheduledChange(featureFlagId uint, projectId uint, id uint) error
+}
+
+type ScheduledChangeEchoHandler struct {
+	ScheduledChangeService ScheduledChangeService
+}
+
+func NewScheduledChangeEchoHandler(scheduledChange ScheduledChangeService, e *echo.Echo) {
+	handler := &ScheduledChangeEchoHandler{
+		ScheduledChangeService: scheduledChange,
+	}
+
+	LoadScheduledChangeRoutes(e, handler)
+}
+
+func LoadScheduledChangeRoutes(e *echo.Echo, handler *ScheduledChangeEchoHandler) {
+	group := e.Group("/api/feature-flags", middlewares.ValidateCookie)
+
+	for _, prefix := range scopedPrefixes {
+		group.POST(prefix+"/feature-flags/:id/scheduled-changes", handler.createScheduledChangeHandler)
+		group.GET(prefix+"/feature-flags/:id/scheduled-changes", handler.getScheduledChangesHandler)
+		group.DELETE(prefix+"/feature-flags/:id/scheduled-changes/:changeId", handler.cancelScheduledChangeHandler)
+	}
+}
+
+func (e *ScheduledChangeEchoHandler) createScheduledChangeHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	var input sc_entity.ScheduledChange
+	if err := utils.GetBodyFromRequest(c, &input); err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
+	}
+
+	var personId int
+	if err := utils.GetAuthenticatedPerson(c, &personId); err != nil {
+		return response.ErrorHandler(http.StatusUnauthorized, err)
+	}
+
+	input.Environment = utils.GetEnvironment(c)
+	input.ProjectID = utils.GetProject(c)
+
+	if err := e.ScheduledChangeService.CreateScheduledChange(uint(id), input, uint(personId)); err != nil {
+		if strings.HasPrefix(err.Error(), "Operation|") ||
+			strings.HasPrefix(err.Error(), "RolloutPercentage|") ||
+			strings.HasPrefix(err.Error(), "ExecuteAt|") ||
+			strings.HasPrefix(err.Error(), "Timezone|") {
+			return response.ErrorHandler(http.StatusBadRequest, err)
+		}
+		if err.Error() == "feature flag not found" {
+			return response.ErrorHandler(http.StatusNotFound, err)
+		}
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusCreated, "Scheduled Change Created")
+}
+
+func (e *ScheduledChangeEchoHandler) getScheduledChangesHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
+	}
+
+	scheduledChanges, err := e.ScheduledChangeService.GetScheduledChanges(uint(id), utils.GetProject(c), sc_entity.ScheduledChangeFilters{
+		Status:      c.QueryParam("status"),
+		Environment: utils.GetEnvironment(c),
+	})
+	if err != nil {
+		if err.Error() == "feature flag not found" {
+			return response.ErrorHandler(http.StatusNotFound, err)
+		}
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	interfaceSlice := make([]interface{}, len(scheduledChanges))
+	for i, v := range scheduledChanges {
+		interfaceSlice[i] = v
+	}
+
+	return response.PaginationHandler(interfaceSlice, int64(len(scheduledChanges)))
+}
+
+func (e *ScheduledChangeEchoHandler) cancelScheduledChangeHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("feature flag id is not a number"))
+	}
+
+	changeId, err := strconv.Atoi(c.Param("changeId"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("scheduled change id is not a number"))
+	}
+
+	if err := e.ScheduledChangeService.CancelScheduledChange(uint(id), utils.GetProject(c), uint(changeId)); err != nil {
+		if err.Error() == "scheduled change not found" || err.Error() == "feature flag not found" {
+			return response.ErrorHandler(http.StatusNotFound, err)
+		}
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Scheduled Change Cancelled")
+}
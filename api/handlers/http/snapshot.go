@@ -0,0 +1,95 @@
+package http
+
+import (
+	"ff/api/middlewares"
+	s_entity "ff/internal/snapshot/entity"
+	"ff/pkg/utils"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// snapshotGzipMinLength keeps small snapshots uncompressed, it is not worth it under a kilobyte
+const snapshotGzipMinLength = 1024
+
+type SnapshotService interface {
+	GetSnapshot(projectId uint, environment string) (s_entity.Snapshot, error)
+	GetSnapshotVersion(projectId uint, environment string) (uint, error)
+}
+
+type SnapshotEchoHandler struct {
+	SnapshotService SnapshotService
+}
+
+func NewSnapshotEchoHandler(snapshot SnapshotService, e *echo.Echo) {
+	handler := &SnapshotEchoHandler{
+		SnapshotService: snapshot,
+	}
+
+	LoadSnapshotRoutes(e, handler)
+}
+
+func LoadSnapshotRoutes(e *echo.Echo, handler *SnapshotEchoHandler) {
+	group := e.Group("/api/feature-flags", middlewares.ValidateApiKeyOrCookie)
+
+	// gzip only when the client sends Accept-Encoding: gzip
+	gzip := middleware.GzipWithConfig(middleware.GzipConfig{MinLength: snapshotGzipMinLength})
+
+	for _, prefix := range scopedPrefixes {
+		group.GET(prefix+"/snapshot", handler.getSnapshotHandler, gzip)
+	}
+}
+
+// getSnapshotHandler answers 304 while the snapshot did not change since the ETag the client has,
+// so a poll costs a single query instead of loading every flag
+func (e *SnapshotEchoHandler) getSnapshotHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	projectId := utils.GetProject(c)
+	environment := utils.GetEnvironment(c)
+
+	version, err := e.SnapshotService.GetSnapshotVersion(projectId, environment)
+	if err != nil {
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	// the project and the environment come from headers, so they are part of the tag
+	etag := snapshotETag(projectId, environment, version)
+	c.Response().Header().Set(echo.HeaderVary, "X-Api-Key, X-Project, X-Environment")
+	c.Response().Header().Set("ETag", etag)
+
+	if etagMatches(c.Request().Header.Get("If-None-Match"), etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	snapshot, err := e.SnapshotService.GetSnapshot(projectId, environment)
+	if err != nil {
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+
+	// a change landed between the version check and the snapshot, it is tagged with what it contains
+	if snapshot.Version != version {
+		c.Response().Header().Set("ETag", snapshotETag(projectId, environment, snapshot.Version))
+	}
+
+	return response.SuccessHandler(http.StatusOK, snapshot)
+}
+
+// snapshotETag is weak, the same snapshot is sent plain or gzipped
+func snapshotETag(projectId uint, environment string, version uint) string {
+	return fmt.Sprintf(`W/"%d-%s-%d"`, projectId, environment, version)
+}
+
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
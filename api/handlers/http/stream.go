@@ -0,0 +1,125 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"ff/api/middlewares"
+	"ff/internal/stream"
+	st_entity "ff/internal/stream/entity"
+	"ff/pkg/utils"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// heartbeatInterval keeps idle connections from being closed by proxies
+const heartbeatInterval = 15 * time.Second
+
+type StreamService interface {
+	Subscribe(projectId uint, environment string, lastEventId uint) (*stream.Subscription, []st_entity.Event, error)
+	Unsubscribe(subscription *stream.Subscription)
+}
+
+type StreamEchoHandler struct {
+	StreamService StreamService
+}
+
+func NewStreamEchoHandler(stream StreamService, e *echo.Echo) {
+	handler := &StreamEchoHandler{
+		StreamService: stream,
+	}
+
+	LoadStreamRoutes(e, handler)
+}
+
+func LoadStreamRoutes(e *echo.Echo, handler *StreamEchoHandler) {
+	group := e.Group("/api/feature-flags", middlewares.ValidateApiKeyOrCookie)
+
+	for _, prefix := range scopedPrefixes {
+		group.GET(prefix+"/stream", handler.streamHandler)
+	}
+}
+
+// streamHandler sends the flag events as Server-Sent Events, a reconnecting client sends the
+// Last-Event-ID header (or the lastEventId query param) to receive what it missed
+func (e *StreamEchoHandler) streamHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	lastEventIdStr := c.Request().Header.Get("Last-Event-ID")
+	if lastEventIdStr == "" {
+		lastEventIdStr = c.QueryParam("lastEventId")
+	}
+
+	var lastEventId uint64
+	if lastEventIdStr != "" {
+		var err error
+		if lastEventId, err = strconv.ParseUint(lastEventIdStr, 10, 64); err != nil {
+			return response.ErrorHandler(http.StatusBadRequest, errors.New("last event id is not a number"))
+		}
+	}
+
+	subscription, missed, err := e.StreamService.Subscribe(utils.GetProject(c), utils.GetEnvironment(c), uint(lastEventId))
+	if err != nil {
+		return response.ErrorHandler(http.StatusInternalServerError, err)
+	}
+	defer e.StreamService.Unsubscribe(subscription)
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+
+	// missed events can be sent again by the subscription, the id tells them apart. A lower id
+	// than the last one sent is not a duplicate, the event may have been stored after it
+	replayed := map[uint]struct{}{}
+	for _, event := range missed {
+		if err := writeEvent(w, event); err != nil {
+			return nil
+		}
+		replayed[event.ID] = struct{}{}
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			w.Flush()
+		case event, ok := <-subscription.Events():
+			if !ok {
+				// dropped for being too slow, the client resumes from the last id it got
+				return nil
+			}
+			if _, ok := replayed[event.ID]; ok {
+				continue
+			}
+			if err := writeEvent(w, event); err != nil {
+				return nil
+			}
+			w.Flush()
+		}
+	}
+}
+
+func writeEvent(w io.Writer, event st_entity.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"errors"
+	"ff/api/middlewares"
+	"ff/internal/db/model"
+	w_entity "ff/internal/webhook/entity"
+	"ff/pkg/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookService interface {
+	CreateWebhook(projectId uint, request w_entity.Webhook) (w_entity.WebhookResponse, error)
+	GetWebhooks(projectId uint) ([]w_entity.WebhookResponse, error)
+	UpdateWebhook(projectId uint, id uint, request w_entity.Webhook) error
+	DeleteWebhook(projectId uint, id uint) error
+	GetWebhookDeliveries(projectId uint, id uint, pagination model.Pagination) ([]w_entity.DeliveryResponse, int64, error)
+}
+
+type WebhookEchoHandler struct {
+	WebhookService WebhookService
+}
+
+func NewWebhookEchoHandler(webhook WebhookService, e *echo.Echo) {
+	handler := &WebhookEchoHandler{
+		WebhookService: webhook,
+	}
+
+	LoadWebhookRoutes(e, handler)
+}
+
+// LoadWebhookRoutes exposes the webhooks of the project, the webhooks are not bound to an
+// environment, the environment of a change is part of its payload
+func LoadWebhookRoutes(e *echo.Echo, handler *WebhookEchoHandler) {
+	group := e.Group("/api/feature-flags", middlewares.ValidateCookie)
+
+	for _, prefix := range scopedPrefixes {
+		group.POST(prefix+"/webhooks", handler.createWebhookHandler)
+		group.GET(prefix+"/webhooks", handler.getWebhooksHandler)
+		group.PUT(prefix+"/webhooks/:id", handler.updateWebhookHandler)
+		group.DELETE(prefix+"/webhooks/:id", handler.deleteWebhookHandler)
+		group.GET(prefix+"/webhooks/:id/deliveries", handler.getWebhookDeliveriesHandler)
+	}
+}
+
+func (e *WebhookEchoHandler) createWebhookHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	var input w_entity.Webhook
+	if err := utils.GetBodyFromRequest(c, &input); err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+
+	webhook, err := e.WebhookService.CreateWebhook(utils.GetProject(c), input)
+	if err != nil {
+		return webhookErrorHandler(response, err)
+	}
+
+	return response.SuccessHandler(http.StatusCreated, webhook)
+}
+
+func (e *WebhookEchoHandler) getWebhooksHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	webhooks, err := e.WebhookService.GetWebhooks(utils.GetProject(c))
+	if err != nil {
+		return webhookErrorHandler(response, err)
+	}
+
+	interfaceSlice := make([]interface{}, len(webhooks))
+	for i, v := range webhooks {
+		interfaceSlice[i] = v
+	}
+
+	return response.PaginationHandler(interfaceSlice, int64(len(webhooks)))
+}
+
+func (e *WebhookEchoHandler) updateWebhookHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("webhook id is not a number"))
+	}
+
+	var input w_entity.Webhook
+	if err := utils.GetBodyFromRequest(c, &input); err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+
+	if err := e.WebhookService.UpdateWebhook(utils.GetProject(c), uint(id), input); err != nil {
+		return webhookErrorHandler(response, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Webhook Updated")
+}
+
+func (e *WebhookEchoHandler) deleteWebhookHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("webhook id is not a number"))
+	}
+
+	if err := e.WebhookService.DeleteWebhook(utils.GetProject(c), uint(id)); err != nil {
+		return webhookErrorHandler(response, err)
+	}
+
+	return response.SuccessHandlerMessage(http.StatusOK, "Webhook Deleted")
+}
+
+func (e *WebhookEchoHandler) getWebhookDeliveriesHandler(c echo.Context) error {
+	response := ResponseJSON{c: c}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return response.ErrorHandler(http.StatusBadRequest, errors.New("webhook id is not a number"))
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	if page <= 1 {
+		page = 1 // Default page
+	}
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	deliveries, totalCount, err := e.WebhookService.GetWebhookDeliveries(utils.GetProject(c), uint(id), model.Pagination{
+		Page:  page,
+		Limit: limit,
+	})
+	if err != nil {
+		return webhookErrorHandler(response, err)
+	}
+
+	interfaceSlice := make([]interface{}, len(deliveries))
+	for i, v := range deliveries {
+		interfaceSlice[i] = v
+	}
+
+	return response.PaginationHandler(interfaceSlice, totalCount)
+}
+
+func webhookErrorHandler(response ResponseJSON, err error) error {
+	if err.Error() == "webhook not found" {
+		return response.ErrorHandler(http.StatusNotFound, err)
+	}
+	if strings.HasPrefix(err.Error(), "URL|") ||
+		strings.HasPrefix(err.Error(), "Secret|") ||
+		strings.HasPrefix(err.Error(), "EventTypes|") {
+		return response.ErrorHandler(http.StatusBadRequest, err)
+	}
+	return response.ErrorHandler(http.StatusInternalServerError, err)
+}
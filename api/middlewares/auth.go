@@ -32,3 +32,17 @@ func ValidateCookie(next echo.HandlerFunc) echo.HandlerFunc {
 		return next(c)
 	}
 }
+
+// ValidateApiKeyOrCookie lets the SDK calls in with the api key checked by ResolveProject,
+// every other request needs the admin cookie
+func ValidateApiKeyOrCookie(next echo.HandlerFunc) echo.HandlerFunc {
+	validateCookie := ValidateCookie(next)
+
+	return func(c echo.Context) error {
+		if authenticated, _ := c.Get(apiKeyAuthenticated).(bool); authenticated {
+			return next(c)
+		}
+
+		return validateCookie(c)
+	}
+}
@@ -0,0 +1,42 @@
+package middlewares
+
+import (
+	"ff/internal/db/model"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const EnvironmentHeader = "X-Environment"
+
+type EnvironmentChecker interface {
+	EnvironmentExists(name string) (bool, error)
+}
+
+// ResolveEnvironment reads the environment from the :environment path param or the
+// X-Environment header (production when none is informed) and stores it in the context
+func ResolveEnvironment(checker EnvironmentChecker) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			environment := c.Param("environment")
+			if environment == "" {
+				environment = c.Request().Header.Get(EnvironmentHeader)
+			}
+			if environment == "" {
+				environment = model.DefaultEnvironment
+			}
+
+			exists, err := checker.EnvironmentExists(environment)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			}
+			if !exists {
+				return echo.NewHTTPError(http.StatusNotFound, "environment not found")
+			}
+
+			c.Set("environment", environment)
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+// Package flags is the Echo middleware of the applications consuming the feature flag
+// service. It loads the flags of the person of the request once, stores them in the
+// echo.Context and lets handlers and routes be gated on them:
+//
+//	e.Use(flags.Middleware(flags.Config{BaseURL: "http://flags:9696", ApiKey: "ffk_..."}))
+//	e.GET("/checkout", checkoutHandler, flags.Require("NEW_CHECKOUT"))
+//
+//	if flags.Enabled(c, "NEW_CHECKOUT") { ... }
+package flags
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"ff/internal/auth"
+	p_entity "ff/internal/person/entity"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DefaultCacheTTL = 10 * time.Second
+	DefaultTimeout  = 2 * time.Second
+
+	contextKey = "feature_flags"
+
+	assignedFeatureFlagsPath = "/api/feature-flags/v1/people/%d/assigned-feature-flags"
+	apiKeyHeader             = "X-Api-Key"
+	projectHeader            = "X-Project"
+	environmentHeader        = "X-Environment"
+
+	// maxCacheEntries bounds the cache, expired entries are dropped once it is reached
+	maxCacheEntries = 10000
+)
+
+type Config struct {
+	// BaseURL is where the service is served, e.g. http://localhost:9696
+	BaseURL string
+	// ApiKey authenticates the application and selects the project of the key
+	ApiKey string
+	// Project is the project key, the default project when empty
+	Project string
+	// Environment is the environment name, the default environment when empty
+	Environment string
+	// CacheTTL is how long the flags of a person are reused between requests
+	CacheTTL   time.Duration
+	HTTPClient *http.Client
+	// PersonID resolves the person of the request, zero for an anonymous request. By
+	// default it is the person authenticated by middlewares.ValidateCookie
+	PersonID func(c echo.Context) (uint, error)
+	Logger   *zerolog.Logger
+}
+
+// Flag is a flag on for the person, with the variant served to them
+type Flag struct {
+	Name         string
+	IsActive     bool
+	Variant      string
+	VariantValue interface{}
+}
+
+// Flags are the flags of the person of the request by name, a flag missing is off
+type Flags map[string]Flag
+
+type cacheEntry struct {
+	flags     Flags
+	expiresAt time.Time
+}
+
+type loader struct {
+	config     Config
+	httpClient *http.Client
+	logger     *zerolog.Logger
+
+	mu    sync.Mutex
+	cache map[uint]cacheEntry
+}
+
+// Middleware loads the flags of the person before the handler runs. The request is never
+// failed because of the service: while it is unavailable the last flags loaded for the
+// person are used, and without them every flag is off
+func Middleware(config Config) echo.MiddlewareFunc {
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = DefaultCacheTTL
+	}
+
+	if config.PersonID == nil {
+		config.PersonID = authenticatedPerson
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	logger := config.Logger
+	if logger == nil {
+		nop := zerolog.Nop()
+		logger = &nop
+	}
+
+	l := &loader{
+		config:     config,
+		httpClient: httpClient,
+		logger:     logger,
+		cache:      make(map[uint]cacheEntry),
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(contextKey, l.flags(c))
+
+			return next(c)
+		}
+	}
+}
+
+// Require answers 404 when the flag is off, as if the route did not exist
+func Require(name string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !Enabled(c, name) {
+				return echo.NewHTTPError(http.StatusNotFound)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// FromContext returns the flags loaded by the middleware, empty when it did not run
+func FromContext(c echo.Context) Flags {
+	flags, ok := c.Get(contextKey).(Flags)
+	if !ok {
+		return Flags{}
+	}
+
+	return flags
+}
+
+// Enabled reports whether the flag is on for the person of the request
+func Enabled(c echo.Context, name string) bool {
+	flag, ok := FromContext(c)[name]
+
+	return ok && flag.IsActive
+}
+
+// Variant returns the variant served to the person of the request and its value
+func Variant(c echo.Context, name string) (string, interface{}) {
+	flag := FromContext(c)[name]
+
+	return flag.Variant, flag.VariantValue
+}
+
+func (l *loader) flags(c echo.Context) Flags {
+	personId, err := l.config.PersonID(c)
+	if err != nil {
+		l.logger.Error().Err(err).Msg("Error when resolving the person of the request")
+		return Flags{}
+	}
+
+	if personId == 0 {
+		return Flags{}
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	entry, cached := l.cache[personId]
+	l.mu.Unlock()
+
+	if cached && now.Before(entry.expiresAt) {
+		return entry.flags
+	}
+
+	flags, err := l.fetch(c, personId)
+	if err != nil {
+		l.logger.Error().Err(err).Uint("personId", personId).Msg("Keeping the last known flags of the person")
+		if cached {
+			return entry.flags
+		}
+		return Flags{}
+	}
+
+	l.mu.Lock()
+	if len(l.cache) >= maxCacheEntries {
+		for id, entry := range l.cache {
+			if now.After(entry.expiresAt) {
+				delete(l.cache, id)
+			}
+		}
+	}
+	l.cache[personId] = cacheEntry{flags: flags, expiresAt: now.Add(l.config.CacheTTL)}
+	l.mu.Unlock()
+
+	return flags
+}
+
+func (l *loader) fetch(c echo.Context, personId uint) (Flags, error) {
+	request, err := http.NewRequestWithContext(c.Request().Context(), http.MethodGet, l.config.BaseURL+fmt.Sprintf(assignedFeatureFlagsPath, personId), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Accept", "application/json")
+	if l.config.ApiKey != "" {
+		request.Header.Set(apiKeyHeader, l.config.ApiKey)
+	}
+	if l.config.Project != "" {
+		request.Header.Set(projectHeader, l.config.Project)
+	}
+	if l.config.Environment != "" {
+		request.Header.Set(environmentHeader, l.config.Environment)
+	}
+
+	response, err := l.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error when fetching the flags of the person: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error when fetching the flags of the person: unexpected status %d", response.StatusCode)
+	}
+
+	var body struct {
+		Items []p_entity.AssignedFeatureFlagResponse `json:"items"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("error when decoding the flags of the person: %w", err)
+	}
+
+	flags := make(Flags, len(body.Items))
+	for _, item := range body.Items {
+		flags[item.Name] = Flag{
+			Name:         item.Name,
+			IsActive:     item.IsActive,
+			Variant:      item.Variant,
+			VariantValue: item.VariantValue,
+		}
+	}
+
+	return flags, nil
+}
+
+// authenticatedPerson reads the person set by middlewares.ValidateCookie, requests
+// without it are anonymous
+func authenticatedPerson(c echo.Context) (uint, error) {
+	authInfo, ok := c.Get("auth_info").(auth.AuthUserResponse)
+	if !ok {
+		return 0, nil
+	}
+
+	if authInfo.PersonID < 0 {
+		return 0, errors.New("invalid person id")
+	}
+
+	return uint(authInfo.PersonID), nil
+}
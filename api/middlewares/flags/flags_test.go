@@ -0,0 +1,170 @@
+package flags
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ff/internal/auth"
+	p_entity "ff/internal/person/entity"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type upstream struct {
+	server   *httptest.Server
+	requests atomic.Int32
+	failing  atomic.Bool
+}
+
+func newUpstream(t *testing.T) *upstream {
+	u := &upstream{}
+	u.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u.requests.Add(1)
+
+		if u.failing.Load() || r.Header.Get(apiKeyHeader) != "ffk_test" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		assert.Equal(t, "/api/feature-flags/v1/people/1/assigned-feature-flags", r.URL.Path)
+		assert.Equal(t, "staging", r.Header.Get(environmentHeader))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"items": []p_entity.AssignedFeatureFlagResponse{
+				{ID: 1, Name: "NEW_CHECKOUT", IsActive: true, IsAssigned: true},
+				{ID: 2, Name: "OLD_REPORTS", IsActive: false, IsAssigned: true},
+				{ID: 3, Name: "BUTTON_COLOR", IsActive: true, IsAssigned: true, Variant: "green", VariantValue: "#0f0"},
+			},
+			"total": 3,
+		})
+	}))
+	t.Cleanup(u.server.Close)
+
+	return u
+}
+
+func newTestEcho(u *upstream, cacheTTL time.Duration) *echo.Echo {
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if personId, err := strconv.Atoi(c.Request().Header.Get("X-Person-Id")); err == nil {
+				c.Set("auth_info", auth.AuthUserResponse{PersonID: personId})
+			}
+			return next(c)
+		}
+	})
+	e.Use(Middleware(Config{BaseURL: u.server.URL + "/", ApiKey: "ffk_test", Environment: "staging", CacheTTL: cacheTTL}))
+
+	e.GET("/flags", func(c echo.Context) error {
+		variant, value := Variant(c, "BUTTON_COLOR")
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"newCheckout":  Enabled(c, "NEW_CHECKOUT"),
+			"oldReports":   Enabled(c, "OLD_REPORTS"),
+			"unknown":      Enabled(c, "UNKNOWN"),
+			"variant":      variant,
+			"variantValue": value,
+		})
+	})
+	e.GET("/checkout", func(c echo.Context) error {
+		return c.String(http.StatusOK, "new checkout")
+	}, Require("NEW_CHECKOUT"))
+	e.GET("/reports", func(c echo.Context) error {
+		return c.String(http.StatusOK, "old reports")
+	}, Require("OLD_REPORTS"))
+
+	return e
+}
+
+func serve(e *echo.Echo, path string, personId string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if personId != "" {
+		req.Header.Set("X-Person-Id", personId)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("Flags of the person are available to the handler", func(t *testing.T) {
+		u := newUpstream(t)
+		e := newTestEcho(u, time.Minute)
+
+		rec := serve(e, "/flags", "1")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"newCheckout":true,"oldReports":false,"unknown":false,"variant":"green","variantValue":"#0f0"}`, rec.Body.String())
+	})
+
+	t.Run("Flags are cached between requests", func(t *testing.T) {
+		u := newUpstream(t)
+		e := newTestEcho(u, time.Minute)
+
+		serve(e, "/flags", "1")
+		serve(e, "/checkout", "1")
+
+		assert.Equal(t, int32(1), u.requests.Load())
+	})
+
+	t.Run("Expired flags are fetched again", func(t *testing.T) {
+		u := newUpstream(t)
+		e := newTestEcho(u, time.Millisecond)
+
+		serve(e, "/flags", "1")
+		time.Sleep(5 * time.Millisecond)
+		serve(e, "/flags", "1")
+
+		assert.Equal(t, int32(2), u.requests.Load())
+	})
+
+	t.Run("Last known flags are kept while the service fails", func(t *testing.T) {
+		u := newUpstream(t)
+		e := newTestEcho(u, time.Millisecond)
+
+		serve(e, "/flags", "1")
+		u.failing.Store(true)
+		time.Sleep(5 * time.Millisecond)
+
+		rec := serve(e, "/checkout", "1")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, int32(2), u.requests.Load())
+	})
+
+	t.Run("Every flag is off without the service", func(t *testing.T) {
+		u := newUpstream(t)
+		u.failing.Store(true)
+		e := newTestEcho(u, time.Minute)
+
+		rec := serve(e, "/flags", "1")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"newCheckout":false,"oldReports":false,"unknown":false,"variant":"","variantValue":null}`, rec.Body.String())
+	})
+
+	t.Run("Anonymous requests are not sent to the service", func(t *testing.T) {
+		u := newUpstream(t)
+		e := newTestEcho(u, time.Minute)
+
+		rec := serve(e, "/checkout", "")
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, int32(0), u.requests.Load())
+	})
+}
+
+func TestRequire(t *testing.T) {
+	u := newUpstream(t)
+	e := newTestEcho(u, time.Minute)
+
+	assert.Equal(t, http.StatusOK, serve(e, "/checkout", "1").Code)
+	assert.Equal(t, http.StatusNotFound, serve(e, "/reports", "1").Code)
+}
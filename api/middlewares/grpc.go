@@ -0,0 +1,111 @@
+package middlewares
+
+import (
+	"context"
+	"ff/internal/db/model"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// metadata keys of the gRPC calls, the lowercase form of the REST headers
+const (
+	grpcApiKeyKey      = "x-api-key"
+	grpcProjectKey     = "x-project"
+	grpcEnvironmentKey = "x-environment"
+)
+
+type grpcScopeKey struct{}
+
+// GrpcScope is the project and environment a gRPC call runs in
+type GrpcScope struct {
+	ProjectID   uint
+	Environment string
+}
+
+// GetGrpcScope returns the scope resolved by the interceptors
+func GetGrpcScope(ctx context.Context) GrpcScope {
+	scope, _ := ctx.Value(grpcScopeKey{}).(GrpcScope)
+	return scope
+}
+
+// ResolveGrpcScope is the gRPC version of ResolveProject and ResolveEnvironment. There
+// is no cookie in the gRPC API, so every call needs an api key, which binds the call
+// to the project of the key
+func ResolveGrpcScope(resolver ProjectResolver, checker EnvironmentChecker) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		scope, err := resolveGrpcScope(ctx, resolver, checker)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(context.WithValue(ctx, grpcScopeKey{}, scope), req)
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		scope, err := resolveGrpcScope(ss.Context(), resolver, checker)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &scopedServerStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), grpcScopeKey{}, scope)})
+	}
+
+	return unary, stream
+}
+
+func resolveGrpcScope(ctx context.Context, resolver ProjectResolver, checker EnvironmentChecker) (GrpcScope, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	apiKey := firstMetadata(md, grpcApiKeyKey)
+	if apiKey == "" {
+		return GrpcScope{}, status.Error(codes.Unauthenticated, "missing api key")
+	}
+
+	project, err := resolver.AuthenticateApiKey(apiKey)
+	if err != nil {
+		if err.Error() == "invalid api key" {
+			return GrpcScope{}, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return GrpcScope{}, status.Error(codes.Internal, err.Error())
+	}
+
+	if key := firstMetadata(md, grpcProjectKey); key != "" && key != project.Key {
+		return GrpcScope{}, status.Error(codes.PermissionDenied, "the api key does not belong to the project")
+	}
+
+	environment := firstMetadata(md, grpcEnvironmentKey)
+	if environment == "" {
+		environment = model.DefaultEnvironment
+	}
+
+	exists, err := checker.EnvironmentExists(environment)
+	if err != nil {
+		return GrpcScope{}, status.Error(codes.Internal, err.Error())
+	}
+	if !exists {
+		return GrpcScope{}, status.Error(codes.NotFound, "environment not found")
+	}
+
+	return GrpcScope{ProjectID: project.ID, Environment: environment}, nil
+}
+
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// scopedServerStream replaces the context of the stream with the one holding the scope
+type scopedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *scopedServerStream) Context() context.Context {
+	return s.ctx
+}
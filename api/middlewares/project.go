@@ -0,0 +1,116 @@
+package middlewares
+
+import (
+	auth "ff/internal/auth"
+	"ff/internal/db/model"
+	project_entity "ff/internal/project/entity"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	ProjectHeader = "X-Project"
+	ApiKeyHeader  = "X-Api-Key"
+
+	// apiKeyAuthenticated is set on the context once the api key of the request was checked
+	apiKeyAuthenticated = "api_key_authenticated"
+)
+
+// unscopedPaths are not bound to a project, so no project permission is checked on them
+var unscopedPaths = []string{"/v1/projects", "/v1/environments"}
+
+type ProjectResolver interface {
+	GetProjectByKey(key string) (project_entity.ProjectResponse, error)
+	AuthenticateApiKey(key string) (project_entity.ProjectResponse, error)
+	CheckPermission(projectId uint, personId uint, role string) error
+}
+
+// ResolveProject reads the project from the :project path param or the X-Project header
+// (the default project when none is informed) and checks the person can access it.
+// A request with an api key is bound to the project of the key
+func ResolveProject(resolver ProjectResolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Param("project")
+			if key == "" {
+				key = c.Request().Header.Get(ProjectHeader)
+			}
+
+			personId := requestPersonId(c)
+
+			var project project_entity.ProjectResponse
+			if apiKey := c.Request().Header.Get(ApiKeyHeader); apiKey != "" {
+				keyProject, err := resolver.AuthenticateApiKey(apiKey)
+				if err != nil {
+					if err.Error() == "invalid api key" {
+						return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+					}
+					return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+				}
+				if key != "" && key != keyProject.Key {
+					return echo.NewHTTPError(http.StatusForbidden, "the api key does not belong to the project")
+				}
+
+				project = keyProject
+				c.Set(apiKeyAuthenticated, true)
+			} else {
+				if key == "" {
+					key = model.DefaultProject
+				}
+
+				keyProject, err := resolver.GetProjectByKey(key)
+				if err != nil {
+					if err.Error() == "project not found" {
+						return echo.NewHTTPError(http.StatusNotFound, err.Error())
+					}
+					return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+				}
+
+				project = keyProject
+			}
+
+			// the api key is enough for the SDK calls, a logged person still needs a role
+			isSdkCall := c.Request().Header.Get(ApiKeyHeader) != "" && personId == 0
+			if isProjectScoped(c) && !isSdkCall {
+				role := project_entity.RoleViewer
+				if c.Request().Method != http.MethodGet && c.Request().Method != http.MethodHead {
+					role = project_entity.RoleEditor
+				}
+
+				if err := resolver.CheckPermission(project.ID, personId, role); err != nil {
+					return echo.NewHTTPError(http.StatusForbidden, err.Error())
+				}
+			}
+
+			c.Set("project", project.ID)
+
+			return next(c)
+		}
+	}
+}
+
+func isProjectScoped(c echo.Context) bool {
+	for _, path := range unscopedPaths {
+		if strings.HasSuffix(c.Path(), path) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func requestPersonId(c echo.Context) uint {
+	cookie := c.Request().Header.Get("Cookie")
+	if cookie == "" {
+		return 0
+	}
+
+	authInfo, err := auth.GetAuthInfo(cookie)
+	if err != nil {
+		return 0
+	}
+
+	return uint(authInfo.PersonID)
+}
@@ -0,0 +1,1418 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: featureflag/v1/feature_flag.proto
+
+package featureflagv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EvaluationContext struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PersonId      uint64                 `protobuf:"varint,1,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Attributes    *structpb.Struct       `protobuf:"bytes,3,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluationContext) Reset() {
+	*x = EvaluationContext{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluationContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluationContext) ProtoMessage() {}
+
+func (x *EvaluationContext) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluationContext.ProtoReflect.Descriptor instead.
+func (*EvaluationContext) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EvaluationContext) GetPersonId() uint64 {
+	if x != nil {
+		return x.PersonId
+	}
+	return 0
+}
+
+func (x *EvaluationContext) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *EvaluationContext) GetAttributes() *structpb.Struct {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+type EvaluateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FlagName      string                 `protobuf:"bytes,1,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
+	Context       *EvaluationContext     `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateRequest) Reset() {
+	*x = EvaluateRequest{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateRequest) ProtoMessage() {}
+
+func (x *EvaluateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateRequest.ProtoReflect.Descriptor instead.
+func (*EvaluateRequest) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EvaluateRequest) GetFlagName() string {
+	if x != nil {
+		return x.FlagName
+	}
+	return ""
+}
+
+func (x *EvaluateRequest) GetContext() *EvaluationContext {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+type Evaluation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FlagName      string                 `protobuf:"bytes,1,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
+	Value         bool                   `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Variant       string                 `protobuf:"bytes,4,opt,name=variant,proto3" json:"variant,omitempty"`
+	VariantValue  *structpb.Value        `protobuf:"bytes,5,opt,name=variant_value,json=variantValue,proto3" json:"variant_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Evaluation) Reset() {
+	*x = Evaluation{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Evaluation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Evaluation) ProtoMessage() {}
+
+func (x *Evaluation) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Evaluation.ProtoReflect.Descriptor instead.
+func (*Evaluation) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Evaluation) GetFlagName() string {
+	if x != nil {
+		return x.FlagName
+	}
+	return ""
+}
+
+func (x *Evaluation) GetValue() bool {
+	if x != nil {
+		return x.Value
+	}
+	return false
+}
+
+func (x *Evaluation) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Evaluation) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *Evaluation) GetVariantValue() *structpb.Value {
+	if x != nil {
+		return x.VariantValue
+	}
+	return nil
+}
+
+type BulkEvaluateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FlagNames     []string               `protobuf:"bytes,1,rep,name=flag_names,json=flagNames,proto3" json:"flag_names,omitempty"`
+	Context       *EvaluationContext     `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkEvaluateRequest) Reset() {
+	*x = BulkEvaluateRequest{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkEvaluateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkEvaluateRequest) ProtoMessage() {}
+
+func (x *BulkEvaluateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkEvaluateRequest.ProtoReflect.Descriptor instead.
+func (*BulkEvaluateRequest) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BulkEvaluateRequest) GetFlagNames() []string {
+	if x != nil {
+		return x.FlagNames
+	}
+	return nil
+}
+
+func (x *BulkEvaluateRequest) GetContext() *EvaluationContext {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+type BulkEvaluateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Evaluations   []*Evaluation          `protobuf:"bytes,1,rep,name=evaluations,proto3" json:"evaluations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkEvaluateResponse) Reset() {
+	*x = BulkEvaluateResponse{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkEvaluateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkEvaluateResponse) ProtoMessage() {}
+
+func (x *BulkEvaluateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkEvaluateResponse.ProtoReflect.Descriptor instead.
+func (*BulkEvaluateResponse) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BulkEvaluateResponse) GetEvaluations() []*Evaluation {
+	if x != nil {
+		return x.Evaluations
+	}
+	return nil
+}
+
+type ListFeatureFlagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	IsActive      *bool                  `protobuf:"varint,4,opt,name=is_active,json=isActive,proto3,oneof" json:"is_active,omitempty"`
+	IsGlobal      *bool                  `protobuf:"varint,5,opt,name=is_global,json=isGlobal,proto3,oneof" json:"is_global,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFeatureFlagsRequest) Reset() {
+	*x = ListFeatureFlagsRequest{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFeatureFlagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeatureFlagsRequest) ProtoMessage() {}
+
+func (x *ListFeatureFlagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFeatureFlagsRequest.ProtoReflect.Descriptor instead.
+func (*ListFeatureFlagsRequest) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListFeatureFlagsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListFeatureFlagsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListFeatureFlagsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListFeatureFlagsRequest) GetIsActive() bool {
+	if x != nil && x.IsActive != nil {
+		return *x.IsActive
+	}
+	return false
+}
+
+func (x *ListFeatureFlagsRequest) GetIsGlobal() bool {
+	if x != nil && x.IsGlobal != nil {
+		return *x.IsGlobal
+	}
+	return false
+}
+
+type Rule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attribute     string                 `protobuf:"bytes,1,opt,name=attribute,proto3" json:"attribute,omitempty"`
+	Operator      string                 `protobuf:"bytes,2,opt,name=operator,proto3" json:"operator,omitempty"`
+	Values        []string               `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty"`
+	Result        bool                   `protobuf:"varint,4,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rule) Reset() {
+	*x = Rule{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rule) ProtoMessage() {}
+
+func (x *Rule) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rule.ProtoReflect.Descriptor instead.
+func (*Rule) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Rule) GetAttribute() string {
+	if x != nil {
+		return x.Attribute
+	}
+	return ""
+}
+
+func (x *Rule) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *Rule) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *Rule) GetResult() bool {
+	if x != nil {
+		return x.Result
+	}
+	return false
+}
+
+type Variant struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Weight        int32                  `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Variant) Reset() {
+	*x = Variant{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Variant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Variant) ProtoMessage() {}
+
+func (x *Variant) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Variant.ProtoReflect.Descriptor instead.
+func (*Variant) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Variant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Variant) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Variant) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type Prerequisite struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FlagName      string                 `protobuf:"bytes,1,opt,name=flag_name,json=flagName,proto3" json:"flag_name,omitempty"`
+	Variant       string                 `protobuf:"bytes,2,opt,name=variant,proto3" json:"variant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Prerequisite) Reset() {
+	*x = Prerequisite{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Prerequisite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Prerequisite) ProtoMessage() {}
+
+func (x *Prerequisite) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Prerequisite.ProtoReflect.Descriptor instead.
+func (*Prerequisite) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Prerequisite) GetFlagName() string {
+	if x != nil {
+		return x.FlagName
+	}
+	return ""
+}
+
+func (x *Prerequisite) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+type FeatureFlag struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description       string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	IsActive          bool                   `protobuf:"varint,4,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	IsGlobal          bool                   `protobuf:"varint,5,opt,name=is_global,json=isGlobal,proto3" json:"is_global,omitempty"`
+	ExpirationDate    string                 `protobuf:"bytes,6,opt,name=expiration_date,json=expirationDate,proto3" json:"expiration_date,omitempty"`
+	RolloutPercentage int32                  `protobuf:"varint,7,opt,name=rollout_percentage,json=rolloutPercentage,proto3" json:"rollout_percentage,omitempty"`
+	Rules             []*Rule                `protobuf:"bytes,8,rep,name=rules,proto3" json:"rules,omitempty"`
+	Type              string                 `protobuf:"bytes,9,opt,name=type,proto3" json:"type,omitempty"`
+	DefaultVariant    string                 `protobuf:"bytes,10,opt,name=default_variant,json=defaultVariant,proto3" json:"default_variant,omitempty"`
+	Variants          []*Variant             `protobuf:"bytes,11,rep,name=variants,proto3" json:"variants,omitempty"`
+	Prerequisites     []*Prerequisite        `protobuf:"bytes,12,rep,name=prerequisites,proto3" json:"prerequisites,omitempty"`
+	Environment       string                 `protobuf:"bytes,13,opt,name=environment,proto3" json:"environment,omitempty"`
+	ProjectId         uint64                 `protobuf:"varint,14,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	CreatedAt         string                 `protobuf:"bytes,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt         string                 `protobuf:"bytes,16,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *FeatureFlag) Reset() {
+	*x = FeatureFlag{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeatureFlag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeatureFlag) ProtoMessage() {}
+
+func (x *FeatureFlag) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeatureFlag.ProtoReflect.Descriptor instead.
+func (*FeatureFlag) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *FeatureFlag) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *FeatureFlag) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FeatureFlag) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *FeatureFlag) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *FeatureFlag) GetIsGlobal() bool {
+	if x != nil {
+		return x.IsGlobal
+	}
+	return false
+}
+
+func (x *FeatureFlag) GetExpirationDate() string {
+	if x != nil {
+		return x.ExpirationDate
+	}
+	return ""
+}
+
+func (x *FeatureFlag) GetRolloutPercentage() int32 {
+	if x != nil {
+		return x.RolloutPercentage
+	}
+	return 0
+}
+
+func (x *FeatureFlag) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *FeatureFlag) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *FeatureFlag) GetDefaultVariant() string {
+	if x != nil {
+		return x.DefaultVariant
+	}
+	return ""
+}
+
+func (x *FeatureFlag) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *FeatureFlag) GetPrerequisites() []*Prerequisite {
+	if x != nil {
+		return x.Prerequisites
+	}
+	return nil
+}
+
+func (x *FeatureFlag) GetEnvironment() string {
+	if x != nil {
+		return x.Environment
+	}
+	return ""
+}
+
+func (x *FeatureFlag) GetProjectId() uint64 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *FeatureFlag) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *FeatureFlag) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type ListFeatureFlagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*FeatureFlag         `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFeatureFlagsResponse) Reset() {
+	*x = ListFeatureFlagsResponse{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFeatureFlagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeatureFlagsResponse) ProtoMessage() {}
+
+func (x *ListFeatureFlagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFeatureFlagsResponse.ProtoReflect.Descriptor instead.
+func (*ListFeatureFlagsResponse) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListFeatureFlagsResponse) GetItems() []*FeatureFlag {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListFeatureFlagsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetAssignedFeatureFlagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PersonId      uint64                 `protobuf:"varint,1,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAssignedFeatureFlagsRequest) Reset() {
+	*x = GetAssignedFeatureFlagsRequest{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssignedFeatureFlagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssignedFeatureFlagsRequest) ProtoMessage() {}
+
+func (x *GetAssignedFeatureFlagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssignedFeatureFlagsRequest.ProtoReflect.Descriptor instead.
+func (*GetAssignedFeatureFlagsRequest) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetAssignedFeatureFlagsRequest) GetPersonId() uint64 {
+	if x != nil {
+		return x.PersonId
+	}
+	return 0
+}
+
+type AssignedFeatureFlag struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	IsActive      bool                   `protobuf:"varint,3,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	IsAssigned    bool                   `protobuf:"varint,4,opt,name=is_assigned,json=isAssigned,proto3" json:"is_assigned,omitempty"`
+	ViaSegment    bool                   `protobuf:"varint,5,opt,name=via_segment,json=viaSegment,proto3" json:"via_segment,omitempty"`
+	Variant       string                 `protobuf:"bytes,6,opt,name=variant,proto3" json:"variant,omitempty"`
+	VariantValue  *structpb.Value        `protobuf:"bytes,7,opt,name=variant_value,json=variantValue,proto3" json:"variant_value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignedFeatureFlag) Reset() {
+	*x = AssignedFeatureFlag{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignedFeatureFlag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignedFeatureFlag) ProtoMessage() {}
+
+func (x *AssignedFeatureFlag) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignedFeatureFlag.ProtoReflect.Descriptor instead.
+func (*AssignedFeatureFlag) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AssignedFeatureFlag) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AssignedFeatureFlag) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AssignedFeatureFlag) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *AssignedFeatureFlag) GetIsAssigned() bool {
+	if x != nil {
+		return x.IsAssigned
+	}
+	return false
+}
+
+func (x *AssignedFeatureFlag) GetViaSegment() bool {
+	if x != nil {
+		return x.ViaSegment
+	}
+	return false
+}
+
+func (x *AssignedFeatureFlag) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *AssignedFeatureFlag) GetVariantValue() *structpb.Value {
+	if x != nil {
+		return x.VariantValue
+	}
+	return nil
+}
+
+type GetAssignedFeatureFlagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*AssignedFeatureFlag `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Total         int64                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAssignedFeatureFlagsResponse) Reset() {
+	*x = GetAssignedFeatureFlagsResponse{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssignedFeatureFlagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssignedFeatureFlagsResponse) ProtoMessage() {}
+
+func (x *GetAssignedFeatureFlagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssignedFeatureFlagsResponse.ProtoReflect.Descriptor instead.
+func (*GetAssignedFeatureFlagsResponse) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetAssignedFeatureFlagsResponse) GetItems() []*AssignedFeatureFlag {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *GetAssignedFeatureFlagsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type AssignmentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PersonId      uint64                 `protobuf:"varint,1,opt,name=person_id,json=personId,proto3" json:"person_id,omitempty"`
+	FeatureFlagId uint64                 `protobuf:"varint,2,opt,name=feature_flag_id,json=featureFlagId,proto3" json:"feature_flag_id,omitempty"`
+	Variant       string                 `protobuf:"bytes,3,opt,name=variant,proto3" json:"variant,omitempty"`
+	// kind is include (the default) or exclude
+	Kind          string `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignmentRequest) Reset() {
+	*x = AssignmentRequest{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignmentRequest) ProtoMessage() {}
+
+func (x *AssignmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignmentRequest.ProtoReflect.Descriptor instead.
+func (*AssignmentRequest) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AssignmentRequest) GetPersonId() uint64 {
+	if x != nil {
+		return x.PersonId
+	}
+	return 0
+}
+
+func (x *AssignmentRequest) GetFeatureFlagId() uint64 {
+	if x != nil {
+		return x.FeatureFlagId
+	}
+	return 0
+}
+
+func (x *AssignmentRequest) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *AssignmentRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+type AssignmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignmentResponse) Reset() {
+	*x = AssignmentResponse{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignmentResponse) ProtoMessage() {}
+
+func (x *AssignmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignmentResponse.ProtoReflect.Descriptor instead.
+func (*AssignmentResponse) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AssignmentResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LastEventId   uint64                 `protobuf:"varint,1,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *WatchRequest) GetLastEventId() uint64 {
+	if x != nil {
+		return x.LastEventId
+	}
+	return 0
+}
+
+type FlagEvent struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type        string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	ProjectId   uint64                 `protobuf:"varint,3,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Environment string                 `protobuf:"bytes,4,opt,name=environment,proto3" json:"environment,omitempty"`
+	// feature_flag is the whole flag after the change, as in the flag snapshot
+	FeatureFlag   *structpb.Struct `protobuf:"bytes,5,opt,name=feature_flag,json=featureFlag,proto3" json:"feature_flag,omitempty"`
+	CreatedAt     string           `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlagEvent) Reset() {
+	*x = FlagEvent{}
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlagEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlagEvent) ProtoMessage() {}
+
+func (x *FlagEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_v1_feature_flag_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlagEvent.ProtoReflect.Descriptor instead.
+func (*FlagEvent) Descriptor() ([]byte, []int) {
+	return file_featureflag_v1_feature_flag_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *FlagEvent) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *FlagEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *FlagEvent) GetProjectId() uint64 {
+	if x != nil {
+		return x.ProjectId
+	}
+	return 0
+}
+
+func (x *FlagEvent) GetEnvironment() string {
+	if x != nil {
+		return x.Environment
+	}
+	return ""
+}
+
+func (x *FlagEvent) GetFeatureFlag() *structpb.Struct {
+	if x != nil {
+		return x.FeatureFlag
+	}
+	return nil
+}
+
+func (x *FlagEvent) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+var File_featureflag_v1_feature_flag_proto protoreflect.FileDescriptor
+
+const file_featureflag_v1_feature_flag_proto_rawDesc = "" +
+	"\n" +
+	"!featureflag/v1/feature_flag.proto\x12\x0efeatureflag.v1\x1a\x1cgoogle/protobuf/struct.proto\"\x7f\n" +
+	"\x11EvaluationContext\x12\x1b\n" +
+	"\tperson_id\x18\x01 \x01(\x04R\bpersonId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x127\n" +
+	"\n" +
+	"attributes\x18\x03 \x01(\v2\x17.google.protobuf.StructR\n" +
+	"attributes\"k\n" +
+	"\x0fEvaluateRequest\x12\x1b\n" +
+	"\tflag_name\x18\x01 \x01(\tR\bflagName\x12;\n" +
+	"\acontext\x18\x02 \x01(\v2!.featureflag.v1.EvaluationContextR\acontext\"\xae\x01\n" +
+	"\n" +
+	"Evaluation\x12\x1b\n" +
+	"\tflag_name\x18\x01 \x01(\tR\bflagName\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\bR\x05value\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x18\n" +
+	"\avariant\x18\x04 \x01(\tR\avariant\x12;\n" +
+	"\rvariant_value\x18\x05 \x01(\v2\x16.google.protobuf.ValueR\fvariantValue\"q\n" +
+	"\x13BulkEvaluateRequest\x12\x1d\n" +
+	"\n" +
+	"flag_names\x18\x01 \x03(\tR\tflagNames\x12;\n" +
+	"\acontext\x18\x02 \x01(\v2!.featureflag.v1.EvaluationContextR\acontext\"T\n" +
+	"\x14BulkEvaluateResponse\x12<\n" +
+	"\vevaluations\x18\x01 \x03(\v2\x1a.featureflag.v1.EvaluationR\vevaluations\"\xb7\x01\n" +
+	"\x17ListFeatureFlagsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
+	"\tis_active\x18\x04 \x01(\bH\x00R\bisActive\x88\x01\x01\x12 \n" +
+	"\tis_global\x18\x05 \x01(\bH\x01R\bisGlobal\x88\x01\x01B\f\n" +
+	"\n" +
+	"_is_activeB\f\n" +
+	"\n" +
+	"_is_global\"p\n" +
+	"\x04Rule\x12\x1c\n" +
+	"\tattribute\x18\x01 \x01(\tR\tattribute\x12\x1a\n" +
+	"\boperator\x18\x02 \x01(\tR\boperator\x12\x16\n" +
+	"\x06values\x18\x03 \x03(\tR\x06values\x12\x16\n" +
+	"\x06result\x18\x04 \x01(\bR\x06result\"K\n" +
+	"\aVariant\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x05R\x06weight\"E\n" +
+	"\fPrerequisite\x12\x1b\n" +
+	"\tflag_name\x18\x01 \x01(\tR\bflagName\x12\x18\n" +
+	"\avariant\x18\x02 \x01(\tR\avariant\"\xc6\x04\n" +
+	"\vFeatureFlag\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1b\n" +
+	"\tis_active\x18\x04 \x01(\bR\bisActive\x12\x1b\n" +
+	"\tis_global\x18\x05 \x01(\bR\bisGlobal\x12'\n" +
+	"\x0fexpiration_date\x18\x06 \x01(\tR\x0eexpirationDate\x12-\n" +
+	"\x12rollout_percentage\x18\a \x01(\x05R\x11rolloutPercentage\x12*\n" +
+	"\x05rules\x18\b \x03(\v2\x14.featureflag.v1.RuleR\x05rules\x12\x12\n" +
+	"\x04type\x18\t \x01(\tR\x04type\x12'\n" +
+	"\x0fdefault_variant\x18\n" +
+	" \x01(\tR\x0edefaultVariant\x123\n" +
+	"\bvariants\x18\v \x03(\v2\x17.featureflag.v1.VariantR\bvariants\x12B\n" +
+	"\rprerequisites\x18\f \x03(\v2\x1c.featureflag.v1.PrerequisiteR\rprerequisites\x12 \n" +
+	"\venvironment\x18\r \x01(\tR\venvironment\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x0e \x01(\x04R\tprojectId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x0f \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x10 \x01(\tR\tupdatedAt\"c\n" +
+	"\x18ListFeatureFlagsResponse\x121\n" +
+	"\x05items\x18\x01 \x03(\v2\x1b.featureflag.v1.FeatureFlagR\x05items\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"=\n" +
+	"\x1eGetAssignedFeatureFlagsRequest\x12\x1b\n" +
+	"\tperson_id\x18\x01 \x01(\x04R\bpersonId\"\xef\x01\n" +
+	"\x13AssignedFeatureFlag\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
+	"\tis_active\x18\x03 \x01(\bR\bisActive\x12\x1f\n" +
+	"\vis_assigned\x18\x04 \x01(\bR\n" +
+	"isAssigned\x12\x1f\n" +
+	"\vvia_segment\x18\x05 \x01(\bR\n" +
+	"viaSegment\x12\x18\n" +
+	"\avariant\x18\x06 \x01(\tR\avariant\x12;\n" +
+	"\rvariant_value\x18\a \x01(\v2\x16.google.protobuf.ValueR\fvariantValue\"r\n" +
+	"\x1fGetAssignedFeatureFlagsResponse\x129\n" +
+	"\x05items\x18\x01 \x03(\v2#.featureflag.v1.AssignedFeatureFlagR\x05items\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\"\x86\x01\n" +
+	"\x11AssignmentRequest\x12\x1b\n" +
+	"\tperson_id\x18\x01 \x01(\x04R\bpersonId\x12&\n" +
+	"\x0ffeature_flag_id\x18\x02 \x01(\x04R\rfeatureFlagId\x12\x18\n" +
+	"\avariant\x18\x03 \x01(\tR\avariant\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\".\n" +
+	"\x12AssignmentResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"2\n" +
+	"\fWatchRequest\x12\"\n" +
+	"\rlast_event_id\x18\x01 \x01(\x04R\vlastEventId\"\xcb\x01\n" +
+	"\tFlagEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x03 \x01(\x04R\tprojectId\x12 \n" +
+	"\venvironment\x18\x04 \x01(\tR\venvironment\x12:\n" +
+	"\ffeature_flag\x18\x05 \x01(\v2\x17.google.protobuf.StructR\vfeatureFlag\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt2\x94\x05\n" +
+	"\x12FeatureFlagService\x12G\n" +
+	"\bEvaluate\x12\x1f.featureflag.v1.EvaluateRequest\x1a\x1a.featureflag.v1.Evaluation\x12Y\n" +
+	"\fBulkEvaluate\x12#.featureflag.v1.BulkEvaluateRequest\x1a$.featureflag.v1.BulkEvaluateResponse\x12e\n" +
+	"\x10ListFeatureFlags\x12'.featureflag.v1.ListFeatureFlagsRequest\x1a(.featureflag.v1.ListFeatureFlagsResponse\x12z\n" +
+	"\x17GetAssignedFeatureFlags\x12..featureflag.v1.GetAssignedFeatureFlagsRequest\x1a/.featureflag.v1.GetAssignedFeatureFlagsResponse\x12X\n" +
+	"\x0fApplyAssignment\x12!.featureflag.v1.AssignmentRequest\x1a\".featureflag.v1.AssignmentResponse\x12Y\n" +
+	"\x10DeleteAssignment\x12!.featureflag.v1.AssignmentRequest\x1a\".featureflag.v1.AssignmentResponse\x12B\n" +
+	"\x05Watch\x12\x1c.featureflag.v1.WatchRequest\x1a\x19.featureflag.v1.FlagEvent0\x01B+Z)ff/api/proto/featureflag/v1;featureflagv1b\x06proto3"
+
+var (
+	file_featureflag_v1_feature_flag_proto_rawDescOnce sync.Once
+	file_featureflag_v1_feature_flag_proto_rawDescData []byte
+)
+
+func file_featureflag_v1_feature_flag_proto_rawDescGZIP() []byte {
+	file_featureflag_v1_feature_flag_proto_rawDescOnce.Do(func() {
+		file_featureflag_v1_feature_flag_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_featureflag_v1_feature_flag_proto_rawDesc), len(file_featureflag_v1_feature_flag_proto_rawDesc)))
+	})
+	return file_featureflag_v1_feature_flag_proto_rawDescData
+}
+
+var file_featureflag_v1_feature_flag_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_featureflag_v1_feature_flag_proto_goTypes = []any{
+	(*EvaluationContext)(nil),               // 0: featureflag.v1.EvaluationContext
+	(*EvaluateRequest)(nil),                 // 1: featureflag.v1.EvaluateRequest
+	(*Evaluation)(nil),                      // 2: featureflag.v1.Evaluation
+	(*BulkEvaluateRequest)(nil),             // 3: featureflag.v1.BulkEvaluateRequest
+	(*BulkEvaluateResponse)(nil),            // 4: featureflag.v1.BulkEvaluateResponse
+	(*ListFeatureFlagsRequest)(nil),         // 5: featureflag.v1.ListFeatureFlagsRequest
+	(*Rule)(nil),                            // 6: featureflag.v1.Rule
+	(*Variant)(nil),                         // 7: featureflag.v1.Variant
+	(*Prerequisite)(nil),                    // 8: featureflag.v1.Prerequisite
+	(*FeatureFlag)(nil),                     // 9: featureflag.v1.FeatureFlag
+	(*ListFeatureFlagsResponse)(nil),        // 10: featureflag.v1.ListFeatureFlagsResponse
+	(*GetAssignedFeatureFlagsRequest)(nil),  // 11: featureflag.v1.GetAssignedFeatureFlagsRequest
+	(*AssignedFeatureFlag)(nil),             // 12: featureflag.v1.AssignedFeatureFlag
+	(*GetAssignedFeatureFlagsResponse)(nil), // 13: featureflag.v1.GetAssignedFeatureFlagsResponse
+	(*AssignmentRequest)(nil),               // 14: featureflag.v1.AssignmentRequest
+	(*AssignmentResponse)(nil),              // 15: featureflag.v1.AssignmentResponse
+	(*WatchRequest)(nil),                    // 16: featureflag.v1.WatchRequest
+	(*FlagEvent)(nil),                       // 17: featureflag.v1.FlagEvent
+	(*structpb.Struct)(nil),                 // 18: google.protobuf.Struct
+	(*structpb.Value)(nil),                  // 19: google.protobuf.Value
+}
+var file_featureflag_v1_feature_flag_proto_depIdxs = []int32{
+	18, // 0: featureflag.v1.EvaluationContext.attributes:type_name -> google.protobuf.Struct
+	0,  // 1: featureflag.v1.EvaluateRequest.context:type_name -> featureflag.v1.EvaluationContext
+	19, // 2: featureflag.v1.Evaluation.variant_value:type_name -> google.protobuf.Value
+	0,  // 3: featureflag.v1.BulkEvaluateRequest.context:type_name -> featureflag.v1.EvaluationContext
+	2,  // 4: featureflag.v1.BulkEvaluateResponse.evaluations:type_name -> featureflag.v1.Evaluation
+	6,  // 5: featureflag.v1.FeatureFlag.rules:type_name -> featureflag.v1.Rule
+	7,  // 6: featureflag.v1.FeatureFlag.variants:type_name -> featureflag.v1.Variant
+	8,  // 7: featureflag.v1.FeatureFlag.prerequisites:type_name -> featureflag.v1.Prerequisite
+	9,  // 8: featureflag.v1.ListFeatureFlagsResponse.items:type_name -> featureflag.v1.FeatureFlag
+	19, // 9: featureflag.v1.AssignedFeatureFlag.variant_value:type_name -> google.protobuf.Value
+	12, // 10: featureflag.v1.GetAssignedFeatureFlagsResponse.items:type_name -> featureflag.v1.AssignedFeatureFlag
+	18, // 11: featureflag.v1.FlagEvent.feature_flag:type_name -> google.protobuf.Struct
+	1,  // 12: featureflag.v1.FeatureFlagService.Evaluate:input_type -> featureflag.v1.EvaluateRequest
+	3,  // 13: featureflag.v1.FeatureFlagService.BulkEvaluate:input_type -> featureflag.v1.BulkEvaluateRequest
+	5,  // 14: featureflag.v1.FeatureFlagService.ListFeatureFlags:input_type -> featureflag.v1.ListFeatureFlagsRequest
+	11, // 15: featureflag.v1.FeatureFlagService.GetAssignedFeatureFlags:input_type -> featureflag.v1.GetAssignedFeatureFlagsRequest
+	14, // 16: featureflag.v1.FeatureFlagService.ApplyAssignment:input_type -> featureflag.v1.AssignmentRequest
+	14, // 17: featureflag.v1.FeatureFlagService.DeleteAssignment:input_type -> featureflag.v1.AssignmentRequest
+	16, // 18: featureflag.v1.FeatureFlagService.Watch:input_type -> featureflag.v1.WatchRequest
+	2,  // 19: featureflag.v1.FeatureFlagService.Evaluate:output_type -> featureflag.v1.Evaluation
+	4,  // 20: featureflag.v1.FeatureFlagService.BulkEvaluate:output_type -> featureflag.v1.BulkEvaluateResponse
+	10, // 21: featureflag.v1.FeatureFlagService.ListFeatureFlags:output_type -> featureflag.v1.ListFeatureFlagsResponse
+	13, // 22: featureflag.v1.FeatureFlagService.GetAssignedFeatureFlags:output_type -> featureflag.v1.GetAssignedFeatureFlagsResponse
+	15, // 23: featureflag.v1.FeatureFlagService.ApplyAssignment:output_type -> featureflag.v1.AssignmentResponse
+	15, // 24: featureflag.v1.FeatureFlagService.DeleteAssignment:output_type -> featureflag.v1.AssignmentResponse
+	17, // 25: featureflag.v1.FeatureFlagService.Watch:output_type -> featureflag.v1.FlagEvent
+	19, // [19:26] is the sub-list for method output_type
+	12, // [12:19] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_featureflag_v1_feature_flag_proto_init() }
+func file_featureflag_v1_feature_flag_proto_init() {
+	if File_featureflag_v1_feature_flag_proto != nil {
+		return
+	}
+	file_featureflag_v1_feature_flag_proto_msgTypes[5].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_featureflag_v1_feature_flag_proto_rawDesc), len(file_featureflag_v1_feature_flag_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_featureflag_v1_feature_flag_proto_goTypes,
+		DependencyIndexes: file_featureflag_v1_feature_flag_proto_depIdxs,
+		MessageInfos:      file_featureflag_v1_feature_flag_proto_msgTypes,
+	}.Build()
+	File_featureflag_v1_feature_flag_proto = out.File
+	file_featureflag_v1_feature_flag_proto_goTypes = nil
+	file_featureflag_v1_feature_flag_proto_depIdxs = nil
+}
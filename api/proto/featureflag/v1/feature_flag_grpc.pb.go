@@ -0,0 +1,373 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: featureflag/v1/feature_flag.proto
+
+package featureflagv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FeatureFlagService_Evaluate_FullMethodName                = "/featureflag.v1.FeatureFlagService/Evaluate"
+	FeatureFlagService_BulkEvaluate_FullMethodName            = "/featureflag.v1.FeatureFlagService/BulkEvaluate"
+	FeatureFlagService_ListFeatureFlags_FullMethodName        = "/featureflag.v1.FeatureFlagService/ListFeatureFlags"
+	FeatureFlagService_GetAssignedFeatureFlags_FullMethodName = "/featureflag.v1.FeatureFlagService/GetAssignedFeatureFlags"
+	FeatureFlagService_ApplyAssignment_FullMethodName         = "/featureflag.v1.FeatureFlagService/ApplyAssignment"
+	FeatureFlagService_DeleteAssignment_FullMethodName        = "/featureflag.v1.FeatureFlagService/DeleteAssignment"
+	FeatureFlagService_Watch_FullMethodName                   = "/featureflag.v1.FeatureFlagService/Watch"
+)
+
+// FeatureFlagServiceClient is the client API for FeatureFlagService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FeatureFlagService is the gRPC API of the service, served next to the REST API with
+// the same services behind it. Every call is authenticated with the x-api-key metadata,
+// which binds it to the project of the key, and runs in the environment of the
+// x-environment metadata (production when it is not sent)
+type FeatureFlagServiceClient interface {
+	// Evaluate evaluates a flag for a person, an unknown flag is answered with the NOT_FOUND reason
+	Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*Evaluation, error)
+	// BulkEvaluate evaluates the flags for the same person, every flag of the project when no name is sent
+	BulkEvaluate(ctx context.Context, in *BulkEvaluateRequest, opts ...grpc.CallOption) (*BulkEvaluateResponse, error)
+	ListFeatureFlags(ctx context.Context, in *ListFeatureFlagsRequest, opts ...grpc.CallOption) (*ListFeatureFlagsResponse, error)
+	// GetAssignedFeatureFlags lists the flags that apply to the person
+	GetAssignedFeatureFlags(ctx context.Context, in *GetAssignedFeatureFlagsRequest, opts ...grpc.CallOption) (*GetAssignedFeatureFlagsResponse, error)
+	ApplyAssignment(ctx context.Context, in *AssignmentRequest, opts ...grpc.CallOption) (*AssignmentResponse, error)
+	DeleteAssignment(ctx context.Context, in *AssignmentRequest, opts ...grpc.CallOption) (*AssignmentResponse, error)
+	// Watch sends the flag and assignment changes, the same events as the REST stream. A
+	// client resuming after a disconnection sends the id of the last event it received
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FlagEvent], error)
+}
+
+type featureFlagServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFeatureFlagServiceClient(cc grpc.ClientConnInterface) FeatureFlagServiceClient {
+	return &featureFlagServiceClient{cc}
+}
+
+func (c *featureFlagServiceClient) Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*Evaluation, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Evaluation)
+	err := c.cc.Invoke(ctx, FeatureFlagService_Evaluate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *featureFlagServiceClient) BulkEvaluate(ctx context.Context, in *BulkEvaluateRequest, opts ...grpc.CallOption) (*BulkEvaluateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BulkEvaluateResponse)
+	err := c.cc.Invoke(ctx, FeatureFlagService_BulkEvaluate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *featureFlagServiceClient) ListFeatureFlags(ctx context.Context, in *ListFeatureFlagsRequest, opts ...grpc.CallOption) (*ListFeatureFlagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFeatureFlagsResponse)
+	err := c.cc.Invoke(ctx, FeatureFlagService_ListFeatureFlags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *featureFlagServiceClient) GetAssignedFeatureFlags(ctx context.Context, in *GetAssignedFeatureFlagsRequest, opts ...grpc.CallOption) (*GetAssignedFeatureFlagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAssignedFeatureFlagsResponse)
+	err := c.cc.Invoke(ctx, FeatureFlagService_GetAssignedFeatureFlags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *featureFlagServiceClient) ApplyAssignment(ctx context.Context, in *AssignmentRequest, opts ...grpc.CallOption) (*AssignmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignmentResponse)
+	err := c.cc.Invoke(ctx, FeatureFlagService_ApplyAssignment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *featureFlagServiceClient) DeleteAssignment(ctx context.Context, in *AssignmentRequest, opts ...grpc.CallOption) (*AssignmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignmentResponse)
+	err := c.cc.Invoke(ctx, FeatureFlagService_DeleteAssignment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *featureFlagServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FlagEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FeatureFlagService_ServiceDesc.Streams[0], FeatureFlagService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, FlagEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FeatureFlagService_WatchClient = grpc.ServerStreamingClient[FlagEvent]
+
+// FeatureFlagServiceServer is the server API for FeatureFlagService service.
+// All implementations must embed UnimplementedFeatureFlagServiceServer
+// for forward compatibility.
+//
+// FeatureFlagService is the gRPC API of the service, served next to the REST API with
+// the same services behind it. Every call is authenticated with the x-api-key metadata,
+// which binds it to the project of the key, and runs in the environment of the
+// x-environment metadata (production when it is not sent)
+type FeatureFlagServiceServer interface {
+	// Evaluate evaluates a flag for a person, an unknown flag is answered with the NOT_FOUND reason
+	Evaluate(context.Context, *EvaluateRequest) (*Evaluation, error)
+	// BulkEvaluate evaluates the flags for the same person, every flag of the project when no name is sent
+	BulkEvaluate(context.Context, *BulkEvaluateRequest) (*BulkEvaluateResponse, error)
+	ListFeatureFlags(context.Context, *ListFeatureFlagsRequest) (*ListFeatureFlagsResponse, error)
+	// GetAssignedFeatureFlags lists the flags that apply to the person
+	GetAssignedFeatureFlags(context.Context, *GetAssignedFeatureFlagsRequest) (*GetAssignedFeatureFlagsResponse, error)
+	ApplyAssignment(context.Context, *AssignmentRequest) (*AssignmentResponse, error)
+	DeleteAssignment(context.Context, *AssignmentRequest) (*AssignmentResponse, error)
+	// Watch sends the flag and assignment changes, the same events as the REST stream. A
+	// client resuming after a disconnection sends the id of the last event it received
+	Watch(*WatchRequest, grpc.ServerStreamingServer[FlagEvent]) error
+	mustEmbedUnimplementedFeatureFlagServiceServer()
+}
+
+// UnimplementedFeatureFlagServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFeatureFlagServiceServer struct{}
+
+func (UnimplementedFeatureFlagServiceServer) Evaluate(context.Context, *EvaluateRequest) (*Evaluation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Evaluate not implemented")
+}
+func (UnimplementedFeatureFlagServiceServer) BulkEvaluate(context.Context, *BulkEvaluateRequest) (*BulkEvaluateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BulkEvaluate not implemented")
+}
+func (UnimplementedFeatureFlagServiceServer) ListFeatureFlags(context.Context, *ListFeatureFlagsRequest) (*ListFeatureFlagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFeatureFlags not implemented")
+}
+func (UnimplementedFeatureFlagServiceServer) GetAssignedFeatureFlags(context.Context, *GetAssignedFeatureFlagsRequest) (*GetAssignedFeatureFlagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAssignedFeatureFlags not implemented")
+}
+func (UnimplementedFeatureFlagServiceServer) ApplyAssignment(context.Context, *AssignmentRequest) (*AssignmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyAssignment not implemented")
+}
+func (UnimplementedFeatureFlagServiceServer) DeleteAssignment(context.Context, *AssignmentRequest) (*AssignmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAssignment not implemented")
+}
+func (UnimplementedFeatureFlagServiceServer) Watch(*WatchRequest, grpc.ServerStreamingServer[FlagEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedFeatureFlagServiceServer) mustEmbedUnimplementedFeatureFlagServiceServer() {}
+func (UnimplementedFeatureFlagServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeFeatureFlagServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FeatureFlagServiceServer will
+// result in compilation errors.
+type UnsafeFeatureFlagServiceServer interface {
+	mustEmbedUnimplementedFeatureFlagServiceServer()
+}
+
+func RegisterFeatureFlagServiceServer(s grpc.ServiceRegistrar, srv FeatureFlagServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFeatureFlagServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FeatureFlagService_ServiceDesc, srv)
+}
+
+func _FeatureFlagService_Evaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeatureFlagServiceServer).Evaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FeatureFlagService_Evaluate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeatureFlagServiceServer).Evaluate(ctx, req.(*EvaluateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FeatureFlagService_BulkEvaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BulkEvaluateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeatureFlagServiceServer).BulkEvaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FeatureFlagService_BulkEvaluate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeatureFlagServiceServer).BulkEvaluate(ctx, req.(*BulkEvaluateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FeatureFlagService_ListFeatureFlags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFeatureFlagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeatureFlagServiceServer).ListFeatureFlags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FeatureFlagService_ListFeatureFlags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeatureFlagServiceServer).ListFeatureFlags(ctx, req.(*ListFeatureFlagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FeatureFlagService_GetAssignedFeatureFlags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAssignedFeatureFlagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeatureFlagServiceServer).GetAssignedFeatureFlags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FeatureFlagService_GetAssignedFeatureFlags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeatureFlagServiceServer).GetAssignedFeatureFlags(ctx, req.(*GetAssignedFeatureFlagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FeatureFlagService_ApplyAssignment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeatureFlagServiceServer).ApplyAssignment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FeatureFlagService_ApplyAssignment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeatureFlagServiceServer).ApplyAssignment(ctx, req.(*AssignmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FeatureFlagService_DeleteAssignment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeatureFlagServiceServer).DeleteAssignment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FeatureFlagService_DeleteAssignment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeatureFlagServiceServer).DeleteAssignment(ctx, req.(*AssignmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FeatureFlagService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FeatureFlagServiceServer).Watch(m, &grpc.GenericServerStream[WatchRequest, FlagEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FeatureFlagService_WatchServer = grpc.ServerStreamingServer[FlagEvent]
+
+// FeatureFlagService_ServiceDesc is the grpc.ServiceDesc for FeatureFlagService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FeatureFlagService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "featureflag.v1.FeatureFlagService",
+	HandlerType: (*FeatureFlagServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Evaluate",
+			Handler:    _FeatureFlagService_Evaluate_Handler,
+		},
+		{
+			MethodName: "BulkEvaluate",
+			Handler:    _FeatureFlagService_BulkEvaluate_Handler,
+		},
+		{
+			MethodName: "ListFeatureFlags",
+			Handler:    _FeatureFlagService_ListFeatureFlags_Handler,
+		},
+		{
+			MethodName: "GetAssignedFeatureFlags",
+			Handler:    _FeatureFlagService_GetAssignedFeatureFlags_Handler,
+		},
+		{
+			MethodName: "ApplyAssignment",
+			Handler:    _FeatureFlagService_ApplyAssignment_Handler,
+		},
+		{
+			MethodName: "DeleteAssignment",
+			Handler:    _FeatureFlagService_DeleteAssignment_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _FeatureFlagService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "featureflag/v1/feature_flag.proto",
+}
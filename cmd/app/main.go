@@ -1,23 +1,39 @@
 package main
 
 import (
+	"context"
 	"ff/config"
 	"ff/config/database"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"time"
 
+	grpcHandler "ff/api/handlers/grpc"
 	handler "ff/api/handlers/http"
+	"ff/api/middlewares"
 	assignment "ff/internal/assignment"
+	assignmentgroup "ff/internal/assignment_group"
 	mysql "ff/internal/db/mysql"
+	"ff/internal/environment"
+	evaluation "ff/internal/evaluation"
 	featureflag "ff/internal/feature_flag"
 	person "ff/internal/person"
+	project "ff/internal/project"
+	"ff/internal/revision"
+	scheduledchange "ff/internal/scheduled_change"
+	"ff/internal/scheduler"
+	"ff/internal/snapshot"
+	"ff/internal/stream"
+	"ff/internal/webhook"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -26,27 +42,116 @@ func main() {
 	config.LoadAppConfig(&logger)
 	ddb := database.DDB{Logger: &logger}
 
-	logger.Info().Msg("Initializing DB (MySQL)")
-	db := ddb.Connect(config.AppConfig.ConnectionString)
-	ddb.RunMigrations(db)
+	logger.Info().Msg(fmt.Sprintf("Initializing DB (%s)", config.AppConfig.DatabaseDriver))
+	db := ddb.Connect(config.AppConfig.DatabaseDriver, config.AppConfig.ConnectionString)
 
-	logger.Info().Msg("Initializing Repository (MySQL)")
+	// `charmander migrate up|down|status` changes the schema, the server only checks it
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(migrate(db, os.Args[2:], &logger))
+	}
+	ddb.CheckMigrations(db, config.AppConfig.DatabaseDriver)
+
+	logger.Info().Msg("Initializing Repository")
 	featureFlagRepository := mysql.NewSqlFeatureFlagRepository(db, &logger)
 	assignmentRepository := mysql.NewSqlAssignmentRepository(db, &logger)
+	assignmentGroupRepository := mysql.NewSqlAssignmentGroupRepository(db, &logger)
 	peopleRepository := mysql.NewSqlPersonRepository(db, &logger)
+	scheduledChangeRepository := mysql.NewSqlScheduledChangeRepository(db, &logger)
+	environmentRepository := mysql.NewSqlEnvironmentRepository(db, &logger)
+	projectRepository := mysql.NewSqlProjectRepository(db, &logger)
+	snapshotRepository := mysql.NewSqlSnapshotRepository(db, &logger)
+	streamRepository := mysql.NewSqlStreamRepository(db, &logger)
+	webhookRepository := mysql.NewSqlWebhookRepository(db, &logger)
+	revisionRepository := mysql.NewSqlRevisionRepository(db, &logger)
 
 	logger.Info().Msg("Initializing Services/UseCases")
 	featureFlagService := featureflag.LoadService(featureFlagRepository, &logger)
 	assignmentService := assignment.LoadService(assignmentRepository, &logger)
+	assignmentGroupService := assignmentgroup.LoadService(assignmentGroupRepository, &logger)
 	personService := person.LoadService(peopleRepository, &logger)
+	evaluationService := evaluation.LoadService(featureFlagRepository, peopleRepository, &logger)
+	scheduledChangeService := scheduledchange.LoadService(scheduledChangeRepository, featureFlagService, &logger)
+	environmentService := environment.LoadService(environmentRepository, &logger)
+	projectService := project.LoadService(projectRepository, &logger)
+	snapshotService := snapshot.LoadService(snapshotRepository, &logger)
+	streamService := stream.LoadService(streamRepository, snapshotService, environmentService, &logger)
+	webhookService := webhook.LoadService(webhookRepository, &logger)
+	revisionService := revision.LoadService(revisionRepository, featureFlagService, &logger)
+
+	// every flag and assignment change is published to the stream
+	featureFlagService.Publisher = streamService
+	assignmentService.Publisher = streamService
+	assignmentGroupService.Publisher = streamService
+
+	// and sent to the webhooks listening to it
+	featureFlagService.Notifier = webhookService
+	assignmentService.Notifier = webhookService
+
+	// and kept as a revision of the flag
+	featureFlagService.Recorder = revisionService
+	assignmentService.Recorder = revisionService
+
+	if config.AppConfig.ExpiryWorkerMode != config.ExpiryModeOff {
+		logger.Info().Msg(fmt.Sprintf("Initializing Expiry Worker (%s every %s)", config.AppConfig.ExpiryWorkerMode, config.AppConfig.ExpiryWorkerInterval))
+		deactivate := config.AppConfig.ExpiryWorkerMode == config.ExpiryModeDeactivate
+		go scheduler.Every(context.Background(), config.AppConfig.ExpiryWorkerInterval, "expiry", &logger, func(now time.Time) error {
+			_, err := featureFlagService.ExpireFeatureFlags(now, deactivate)
+			return err
+		})
+	}
+
+	logger.Info().Msg(fmt.Sprintf("Initializing Scheduled Changes Worker (every %s)", config.AppConfig.ScheduledChangesInterval))
+	go scheduler.Every(context.Background(), config.AppConfig.ScheduledChangesInterval, "scheduled-changes", &logger, func(now time.Time) error {
+		_, err := scheduledChangeService.ApplyScheduledChanges(now)
+		return err
+	})
+
+	logger.Info().Msg(fmt.Sprintf("Initializing Flag Events Stream (every %s, kept for %s)", config.AppConfig.StreamPollInterval, config.AppConfig.StreamRetention))
+	go streamService.Run(context.Background(), config.AppConfig.StreamPollInterval)
+	go scheduler.Every(context.Background(), time.Hour, "stream-retention", &logger, func(now time.Time) error {
+		return streamService.PruneFlagEvents(now, config.AppConfig.StreamRetention)
+	})
+
+	logger.Info().Msg(fmt.Sprintf("Initializing Webhooks Worker (every %s)", config.AppConfig.WebhookInterval))
+	go scheduler.Every(context.Background(), config.AppConfig.WebhookInterval, "webhooks", &logger, func(now time.Time) error {
+		return webhookService.DeliverWebhooks(now)
+	})
 
 	e := echo.New()
 	e.Use(middleware.Logger())
+	e.Use(middlewares.ResolveEnvironment(environmentService))
+	e.Use(middlewares.ResolveProject(projectService))
 
 	logger.Info().Msg("Initializing Handlers")
 	handler.NewFeatureFlagEchoHandler(featureFlagService, e)
 	handler.NewAssignmentEchoHandler(assignmentService, e)
+	handler.NewAssignmentGroupEchoHandler(assignmentGroupService, e)
 	handler.NewPersonEchoHandler(personService, e)
+	handler.NewEvaluationEchoHandler(evaluationService, e)
+	handler.NewScheduledChangeEchoHandler(scheduledChangeService, e)
+	handler.NewEnvironmentEchoHandler(environmentService, e)
+	handler.NewProjectEchoHandler(projectService, e)
+	handler.NewSnapshotEchoHandler(snapshotService, e)
+	handler.NewStreamEchoHandler(streamService, e)
+	handler.NewWebhookEchoHandler(webhookService, e)
+	handler.NewRevisionEchoHandler(revisionService, e)
+
+	// the gRPC server shares the services with the REST API
+	unaryScope, streamScope := middlewares.ResolveGrpcScope(projectService, environmentService)
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(unaryScope), grpc.StreamInterceptor(streamScope))
+	grpcHandler.NewFeatureFlagGrpcHandler(evaluationService, featureFlagService, personService, assignmentService, streamService, grpcServer)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%v", config.AppConfig.GrpcPort))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Error when listening to the gRPC port")
+	}
+
+	logger.Info().Msg(fmt.Sprintf("Starting gRPC Server on port %s", config.AppConfig.GrpcPort))
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			logger.Fatal().Err(err).Msg("Error when serving gRPC")
+		}
+	}()
 
 	// Start the server
 	logger.Info().Msg(fmt.Sprintf("Starting Server on port %s", config.AppConfig.Port))
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"ff/config"
+	"ff/config/database"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+const migrateUsage = "usage: migrate up|down|status"
+
+// migrate runs the migrate subcommand and returns the exit code,
+// up applies the pending migrations, down reverts the last one
+func migrate(db *gorm.DB, args []string, logger *zerolog.Logger) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	migrator, err := database.NewMigrator(db, config.AppConfig.DatabaseDriver, logger)
+	if err != nil {
+		logger.Error().Err(err).Msg("Error when loading the migrations")
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up()
+		if err != nil {
+			logger.Error().Err(err).Msg("Error when migrating up")
+			return 1
+		}
+		logger.Info().Msg(fmt.Sprintf("%d migrations applied", count))
+	case "down":
+		migration, reverted, err := migrator.Down()
+		if err != nil {
+			logger.Error().Err(err).Msg("Error when migrating down")
+			return 1
+		}
+		if !reverted {
+			logger.Info().Msg("No migration to revert")
+			return 0
+		}
+		logger.Info().Msg(fmt.Sprintf("Migration %d_%s reverted", migration.Version, migration.Name))
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			logger.Error().Err(err).Msg("Error when getting the migrations status")
+			return 1
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, migration := range status {
+			appliedAt := "pending"
+			if migration.AppliedAt != nil {
+				appliedAt = migration.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if migration.Unknown {
+				appliedAt += " (unknown to this version)"
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", migration.Version, migration.Name, appliedAt)
+		}
+		w.Flush()
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	return 0
+}
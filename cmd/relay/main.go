@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"ff/config"
+	"ff/config/database"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	handler "ff/api/handlers/http"
+	mysql "ff/internal/db/mysql"
+	project "ff/internal/project"
+	"ff/internal/relay"
+	"ff/internal/scheduler"
+	"ff/internal/snapshot"
+	"ff/pkg/ffclient"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/rs/zerolog"
+)
+
+// the relay syncs the flag snapshot of one project and environment from the API or the
+// database and serves the read-only routes of the consumers from memory, it keeps
+// serving the last known flags while the upstream is down
+func main() {
+	logger := zerolog.New(os.Stdout)
+
+	config.LoadRelayConfig(&logger)
+
+	var source relay.Source
+	if config.RelayConfig.Source == config.RelaySourceDatabase {
+		logger.Info().Msg(fmt.Sprintf("Initializing DB (%s)", config.RelayConfig.DatabaseDriver))
+		ddb := database.DDB{Logger: &logger}
+		db := ddb.Connect(config.RelayConfig.DatabaseDriver, config.RelayConfig.ConnectionString)
+
+		// the relay only reads, the migrations are run by the API's migrate command
+		ddb.CheckMigrations(db, config.RelayConfig.DatabaseDriver)
+		snapshotService := snapshot.LoadService(mysql.NewSqlSnapshotRepository(db, &logger), &logger)
+		projectService := project.LoadService(mysql.NewSqlProjectRepository(db, &logger), &logger)
+
+		source = &relay.DatabaseSource{
+			Snapshots:   snapshotService,
+			Projects:    projectService,
+			ProjectKey:  config.RelayConfig.Project,
+			Environment: config.RelayConfig.Environment,
+		}
+	} else {
+		client, err := ffclient.New(ffclient.Config{
+			BaseURL:     config.RelayConfig.UpstreamURL,
+			ApiKey:      config.RelayConfig.ApiKey,
+			Project:     config.RelayConfig.Project,
+			Environment: config.RelayConfig.Environment,
+			Logger:      &logger,
+		})
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Error when creating the upstream client")
+		}
+
+		source = &relay.APISource{Client: client}
+	}
+
+	relayService := relay.LoadService(source, &logger)
+	relayService.ProjectKey = config.RelayConfig.Project
+
+	logger.Info().Msg(fmt.Sprintf("Initializing Relay Sync (%s every %s)", source.Name(), config.RelayConfig.SyncInterval))
+	go scheduler.Every(context.Background(), config.RelayConfig.SyncInterval, "relay-sync", &logger, func(now time.Time) error {
+		return relayService.Sync(context.Background())
+	})
+
+	e := echo.New()
+	e.Use(middleware.Logger())
+
+	logger.Info().Msg("Initializing Handlers")
+	handler.NewRelayEchoHandler(relayService, e)
+
+	// Start the server
+	logger.Info().Msg(fmt.Sprintf("Starting Relay on port %s", config.RelayConfig.Port))
+	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%v", config.RelayConfig.Port), e))
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"ff/internal/db/model"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	// DriverSQLite needs cgo, the connection string is the file path (or file::memory:)
+	DriverSQLite = "sqlite"
+)
+
+var Drivers = []string{DriverMySQL, DriverPostgres, DriverSQLite}
+
+type DDB struct {
+	Logger *zerolog.Logger
+}
+
+// Dialector returns the gorm dialector of the driver for the connection string
+func Dialector(driver string, uri string) (gorm.Dialector, error) {
+	switch driver {
+	case DriverMySQL:
+		return mysql.Open(uri), nil
+	case DriverPostgres:
+		return postgres.Open(uri), nil
+	case DriverSQLite:
+		return sqlite.Open(uri), nil
+	}
+
+	return nil, fmt.Errorf("unknown database driver %q", driver)
+}
+
+func (ddb *DDB) Connect(driver string, uri string) *gorm.DB {
+	dialector, err := Dialector(driver, uri)
+	if err != nil {
+		ddb.Logger.Fatal().Err(err).Msg("Database configuration error")
+		os.Exit(1)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+
+	if err != nil {
+		ddb.Logger.Fatal().Err(err).Str("driver", driver).Msg("Database connection error")
+		os.Exit(1)
+	}
+
+	return db
+}
+
+// Models are the tables of the application, the repository tests drop them between the tests
+func Models() []interface{} {
+	return []interface{}{&model.FeatureFlag{}, &model.Person{}, &model.Assignment{}, &model.Rule{}, &model.Variant{}, &model.Prerequisite{}, &model.ScheduledChange{}, &model.AssignmentGroup{}, &model.AssignmentGroupMember{}, &model.GroupAssignment{}, &model.Environment{}, &model.FeatureFlagEnvironment{}, &model.Project{}, &model.ProjectMember{}, &model.ApiKey{}, &model.FlagEvent{}, &model.Webhook{}, &model.WebhookDelivery{}, &model.WebhookAttempt{}, &model.FeatureFlagRevision{}, &model.SchemaMigration{}}
+}
+
+// CheckMigrations stops the application when the schema is behind or ahead of its migrations,
+// the schema is only changed by the migrate command
+func (ddb *DDB) CheckMigrations(db *gorm.DB, driver string) {
+	migrator, err := NewMigrator(db, driver, ddb.Logger)
+	if err != nil {
+		ddb.Logger.Fatal().Err(err).Msg("Database migrations error")
+		os.Exit(1)
+	}
+
+	if err := migrator.Check(); err != nil {
+		ddb.Logger.Fatal().Err(err).Msg("Database schema error")
+		os.Exit(1)
+	}
+}
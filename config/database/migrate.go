@@ -0,0 +1,392 @@
+package database
+
+import (
+	"embed"
+	"errors"
+	"ff/internal/db/model"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+// the migrations of every driver are embedded in the binary, named NNNN_name.up.sql and NNNN_name.down.sql
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+var (
+	ErrSchemaBehind = errors.New("database schema is behind the application, run `migrate up`")
+	ErrSchemaAhead  = errors.New("database schema is ahead of the application, deploy a newer version or run `migrate down` with it")
+)
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   uint
+	Name      string
+	AppliedAt *time.Time
+	// Unknown is set for versions applied to the database but missing in the binary
+	Unknown bool
+}
+
+// Migrations returns the migrations of the driver sorted by version
+func Migrations(driver string) ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, path.Join("migrations", driver))
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for the database driver %q", driver)
+	}
+
+	byVersion := map[uint]*Migration{}
+	for _, entry := range entries {
+		matches := migrationFileName.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+
+		version, _ := strconv.ParseUint(matches[1], 10, 32)
+		content, err := migrationFiles.ReadFile(path.Join("migrations", driver, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: matches[2]}
+			byVersion[uint(version)] = migration
+		}
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("migration %d has two names, %s and %s", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// statements splits a migration file in its statements, every statement ends a line with a
+// semicolon and the lines starting with -- are comments
+func statements(sql string) []string {
+	var result []string
+	var current []string
+	for _, line := range strings.Split(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current = append(current, line)
+		if strings.HasSuffix(trimmed, ";") {
+			result = append(result, strings.TrimSuffix(strings.TrimSpace(strings.Join(current, "\n")), ";"))
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		result = append(result, strings.TrimSpace(strings.Join(current, "\n")))
+	}
+
+	return result
+}
+
+// Migrator applies the migrations of the driver and records them in the schema_migrations table,
+// every migration runs in a transaction but MySQL commits each schema change on its own
+type Migrator struct {
+	DB         *gorm.DB
+	Logger     *zerolog.Logger
+	Migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB, driver string, logger *zerolog.Logger) (*Migrator, error) {
+	migrations, err := Migrations(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{DB: db, Logger: logger, Migrations: migrations}, nil
+}
+
+// applied returns the applied migrations, it only reads so the check can run against a
+// database the application is not allowed to change
+func (m *Migrator) applied() (map[uint]model.SchemaMigration, error) {
+	applied := map[uint]model.SchemaMigration{}
+	if !m.DB.Migrator().HasTable(&model.SchemaMigration{}) {
+		return applied, nil
+	}
+
+	var rows []model.SchemaMigration
+	if err := m.DB.Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error when getting the applied migrations: %w", err)
+	}
+
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+
+	return applied, nil
+}
+
+// prepare creates the schema_migrations table when missing and returns the applied migrations.
+// A database created by AutoMigrate in the first release is recorded at the first version, the
+// schema of that release, only when it has every table and column the first migration creates
+func (m *Migrator) prepare() (map[uint]model.SchemaMigration, error) {
+	err := m.DB.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at TIMESTAMP NOT NULL)").Error
+	if err != nil {
+		return nil, fmt.Errorf("error when creating the schema_migrations table: %w", err)
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(applied) > 0 || len(m.Migrations) == 0 || !m.DB.Migrator().HasTable(&model.FeatureFlag{}) {
+		return applied, nil
+	}
+
+	first := m.Migrations[0]
+	if missing := m.missing(first); len(missing) > 0 {
+		return nil, fmt.Errorf("the existing schema does not match migration %d_%s, it is missing %s",
+			first.Version, first.Name, strings.Join(missing, ", "))
+	}
+
+	adopted := model.SchemaMigration{Version: first.Version, Name: first.Name, AppliedAt: time.Now().UTC()}
+	if err := m.DB.Create(&adopted).Error; err != nil {
+		return nil, fmt.Errorf("error when recording the existing schema: %w", err)
+	}
+	m.Logger.Warn().Msg(fmt.Sprintf("Existing schema recorded as migration %d_%s", adopted.Version, adopted.Name))
+	applied[adopted.Version] = adopted
+
+	return applied, nil
+}
+
+// missing lists the tables and columns created by the migration that the database does not have
+func (m *Migrator) missing(migration Migration) []string {
+	var missing []string
+	for _, table := range createdTables(migration.Up) {
+		if !m.DB.Migrator().HasTable(table.Name) {
+			missing = append(missing, "table "+table.Name)
+			continue
+		}
+
+		for _, column := range table.Columns {
+			if !m.DB.Migrator().HasColumn(table.Name, column) {
+				missing = append(missing, "column "+table.Name+"."+column)
+			}
+		}
+	}
+
+	return missing
+}
+
+type createdTable struct {
+	Name    string
+	Columns []string
+}
+
+var createTableStatement = regexp.MustCompile("(?is)^CREATE TABLE (?:IF NOT EXISTS )?[`\"]?(\\w+)[`\"]?\\s*\\((.*)\\)")
+
+var columnDefinition = regexp.MustCompile("^[`\"](\\w+)[`\"]")
+
+// createdTables reads the tables and their columns from the CREATE TABLE statements, the
+// constraints and indexes of the statements are not columns
+func createdTables(sql string) []createdTable {
+	var tables []createdTable
+	for _, statement := range statements(sql) {
+		matches := createTableStatement.FindStringSubmatch(statement)
+		if matches == nil {
+			continue
+		}
+
+		table := createdTable{Name: matches[1]}
+		for _, definition := range splitDefinitions(matches[2]) {
+			if column := columnDefinition.FindStringSubmatch(strings.TrimSpace(definition)); column != nil {
+				table.Columns = append(table.Columns, column[1])
+			}
+		}
+		tables = append(tables, table)
+	}
+
+	return tables
+}
+
+// splitDefinitions splits the definitions of a table on the commas outside parentheses
+func splitDefinitions(definitions string) []string {
+	var result []string
+	depth, start := 0, 0
+	for i, char := range definitions {
+		switch char {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, definitions[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(result, definitions[start:])
+}
+
+func (m *Migrator) run(sql string, after func(tx *gorm.DB) error) error {
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements(sql) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+
+		return after(tx)
+	})
+}
+
+// Up applies the pending migrations in order and returns how many were applied
+func (m *Migrator) Up() (int, error) {
+	applied, err := m.prepare()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range m.Migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		m.Logger.Info().Msg(fmt.Sprintf("Applying migration %d_%s", migration.Version, migration.Name))
+		err := m.run(migration.Up, func(tx *gorm.DB) error {
+			return tx.Create(&model.SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return count, fmt.Errorf("error when applying migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// Down reverts the last applied migration, it returns false when there is none
+func (m *Migrator) Down() (Migration, bool, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return Migration{}, false, err
+	}
+
+	var last *model.SchemaMigration
+	for _, row := range applied {
+		if last == nil || row.Version > last.Version {
+			row := row
+			last = &row
+		}
+	}
+	if last == nil {
+		return Migration{}, false, nil
+	}
+
+	for _, migration := range m.Migrations {
+		if migration.Version != last.Version {
+			continue
+		}
+
+		m.Logger.Info().Msg(fmt.Sprintf("Reverting migration %d_%s", migration.Version, migration.Name))
+		err := m.run(migration.Down, func(tx *gorm.DB) error {
+			return tx.Where("version = ?", migration.Version).Delete(&model.SchemaMigration{}).Error
+		})
+		if err != nil {
+			return Migration{}, false, fmt.Errorf("error when reverting migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+
+		return migration, true, nil
+	}
+
+	return Migration{}, false, fmt.Errorf("migration %d_%s is not known by this version, %w", last.Version, last.Name, ErrSchemaAhead)
+}
+
+// Status lists the migrations of the binary and the unknown ones applied to the database
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var status []MigrationStatus
+	for _, migration := range m.Migrations {
+		current := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			current.AppliedAt = &row.AppliedAt
+			delete(applied, migration.Version)
+		}
+		status = append(status, current)
+	}
+
+	for _, row := range applied {
+		row := row
+		status = append(status, MigrationStatus{Version: row.Version, Name: row.Name, AppliedAt: &row.AppliedAt, Unknown: true})
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Version < status[j].Version
+	})
+
+	return status, nil
+}
+
+// Check fails when a migration is pending or when the database has one the binary does not know,
+// the application refuses to start in both cases
+func (m *Migrator) Check() error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	var pending, unknown []string
+	for _, migration := range status {
+		name := fmt.Sprintf("%d_%s", migration.Version, migration.Name)
+		if migration.Unknown {
+			unknown = append(unknown, name)
+		} else if migration.AppliedAt == nil {
+			pending = append(pending, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w (unknown %s)", ErrSchemaAhead, strings.Join(unknown, ", "))
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w (pending %s)", ErrSchemaBehind, strings.Join(pending, ", "))
+	}
+
+	return nil
+}
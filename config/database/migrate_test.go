@@ -0,0 +1,227 @@
+package database
+
+import (
+	"errors"
+	"ff/internal/db/model"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestMigrator(t *testing.T, name string) *Migrator {
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+
+	logger := zerolog.New(os.Stdout)
+	migrator, err := NewMigrator(db, DriverSQLite, &logger)
+	require.NoError(t, err)
+
+	return migrator
+}
+
+func TestMigrations(t *testing.T) {
+	sqliteMigrations, err := Migrations(DriverSQLite)
+	require.NoError(t, err)
+	require.NotEmpty(t, sqliteMigrations)
+
+	// every driver has the same migrations, only the SQL changes
+	for _, driver := range Drivers {
+		migrations, err := Migrations(driver)
+		require.NoError(t, err, driver)
+		require.Equal(t, len(sqliteMigrations), len(migrations), driver)
+
+		for i, migration := range migrations {
+			assert.Equal(t, uint(i+1), migration.Version, driver)
+			assert.Equal(t, sqliteMigrations[i].Name, migration.Name, driver)
+			assert.NotEmpty(t, statements(migration.Up), driver)
+			assert.NotEmpty(t, statements(migration.Down), driver)
+		}
+	}
+
+	_, err = Migrations("oracle")
+	assert.Error(t, err)
+}
+
+func TestStatements(t *testing.T) {
+	sql := "-- comment\nCREATE TABLE a (id int);\n\nINSERT INTO a\nVALUES (1);\n-- trailing\nDROP TABLE b"
+
+	assert.Equal(t, []string{"CREATE TABLE a (id int)", "INSERT INTO a\nVALUES (1)", "DROP TABLE b"}, statements(sql))
+}
+
+func TestMigrator(t *testing.T) {
+	migrator := newTestMigrator(t, "migrator")
+	latest := migrator.Migrations[len(migrator.Migrations)-1]
+
+	t.Run("Empty database is behind", func(t *testing.T) {
+		err := migrator.Check()
+		assert.True(t, errors.Is(err, ErrSchemaBehind))
+	})
+
+	t.Run("Up applies every migration once", func(t *testing.T) {
+		count, err := migrator.Up()
+		require.NoError(t, err)
+		assert.Equal(t, len(migrator.Migrations), count)
+
+		count, err = migrator.Up()
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+		assert.NoError(t, migrator.Check())
+
+		var environments int64
+		require.NoError(t, migrator.DB.Model(&model.Environment{}).Count(&environments).Error)
+		assert.Equal(t, int64(len(model.DefaultEnvironments)), environments)
+	})
+
+	t.Run("Unique assignment per person, flag and environment", func(t *testing.T) {
+		assignment := model.Assignment{PersonID: 1, FeatureFlagID: 1, Environment: model.DefaultEnvironment}
+		require.NoError(t, migrator.DB.Create(&assignment).Error)
+
+		duplicate := model.Assignment{PersonID: 1, FeatureFlagID: 1, Environment: model.DefaultEnvironment}
+		assert.Error(t, migrator.DB.Create(&duplicate).Error)
+
+		staging := model.Assignment{PersonID: 1, FeatureFlagID: 1, Environment: "staging"}
+		assert.NoError(t, migrator.DB.Create(&staging).Error)
+	})
+
+	t.Run("Down reverts the last migration", func(t *testing.T) {
+		migration, reverted, err := migrator.Down()
+		require.NoError(t, err)
+		assert.True(t, reverted)
+		assert.Equal(t, latest.Version, migration.Version)
+
+		status, err := migrator.Status()
+		require.NoError(t, err)
+		assert.Nil(t, status[len(status)-1].AppliedAt)
+		assert.True(t, errors.Is(migrator.Check(), ErrSchemaBehind))
+
+		_, err = migrator.Up()
+		require.NoError(t, err)
+	})
+
+	t.Run("Unknown migration is ahead", func(t *testing.T) {
+		require.NoError(t, migrator.DB.Create(&model.SchemaMigration{Version: 999, Name: "from_the_future", AppliedAt: time.Now()}).Error)
+
+		err := migrator.Check()
+		assert.True(t, errors.Is(err, ErrSchemaAhead))
+
+		status, err := migrator.Status()
+		require.NoError(t, err)
+		assert.True(t, status[len(status)-1].Unknown)
+
+		_, _, err = migrator.Down()
+		assert.True(t, errors.Is(err, ErrSchemaAhead))
+	})
+}
+
+func TestMigratorExistingSchema(t *testing.T) {
+	migrator := newTestMigrator(t, "existing")
+
+	// the first migration creates the schema AutoMigrate used to create
+	require.NoError(t, migrator.run(migrator.Migrations[0].Up, func(tx *gorm.DB) error { return nil }))
+
+	count, err := migrator.Up()
+	require.NoError(t, err)
+	assert.Equal(t, len(migrator.Migrations)-1, count)
+
+	status, err := migrator.Status()
+	require.NoError(t, err)
+	for _, migration := range status {
+		assert.NotNil(t, migration.AppliedAt, migration.Name)
+	}
+}
+
+func TestMigratorCheckIsReadOnly(t *testing.T) {
+	migrator := newTestMigrator(t, "readonly")
+
+	assert.True(t, errors.Is(migrator.Check(), ErrSchemaBehind))
+	assert.False(t, migrator.DB.Migrator().HasTable(&model.SchemaMigration{}))
+}
+
+func TestMigratorBaselineSchema(t *testing.T) {
+	migrator := newTestMigrator(t, "baseline")
+
+	// the tables AutoMigrate created in the first release, before projects, rules, variants and environments existed
+	require.NoError(t, migrator.run(`
+CREATE TABLE person (id integer PRIMARY KEY AUTOINCREMENT, name text, email text);
+CREATE TABLE feature_flags (id integer PRIMARY KEY AUTOINCREMENT, name text NOT NULL UNIQUE, description text NOT NULL, is_active numeric NOT NULL DEFAULT false, is_global numeric NOT NULL DEFAULT false, expiration_date text, created_at datetime, updated_at datetime, person_id integer);
+CREATE TABLE feature_flag_assignments (id integer PRIMARY KEY AUTOINCREMENT, person_id integer, feature_flag_id integer);
+INSERT INTO person (name, email) VALUES ('Ash', 'ash@pallet.town');
+INSERT INTO feature_flags (name, description, is_active, person_id) VALUES ('pokedex', 'Pokedex', true, 1);
+INSERT INTO feature_flag_assignments (person_id, feature_flag_id) VALUES (1, 1);
+`, func(tx *gorm.DB) error { return nil }))
+
+	count, err := migrator.Up()
+	require.NoError(t, err)
+	assert.Equal(t, len(migrator.Migrations)-1, count)
+	assert.NoError(t, migrator.Check())
+
+	applied, err := migrator.applied()
+	require.NoError(t, err)
+	assert.Contains(t, applied, migrator.Migrations[0].Version)
+
+	var project model.Project
+	require.NoError(t, migrator.DB.Where("key = ?", model.DefaultProject).First(&project).Error)
+
+	var featureFlag model.FeatureFlag
+	require.NoError(t, migrator.DB.First(&featureFlag, 1).Error)
+	assert.Equal(t, "pokedex", featureFlag.Name)
+	assert.Equal(t, project.ID, featureFlag.ProjectID)
+	assert.True(t, featureFlag.IsActive)
+
+	var assignment model.Assignment
+	require.NoError(t, migrator.DB.First(&assignment, 1).Error)
+	assert.Equal(t, model.DefaultEnvironment, assignment.Environment)
+
+	// the name is unique per project instead of globally
+	other := model.Project{Key: "other", Name: "Other"}
+	require.NoError(t, migrator.DB.Create(&other).Error)
+	assert.NoError(t, migrator.DB.Exec("INSERT INTO feature_flags (name, description, project_id) VALUES ('pokedex', 'Pokedex', ?)", other.ID).Error)
+}
+
+func TestMigratorUnknownSchema(t *testing.T) {
+	migrator := newTestMigrator(t, "unknown")
+
+	require.NoError(t, migrator.run(`
+CREATE TABLE feature_flags (id integer PRIMARY KEY AUTOINCREMENT, title text NOT NULL);
+`, func(tx *gorm.DB) error { return nil }))
+
+	_, err := migrator.Up()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "table person")
+	assert.Contains(t, err.Error(), "column feature_flags.name")
+	assert.Contains(t, err.Error(), "table feature_flag_assignments")
+
+	applied, err := migrator.applied()
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.True(t, errors.Is(migrator.Check(), ErrSchemaBehind))
+}
+
+func TestCreatedTables(t *testing.T) {
+	for _, driver := range Drivers {
+		migrations, err := Migrations(driver)
+		require.NoError(t, err, driver)
+
+		columns := map[string][]string{}
+		for _, table := range createdTables(migrations[0].Up) {
+			columns[table.Name] = table.Columns
+		}
+
+		assert.Equal(t, []string{"id", "name", "email"}, columns["person"], driver)
+		assert.Contains(t, columns["feature_flags"], "person_id", driver)
+		assert.NotContains(t, columns["feature_flags"], "project_id", driver)
+		assert.NotContains(t, columns, "projects", driver)
+
+		tables := map[string]bool{}
+		for _, table := range createdTables(migrations[1].Up) {
+			tables[table.Name] = true
+		}
+		assert.True(t, tables["projects"], driver)
+	}
+}
@@ -0,0 +1,5 @@
+-- the schema of the first release, created by AutoMigrate before the migrations existed
+
+CREATE TABLE `person` (`id` bigint unsigned AUTO_INCREMENT,`name` longtext,`email` longtext,PRIMARY KEY (`id`));
+CREATE TABLE `feature_flags` (`id` bigint unsigned AUTO_INCREMENT,`name` varchar(191) NOT NULL,`description` longtext NOT NULL,`is_active` boolean NOT NULL DEFAULT false,`is_global` boolean NOT NULL DEFAULT false,`expiration_date` longtext,`created_at` datetime(3) NULL,`updated_at` datetime(3) NULL,`person_id` bigint unsigned,PRIMARY KEY (`id`),CONSTRAINT `fk_feature_flags_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`),CONSTRAINT `uni_feature_flags_name` UNIQUE (`name`));
+CREATE TABLE `feature_flag_assignments` (`id` bigint unsigned AUTO_INCREMENT,`person_id` bigint unsigned,`feature_flag_id` bigint unsigned,PRIMARY KEY (`id`),CONSTRAINT `fk_feature_flag_assignments_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`),CONSTRAINT `fk_feature_flag_assignments_feature_flag` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`));
@@ -0,0 +1,29 @@
+DROP TABLE IF EXISTS `webhook_attempts`;
+DROP TABLE IF EXISTS `webhook_deliveries`;
+DROP TABLE IF EXISTS `webhooks`;
+DROP TABLE IF EXISTS `feature_flag_events`;
+DROP TABLE IF EXISTS `project_api_keys`;
+DROP TABLE IF EXISTS `project_members`;
+DROP TABLE IF EXISTS `feature_flag_environments`;
+DROP TABLE IF EXISTS `environments`;
+DROP TABLE IF EXISTS `feature_flag_group_assignments`;
+DROP TABLE IF EXISTS `assignment_group_members`;
+DROP TABLE IF EXISTS `assignment_groups`;
+DROP TABLE IF EXISTS `feature_flag_scheduled_changes`;
+DROP TABLE IF EXISTS `feature_flag_prerequisites`;
+DROP TABLE IF EXISTS `feature_flag_variants`;
+DROP TABLE IF EXISTS `feature_flag_rules`;
+DROP TABLE IF EXISTS `projects`;
+DROP INDEX `idx_feature_flag_assignments_environment` ON `feature_flag_assignments`;
+ALTER TABLE `feature_flag_assignments` DROP COLUMN `kind`;
+ALTER TABLE `feature_flag_assignments` DROP COLUMN `environment`;
+ALTER TABLE `feature_flag_assignments` DROP COLUMN `variant`;
+DROP INDEX `idx_feature_flags_project_name` ON `feature_flags`;
+ALTER TABLE `feature_flags` DROP COLUMN `project_id`;
+ALTER TABLE `feature_flags` DROP COLUMN `status_reason`;
+ALTER TABLE `feature_flags` DROP COLUMN `expired_at`;
+ALTER TABLE `feature_flags` DROP COLUMN `default_variant`;
+ALTER TABLE `feature_flags` DROP COLUMN `type`;
+ALTER TABLE `feature_flags` DROP COLUMN `rollout_percentage`;
+ALTER TABLE `feature_flags` MODIFY COLUMN `name` varchar(191) NOT NULL;
+ALTER TABLE `feature_flags` ADD CONSTRAINT `uni_feature_flags_name` UNIQUE (`name`);
@@ -0,0 +1,37 @@
+-- the tables and columns AutoMigrate added after the first release, a flag name is now unique per project
+ALTER TABLE `feature_flags` DROP INDEX `uni_feature_flags_name`;
+ALTER TABLE `feature_flags` MODIFY COLUMN `name` varchar(255) NOT NULL;
+ALTER TABLE `feature_flags` ADD COLUMN `rollout_percentage` bigint NOT NULL DEFAULT 0;
+ALTER TABLE `feature_flags` ADD COLUMN `type` varchar(191) NOT NULL DEFAULT 'boolean';
+ALTER TABLE `feature_flags` ADD COLUMN `default_variant` longtext;
+ALTER TABLE `feature_flags` ADD COLUMN `expired_at` datetime(3) NULL;
+ALTER TABLE `feature_flags` ADD COLUMN `status_reason` longtext;
+ALTER TABLE `feature_flags` ADD COLUMN `project_id` bigint unsigned NOT NULL DEFAULT 0;
+CREATE UNIQUE INDEX `idx_feature_flags_project_name` ON `feature_flags` (`name`,`project_id`);
+ALTER TABLE `feature_flag_assignments` ADD COLUMN `variant` longtext;
+ALTER TABLE `feature_flag_assignments` ADD COLUMN `environment` varchar(64) NOT NULL DEFAULT 'production';
+ALTER TABLE `feature_flag_assignments` ADD COLUMN `kind` varchar(16) NOT NULL DEFAULT 'include';
+CREATE INDEX `idx_feature_flag_assignments_environment` ON `feature_flag_assignments` (`environment`);
+
+CREATE TABLE `projects` (`id` bigint unsigned AUTO_INCREMENT,`key` varchar(64) NOT NULL,`name` longtext NOT NULL,`description` longtext,`created_at` datetime(3) NULL,`updated_at` datetime(3) NULL,PRIMARY KEY (`id`),CONSTRAINT `uni_projects_key` UNIQUE (`key`));
+CREATE TABLE `feature_flag_rules` (`id` bigint unsigned AUTO_INCREMENT,`feature_flag_id` bigint unsigned NOT NULL,`position` bigint NOT NULL,`attribute` longtext NOT NULL,`operator` longtext NOT NULL,`values` text,`result` boolean NOT NULL DEFAULT false,PRIMARY KEY (`id`),INDEX `idx_feature_flag_rules_feature_flag_id` (`feature_flag_id`),CONSTRAINT `fk_feature_flags_rules` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`));
+CREATE TABLE `feature_flag_variants` (`id` bigint unsigned AUTO_INCREMENT,`feature_flag_id` bigint unsigned NOT NULL,`position` bigint NOT NULL,`name` longtext NOT NULL,`value` text,`weight` bigint NOT NULL DEFAULT 0,PRIMARY KEY (`id`),INDEX `idx_feature_flag_variants_feature_flag_id` (`feature_flag_id`),CONSTRAINT `fk_feature_flags_variants` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`));
+CREATE TABLE `feature_flag_prerequisites` (`id` bigint unsigned AUTO_INCREMENT,`feature_flag_id` bigint unsigned NOT NULL,`prerequisite_id` bigint unsigned NOT NULL,`position` bigint NOT NULL,`variant` longtext,PRIMARY KEY (`id`),UNIQUE INDEX `idx_feature_flag_prerequisite` (`feature_flag_id`,`prerequisite_id`),INDEX `idx_feature_flag_prerequisites_prerequisite_id` (`prerequisite_id`),CONSTRAINT `fk_feature_flag_prerequisites_prerequisite` FOREIGN KEY (`prerequisite_id`) REFERENCES `feature_flags`(`id`),CONSTRAINT `fk_feature_flags_prerequisites` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`));
+CREATE TABLE `feature_flag_scheduled_changes` (`id` bigint unsigned AUTO_INCREMENT,`feature_flag_id` bigint unsigned NOT NULL,`operation` longtext NOT NULL,`is_global` boolean NOT NULL DEFAULT false,`rollout_percentage` bigint NOT NULL DEFAULT 0,`execute_at` datetime(3) NOT NULL,`timezone` longtext NOT NULL,`environment` varchar(64) NOT NULL DEFAULT 'production',`status` varchar(191) NOT NULL,`applied_at` datetime(3) NULL,`error_message` longtext,`person_id` bigint unsigned,`created_at` datetime(3) NULL,`updated_at` datetime(3) NULL,PRIMARY KEY (`id`),INDEX `idx_feature_flag_scheduled_changes_feature_flag_id` (`feature_flag_id`),INDEX `idx_feature_flag_scheduled_changes_execute_at` (`execute_at`),INDEX `idx_feature_flag_scheduled_changes_status` (`status`),CONSTRAINT `fk_feature_flag_scheduled_changes_feature_flag` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`),CONSTRAINT `fk_feature_flag_scheduled_changes_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`));
+CREATE TABLE `assignment_groups` (`id` bigint unsigned AUTO_INCREMENT,`name` varchar(255) NOT NULL,`description` longtext,`created_at` datetime(3) NULL,`updated_at` datetime(3) NULL,PRIMARY KEY (`id`),CONSTRAINT `uni_assignment_groups_name` UNIQUE (`name`));
+CREATE TABLE `assignment_group_members` (`assignment_group_id` bigint unsigned,`person_id` bigint unsigned,PRIMARY KEY (`assignment_group_id`,`person_id`));
+CREATE TABLE `feature_flag_group_assignments` (`assignment_group_id` bigint unsigned,`feature_flag_id` bigint unsigned,PRIMARY KEY (`assignment_group_id`,`feature_flag_id`));
+CREATE TABLE `environments` (`id` bigint unsigned AUTO_INCREMENT,`name` varchar(64) NOT NULL,`position` bigint NOT NULL DEFAULT 0,`created_at` datetime(3) NULL,PRIMARY KEY (`id`),CONSTRAINT `uni_environments_name` UNIQUE (`name`));
+CREATE TABLE `feature_flag_environments` (`feature_flag_id` bigint unsigned,`environment` varchar(64),`is_active` boolean NOT NULL DEFAULT false,`is_global` boolean NOT NULL DEFAULT false,`updated_at` datetime(3) NULL,PRIMARY KEY (`feature_flag_id`,`environment`),CONSTRAINT `fk_feature_flags_environments` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`));
+CREATE TABLE `project_members` (`project_id` bigint unsigned,`person_id` bigint unsigned,`role` varchar(16) NOT NULL,`created_at` datetime(3) NULL,PRIMARY KEY (`project_id`,`person_id`),CONSTRAINT `fk_project_members_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`));
+CREATE TABLE `project_api_keys` (`id` bigint unsigned AUTO_INCREMENT,`project_id` bigint unsigned NOT NULL,`name` longtext NOT NULL,`prefix` varchar(16) NOT NULL,`key_hash` varchar(64) NOT NULL,`last_used_at` datetime(3) NULL,`revoked_at` datetime(3) NULL,`created_at` datetime(3) NULL,PRIMARY KEY (`id`),INDEX `idx_project_api_keys_project_id` (`project_id`),CONSTRAINT `fk_project_api_keys_project` FOREIGN KEY (`project_id`) REFERENCES `projects`(`id`),CONSTRAINT `uni_project_api_keys_key_hash` UNIQUE (`key_hash`));
+CREATE TABLE `feature_flag_events` (`id` bigint unsigned AUTO_INCREMENT,`type` varchar(32) NOT NULL,`project_id` bigint unsigned NOT NULL,`environment` varchar(64) NOT NULL DEFAULT 'production',`feature_flag_id` bigint unsigned NOT NULL,`payload` text,`created_at` datetime(3) NULL,PRIMARY KEY (`id`),INDEX `idx_feature_flag_events_project_id` (`project_id`),INDEX `idx_feature_flag_events_created_at` (`created_at`));
+CREATE TABLE `webhooks` (`id` bigint unsigned AUTO_INCREMENT,`project_id` bigint unsigned NOT NULL,`url` varchar(512) NOT NULL,`secret` varchar(128) NOT NULL,`event_types` varchar(512),`is_active` boolean NOT NULL DEFAULT true,`created_at` datetime(3) NULL,`updated_at` datetime(3) NULL,PRIMARY KEY (`id`),INDEX `idx_webhooks_project_id` (`project_id`));
+CREATE TABLE `webhook_deliveries` (`id` bigint unsigned AUTO_INCREMENT,`webhook_id` bigint unsigned NOT NULL,`event_type` varchar(64) NOT NULL,`payload` text,`status` varchar(16) NOT NULL DEFAULT 'pending',`attempts` bigint NOT NULL DEFAULT 0,`next_attempt_at` datetime(3) NULL,`created_at` datetime(3) NULL,`updated_at` datetime(3) NULL,PRIMARY KEY (`id`),INDEX `idx_webhook_deliveries_webhook_id` (`webhook_id`),INDEX `idx_webhook_deliveries_status` (`status`),INDEX `idx_webhook_deliveries_next_attempt_at` (`next_attempt_at`),CONSTRAINT `fk_webhook_deliveries_webhook` FOREIGN KEY (`webhook_id`) REFERENCES `webhooks`(`id`));
+CREATE TABLE `webhook_attempts` (`id` bigint unsigned AUTO_INCREMENT,`delivery_id` bigint unsigned NOT NULL,`status_code` bigint NOT NULL DEFAULT 0,`error` text,`duration_ms` bigint NOT NULL DEFAULT 0,`created_at` datetime(3) NULL,PRIMARY KEY (`id`),INDEX `idx_webhook_attempts_delivery_id` (`delivery_id`),CONSTRAINT `fk_webhook_deliveries_attempt_logs` FOREIGN KEY (`delivery_id`) REFERENCES `webhook_deliveries`(`id`));
+
+-- environments and the default project every flag belongs to when none is informed,
+-- the existing flags move to it
+INSERT INTO `environments` (`name`,`position`,`created_at`) VALUES ('development',0,CURRENT_TIMESTAMP),('staging',1,CURRENT_TIMESTAMP),('production',2,CURRENT_TIMESTAMP);
+INSERT INTO `projects` (`key`,`name`,`created_at`,`updated_at`) VALUES ('default','Default',CURRENT_TIMESTAMP,CURRENT_TIMESTAMP);
+UPDATE `feature_flags` SET `project_id` = (SELECT `id` FROM `projects` WHERE `key` = 'default') WHERE `project_id` = 0;
@@ -0,0 +1,10 @@
+ALTER TABLE `project_members` DROP FOREIGN KEY `fk_project_members_project`;
+ALTER TABLE `feature_flag_group_assignments` DROP FOREIGN KEY `fk_feature_flag_group_assignments_feature_flag`;
+ALTER TABLE `feature_flag_group_assignments` DROP FOREIGN KEY `fk_feature_flag_group_assignments_assignment_group`;
+ALTER TABLE `assignment_group_members` DROP FOREIGN KEY `fk_assignment_group_members_person`;
+ALTER TABLE `assignment_group_members` DROP FOREIGN KEY `fk_assignment_group_members_assignment_group`;
+
+-- the unique index backs the person foreign key since it exists, the key is added back to get its own index
+ALTER TABLE `feature_flag_assignments` DROP FOREIGN KEY `fk_feature_flag_assignments_person`;
+DROP INDEX `idx_feature_flag_assignment` ON `feature_flag_assignments`;
+ALTER TABLE `feature_flag_assignments` ADD CONSTRAINT `fk_feature_flag_assignments_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`);
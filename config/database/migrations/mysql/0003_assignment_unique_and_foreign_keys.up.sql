@@ -0,0 +1,13 @@
+-- a person is assigned once per flag and environment, the duplicates left by concurrent requests are removed first
+DELETE a FROM `feature_flag_assignments` a JOIN `feature_flag_assignments` b ON a.`person_id` = b.`person_id` AND a.`feature_flag_id` = b.`feature_flag_id` AND a.`environment` = b.`environment` AND a.`id` > b.`id`;
+CREATE UNIQUE INDEX `idx_feature_flag_assignment` ON `feature_flag_assignments` (`person_id`,`feature_flag_id`,`environment`);
+
+-- the join tables never had foreign keys, the rows of removed parents are dropped before adding them
+DELETE FROM `assignment_group_members` WHERE `assignment_group_id` NOT IN (SELECT `id` FROM `assignment_groups`) OR `person_id` NOT IN (SELECT `id` FROM `person`);
+DELETE FROM `feature_flag_group_assignments` WHERE `assignment_group_id` NOT IN (SELECT `id` FROM `assignment_groups`) OR `feature_flag_id` NOT IN (SELECT `id` FROM `feature_flags`);
+DELETE FROM `project_members` WHERE `project_id` NOT IN (SELECT `id` FROM `projects`);
+ALTER TABLE `assignment_group_members` ADD CONSTRAINT `fk_assignment_group_members_assignment_group` FOREIGN KEY (`assignment_group_id`) REFERENCES `assignment_groups`(`id`);
+ALTER TABLE `assignment_group_members` ADD CONSTRAINT `fk_assignment_group_members_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`);
+ALTER TABLE `feature_flag_group_assignments` ADD CONSTRAINT `fk_feature_flag_group_assignments_assignment_group` FOREIGN KEY (`assignment_group_id`) REFERENCES `assignment_groups`(`id`);
+ALTER TABLE `feature_flag_group_assignments` ADD CONSTRAINT `fk_feature_flag_group_assignments_feature_flag` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`);
+ALTER TABLE `project_members` ADD CONSTRAINT `fk_project_members_project` FOREIGN KEY (`project_id`) REFERENCES `projects`(`id`);
@@ -0,0 +1,2 @@
+DROP INDEX `idx_feature_flags_archived_at` ON `feature_flags`;
+ALTER TABLE `feature_flags` DROP COLUMN `archived_at`;
@@ -0,0 +1,3 @@
+-- archived flags are kept, hidden and off, until they are restored or deleted
+ALTER TABLE `feature_flags` ADD COLUMN `archived_at` datetime(3) NULL;
+CREATE INDEX `idx_feature_flags_archived_at` ON `feature_flags` (`archived_at`);
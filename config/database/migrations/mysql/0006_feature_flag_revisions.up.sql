@@ -0,0 +1,2 @@
+-- every change of a flag keeps the flag as it was after it, revisions are never updated
+CREATE TABLE `feature_flag_revisions` (`id` bigint unsigned AUTO_INCREMENT,`feature_flag_id` bigint unsigned NOT NULL,`event` varchar(64) NOT NULL,`environment` varchar(64) NOT NULL DEFAULT 'production',`snapshot` longtext NOT NULL,`person_id` bigint unsigned NULL,`created_at` datetime(3) NULL,PRIMARY KEY (`id`),INDEX `idx_feature_flag_revisions_feature_flag_id` (`feature_flag_id`),CONSTRAINT `fk_feature_flag_revisions_feature_flag` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`),CONSTRAINT `fk_feature_flag_revisions_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`));
@@ -0,0 +1,2 @@
+-- the revisions outlive their flag, a deleted flag keeps its history
+ALTER TABLE `feature_flag_revisions` DROP FOREIGN KEY `fk_feature_flag_revisions_feature_flag`;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "feature_flag_assignments";
+DROP TABLE IF EXISTS "feature_flags";
+DROP TABLE IF EXISTS "person";
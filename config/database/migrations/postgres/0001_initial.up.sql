@@ -0,0 +1,5 @@
+-- the schema of the first release, created by AutoMigrate before the migrations existed
+
+CREATE TABLE "person" ("id" bigserial,"name" text,"email" text,PRIMARY KEY ("id"));
+CREATE TABLE "feature_flags" ("id" bigserial,"name" text NOT NULL,"description" text NOT NULL,"is_active" boolean NOT NULL DEFAULT false,"is_global" boolean NOT NULL DEFAULT false,"expiration_date" text,"created_at" timestamptz,"updated_at" timestamptz,"person_id" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_feature_flags_person" FOREIGN KEY ("person_id") REFERENCES "person"("id"),CONSTRAINT "uni_feature_flags_name" UNIQUE ("name"));
+CREATE TABLE "feature_flag_assignments" ("id" bigserial,"person_id" bigint,"feature_flag_id" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_feature_flag_assignments_person" FOREIGN KEY ("person_id") REFERENCES "person"("id"),CONSTRAINT "fk_feature_flag_assignments_feature_flag" FOREIGN KEY ("feature_flag_id") REFERENCES "feature_flags"("id"));
@@ -0,0 +1,52 @@
+-- the tables and columns AutoMigrate added after the first release, a flag name is now unique per project
+ALTER TABLE "feature_flags" DROP CONSTRAINT "uni_feature_flags_name";
+ALTER TABLE "feature_flags" ALTER COLUMN "name" TYPE varchar(255);
+ALTER TABLE "feature_flags" ADD COLUMN "rollout_percentage" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "feature_flags" ADD COLUMN "type" text NOT NULL DEFAULT 'boolean';
+ALTER TABLE "feature_flags" ADD COLUMN "default_variant" text;
+ALTER TABLE "feature_flags" ADD COLUMN "expired_at" timestamptz;
+ALTER TABLE "feature_flags" ADD COLUMN "status_reason" text;
+ALTER TABLE "feature_flags" ADD COLUMN "project_id" bigint NOT NULL DEFAULT 0;
+CREATE UNIQUE INDEX "idx_feature_flags_project_name" ON "feature_flags" ("name","project_id");
+ALTER TABLE "feature_flag_assignments" ADD COLUMN "variant" text;
+ALTER TABLE "feature_flag_assignments" ADD COLUMN "environment" varchar(64) NOT NULL DEFAULT 'production';
+ALTER TABLE "feature_flag_assignments" ADD COLUMN "kind" varchar(16) NOT NULL DEFAULT 'include';
+CREATE INDEX "idx_feature_flag_assignments_environment" ON "feature_flag_assignments" ("environment");
+
+CREATE TABLE "projects" ("id" bigserial,"key" varchar(64) NOT NULL,"name" text NOT NULL,"description" text,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "uni_projects_key" UNIQUE ("key"));
+CREATE TABLE "feature_flag_rules" ("id" bigserial,"feature_flag_id" bigint NOT NULL,"position" bigint NOT NULL,"attribute" text NOT NULL,"operator" text NOT NULL,"values" text,"result" boolean NOT NULL DEFAULT false,PRIMARY KEY ("id"),CONSTRAINT "fk_feature_flags_rules" FOREIGN KEY ("feature_flag_id") REFERENCES "feature_flags"("id"));
+CREATE INDEX IF NOT EXISTS "idx_feature_flag_rules_feature_flag_id" ON "feature_flag_rules" ("feature_flag_id");
+CREATE TABLE "feature_flag_variants" ("id" bigserial,"feature_flag_id" bigint NOT NULL,"position" bigint NOT NULL,"name" text NOT NULL,"value" text,"weight" bigint NOT NULL DEFAULT 0,PRIMARY KEY ("id"),CONSTRAINT "fk_feature_flags_variants" FOREIGN KEY ("feature_flag_id") REFERENCES "feature_flags"("id"));
+CREATE INDEX IF NOT EXISTS "idx_feature_flag_variants_feature_flag_id" ON "feature_flag_variants" ("feature_flag_id");
+CREATE TABLE "feature_flag_prerequisites" ("id" bigserial,"feature_flag_id" bigint NOT NULL,"prerequisite_id" bigint NOT NULL,"position" bigint NOT NULL,"variant" text,PRIMARY KEY ("id"),CONSTRAINT "fk_feature_flags_prerequisites" FOREIGN KEY ("feature_flag_id") REFERENCES "feature_flags"("id"),CONSTRAINT "fk_feature_flag_prerequisites_prerequisite" FOREIGN KEY ("prerequisite_id") REFERENCES "feature_flags"("id"));
+CREATE INDEX IF NOT EXISTS "idx_feature_flag_prerequisites_prerequisite_id" ON "feature_flag_prerequisites" ("prerequisite_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_feature_flag_prerequisite" ON "feature_flag_prerequisites" ("feature_flag_id","prerequisite_id");
+CREATE TABLE "feature_flag_scheduled_changes" ("id" bigserial,"feature_flag_id" bigint NOT NULL,"operation" text NOT NULL,"is_global" boolean NOT NULL DEFAULT false,"rollout_percentage" bigint NOT NULL DEFAULT 0,"execute_at" timestamptz NOT NULL,"timezone" text NOT NULL,"environment" varchar(64) NOT NULL DEFAULT 'production',"status" text NOT NULL,"applied_at" timestamptz,"error_message" text,"person_id" bigint,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_feature_flag_scheduled_changes_feature_flag" FOREIGN KEY ("feature_flag_id") REFERENCES "feature_flags"("id"),CONSTRAINT "fk_feature_flag_scheduled_changes_person" FOREIGN KEY ("person_id") REFERENCES "person"("id"));
+CREATE INDEX IF NOT EXISTS "idx_feature_flag_scheduled_changes_status" ON "feature_flag_scheduled_changes" ("status");
+CREATE INDEX IF NOT EXISTS "idx_feature_flag_scheduled_changes_execute_at" ON "feature_flag_scheduled_changes" ("execute_at");
+CREATE INDEX IF NOT EXISTS "idx_feature_flag_scheduled_changes_feature_flag_id" ON "feature_flag_scheduled_changes" ("feature_flag_id");
+CREATE TABLE "assignment_groups" ("id" bigserial,"name" varchar(255) NOT NULL,"description" text,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "uni_assignment_groups_name" UNIQUE ("name"));
+CREATE TABLE "assignment_group_members" ("assignment_group_id" bigint,"person_id" bigint,PRIMARY KEY ("assignment_group_id","person_id"));
+CREATE TABLE "feature_flag_group_assignments" ("assignment_group_id" bigint,"feature_flag_id" bigint,PRIMARY KEY ("assignment_group_id","feature_flag_id"));
+CREATE TABLE "environments" ("id" bigserial,"name" varchar(64) NOT NULL,"position" bigint NOT NULL DEFAULT 0,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "uni_environments_name" UNIQUE ("name"));
+CREATE TABLE "feature_flag_environments" ("feature_flag_id" bigint,"environment" varchar(64),"is_active" boolean NOT NULL DEFAULT false,"is_global" boolean NOT NULL DEFAULT false,"updated_at" timestamptz,PRIMARY KEY ("feature_flag_id","environment"),CONSTRAINT "fk_feature_flags_environments" FOREIGN KEY ("feature_flag_id") REFERENCES "feature_flags"("id"));
+CREATE TABLE "project_members" ("project_id" bigint,"person_id" bigint,"role" varchar(16) NOT NULL,"created_at" timestamptz,PRIMARY KEY ("project_id","person_id"),CONSTRAINT "fk_project_members_person" FOREIGN KEY ("person_id") REFERENCES "person"("id"));
+CREATE TABLE "project_api_keys" ("id" bigserial,"project_id" bigint NOT NULL,"name" text NOT NULL,"prefix" varchar(16) NOT NULL,"key_hash" varchar(64) NOT NULL,"last_used_at" timestamptz,"revoked_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_project_api_keys_project" FOREIGN KEY ("project_id") REFERENCES "projects"("id"),CONSTRAINT "uni_project_api_keys_key_hash" UNIQUE ("key_hash"));
+CREATE INDEX IF NOT EXISTS "idx_project_api_keys_project_id" ON "project_api_keys" ("project_id");
+CREATE TABLE "feature_flag_events" ("id" bigserial,"type" varchar(32) NOT NULL,"project_id" bigint NOT NULL,"environment" varchar(64) NOT NULL DEFAULT 'production',"feature_flag_id" bigint NOT NULL,"payload" text,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_feature_flag_events_created_at" ON "feature_flag_events" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_feature_flag_events_project_id" ON "feature_flag_events" ("project_id");
+CREATE TABLE "webhooks" ("id" bigserial,"project_id" bigint NOT NULL,"url" varchar(512) NOT NULL,"secret" varchar(128) NOT NULL,"event_types" varchar(512),"is_active" boolean NOT NULL DEFAULT true,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_webhooks_project_id" ON "webhooks" ("project_id");
+CREATE TABLE "webhook_deliveries" ("id" bigserial,"webhook_id" bigint NOT NULL,"event_type" varchar(64) NOT NULL,"payload" text,"status" varchar(16) NOT NULL DEFAULT 'pending',"attempts" bigint NOT NULL DEFAULT 0,"next_attempt_at" timestamptz,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_webhook_deliveries_webhook" FOREIGN KEY ("webhook_id") REFERENCES "webhooks"("id"));
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_next_attempt_at" ON "webhook_deliveries" ("next_attempt_at");
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_status" ON "webhook_deliveries" ("status");
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_webhook_id" ON "webhook_deliveries" ("webhook_id");
+CREATE TABLE "webhook_attempts" ("id" bigserial,"delivery_id" bigint NOT NULL,"status_code" bigint NOT NULL DEFAULT 0,"error" text,"duration_ms" bigint NOT NULL DEFAULT 0,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_webhook_deliveries_attempt_logs" FOREIGN KEY ("delivery_id") REFERENCES "webhook_deliveries"("id"));
+CREATE INDEX IF NOT EXISTS "idx_webhook_attempts_delivery_id" ON "webhook_attempts" ("delivery_id");
+
+-- environments and the default project every flag belongs to when none is informed,
+-- the existing flags move to it
+INSERT INTO "environments" ("name","position","created_at") VALUES ('development',0,CURRENT_TIMESTAMP),('staging',1,CURRENT_TIMESTAMP),('production',2,CURRENT_TIMESTAMP);
+INSERT INTO "projects" ("key","name","created_at","updated_at") VALUES ('default','Default',CURRENT_TIMESTAMP,CURRENT_TIMESTAMP);
+UPDATE "feature_flags" SET "project_id" = (SELECT "id" FROM "projects" WHERE "key" = 'default') WHERE "project_id" = 0;
@@ -0,0 +1,6 @@
+ALTER TABLE "project_members" DROP CONSTRAINT "fk_project_members_project";
+ALTER TABLE "feature_flag_group_assignments" DROP CONSTRAINT "fk_feature_flag_group_assignments_feature_flag";
+ALTER TABLE "feature_flag_group_assignments" DROP CONSTRAINT "fk_feature_flag_group_assignments_assignment_group";
+ALTER TABLE "assignment_group_members" DROP CONSTRAINT "fk_assignment_group_members_person";
+ALTER TABLE "assignment_group_members" DROP CONSTRAINT "fk_assignment_group_members_assignment_group";
+DROP INDEX "idx_feature_flag_assignment";
@@ -0,0 +1,2 @@
+DROP INDEX "idx_feature_flags_archived_at";
+ALTER TABLE "feature_flags" DROP COLUMN "archived_at";
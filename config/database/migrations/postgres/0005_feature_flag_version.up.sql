@@ -0,0 +1,2 @@
+-- the version moves on with every change of a flag, an update based on an older one is refused
+ALTER TABLE "feature_flags" ADD COLUMN "version" bigint NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+-- every change of a flag keeps the flag as it was after it, revisions are never updated
+CREATE TABLE "feature_flag_revisions" ("id" bigserial,"feature_flag_id" bigint NOT NULL,"event" varchar(64) NOT NULL,"environment" varchar(64) NOT NULL DEFAULT 'production',"snapshot" text NOT NULL,"person_id" bigint,"created_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_feature_flag_revisions_feature_flag" FOREIGN KEY ("feature_flag_id") REFERENCES "feature_flags"("id"),CONSTRAINT "fk_feature_flag_revisions_person" FOREIGN KEY ("person_id") REFERENCES "person"("id"));
+CREATE INDEX "idx_feature_flag_revisions_feature_flag_id" ON "feature_flag_revisions" ("feature_flag_id");
@@ -0,0 +1 @@
+ALTER TABLE "feature_flag_scheduled_changes" DROP COLUMN "force";
@@ -0,0 +1,2 @@
+-- a forced change deactivates the flag even when other flags depend on it
+ALTER TABLE "feature_flag_scheduled_changes" ADD COLUMN "force" boolean NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+DELETE FROM "feature_flag_revisions" WHERE "feature_flag_id" NOT IN (SELECT "id" FROM "feature_flags");
+ALTER TABLE "feature_flag_revisions" ADD CONSTRAINT "fk_feature_flag_revisions_feature_flag" FOREIGN KEY ("feature_flag_id") REFERENCES "feature_flags"("id");
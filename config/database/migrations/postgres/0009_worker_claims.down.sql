@@ -0,0 +1,2 @@
+ALTER TABLE "feature_flag_scheduled_changes" DROP COLUMN "locked_until";
+ALTER TABLE "webhook_deliveries" DROP COLUMN "locked_until";
@@ -0,0 +1,5 @@
+-- the schema of the first release, created by AutoMigrate before the migrations existed
+
+CREATE TABLE `person` (`id` integer PRIMARY KEY AUTOINCREMENT,`name` text,`email` text);
+CREATE TABLE `feature_flags` (`id` integer PRIMARY KEY AUTOINCREMENT,`name` text NOT NULL,`description` text NOT NULL,`is_active` numeric NOT NULL DEFAULT false,`is_global` numeric NOT NULL DEFAULT false,`expiration_date` text,`created_at` datetime,`updated_at` datetime,`person_id` integer,CONSTRAINT `fk_feature_flags_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`),CONSTRAINT `uni_feature_flags_name` UNIQUE (`name`));
+CREATE TABLE `feature_flag_assignments` (`id` integer PRIMARY KEY AUTOINCREMENT,`person_id` integer,`feature_flag_id` integer,CONSTRAINT `fk_feature_flag_assignments_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`),CONSTRAINT `fk_feature_flag_assignments_feature_flag` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`));
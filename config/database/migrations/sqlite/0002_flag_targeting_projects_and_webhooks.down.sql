@@ -0,0 +1,25 @@
+DROP TABLE IF EXISTS `webhook_attempts`;
+DROP TABLE IF EXISTS `webhook_deliveries`;
+DROP TABLE IF EXISTS `webhooks`;
+DROP TABLE IF EXISTS `feature_flag_events`;
+DROP TABLE IF EXISTS `project_api_keys`;
+DROP TABLE IF EXISTS `project_members`;
+DROP TABLE IF EXISTS `feature_flag_environments`;
+DROP TABLE IF EXISTS `environments`;
+DROP TABLE IF EXISTS `feature_flag_group_assignments`;
+DROP TABLE IF EXISTS `assignment_group_members`;
+DROP TABLE IF EXISTS `assignment_groups`;
+DROP TABLE IF EXISTS `feature_flag_scheduled_changes`;
+DROP TABLE IF EXISTS `feature_flag_prerequisites`;
+DROP TABLE IF EXISTS `feature_flag_variants`;
+DROP TABLE IF EXISTS `feature_flag_rules`;
+DROP TABLE IF EXISTS `projects`;
+DROP INDEX `idx_feature_flag_assignments_environment`;
+ALTER TABLE `feature_flag_assignments` DROP COLUMN `kind`;
+ALTER TABLE `feature_flag_assignments` DROP COLUMN `environment`;
+ALTER TABLE `feature_flag_assignments` DROP COLUMN `variant`;
+-- sqlite can not add a constraint, the flags table is rebuilt with the unique name
+CREATE TABLE `feature_flags_baseline` (`id` integer PRIMARY KEY AUTOINCREMENT,`name` text NOT NULL,`description` text NOT NULL,`is_active` numeric NOT NULL DEFAULT false,`is_global` numeric NOT NULL DEFAULT false,`expiration_date` text,`created_at` datetime,`updated_at` datetime,`person_id` integer,CONSTRAINT `fk_feature_flags_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`),CONSTRAINT `uni_feature_flags_name` UNIQUE (`name`));
+INSERT INTO `feature_flags_baseline` (`id`,`name`,`description`,`is_active`,`is_global`,`expiration_date`,`created_at`,`updated_at`,`person_id`) SELECT `id`,`name`,`description`,`is_active`,`is_global`,`expiration_date`,`created_at`,`updated_at`,`person_id` FROM `feature_flags`;
+DROP TABLE `feature_flags`;
+ALTER TABLE `feature_flags_baseline` RENAME TO `feature_flags`;
@@ -0,0 +1,49 @@
+-- the tables and columns AutoMigrate added after the first release, a flag name is now unique per project
+-- sqlite can not drop a constraint, the flags table is rebuilt without the unique name
+CREATE TABLE `feature_flags_projects` (`id` integer PRIMARY KEY AUTOINCREMENT,`name` text NOT NULL,`description` text NOT NULL,`is_active` numeric NOT NULL DEFAULT false,`is_global` numeric NOT NULL DEFAULT false,`expiration_date` text,`rollout_percentage` integer NOT NULL DEFAULT 0,`type` text NOT NULL DEFAULT "boolean",`default_variant` text,`expired_at` datetime,`status_reason` text,`created_at` datetime,`updated_at` datetime,`person_id` integer,`project_id` integer NOT NULL DEFAULT 0,CONSTRAINT `fk_feature_flags_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`));
+INSERT INTO `feature_flags_projects` (`id`,`name`,`description`,`is_active`,`is_global`,`expiration_date`,`created_at`,`updated_at`,`person_id`) SELECT `id`,`name`,`description`,`is_active`,`is_global`,`expiration_date`,`created_at`,`updated_at`,`person_id` FROM `feature_flags`;
+DROP TABLE `feature_flags`;
+ALTER TABLE `feature_flags_projects` RENAME TO `feature_flags`;
+CREATE UNIQUE INDEX `idx_feature_flags_project_name` ON `feature_flags`(`name`,`project_id`);
+ALTER TABLE `feature_flag_assignments` ADD COLUMN `variant` text;
+ALTER TABLE `feature_flag_assignments` ADD COLUMN `environment` text NOT NULL DEFAULT "production";
+ALTER TABLE `feature_flag_assignments` ADD COLUMN `kind` text NOT NULL DEFAULT "include";
+CREATE INDEX `idx_feature_flag_assignments_environment` ON `feature_flag_assignments`(`environment`);
+
+CREATE TABLE `projects` (`id` integer PRIMARY KEY AUTOINCREMENT,`key` text NOT NULL,`name` text NOT NULL,`description` text,`created_at` datetime,`updated_at` datetime,CONSTRAINT `uni_projects_key` UNIQUE (`key`));
+CREATE TABLE `feature_flag_rules` (`id` integer PRIMARY KEY AUTOINCREMENT,`feature_flag_id` integer NOT NULL,`position` integer NOT NULL,`attribute` text NOT NULL,`operator` text NOT NULL,`values` text,`result` numeric NOT NULL DEFAULT false,CONSTRAINT `fk_feature_flags_rules` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`));
+CREATE INDEX `idx_feature_flag_rules_feature_flag_id` ON `feature_flag_rules`(`feature_flag_id`);
+CREATE TABLE `feature_flag_variants` (`id` integer PRIMARY KEY AUTOINCREMENT,`feature_flag_id` integer NOT NULL,`position` integer NOT NULL,`name` text NOT NULL,`value` text,`weight` integer NOT NULL DEFAULT 0,CONSTRAINT `fk_feature_flags_variants` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`));
+CREATE INDEX `idx_feature_flag_variants_feature_flag_id` ON `feature_flag_variants`(`feature_flag_id`);
+CREATE TABLE `feature_flag_prerequisites` (`id` integer PRIMARY KEY AUTOINCREMENT,`feature_flag_id` integer NOT NULL,`prerequisite_id` integer NOT NULL,`position` integer NOT NULL,`variant` text,CONSTRAINT `fk_feature_flag_prerequisites_prerequisite` FOREIGN KEY (`prerequisite_id`) REFERENCES `feature_flags`(`id`),CONSTRAINT `fk_feature_flags_prerequisites` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`));
+CREATE UNIQUE INDEX `idx_feature_flag_prerequisite` ON `feature_flag_prerequisites`(`feature_flag_id`,`prerequisite_id`);
+CREATE INDEX `idx_feature_flag_prerequisites_prerequisite_id` ON `feature_flag_prerequisites`(`prerequisite_id`);
+CREATE TABLE `feature_flag_scheduled_changes` (`id` integer PRIMARY KEY AUTOINCREMENT,`feature_flag_id` integer NOT NULL,`operation` text NOT NULL,`is_global` numeric NOT NULL DEFAULT false,`rollout_percentage` integer NOT NULL DEFAULT 0,`execute_at` datetime NOT NULL,`timezone` text NOT NULL,`environment` text NOT NULL DEFAULT "production",`status` text NOT NULL,`applied_at` datetime,`error_message` text,`person_id` integer,`created_at` datetime,`updated_at` datetime,CONSTRAINT `fk_feature_flag_scheduled_changes_feature_flag` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`),CONSTRAINT `fk_feature_flag_scheduled_changes_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`));
+CREATE INDEX `idx_feature_flag_scheduled_changes_status` ON `feature_flag_scheduled_changes`(`status`);
+CREATE INDEX `idx_feature_flag_scheduled_changes_execute_at` ON `feature_flag_scheduled_changes`(`execute_at`);
+CREATE INDEX `idx_feature_flag_scheduled_changes_feature_flag_id` ON `feature_flag_scheduled_changes`(`feature_flag_id`);
+CREATE TABLE `assignment_groups` (`id` integer PRIMARY KEY AUTOINCREMENT,`name` text NOT NULL,`description` text,`created_at` datetime,`updated_at` datetime,CONSTRAINT `uni_assignment_groups_name` UNIQUE (`name`));
+CREATE TABLE `assignment_group_members` (`assignment_group_id` integer,`person_id` integer,PRIMARY KEY (`assignment_group_id`,`person_id`));
+CREATE TABLE `feature_flag_group_assignments` (`assignment_group_id` integer,`feature_flag_id` integer,PRIMARY KEY (`assignment_group_id`,`feature_flag_id`));
+CREATE TABLE `environments` (`id` integer PRIMARY KEY AUTOINCREMENT,`name` text NOT NULL,`position` integer NOT NULL DEFAULT 0,`created_at` datetime,CONSTRAINT `uni_environments_name` UNIQUE (`name`));
+CREATE TABLE `feature_flag_environments` (`feature_flag_id` integer,`environment` text,`is_active` numeric NOT NULL DEFAULT false,`is_global` numeric NOT NULL DEFAULT false,`updated_at` datetime,PRIMARY KEY (`feature_flag_id`,`environment`),CONSTRAINT `fk_feature_flags_environments` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`));
+CREATE TABLE `project_members` (`project_id` integer,`person_id` integer,`role` text NOT NULL,`created_at` datetime,PRIMARY KEY (`project_id`,`person_id`),CONSTRAINT `fk_project_members_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`));
+CREATE TABLE `project_api_keys` (`id` integer PRIMARY KEY AUTOINCREMENT,`project_id` integer NOT NULL,`name` text NOT NULL,`prefix` text NOT NULL,`key_hash` text NOT NULL,`last_used_at` datetime,`revoked_at` datetime,`created_at` datetime,CONSTRAINT `fk_project_api_keys_project` FOREIGN KEY (`project_id`) REFERENCES `projects`(`id`),CONSTRAINT `uni_project_api_keys_key_hash` UNIQUE (`key_hash`));
+CREATE INDEX `idx_project_api_keys_project_id` ON `project_api_keys`(`project_id`);
+CREATE TABLE `feature_flag_events` (`id` integer PRIMARY KEY AUTOINCREMENT,`type` text NOT NULL,`project_id` integer NOT NULL,`environment` text NOT NULL DEFAULT "production",`feature_flag_id` integer NOT NULL,`payload` text,`created_at` datetime);
+CREATE INDEX `idx_feature_flag_events_created_at` ON `feature_flag_events`(`created_at`);
+CREATE INDEX `idx_feature_flag_events_project_id` ON `feature_flag_events`(`project_id`);
+CREATE TABLE `webhooks` (`id` integer PRIMARY KEY AUTOINCREMENT,`project_id` integer NOT NULL,`url` text NOT NULL,`secret` text NOT NULL,`event_types` text,`is_active` numeric NOT NULL DEFAULT true,`created_at` datetime,`updated_at` datetime);
+CREATE INDEX `idx_webhooks_project_id` ON `webhooks`(`project_id`);
+CREATE TABLE `webhook_deliveries` (`id` integer PRIMARY KEY AUTOINCREMENT,`webhook_id` integer NOT NULL,`event_type` text NOT NULL,`payload` text,`status` text NOT NULL DEFAULT "pending",`attempts` integer NOT NULL DEFAULT 0,`next_attempt_at` datetime,`created_at` datetime,`updated_at` datetime,CONSTRAINT `fk_webhook_deliveries_webhook` FOREIGN KEY (`webhook_id`) REFERENCES `webhooks`(`id`));
+CREATE INDEX `idx_webhook_deliveries_next_attempt_at` ON `webhook_deliveries`(`next_attempt_at`);
+CREATE INDEX `idx_webhook_deliveries_status` ON `webhook_deliveries`(`status`);
+CREATE INDEX `idx_webhook_deliveries_webhook_id` ON `webhook_deliveries`(`webhook_id`);
+CREATE TABLE `webhook_attempts` (`id` integer PRIMARY KEY AUTOINCREMENT,`delivery_id` integer NOT NULL,`status_code` integer NOT NULL DEFAULT 0,`error` text,`duration_ms` integer NOT NULL DEFAULT 0,`created_at` datetime,CONSTRAINT `fk_webhook_deliveries_attempt_logs` FOREIGN KEY (`delivery_id`) REFERENCES `webhook_deliveries`(`id`));
+CREATE INDEX `idx_webhook_attempts_delivery_id` ON `webhook_attempts`(`delivery_id`);
+
+-- environments and the default project every flag belongs to when none is informed,
+-- the existing flags move to it
+INSERT INTO `environments` (`name`,`position`,`created_at`) VALUES ('development',0,CURRENT_TIMESTAMP),('staging',1,CURRENT_TIMESTAMP),('production',2,CURRENT_TIMESTAMP);
+INSERT INTO `projects` (`key`,`name`,`created_at`,`updated_at`) VALUES ('default','Default',CURRENT_TIMESTAMP,CURRENT_TIMESTAMP);
+UPDATE `feature_flags` SET `project_id` = (SELECT `id` FROM `projects` WHERE `key` = 'default') WHERE `project_id` = 0;
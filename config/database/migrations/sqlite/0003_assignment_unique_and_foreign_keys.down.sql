@@ -0,0 +1 @@
+DROP INDEX `idx_feature_flag_assignment`;
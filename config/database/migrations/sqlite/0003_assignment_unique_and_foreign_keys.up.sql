@@ -0,0 +1,6 @@
+-- a person is assigned once per flag and environment, the duplicates left by concurrent requests are removed first
+DELETE FROM `feature_flag_assignments` WHERE `id` NOT IN (SELECT MIN(`id`) FROM `feature_flag_assignments` GROUP BY `person_id`,`feature_flag_id`,`environment`);
+CREATE UNIQUE INDEX `idx_feature_flag_assignment` ON `feature_flag_assignments` (`person_id`,`feature_flag_id`,`environment`);
+
+-- SQLite can not add a foreign key to an existing table and does not enforce them by default,
+-- the foreign keys of the join tables are only added on the other databases
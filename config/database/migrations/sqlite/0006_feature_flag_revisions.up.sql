@@ -0,0 +1,3 @@
+-- every change of a flag keeps the flag as it was after it, revisions are never updated
+CREATE TABLE `feature_flag_revisions` (`id` integer PRIMARY KEY AUTOINCREMENT,`feature_flag_id` integer NOT NULL,`event` text NOT NULL,`environment` text NOT NULL DEFAULT "production",`snapshot` text NOT NULL,`person_id` integer,`created_at` datetime,CONSTRAINT `fk_feature_flag_revisions_feature_flag` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`),CONSTRAINT `fk_feature_flag_revisions_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`));
+CREATE INDEX `idx_feature_flag_revisions_feature_flag_id` ON `feature_flag_revisions`(`feature_flag_id`);
@@ -0,0 +1,5 @@
+CREATE TABLE `feature_flag_revisions_history` (`id` integer PRIMARY KEY AUTOINCREMENT,`feature_flag_id` integer NOT NULL,`event` text NOT NULL,`environment` text NOT NULL DEFAULT "production",`snapshot` text NOT NULL,`person_id` integer,`created_at` datetime,CONSTRAINT `fk_feature_flag_revisions_feature_flag` FOREIGN KEY (`feature_flag_id`) REFERENCES `feature_flags`(`id`),CONSTRAINT `fk_feature_flag_revisions_person` FOREIGN KEY (`person_id`) REFERENCES `person`(`id`));
+INSERT INTO `feature_flag_revisions_history` (`id`,`feature_flag_id`,`event`,`environment`,`snapshot`,`person_id`,`created_at`) SELECT `id`,`feature_flag_id`,`event`,`environment`,`snapshot`,`person_id`,`created_at` FROM `feature_flag_revisions` WHERE `feature_flag_id` IN (SELECT `id` FROM `feature_flags`);
+DROP TABLE `feature_flag_revisions`;
+ALTER TABLE `feature_flag_revisions_history` RENAME TO `feature_flag_revisions`;
+CREATE INDEX `idx_feature_flag_revisions_feature_flag_id` ON `feature_flag_revisions`(`feature_flag_id`);
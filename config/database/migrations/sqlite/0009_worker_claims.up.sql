@@ -0,0 +1,4 @@
+-- a worker claims the rows it is about to handle until locked_until, the other replicas skip them
+-- and a claim left by a stopped worker runs out
+ALTER TABLE `webhook_deliveries` ADD COLUMN `locked_until` datetime;
+ALTER TABLE `feature_flag_scheduled_changes` ADD COLUMN `locked_until` datetime;
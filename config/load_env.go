@@ -1,17 +1,34 @@
 package config
 
 import (
+	"ff/config/database"
 	"os"
+	"slices"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog"
 )
 
 type EnvConfig struct {
-	Port             string
-	ConnectionString string
+	Port                     string
+	GrpcPort                 string
+	DatabaseDriver           string
+	ConnectionString         string
+	ExpiryWorkerInterval     time.Duration
+	ExpiryWorkerMode         string
+	ScheduledChangesInterval time.Duration
+	StreamPollInterval       time.Duration
+	StreamRetention          time.Duration
+	WebhookInterval          time.Duration
 }
 
+const (
+	ExpiryModeDeactivate = "deactivate"
+	ExpiryModeWarn       = "warn"
+	ExpiryModeOff        = "off"
+)
+
 var AppConfig *EnvConfig
 
 func LoadAppConfig(logger *zerolog.Logger) {
@@ -25,8 +42,88 @@ func LoadAppConfig(logger *zerolog.Logger) {
 	envPort := os.Getenv("PORT")
 	envDBString := os.Getenv("DB_STRING")
 
+	// database driver, mysql unless another is informed
+	envDBDriver := os.Getenv("DB_DRIVER")
+	if envDBDriver == "" {
+		envDBDriver = database.DriverMySQL
+	}
+	if !slices.Contains(database.Drivers, envDBDriver) {
+		logger.Fatal().Msg("DB_DRIVER must be mysql, postgres or sqlite")
+	}
+
+	// gRPC server, served next to the REST API
+	envGrpcPort := os.Getenv("GRPC_PORT")
+	if envGrpcPort == "" {
+		envGrpcPort = "9697"
+	}
+
+	// expiry worker, runs every hour deactivating expired flags by default
+	expiryWorkerInterval := time.Hour
+	if envInterval := os.Getenv("EXPIRY_WORKER_INTERVAL"); envInterval != "" {
+		interval, err := time.ParseDuration(envInterval)
+		if err != nil || interval <= 0 {
+			logger.Fatal().Err(err).Msg("EXPIRY_WORKER_INTERVAL must be a positive duration (e.g. 30m, 1h)")
+		}
+		expiryWorkerInterval = interval
+	}
+
+	expiryWorkerMode := os.Getenv("EXPIRY_WORKER_MODE")
+	if expiryWorkerMode == "" {
+		expiryWorkerMode = ExpiryModeDeactivate
+	}
+	if expiryWorkerMode != ExpiryModeDeactivate && expiryWorkerMode != ExpiryModeWarn && expiryWorkerMode != ExpiryModeOff {
+		logger.Fatal().Msg("EXPIRY_WORKER_MODE must be deactivate, warn or off")
+	}
+
+	// scheduled changes worker, checks for due changes every minute by default
+	scheduledChangesInterval := time.Minute
+	if envInterval := os.Getenv("SCHEDULED_CHANGES_INTERVAL"); envInterval != "" {
+		interval, err := time.ParseDuration(envInterval)
+		if err != nil || interval <= 0 {
+			logger.Fatal().Err(err).Msg("SCHEDULED_CHANGES_INTERVAL must be a positive duration (e.g. 30s, 1m)")
+		}
+		scheduledChangesInterval = interval
+	}
+
+	// flag events stream, new events are looked up every second and kept for a day
+	streamPollInterval := time.Second
+	if envInterval := os.Getenv("STREAM_POLL_INTERVAL"); envInterval != "" {
+		interval, err := time.ParseDuration(envInterval)
+		if err != nil || interval <= 0 {
+			logger.Fatal().Err(err).Msg("STREAM_POLL_INTERVAL must be a positive duration (e.g. 500ms, 1s)")
+		}
+		streamPollInterval = interval
+	}
+
+	streamRetention := 24 * time.Hour
+	if envRetention := os.Getenv("STREAM_RETENTION"); envRetention != "" {
+		retention, err := time.ParseDuration(envRetention)
+		if err != nil || retention <= 0 {
+			logger.Fatal().Err(err).Msg("STREAM_RETENTION must be a positive duration (e.g. 12h, 24h)")
+		}
+		streamRetention = retention
+	}
+
+	// webhooks worker, due deliveries are sent every 10 seconds
+	webhookInterval := 10 * time.Second
+	if envInterval := os.Getenv("WEBHOOK_INTERVAL"); envInterval != "" {
+		interval, err := time.ParseDuration(envInterval)
+		if err != nil || interval <= 0 {
+			logger.Fatal().Err(err).Msg("WEBHOOK_INTERVAL must be a positive duration (e.g. 5s, 1m)")
+		}
+		webhookInterval = interval
+	}
+
 	AppConfig = &EnvConfig{
-		Port:             envPort,
-		ConnectionString: envDBString,
+		Port:                     envPort,
+		GrpcPort:                 envGrpcPort,
+		DatabaseDriver:           envDBDriver,
+		ConnectionString:         envDBString,
+		ExpiryWorkerInterval:     expiryWorkerInterval,
+		ExpiryWorkerMode:         expiryWorkerMode,
+		ScheduledChangesInterval: scheduledChangesInterval,
+		StreamPollInterval:       streamPollInterval,
+		StreamRetention:          streamRetention,
+		WebhookInterval:          webhookInterval,
 	}
 }
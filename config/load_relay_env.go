@@ -0,0 +1,94 @@
+package config
+
+import (
+	"ff/config/database"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog"
+)
+
+const (
+	RelaySourceAPI      = "api"
+	RelaySourceDatabase = "db"
+)
+
+type RelayEnvConfig struct {
+	Port   string
+	Source string
+	// UpstreamURL and ApiKey reach the main API when the source is the api
+	UpstreamURL string
+	ApiKey      string
+	// DatabaseDriver and ConnectionString reach the database when the source is the db
+	DatabaseDriver   string
+	ConnectionString string
+	Project          string
+	Environment      string
+	SyncInterval     time.Duration
+}
+
+var RelayConfig *RelayEnvConfig
+
+func LoadRelayConfig(logger *zerolog.Logger) {
+	logger.Info().Msg("Loading Relay Configurations...")
+
+	// a sidecar is usually configured from its environment only, the .env file is optional
+	if err := godotenv.Load(); err != nil {
+		logger.Info().Msg("No .env file, reading the environment only")
+	}
+
+	envPort := os.Getenv("RELAY_PORT")
+	if envPort == "" {
+		envPort = "9698"
+	}
+
+	source := os.Getenv("RELAY_SOURCE")
+	if source == "" {
+		source = RelaySourceAPI
+	}
+	if source != RelaySourceAPI && source != RelaySourceDatabase {
+		logger.Fatal().Msg("RELAY_SOURCE must be api or db")
+	}
+
+	upstreamURL := os.Getenv("RELAY_UPSTREAM_URL")
+	if source == RelaySourceAPI && upstreamURL == "" {
+		logger.Fatal().Msg("RELAY_UPSTREAM_URL is required when RELAY_SOURCE is api")
+	}
+
+	envDBString := os.Getenv("DB_STRING")
+	if source == RelaySourceDatabase && envDBString == "" {
+		logger.Fatal().Msg("DB_STRING is required when RELAY_SOURCE is db")
+	}
+
+	envDBDriver := os.Getenv("DB_DRIVER")
+	if envDBDriver == "" {
+		envDBDriver = database.DriverMySQL
+	}
+	if !slices.Contains(database.Drivers, envDBDriver) {
+		logger.Fatal().Msg("DB_DRIVER must be mysql, postgres or sqlite")
+	}
+
+	// the snapshot is synced every 10 seconds, an unchanged one costs a 304 or a single query
+	syncInterval := 10 * time.Second
+	if envInterval := os.Getenv("RELAY_SYNC_INTERVAL"); envInterval != "" {
+		interval, err := time.ParseDuration(envInterval)
+		if err != nil || interval <= 0 {
+			logger.Fatal().Err(err).Msg("RELAY_SYNC_INTERVAL must be a positive duration (e.g. 5s, 1m)")
+		}
+		syncInterval = interval
+	}
+
+	RelayConfig = &RelayEnvConfig{
+		Port:             envPort,
+		Source:           source,
+		UpstreamURL:      upstreamURL,
+		ApiKey:           os.Getenv("RELAY_API_KEY"),
+		DatabaseDriver:   envDBDriver,
+		ConnectionString: envDBString,
+		Project:          os.Getenv("RELAY_PROJECT"),
+		Environment:      os.Getenv("RELAY_ENVIRONMENT"),
+		SyncInterval:     syncInterval,
+	}
+}
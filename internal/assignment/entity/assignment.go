@@ -2,9 +2,20 @@ package entity
 
 import "errors"
 
+const (
+	KindInclude = "include"
+	KindExclude = "exclude"
+)
+
 type Assignment struct {
-	PersonID      uint `json:"personId"`
-	FeatureFlagID uint `json:"featureFlagId"`
+	PersonID      uint   `json:"personId"`
+	FeatureFlagID uint   `json:"featureFlagId"`
+	Variant       string `json:"variant"`
+	// Kind is include (the default) or exclude, an exclusion turns the flag off for the person
+	Kind        string `json:"kind"`
+	Environment string `json:"-"`
+	// ProjectID is the project of the request, the flag has to belong to it
+	ProjectID uint `json:"-"`
 }
 
 func (ff *Assignment) Validate() error {
@@ -16,5 +27,9 @@ func (ff *Assignment) Validate() error {
 		return errors.New("feature flag id is required")
 	}
 
+	if ff.Kind != "" && ff.Kind != KindInclude && ff.Kind != KindExclude {
+		return errors.New("kind must be include or exclude")
+	}
+
 	return nil
 }
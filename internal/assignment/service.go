@@ -6,18 +6,40 @@ import (
 
 	assignmentEntity "ff/internal/assignment/entity"
 	"ff/internal/db/model"
+	streamEntity "ff/internal/stream/entity"
+	webhookEntity "ff/internal/webhook/entity"
 
 	"github.com/rs/zerolog"
 )
 
 type AssignmentRepository interface {
 	ApplyAssignment(assignment model.Assignment) error
-	GetAssignmentsByPersonAndFeatureFlagId(personId, featureFlagId uint) (model.Assignment, error)
+	GetAssignmentsByPersonAndFeatureFlagId(personId, featureFlagId uint, environment string) (model.Assignment, error)
 	DeleteAssignment(assignment model.Assignment) error
+	SwitchAssignment(assignment model.Assignment) error
+	GetFeatureFlag(filters model.FeatureFlagFilters, pagination model.Pagination) ([]model.FeatureFlag, int64, error)
+}
+
+// Publisher is told about every change of a flag, assignments only change the flag in their environment
+type Publisher interface {
+	PublishFeatureFlagChange(eventType string, featureFlagId uint, environment string)
+}
+
+// Notifier is told about every assignment change with the assignment before and after it
+type Notifier interface {
+	NotifyChange(change webhookEntity.Change)
+}
+
+// Recorder keeps a revision of the flag after every assignment change, with the person who made it
+type Recorder interface {
+	RecordRevision(event string, featureFlagId uint, environment string, personId uint)
 }
 
 type AssignmentService struct {
 	Repository AssignmentRepository
+	Publisher  Publisher
+	Notifier   Notifier
+	Recorder   Recorder
 	Logger     *zerolog.Logger
 }
 
@@ -35,20 +57,58 @@ func (as *AssignmentService) ApplyAssignment(request assignmentEntity.Assignment
 		return errors.New(err.Error())
 	}
 
-	assignment, err := as.Repository.GetAssignmentsByPersonAndFeatureFlagId(request.PersonID, request.FeatureFlagID)
+	if request.Environment == "" {
+		request.Environment = model.DefaultEnvironment
+	}
+
+	if request.Kind == "" {
+		request.Kind = assignmentEntity.KindInclude
+	}
+
+	// an excluded person is never served a variant
+	if request.Kind == assignmentEntity.KindExclude {
+		request.Variant = ""
+	}
+
+	featureFlag, err := as.findFeatureFlag(request.FeatureFlagID, request.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	if request.Variant != "" && !hasVariant(featureFlag, request.Variant) {
+		return errors.New(fmt.Sprintf("variant %s is not a variant of the feature flag %d", request.Variant, request.FeatureFlagID))
+	}
+
+	assignment, err := as.Repository.GetAssignmentsByPersonAndFeatureFlagId(request.PersonID, request.FeatureFlagID, request.Environment)
 	if err != nil {
 		return err
 	}
 
+	// a person is either included or excluded, the assignment has to be removed to switch
+	if assignment.ID != 0 && assignment.Kind == model.AssignmentExclude {
+		return errors.New(fmt.Sprintf("Person %d is already excluded from the feature flag %d", request.PersonID, request.FeatureFlagID))
+	}
+
 	if assignment.ID != 0 {
 		return errors.New(fmt.Sprintf("Person %d is already assigned to the feature flag %d", request.PersonID, request.FeatureFlagID))
 	}
 
-	// TODO: validate ids against DB
-	return as.Repository.ApplyAssignment(model.Assignment{
+	// the person id is checked by the foreign key of the assignment
+	if err := as.Repository.ApplyAssignment(model.Assignment{
 		PersonID:      request.PersonID,
 		FeatureFlagID: request.FeatureFlagID,
-	})
+		Variant:       request.Variant,
+		Kind:          request.Kind,
+		Environment:   request.Environment,
+	}); err != nil {
+		return err
+	}
+
+	as.publish(streamEntity.EventAssignmentApplied, request.FeatureFlagID, request.Environment)
+	as.notify(webhookEntity.EventAssignmentApplied, request, nil, &request)
+	as.record(webhookEntity.EventAssignmentApplied, request, personId)
+
+	return nil
 }
 
 func (as *AssignmentService) DeleteAssignment(request assignmentEntity.Assignment, personId uint) error {
@@ -58,7 +118,15 @@ func (as *AssignmentService) DeleteAssignment(request assignmentEntity.Assignmen
 		return errors.New(err.Error())
 	}
 
-	assignment, err := as.Repository.GetAssignmentsByPersonAndFeatureFlagId(request.PersonID, request.FeatureFlagID)
+	if request.Environment == "" {
+		request.Environment = model.DefaultEnvironment
+	}
+
+	if _, err := as.findFeatureFlag(request.FeatureFlagID, request.ProjectID); err != nil {
+		return err
+	}
+
+	assignment, err := as.Repository.GetAssignmentsByPersonAndFeatureFlagId(request.PersonID, request.FeatureFlagID, request.Environment)
 	if err != nil {
 		return err
 	}
@@ -67,8 +135,142 @@ func (as *AssignmentService) DeleteAssignment(request assignmentEntity.Assignmen
 		return errors.New(fmt.Sprintf("Person %d is not assigned to the feature flag %d", request.PersonID, request.FeatureFlagID))
 	}
 
-	return as.Repository.DeleteAssignment(model.Assignment{
+	if err := as.Repository.DeleteAssignment(model.Assignment{
 		PersonID:      request.PersonID,
 		FeatureFlagID: request.FeatureFlagID,
+		Environment:   request.Environment,
+	}); err != nil {
+		return err
+	}
+
+	as.publish(streamEntity.EventAssignmentDeleted, request.FeatureFlagID, request.Environment)
+	as.notify(webhookEntity.EventAssignmentDeleted, request, assignmentFromModel(assignment), nil)
+	as.record(webhookEntity.EventAssignmentDeleted, request, personId)
+
+	return nil
+}
+
+// CycleAssignment moves the person to the next state of the flag and returns the new kind, empty when the person is not assigned.
+// The states are not assigned, included and excluded; a global flag already includes everyone so it skips the include step.
+func (as *AssignmentService) CycleAssignment(request assignmentEntity.Assignment, personId uint) (string, error) {
+	as.Logger.Info().Msg("Cycle assignment")
+
+	if err := request.Validate(); err != nil {
+		return "", errors.New(err.Error())
+	}
+
+	if request.Environment == "" {
+		request.Environment = model.DefaultEnvironment
+	}
+
+	featureFlag, err := as.findFeatureFlag(request.FeatureFlagID, request.ProjectID)
+	if err != nil {
+		return "", err
+	}
+
+	assignment, err := as.Repository.GetAssignmentsByPersonAndFeatureFlagId(request.PersonID, request.FeatureFlagID, request.Environment)
+	if err != nil {
+		return "", err
+	}
+
+	var before *assignmentEntity.Assignment
+	if assignment.ID != 0 {
+		before = assignmentFromModel(assignment)
+	}
+
+	switch {
+	case assignment.ID == 0 && !featureFlag.IsGlobal:
+		request.Kind = assignmentEntity.KindInclude
+	case assignment.ID == 0 || assignment.Kind != model.AssignmentExclude:
+		request.Kind = assignmentEntity.KindExclude
+	default:
+		request.Kind = ""
+	}
+	request.Variant = ""
+
+	if err := as.Repository.SwitchAssignment(model.Assignment{
+		PersonID:      request.PersonID,
+		FeatureFlagID: request.FeatureFlagID,
+		Kind:          request.Kind,
+		Environment:   request.Environment,
+	}); err != nil {
+		return "", err
+	}
+
+	if request.Kind == "" {
+		as.publish(streamEntity.EventAssignmentDeleted, request.FeatureFlagID, request.Environment)
+		as.notify(webhookEntity.EventAssignmentDeleted, request, before, nil)
+		as.record(webhookEntity.EventAssignmentDeleted, request, personId)
+		return "", nil
+	}
+
+	as.publish(streamEntity.EventAssignmentApplied, request.FeatureFlagID, request.Environment)
+	as.notify(webhookEntity.EventAssignmentApplied, request, before, &request)
+	as.record(webhookEntity.EventAssignmentApplied, request, personId)
+
+	return request.Kind, nil
+}
+
+// findFeatureFlag reads the flag in the project of the request, a flag of another project is not found
+func (as *AssignmentService) findFeatureFlag(featureFlagId uint, projectId uint) (model.FeatureFlag, error) {
+	featureFlags, _, err := as.Repository.GetFeatureFlag(model.FeatureFlagFilters{
+		ID:        featureFlagId,
+		ProjectID: projectId,
+	}, model.Pagination{
+		Limit: 1,
+		Page:  1,
 	})
+	if err != nil {
+		return model.FeatureFlag{}, err
+	}
+
+	if len(featureFlags) == 0 {
+		return model.FeatureFlag{}, errors.New("feature flag not found")
+	}
+
+	return featureFlags[0], nil
+}
+
+func hasVariant(featureFlag model.FeatureFlag, name string) bool {
+	for _, variant := range featureFlag.Variants {
+		if variant.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (as *AssignmentService) publish(eventType string, featureFlagId uint, environment string) {
+	if as.Publisher != nil {
+		as.Publisher.PublishFeatureFlagChange(eventType, featureFlagId, environment)
+	}
+}
+
+func (as *AssignmentService) notify(event string, request assignmentEntity.Assignment, before *assignmentEntity.Assignment, after *assignmentEntity.Assignment) {
+	if as.Notifier != nil {
+		as.Notifier.NotifyChange(webhookEntity.Change{
+			Event:         event,
+			Environment:   request.Environment,
+			FeatureFlagID: request.FeatureFlagID,
+			Before:        before,
+			After:         after,
+		})
+	}
+}
+
+func (as *AssignmentService) record(event string, request assignmentEntity.Assignment, personId uint) {
+	if as.Recorder != nil {
+		as.Recorder.RecordRevision(event, request.FeatureFlagID, request.Environment, personId)
+	}
+}
+
+func assignmentFromModel(assignment model.Assignment) *assignmentEntity.Assignment {
+	return &assignmentEntity.Assignment{
+		PersonID:      assignment.PersonID,
+		FeatureFlagID: assignment.FeatureFlagID,
+		Variant:       assignment.Variant,
+		Kind:          assignment.Kind,
+		Environment:   assignment.Environment,
+	}
 }
@@ -0,0 +1,106 @@
+package assignment
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	assignmentEntity "ff/internal/assignment/entity"
+	"ff/internal/db/model"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock of AssignmentRepository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) ApplyAssignment(assignment model.Assignment) error {
+	args := m.Called(assignment)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetAssignmentsByPersonAndFeatureFlagId(personId, featureFlagId uint, environment string) (model.Assignment, error) {
+	args := m.Called(personId, featureFlagId, environment)
+	return args.Get(0).(model.Assignment), args.Error(1)
+}
+
+func (m *MockRepository) DeleteAssignment(assignment model.Assignment) error {
+	args := m.Called(assignment)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SwitchAssignment(assignment model.Assignment) error {
+	args := m.Called(assignment)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetFeatureFlag(filters model.FeatureFlagFilters, pagination model.Pagination) ([]model.FeatureFlag, int64, error) {
+	args := m.Called(filters, pagination)
+	return args.Get(0).([]model.FeatureFlag), int64(args.Get(1).(int)), args.Error(2)
+}
+
+func loadTestService() (*AssignmentService, *MockRepository) {
+	mockRepo := new(MockRepository)
+	logger := zerolog.New(os.Stdout)
+
+	return LoadService(mockRepo, &logger), mockRepo
+}
+
+// Cycle Assignment Tests Cases
+func TestCycleAssignment(t *testing.T) {
+	tests := []struct {
+		name     string
+		isGlobal bool
+		stored   model.Assignment
+		expected string
+	}{
+		{name: "Not assigned is included", expected: assignmentEntity.KindInclude},
+		{name: "Included is excluded", stored: model.Assignment{ID: 3, Kind: model.AssignmentInclude}, expected: assignmentEntity.KindExclude},
+		{name: "Excluded is removed", stored: model.Assignment{ID: 3, Kind: model.AssignmentExclude}, expected: ""},
+		{name: "Not assigned to a global flag is excluded", isGlobal: true, expected: assignmentEntity.KindExclude},
+		{name: "Excluded from a global flag is removed", isGlobal: true, stored: model.Assignment{ID: 3, Kind: model.AssignmentExclude}, expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service, mockRepo := loadTestService()
+
+			mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 1, ProjectID: 1}, model.Pagination{Limit: 1, Page: 1}).Return([]model.FeatureFlag{{ID: 1, IsGlobal: tc.isGlobal}}, 1, nil)
+			mockRepo.On("GetAssignmentsByPersonAndFeatureFlagId", uint(2), uint(1), model.DefaultEnvironment).Return(tc.stored, nil)
+			mockRepo.On("SwitchAssignment", model.Assignment{PersonID: 2, FeatureFlagID: 1, Kind: tc.expected, Environment: model.DefaultEnvironment}).Return(nil)
+
+			kind, err := service.CycleAssignment(assignmentEntity.Assignment{PersonID: 2, FeatureFlagID: 1, ProjectID: 1}, 9)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, kind)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("A failed switch is returned", func(t *testing.T) {
+		service, mockRepo := loadTestService()
+
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 1, ProjectID: 1}, model.Pagination{Limit: 1, Page: 1}).Return([]model.FeatureFlag{{ID: 1}}, 1, nil)
+		mockRepo.On("GetAssignmentsByPersonAndFeatureFlagId", uint(2), uint(1), model.DefaultEnvironment).Return(model.Assignment{}, nil)
+		mockRepo.On("SwitchAssignment", mock.Anything).Return(errors.New("error when switching assignment"))
+
+		_, err := service.CycleAssignment(assignmentEntity.Assignment{PersonID: 2, FeatureFlagID: 1, ProjectID: 1}, 9)
+
+		assert.EqualError(t, err, "error when switching assignment")
+	})
+
+	t.Run("Feature flag of another project", func(t *testing.T) {
+		service, mockRepo := loadTestService()
+
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 1, ProjectID: 2}, model.Pagination{Limit: 1, Page: 1}).Return([]model.FeatureFlag{}, 0, nil)
+
+		_, err := service.CycleAssignment(assignmentEntity.Assignment{PersonID: 2, FeatureFlagID: 1, ProjectID: 2}, 9)
+
+		assert.EqualError(t, err, "feature flag not found")
+		mockRepo.AssertNotCalled(t, "SwitchAssignment", mock.Anything)
+	})
+}
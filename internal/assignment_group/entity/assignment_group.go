@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"errors"
+	personEntity "ff/internal/person/entity"
+)
+
+type AssignmentGroup struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (ag *AssignmentGroup) Validate() error {
+	if ag.Name == "" {
+		return errors.New("Name|Name is required")
+	}
+
+	if len(ag.Name) > 255 {
+		return errors.New("Name|Name must have at most 255 characters")
+	}
+
+	return nil
+}
+
+type AssignmentGroupMembers struct {
+	PersonIDs []uint `json:"personIds"`
+}
+
+func (agm *AssignmentGroupMembers) Validate() error {
+	if len(agm.PersonIDs) == 0 {
+		return errors.New("PersonIDs|At least one person id is required")
+	}
+
+	for _, personId := range agm.PersonIDs {
+		if personId == 0 {
+			return errors.New("PersonIDs|Person id is required")
+		}
+	}
+
+	return nil
+}
+
+type GroupAssignment struct {
+	FeatureFlagID uint `json:"featureFlagId"`
+}
+
+func (ga *GroupAssignment) Validate() error {
+	if ga.FeatureFlagID == 0 {
+		return errors.New("feature flag id is required")
+	}
+
+	return nil
+}
+
+type AssignmentGroupFeatureFlagResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type AssignmentGroupResponse struct {
+	ID           uint                                 `json:"id"`
+	Name         string                               `json:"name"`
+	Description  string                               `json:"description"`
+	People       []personEntity.PersonResponse        `json:"people"`
+	FeatureFlags []AssignmentGroupFeatureFlagResponse `json:"featureFlags"`
+	CreatedAt    string                               `json:"createdAt"`
+	UpdatedAt    string                               `json:"updatedAt"`
+}
+
+type AssignmentGroupFilters struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
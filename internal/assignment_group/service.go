@@ -0,0 +1,274 @@
+package assignmentgroup
+
+import (
+	"errors"
+
+	assignmentGroupEntity "ff/internal/assignment_group/entity"
+	"ff/internal/db/model"
+	personEntity "ff/internal/person/entity"
+	streamEntity "ff/internal/stream/entity"
+
+	"github.com/rs/zerolog"
+)
+
+type AssignmentGroupRepository interface {
+	AddAssignmentGroup(assignmentGroup model.AssignmentGroup) error
+	GetAssignmentGroups(filters model.AssignmentGroupFilters, pagination model.Pagination) ([]model.AssignmentGroup, int64, error)
+	UpdateAssignmentGroupById(id uint, assignmentGroup model.UpdateAssignmentGroup) error
+	DeleteAssignmentGroup(id uint) error
+	AddAssignmentGroupMembers(assignmentGroupId uint, personIds []uint) error
+	RemoveAssignmentGroupMember(assignmentGroupId uint, personId uint) error
+	ApplyGroupAssignment(assignmentGroupId uint, featureFlagId uint) error
+	DeleteGroupAssignment(assignmentGroupId uint, featureFlagId uint) error
+}
+
+// Publisher is told about every change of a flag, the members of a group are part of
+// every flag assigned to it
+type Publisher interface {
+	PublishFeatureFlagChange(eventType string, featureFlagId uint, environment string)
+}
+
+type AssignmentGroupService struct {
+	Repository AssignmentGroupRepository
+	Publisher  Publisher
+	Logger     *zerolog.Logger
+}
+
+func LoadService(r AssignmentGroupRepository, l *zerolog.Logger) *AssignmentGroupService {
+	return &AssignmentGroupService{
+		Logger:     l,
+		Repository: r,
+	}
+}
+
+func (ags *AssignmentGroupService) CreateAssignmentGroup(request assignmentGroupEntity.AssignmentGroup, personId uint) error {
+	ags.Logger.Info().Msg("Creating a new Assignment Group")
+
+	if err := request.Validate(); err != nil {
+		return errors.New(err.Error())
+	}
+
+	_, totalCount, err := ags.Repository.GetAssignmentGroups(model.AssignmentGroupFilters{
+		Name: request.Name,
+	}, model.Pagination{
+		Limit: 1,
+		Page:  1,
+	})
+	if err != nil {
+		return err
+	}
+
+	if totalCount > 0 {
+		return errors.New("assignment group already exists")
+	}
+
+	return ags.Repository.AddAssignmentGroup(model.AssignmentGroup{
+		Name:        request.Name,
+		Description: request.Description,
+	})
+}
+
+func (ags *AssignmentGroupService) GetAssignmentGroups(pagination model.Pagination, filters assignmentGroupEntity.AssignmentGroupFilters) ([]assignmentGroupEntity.AssignmentGroupResponse, int64, error) {
+	ags.Logger.Info().Msg("Getting Assignment Groups")
+
+	assignmentGroups, totalCount, err := ags.Repository.GetAssignmentGroups(model.AssignmentGroupFilters{
+		ID:   filters.ID,
+		Name: filters.Name,
+	}, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var assignmentGroupResponses []assignmentGroupEntity.AssignmentGroupResponse
+	for _, agDB := range assignmentGroups {
+		response := assignmentGroupEntity.AssignmentGroupResponse{
+			ID:           agDB.ID,
+			Name:         agDB.Name,
+			Description:  agDB.Description,
+			People:       []personEntity.PersonResponse{},
+			FeatureFlags: []assignmentGroupEntity.AssignmentGroupFeatureFlagResponse{},
+			CreatedAt:    agDB.CreatedAt.Format("2006-01-02 15:04:05"),
+			UpdatedAt:    agDB.UpdatedAt.Format("2006-01-02 15:04:05"),
+		}
+
+		for _, person := range agDB.People {
+			response.People = append(response.People, personEntity.PersonResponse{
+				ID:    person.ID,
+				Name:  person.Name,
+				Email: person.Email,
+			})
+		}
+
+		for _, featureFlag := range agDB.FeatureFlags {
+			response.FeatureFlags = append(response.FeatureFlags, assignmentGroupEntity.AssignmentGroupFeatureFlagResponse{
+				ID:   featureFlag.ID,
+				Name: featureFlag.Name,
+			})
+		}
+
+		assignmentGroupResponses = append(assignmentGroupResponses, response)
+	}
+
+	return assignmentGroupResponses, totalCount, nil
+}
+
+func (ags *AssignmentGroupService) UpdateAssignmentGroupById(id uint, request assignmentGroupEntity.AssignmentGroup) error {
+	ags.Logger.Info().Msg("Updating an Assignment Group")
+
+	if err := request.Validate(); err != nil {
+		return errors.New(err.Error())
+	}
+
+	if err := ags.checkAssignmentGroupExists(id); err != nil {
+		return err
+	}
+
+	// the new name can not be taken by another group
+	assignmentGroups, _, err := ags.Repository.GetAssignmentGroups(model.AssignmentGroupFilters{
+		Name: request.Name,
+	}, model.Pagination{
+		Limit: 1,
+		Page:  1,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(assignmentGroups) > 0 && assignmentGroups[0].ID != id {
+		return errors.New("assignment group already exists")
+	}
+
+	return ags.Repository.UpdateAssignmentGroupById(id, model.UpdateAssignmentGroup{
+		Name:        request.Name,
+		Description: request.Description,
+	})
+}
+
+func (ags *AssignmentGroupService) DeleteAssignmentGroup(id uint) error {
+	ags.Logger.Info().Msg("Deleting an Assignment Group")
+
+	assignmentGroup, err := ags.getAssignmentGroup(id)
+	if err != nil {
+		return err
+	}
+
+	if err := ags.Repository.DeleteAssignmentGroup(id); err != nil {
+		return err
+	}
+
+	ags.publishFeatureFlags(assignmentGroup)
+
+	return nil
+}
+
+func (ags *AssignmentGroupService) AddAssignmentGroupMembers(id uint, request assignmentGroupEntity.AssignmentGroupMembers) error {
+	ags.Logger.Info().Msg("Adding Assignment Group members")
+
+	if err := request.Validate(); err != nil {
+		return errors.New(err.Error())
+	}
+
+	assignmentGroup, err := ags.getAssignmentGroup(id)
+	if err != nil {
+		return err
+	}
+
+	if err := ags.Repository.AddAssignmentGroupMembers(id, request.PersonIDs); err != nil {
+		return err
+	}
+
+	ags.publishFeatureFlags(assignmentGroup)
+
+	return nil
+}
+
+func (ags *AssignmentGroupService) RemoveAssignmentGroupMember(id uint, personId uint) error {
+	ags.Logger.Info().Msg("Removing an Assignment Group member")
+
+	assignmentGroup, err := ags.getAssignmentGroup(id)
+	if err != nil {
+		return err
+	}
+
+	if err := ags.Repository.RemoveAssignmentGroupMember(id, personId); err != nil {
+		return err
+	}
+
+	ags.publishFeatureFlags(assignmentGroup)
+
+	return nil
+}
+
+// ApplyGroupAssignment enables the feature flag for every current and future member of the group
+func (ags *AssignmentGroupService) ApplyGroupAssignment(id uint, request assignmentGroupEntity.GroupAssignment) error {
+	ags.Logger.Info().Msg("Applying group assignment")
+
+	if err := request.Validate(); err != nil {
+		return errors.New(err.Error())
+	}
+
+	if err := ags.checkAssignmentGroupExists(id); err != nil {
+		return err
+	}
+
+	// TODO: validate the feature flag id against DB
+	if err := ags.Repository.ApplyGroupAssignment(id, request.FeatureFlagID); err != nil {
+		return err
+	}
+
+	ags.publish(request.FeatureFlagID)
+
+	return nil
+}
+
+func (ags *AssignmentGroupService) DeleteGroupAssignment(id uint, featureFlagId uint) error {
+	ags.Logger.Info().Msg("Deleting group assignment")
+
+	if err := ags.checkAssignmentGroupExists(id); err != nil {
+		return err
+	}
+
+	if err := ags.Repository.DeleteGroupAssignment(id, featureFlagId); err != nil {
+		return err
+	}
+
+	ags.publish(featureFlagId)
+
+	return nil
+}
+
+func (ags *AssignmentGroupService) checkAssignmentGroupExists(id uint) error {
+	_, err := ags.getAssignmentGroup(id)
+	return err
+}
+
+func (ags *AssignmentGroupService) getAssignmentGroup(id uint) (model.AssignmentGroup, error) {
+	assignmentGroups, totalCount, err := ags.Repository.GetAssignmentGroups(model.AssignmentGroupFilters{
+		ID: id,
+	}, model.Pagination{
+		Limit: 1,
+		Page:  1,
+	})
+	if err != nil {
+		return model.AssignmentGroup{}, err
+	}
+
+	if totalCount == 0 || len(assignmentGroups) == 0 {
+		return model.AssignmentGroup{}, errors.New("assignment group not found")
+	}
+
+	return assignmentGroups[0], nil
+}
+
+func (ags *AssignmentGroupService) publishFeatureFlags(assignmentGroup model.AssignmentGroup) {
+	for _, featureFlag := range assignmentGroup.FeatureFlags {
+		ags.publish(featureFlag.ID)
+	}
+}
+
+// publish sends the flag to every environment, segments are not bound to one
+func (ags *AssignmentGroupService) publish(featureFlagId uint) {
+	if ags.Publisher != nil {
+		ags.Publisher.PublishFeatureFlagChange(streamEntity.EventFeatureFlagUpdated, featureFlagId, "")
+	}
+}
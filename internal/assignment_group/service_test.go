@@ -0,0 +1,184 @@
+package assignmentgroup
+
+import (
+	"os"
+	"testing"
+
+	assignmentGroupEntity "ff/internal/assignment_group/entity"
+	"ff/internal/db/model"
+	streamEntity "ff/internal/stream/entity"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock of AssignmentGroupRepository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) AddAssignmentGroup(assignmentGroup model.AssignmentGroup) error {
+	args := m.Called(assignmentGroup)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetAssignmentGroups(filters model.AssignmentGroupFilters, pagination model.Pagination) ([]model.AssignmentGroup, int64, error) {
+	args := m.Called(filters, pagination)
+	return args.Get(0).([]model.AssignmentGroup), int64(args.Get(1).(int)), args.Error(2)
+}
+
+func (m *MockRepository) UpdateAssignmentGroupById(id uint, assignmentGroup model.UpdateAssignmentGroup) error {
+	args := m.Called(id, assignmentGroup)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteAssignmentGroup(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AddAssignmentGroupMembers(assignmentGroupId uint, personIds []uint) error {
+	args := m.Called(assignmentGroupId, personIds)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RemoveAssignmentGroupMember(assignmentGroupId uint, personId uint) error {
+	args := m.Called(assignmentGroupId, personId)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ApplyGroupAssignment(assignmentGroupId uint, featureFlagId uint) error {
+	args := m.Called(assignmentGroupId, featureFlagId)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteGroupAssignment(assignmentGroupId uint, featureFlagId uint) error {
+	args := m.Called(assignmentGroupId, featureFlagId)
+	return args.Error(0)
+}
+
+// MockPublisher is a mock of the stream service
+type MockPublisher struct {
+	mock.Mock
+}
+
+func (m *MockPublisher) PublishFeatureFlagChange(eventType string, featureFlagId uint, environment string) {
+	m.Called(eventType, featureFlagId, environment)
+}
+
+var onePage = model.Pagination{Limit: 1, Page: 1}
+
+// Create Assignment Group Tests Cases
+func TestCreateAssignmentGroup(t *testing.T) {
+	t.Run("Successfully create an assignment group", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetAssignmentGroups", model.AssignmentGroupFilters{Name: "QA team"}, onePage).Return([]model.AssignmentGroup{}, 0, nil)
+		mockRepo.On("AddAssignmentGroup", model.AssignmentGroup{Name: "QA team", Description: "Testers"}).Return(nil)
+
+		err := service.CreateAssignmentGroup(assignmentGroupEntity.AssignmentGroup{Name: "QA team", Description: "Testers"}, 1)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Name is required", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		err := service.CreateAssignmentGroup(assignmentGroupEntity.AssignmentGroup{}, 1)
+
+		assert.EqualError(t, err, "Name|Name is required")
+	})
+
+	t.Run("Assignment group already exists", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetAssignmentGroups", model.AssignmentGroupFilters{Name: "QA team"}, onePage).Return([]model.AssignmentGroup{{ID: 1, Name: "QA team"}}, 1, nil)
+
+		err := service.CreateAssignmentGroup(assignmentGroupEntity.AssignmentGroup{Name: "QA team"}, 1)
+
+		assert.EqualError(t, err, "assignment group already exists")
+		mockRepo.AssertNotCalled(t, "AddAssignmentGroup", mock.Anything)
+	})
+}
+
+// Update Assignment Group Tests Cases
+func TestUpdateAssignmentGroupById(t *testing.T) {
+	t.Run("Name taken by another assignment group", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetAssignmentGroups", model.AssignmentGroupFilters{ID: 1}, onePage).Return([]model.AssignmentGroup{{ID: 1, Name: "QA team"}}, 1, nil)
+		mockRepo.On("GetAssignmentGroups", model.AssignmentGroupFilters{Name: "Beta customers"}, onePage).Return([]model.AssignmentGroup{{ID: 2, Name: "Beta customers"}}, 1, nil)
+
+		err := service.UpdateAssignmentGroupById(1, assignmentGroupEntity.AssignmentGroup{Name: "Beta customers"})
+
+		assert.EqualError(t, err, "assignment group already exists")
+		mockRepo.AssertNotCalled(t, "UpdateAssignmentGroupById", mock.Anything, mock.Anything)
+	})
+}
+
+// Members Tests Cases
+func TestAddAssignmentGroupMembers(t *testing.T) {
+	t.Run("Successfully add members", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetAssignmentGroups", model.AssignmentGroupFilters{ID: 1}, onePage).Return([]model.AssignmentGroup{{ID: 1}}, 1, nil)
+		mockRepo.On("AddAssignmentGroupMembers", uint(1), []uint{2, 3}).Return(nil)
+
+		err := service.AddAssignmentGroupMembers(1, assignmentGroupEntity.AssignmentGroupMembers{PersonIDs: []uint{2, 3}})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Flags of the group are published", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockPublisher := new(MockPublisher)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Publisher = mockPublisher
+
+		mockRepo.On("GetAssignmentGroups", model.AssignmentGroupFilters{ID: 1}, onePage).Return([]model.AssignmentGroup{{ID: 1, FeatureFlags: []model.FeatureFlag{{ID: 4}, {ID: 5}}}}, 1, nil)
+		mockRepo.On("AddAssignmentGroupMembers", uint(1), []uint{2}).Return(nil)
+		mockPublisher.On("PublishFeatureFlagChange", streamEntity.EventFeatureFlagUpdated, uint(4), "").Return()
+		mockPublisher.On("PublishFeatureFlagChange", streamEntity.EventFeatureFlagUpdated, uint(5), "").Return()
+
+		err := service.AddAssignmentGroupMembers(1, assignmentGroupEntity.AssignmentGroupMembers{PersonIDs: []uint{2}})
+
+		assert.NoError(t, err)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("Assignment group not found", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetAssignmentGroups", model.AssignmentGroupFilters{ID: 9}, onePage).Return([]model.AssignmentGroup{}, 0, nil)
+
+		err := service.AddAssignmentGroupMembers(9, assignmentGroupEntity.AssignmentGroupMembers{PersonIDs: []uint{2}})
+
+		assert.EqualError(t, err, "assignment group not found")
+	})
+
+	t.Run("Person ids are required", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		err := service.AddAssignmentGroupMembers(1, assignmentGroupEntity.AssignmentGroupMembers{})
+
+		assert.EqualError(t, err, "PersonIDs|At least one person id is required")
+	})
+}
@@ -1,11 +1,20 @@
 package model
 
+const (
+	AssignmentInclude = "include"
+	// AssignmentExclude turns the flag off for the person, even when it is global
+	AssignmentExclude = "exclude"
+)
+
 type Assignment struct {
 	ID            uint         `gorm:"primaryKey;autoIncrement" json:"id"`
 	Person        *Person      `gorm:"foreignKey:PersonID"`
-	PersonID      uint         `gorm:"column:person_id" json:"person_id"`
+	PersonID      uint         `gorm:"column:person_id;uniqueIndex:idx_feature_flag_assignment" json:"person_id"`
 	FeatureFlag   *FeatureFlag `gorm:"foreignKey:FeatureFlagID"`
-	FeatureFlagID uint         `gorm:"column:feature_flag_id" json:"feature_flag_id"`
+	FeatureFlagID uint         `gorm:"column:feature_flag_id;uniqueIndex:idx_feature_flag_assignment" json:"feature_flag_id"`
+	Variant       string       `gorm:"null" json:"variant"`
+	Environment   string       `gorm:"not null;default:production;size:64;index;uniqueIndex:idx_feature_flag_assignment" json:"environment"`
+	Kind          string       `gorm:"not null;default:include;size:16" json:"kind"`
 }
 
 func (Assignment) TableName() string {
@@ -1,8 +1,52 @@
 package model
 
+import "time"
+
+// AssignmentGroup is a segment, a named group of people that can be assigned to feature flags
 type AssignmentGroup struct {
-	ID       uint      `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name     string    `gorm:"not null;unique" json:"name"`
-	Person   *[]Person `gorm:"foreignKey:PersonID"`
-	PersonID []uint    `gorm:"column:person_id" json:"person_id"`
+	ID           uint          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name         string        `gorm:"not null;unique;size:255" json:"name"`
+	Description  string        `gorm:"null" json:"description"`
+	People       []Person      `gorm:"many2many:assignment_group_members;joinForeignKey:AssignmentGroupID;joinReferences:PersonID" json:"people"`
+	FeatureFlags []FeatureFlag `gorm:"many2many:feature_flag_group_assignments;joinForeignKey:AssignmentGroupID;joinReferences:FeatureFlagID" json:"feature_flags"`
+	CreatedAt    time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (AssignmentGroup) TableName() string {
+	return "assignment_groups"
+}
+
+type AssignmentGroupFilters struct {
+	ID   uint
+	Name string
+}
+
+type UpdateAssignmentGroup struct {
+	Name        string `gorm:"update;not null" json:"name"`
+	Description string `gorm:"update;null" json:"description"`
+}
+
+func (UpdateAssignmentGroup) TableName() string {
+	return "assignment_groups"
+}
+
+// AssignmentGroupMember is the membership of a person in an assignment group
+type AssignmentGroupMember struct {
+	AssignmentGroupID uint `gorm:"primaryKey;column:assignment_group_id" json:"assignment_group_id"`
+	PersonID          uint `gorm:"primaryKey;column:person_id" json:"person_id"`
+}
+
+func (AssignmentGroupMember) TableName() string {
+	return "assignment_group_members"
+}
+
+// GroupAssignment enables a feature flag for every member of an assignment group
+type GroupAssignment struct {
+	AssignmentGroupID uint `gorm:"primaryKey;column:assignment_group_id" json:"assignment_group_id"`
+	FeatureFlagID     uint `gorm:"primaryKey;column:feature_flag_id" json:"feature_flag_id"`
+}
+
+func (GroupAssignment) TableName() string {
+	return "feature_flag_group_assignments"
 }
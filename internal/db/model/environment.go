@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// DefaultEnvironment is used when a request does not name an environment,
+// its flag state is the one stored in the feature_flags table
+const DefaultEnvironment = "production"
+
+// DefaultEnvironments are created on the first migration
+var DefaultEnvironments = []Environment{
+	{Name: "development", Position: 0},
+	{Name: "staging", Position: 1},
+	{Name: DefaultEnvironment, Position: 2},
+}
+
+type Environment struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string    `gorm:"not null;unique;size:64" json:"name"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (Environment) TableName() string {
+	return "environments"
+}
+
+// FeatureFlagEnvironment holds the state of a feature flag in an environment other than
+// the default one, the flag definition (description, rules, variants...) is shared
+type FeatureFlagEnvironment struct {
+	FeatureFlagID uint      `gorm:"primaryKey;column:feature_flag_id" json:"feature_flag_id"`
+	Environment   string    `gorm:"primaryKey;size:64" json:"environment"`
+	IsActive      bool      `gorm:"not null;default:false" json:"is_active"`
+	IsGlobal      bool      `gorm:"not null;default:false" json:"is_global"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (FeatureFlagEnvironment) TableName() string {
+	return "feature_flag_environments"
+}
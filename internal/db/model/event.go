@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// FlagEvent is a change of a flag as streamed to the clients, the payload is the
+// flag in the environment right after the change
+type FlagEvent struct {
+	ID            uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Type          string    `gorm:"not null;size:32" json:"type"`
+	ProjectID     uint      `gorm:"column:project_id;not null;index" json:"project_id"`
+	Environment   string    `gorm:"not null;default:production;size:64" json:"environment"`
+	FeatureFlagID uint      `gorm:"column:feature_flag_id;not null" json:"feature_flag_id"`
+	Payload       string    `gorm:"type:text" json:"payload"`
+	CreatedAt     time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (FlagEvent) TableName() string {
+	return "feature_flag_events"
+}
@@ -3,16 +3,29 @@ package model
 import "time"
 
 type FeatureFlag struct {
-	ID             uint      `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name           string    `gorm:"unique;not null" json:"name"`
-	Description    string    `gorm:"not null" json:"description"`
-	IsActive       bool      `gorm:"not null;default:false" json:"is_active"`
-	IsGlobal       bool      `gorm:"not null;default:false" json:"is_global"`
-	ExpirationDate string    `gorm:"null" json:"expiration_date"`
-	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
-	Person         *Person   `gorm:"foreignKey:PersonID"`
-	PersonID       uint      `gorm:"column:person_id" json:"person_id"`
+	ID                uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name              string         `gorm:"not null;size:255;uniqueIndex:idx_feature_flags_project_name" json:"name"`
+	Description       string         `gorm:"not null" json:"description"`
+	IsActive          bool           `gorm:"not null;default:false" json:"is_active"`
+	IsGlobal          bool           `gorm:"not null;default:false" json:"is_global"`
+	ExpirationDate    string         `gorm:"null" json:"expiration_date"`
+	RolloutPercentage int            `gorm:"not null;default:0" json:"rollout_percentage"`
+	Type              string         `gorm:"not null;default:boolean" json:"type"`
+	DefaultVariant    string         `gorm:"null" json:"default_variant"`
+	ExpiredAt         *time.Time     `gorm:"null" json:"expired_at"`
+	StatusReason      string         `gorm:"null" json:"status_reason"`
+	ArchivedAt        *time.Time     `gorm:"null;index" json:"archived_at"`
+	Version           uint           `gorm:"not null;default:1" json:"version"`
+	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	Person            *Person        `gorm:"foreignKey:PersonID"`
+	PersonID          uint           `gorm:"column:person_id" json:"person_id"`
+	ProjectID         uint           `gorm:"not null;default:0;uniqueIndex:idx_feature_flags_project_name" json:"project_id"`
+	Rules             []Rule         `gorm:"foreignKey:FeatureFlagID" json:"rules"`
+	Variants          []Variant      `gorm:"foreignKey:FeatureFlagID" json:"variants"`
+	Prerequisites     []Prerequisite `gorm:"foreignKey:FeatureFlagID" json:"prerequisites"`
+	// Environments holds the state in the environments other than the default one
+	Environments []FeatureFlagEnvironment `gorm:"foreignKey:FeatureFlagID" json:"environments"`
 }
 
 func (FeatureFlag) TableName() string {
@@ -25,15 +38,41 @@ type FeatureFlagFilters struct {
 	IsActive *bool
 	IsGlobal *bool
 	PersonID uint
+	// ProjectID scopes the flags to a project, zero means every project
+	ProjectID uint
+	// Environment selects which environment state is returned, empty means the default one
+	Environment string
+	// Archived returns only the archived flags when true and only the others when false,
+	// both when nil
+	Archived *bool
 }
 
 type UpdateFeatureFlag struct {
-	Description    string `gorm:"update;not null" json:"description"`
-	IsActive       bool   `gorm:"update;not null" json:"is_active"`
-	IsGlobal       bool   `gorm:"update;not null" json:"is_global"`
-	ExpirationDate string `gorm:"update;null" json:"expiration_date"`
+	Description       string     `gorm:"update;not null" json:"description"`
+	IsActive          bool       `gorm:"update;not null" json:"is_active"`
+	IsGlobal          bool       `gorm:"update;not null" json:"is_global"`
+	ExpirationDate    string     `gorm:"update;null" json:"expiration_date"`
+	RolloutPercentage int        `gorm:"update;not null" json:"rollout_percentage"`
+	ExpiredAt         *time.Time `gorm:"update;null" json:"expired_at"`
+	StatusReason      string     `gorm:"update;null" json:"status_reason"`
+	Environment       string     `gorm:"-" json:"environment"`
+	// Version is the version the change is based on, zero overwrites whatever is stored
+	Version uint `gorm:"-" json:"version"`
 }
 
 func (UpdateFeatureFlag) TableName() string {
 	return "feature_flags"
 }
+
+// RollbackFeatureFlag is everything a rollback writes in one go. The status and the assignments are
+// those of the environment of the update, the rest is shared by the environments and only written
+// when Shared is set
+type RollbackFeatureFlag struct {
+	Update         UpdateFeatureFlag
+	Shared         bool
+	DefaultVariant string
+	Variants       []Variant
+	Rules          []Rule
+	Prerequisites  []Prerequisite
+	Assignments    []Assignment
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// FeatureFlagRevision is the flag as it was right after a change, revisions are only added.
+// They have no foreign key to the flag, the history is kept once the flag is deleted
+type FeatureFlagRevision struct {
+	ID            uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	FeatureFlagID uint   `gorm:"column:feature_flag_id;not null;index" json:"feature_flag_id"`
+	Event         string `gorm:"not null;size:64" json:"event"`
+	Environment   string `gorm:"not null;default:production;size:64" json:"environment"`
+	// Snapshot is the flag and its assignments in the environment, encoded as JSON
+	Snapshot string  `gorm:"not null" json:"snapshot"`
+	Person   *Person `gorm:"foreignKey:PersonID"`
+	// PersonID is empty for the changes made by the application, like the expiry
+	PersonID  *uint     `gorm:"column:person_id" json:"person_id"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (FeatureFlagRevision) TableName() string {
+	return "feature_flag_revisions"
+}
+
+type FeatureFlagRevisionFilters struct {
+	FeatureFlagID uint
+	Environment   string
+}
@@ -15,7 +15,9 @@ type PersonWithAssignment struct {
 	Name       string
 	Email      string
 	IsAssigned bool
+	IsExcluded bool
 	IsGlobal   bool
+	Segments   []string `gorm:"-"`
 }
 
 func (PersonWithAssignment) TableName() string {
@@ -28,4 +30,14 @@ type AssignedFeatureFlag struct {
 	IsActive   bool   `json:"is_active"`
 	IsGlobal   bool   `json:"is_global"`
 	IsAssigned bool   `json:"is_assigned"`
+	// IsExcluded is true when the person is excluded from the flag, it wins over everything else
+	IsExcluded bool `json:"is_excluded"`
+	// IsAssignedViaSegment is true when one of the person segments is assigned to the flag
+	IsAssignedViaSegment bool      `json:"is_assigned_via_segment"`
+	ExpirationDate       string    `json:"expiration_date"`
+	RolloutPercentage    int       `json:"rollout_percentage"`
+	Type                 string    `json:"type"`
+	DefaultVariant       string    `json:"default_variant"`
+	AssignedVariant      string    `json:"assigned_variant"`
+	Variants             []Variant `gorm:"-" json:"variants"`
 }
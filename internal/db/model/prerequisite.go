@@ -0,0 +1,16 @@
+package model
+
+// Prerequisite makes a flag depend on another flag of the same project, the flag is
+// only served when the prerequisite is on and, if set, serves the required variant
+type Prerequisite struct {
+	ID             uint         `gorm:"primaryKey;autoIncrement" json:"id"`
+	FeatureFlagID  uint         `gorm:"column:feature_flag_id;not null;uniqueIndex:idx_feature_flag_prerequisite" json:"feature_flag_id"`
+	PrerequisiteID uint         `gorm:"column:prerequisite_id;not null;uniqueIndex:idx_feature_flag_prerequisite;index" json:"prerequisite_id"`
+	Prerequisite   *FeatureFlag `gorm:"foreignKey:PrerequisiteID" json:"prerequisite"`
+	Position       int          `gorm:"not null" json:"position"`
+	Variant        string       `gorm:"null" json:"variant"`
+}
+
+func (Prerequisite) TableName() string {
+	return "feature_flag_prerequisites"
+}
@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// DefaultProject owns the flags created before projects existed and is used
+// when a request does not name a project
+const DefaultProject = "default"
+
+type Project struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Key         string    `gorm:"not null;unique;size:64" json:"key"`
+	Name        string    `gorm:"not null" json:"name"`
+	Description string    `gorm:"null" json:"description"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Project) TableName() string {
+	return "projects"
+}
+
+type ProjectFilters struct {
+	ID  uint
+	Key string
+}
+
+// ProjectMember gives a person a role in a project, a project without members is open to everyone
+type ProjectMember struct {
+	ProjectID uint      `gorm:"primaryKey;column:project_id" json:"project_id"`
+	PersonID  uint      `gorm:"primaryKey;column:person_id" json:"person_id"`
+	Person    *Person   `gorm:"foreignKey:PersonID"`
+	Role      string    `gorm:"not null;size:16" json:"role"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (ProjectMember) TableName() string {
+	return "project_members"
+}
+
+// ApiKey authenticates the SDK calls of a project, only the SHA-256 of the key is stored
+type ApiKey struct {
+	ID         uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProjectID  uint       `gorm:"not null;index" json:"project_id"`
+	Project    *Project   `gorm:"foreignKey:ProjectID"`
+	Name       string     `gorm:"not null" json:"name"`
+	Prefix     string     `gorm:"not null;size:16" json:"prefix"`
+	KeyHash    string     `gorm:"not null;unique;size:64" json:"-"`
+	LastUsedAt *time.Time `gorm:"null" json:"last_used_at"`
+	RevokedAt  *time.Time `gorm:"null" json:"revoked_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (ApiKey) TableName() string {
+	return "project_api_keys"
+}
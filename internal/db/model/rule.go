@@ -0,0 +1,15 @@
+package model
+
+type Rule struct {
+	ID            uint     `gorm:"primaryKey;autoIncrement" json:"id"`
+	FeatureFlagID uint     `gorm:"column:feature_flag_id;not null;index" json:"feature_flag_id"`
+	Position      int      `gorm:"not null" json:"position"`
+	Attribute     string   `gorm:"not null" json:"attribute"`
+	Operator      string   `gorm:"not null" json:"operator"`
+	Values        []string `gorm:"serializer:json;type:text" json:"values"`
+	Result        bool     `gorm:"not null;default:false" json:"result"`
+}
+
+func (Rule) TableName() string {
+	return "feature_flag_rules"
+}
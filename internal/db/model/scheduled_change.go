@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+type ScheduledChange struct {
+	ID                uint         `gorm:"primaryKey;autoIncrement" json:"id"`
+	FeatureFlag       *FeatureFlag `gorm:"foreignKey:FeatureFlagID"`
+	FeatureFlagID     uint         `gorm:"column:feature_flag_id;not null;index" json:"feature_flag_id"`
+	Operation         string       `gorm:"not null" json:"operation"`
+	IsGlobal          bool         `gorm:"not null;default:false" json:"is_global"`
+	RolloutPercentage int          `gorm:"not null;default:0" json:"rollout_percentage"`
+	Force             bool         `gorm:"not null;default:false" json:"force"`
+	ExecuteAt         time.Time    `gorm:"not null;index" json:"execute_at"`
+	Timezone          string       `gorm:"not null" json:"timezone"`
+	Environment       string       `gorm:"not null;default:production;size:64" json:"environment"`
+	Status            string       `gorm:"not null;index" json:"status"`
+	AppliedAt         *time.Time   `gorm:"null" json:"applied_at"`
+	ErrorMessage      string       `gorm:"null" json:"error_message"`
+	LockedUntil       *time.Time   `gorm:"null" json:"-"`
+	Person            *Person      `gorm:"foreignKey:PersonID"`
+	PersonID          uint         `gorm:"column:person_id" json:"person_id"`
+	CreatedAt         time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (ScheduledChange) TableName() string {
+	return "feature_flag_scheduled_changes"
+}
+
+type ScheduledChangeFilters struct {
+	FeatureFlagID uint
+	Status        string
+	Environment   string
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// SchemaMigration is a migration applied to the database, the versions are the
+// numbers of the files under config/database/migrations
+type SchemaMigration struct {
+	Version   uint      `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Name      string    `gorm:"not null;size:255" json:"name"`
+	AppliedAt time.Time `gorm:"not null" json:"applied_at"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
@@ -0,0 +1,15 @@
+package model
+
+// FeatureFlagSnapshot holds every flag of a project in an environment with what is
+// needed to evaluate them without going back to the database
+type FeatureFlagSnapshot struct {
+	FeatureFlags   []FeatureFlag
+	Assignments    []Assignment
+	SegmentMembers []SegmentMember
+}
+
+// SegmentMember is a person enabled for a flag through one of their segments
+type SegmentMember struct {
+	FeatureFlagID uint
+	PersonID      uint
+}
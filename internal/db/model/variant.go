@@ -0,0 +1,14 @@
+package model
+
+type Variant struct {
+	ID            uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	FeatureFlagID uint   `gorm:"column:feature_flag_id;not null;index" json:"feature_flag_id"`
+	Position      int    `gorm:"not null" json:"position"`
+	Name          string `gorm:"not null" json:"name"`
+	Value         string `gorm:"type:text" json:"value"`
+	Weight        int    `gorm:"not null;default:0" json:"weight"`
+}
+
+func (Variant) TableName() string {
+	return "feature_flag_variants"
+}
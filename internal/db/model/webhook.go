@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook receives the changes of the flags of a project, the secret signs the payloads
+type Webhook struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProjectID uint   `gorm:"not null;index" json:"project_id"`
+	URL       string `gorm:"not null;size:512" json:"url"`
+	Secret    string `gorm:"not null;size:128" json:"-"`
+	// EventTypes is the comma separated list of the events sent, every event when empty
+	EventTypes string    `gorm:"size:512" json:"event_types"`
+	IsActive   bool      `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// WebhookDelivery is a payload to send to a webhook, it is retried until it succeeds
+// or runs out of attempts
+type WebhookDelivery struct {
+	ID            uint             `gorm:"primaryKey;autoIncrement" json:"id"`
+	WebhookID     uint             `gorm:"not null;index" json:"webhook_id"`
+	Webhook       *Webhook         `gorm:"foreignKey:WebhookID"`
+	EventType     string           `gorm:"not null;size:64" json:"event_type"`
+	Payload       string           `gorm:"type:text" json:"payload"`
+	Status        string           `gorm:"not null;size:16;default:pending;index" json:"status"`
+	Attempts      int              `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time        `gorm:"index" json:"next_attempt_at"`
+	LockedUntil   *time.Time       `gorm:"null" json:"-"`
+	AttemptLogs   []WebhookAttempt `gorm:"foreignKey:DeliveryID"`
+	CreatedAt     time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookAttempt records the answer of the receiver to one attempt of a delivery
+type WebhookAttempt struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	DeliveryID uint      `gorm:"not null;index" json:"delivery_id"`
+	StatusCode int       `gorm:"not null;default:0" json:"status_code"`
+	Error      string    `gorm:"type:text" json:"error"`
+	DurationMs int64     `gorm:"not null;default:0" json:"duration_ms"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (WebhookAttempt) TableName() string {
+	return "webhook_attempts"
+}
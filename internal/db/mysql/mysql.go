@@ -1,10 +1,20 @@
+// Package mysql builds the SQL repositories of the services. The name is historical, the
+// repositories run on every driver of config/database
 package mysql
 
 import (
 	"ff/internal/assignment"
+	assignmentgroup "ff/internal/assignment_group"
 	"ff/internal/db/repository"
+	"ff/internal/environment"
 	featureflag "ff/internal/feature_flag"
 	"ff/internal/person"
+	"ff/internal/project"
+	"ff/internal/revision"
+	scheduledchange "ff/internal/scheduled_change"
+	"ff/internal/snapshot"
+	"ff/internal/stream"
+	"ff/internal/webhook"
 
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
@@ -20,7 +30,47 @@ func NewSqlAssignmentRepository(db *gorm.DB, logger *zerolog.Logger) assignment.
 	return &assignmentRepository
 }
 
+func NewSqlAssignmentGroupRepository(db *gorm.DB, logger *zerolog.Logger) assignmentgroup.AssignmentGroupRepository {
+	assignmentGroupRepository := repository.SqlRepository{DB: db, Logger: logger}
+	return &assignmentGroupRepository
+}
+
 func NewSqlPersonRepository(db *gorm.DB, logger *zerolog.Logger) person.PersonRepository {
 	personRepository := repository.SqlRepository{DB: db, Logger: logger}
 	return &personRepository
 }
+
+func NewSqlScheduledChangeRepository(db *gorm.DB, logger *zerolog.Logger) scheduledchange.ScheduledChangeRepository {
+	scheduledChangeRepository := repository.SqlRepository{DB: db, Logger: logger}
+	return &scheduledChangeRepository
+}
+
+func NewSqlEnvironmentRepository(db *gorm.DB, logger *zerolog.Logger) environment.EnvironmentRepository {
+	environmentRepository := repository.SqlRepository{DB: db, Logger: logger}
+	return &environmentRepository
+}
+
+func NewSqlProjectRepository(db *gorm.DB, logger *zerolog.Logger) project.ProjectRepository {
+	projectRepository := repository.SqlRepository{DB: db, Logger: logger}
+	return &projectRepository
+}
+
+func NewSqlSnapshotRepository(db *gorm.DB, logger *zerolog.Logger) snapshot.SnapshotRepository {
+	snapshotRepository := repository.SqlRepository{DB: db, Logger: logger}
+	return &snapshotRepository
+}
+
+func NewSqlStreamRepository(db *gorm.DB, logger *zerolog.Logger) stream.StreamRepository {
+	streamRepository := repository.SqlRepository{DB: db, Logger: logger}
+	return &streamRepository
+}
+
+func NewSqlWebhookRepository(db *gorm.DB, logger *zerolog.Logger) webhook.WebhookRepository {
+	webhookRepository := repository.SqlRepository{DB: db, Logger: logger}
+	return &webhookRepository
+}
+
+func NewSqlRevisionRepository(db *gorm.DB, logger *zerolog.Logger) revision.RevisionRepository {
+	revisionRepository := repository.SqlRepository{DB: db, Logger: logger}
+	return &revisionRepository
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"errors"
 	model "ff/internal/db/model"
+
+	"gorm.io/gorm"
 )
 
 func (s *SqlRepository) ApplyAssignment(assignment model.Assignment) error {
@@ -14,8 +16,8 @@ func (s *SqlRepository) ApplyAssignment(assignment model.Assignment) error {
 	return nil
 }
 
-func (s *SqlRepository) GetAssignmentsByPersonAndFeatureFlagId(personId, featureFlagId uint) (model.Assignment, error) {
-	query := s.DB.Debug().Model(&model.Assignment{}).Where("person_id = ?", personId).Where("feature_flag_id = ?", featureFlagId)
+func (s *SqlRepository) GetAssignmentsByPersonAndFeatureFlagId(personId, featureFlagId uint, environment string) (model.Assignment, error) {
+	query := s.DB.Debug().Model(&model.Assignment{}).Where("person_id = ?", personId).Where("feature_flag_id = ?", featureFlagId).Where("environment = ?", environment)
 
 	// get feature flags
 	var assignment model.Assignment
@@ -27,11 +29,43 @@ func (s *SqlRepository) GetAssignmentsByPersonAndFeatureFlagId(personId, feature
 	return assignment, nil
 }
 
+// GetAssignmentsByFeatureFlagId returns the assignments of the flag in the environment
+func (s *SqlRepository) GetAssignmentsByFeatureFlagId(featureFlagId uint, environment string) ([]model.Assignment, error) {
+	var assignments []model.Assignment
+	if result := s.DB.Debug().Model(&model.Assignment{}).Where("feature_flag_id = ?", featureFlagId).Where("environment = ?", environment).Order("person_id").Find(&assignments); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return nil, errors.New("error when getting assignments")
+	}
+
+	return assignments, nil
+}
+
 func (s *SqlRepository) DeleteAssignment(assignment model.Assignment) error {
-	if result := s.DB.Debug().Where("person_id = ? AND feature_flag_id = ?", assignment.PersonID, assignment.FeatureFlagID).Delete(&model.Assignment{}); result.Error != nil {
+	if result := s.DB.Debug().Where("person_id = ? AND feature_flag_id = ? AND environment = ?", assignment.PersonID, assignment.FeatureFlagID, assignment.Environment).Delete(&model.Assignment{}); result.Error != nil {
 		s.Logger.Error().Err(result.Error)
 		return errors.New("error when deleting assigning")
 	}
 
 	return nil
 }
+
+// SwitchAssignment replaces the assignment of the person in one transaction, an empty kind only removes it
+func (s *SqlRepository) SwitchAssignment(assignment model.Assignment) error {
+	err := s.DB.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("person_id = ? AND feature_flag_id = ? AND environment = ?", assignment.PersonID, assignment.FeatureFlagID, assignment.Environment).Delete(&model.Assignment{}).Error; err != nil {
+			return err
+		}
+
+		if assignment.Kind == "" {
+			return nil
+		}
+
+		return tx.Create(&assignment).Error
+	})
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return errors.New("error when switching assignment")
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"errors"
+	model "ff/internal/db/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *SqlRepository) AddAssignmentGroup(assignmentGroup model.AssignmentGroup) error {
+	if result := s.DB.Debug().Create(&assignmentGroup); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when creating assignment group")
+	}
+
+	return nil
+}
+
+func (s *SqlRepository) GetAssignmentGroups(filters model.AssignmentGroupFilters, pagination model.Pagination) ([]model.AssignmentGroup, int64, error) {
+	query := s.DB.Debug().Model(&model.AssignmentGroup{})
+
+	if filters.ID != 0 {
+		query.Where("assignment_groups.id = ?", filters.ID)
+	}
+
+	// names are unique, so the filter is exact
+	if filters.Name != "" {
+		query.Where("assignment_groups.name = ?", filters.Name)
+	}
+
+	// get total count
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// apply pagination
+	offset := (pagination.Page - 1) * pagination.Limit
+	query.Offset(offset).Limit(pagination.Limit)
+
+	var assignmentGroups []model.AssignmentGroup
+	if result := query.Preload("People").Preload("FeatureFlags").Order("assignment_groups.id").Find(&assignmentGroups); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return nil, 0, errors.New("error when getting assignment groups")
+	}
+
+	return assignmentGroups, totalCount, nil
+}
+
+func (s *SqlRepository) UpdateAssignmentGroupById(id uint, assignmentGroup model.UpdateAssignmentGroup) error {
+	updateData := map[string]interface{}{
+		"name":        assignmentGroup.Name,
+		"description": assignmentGroup.Description,
+	}
+
+	result := s.DB.Debug().Model(&model.UpdateAssignmentGroup{}).Where("id = ?", id).Updates(updateData)
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when updating assignment group")
+	}
+
+	return nil
+}
+
+// DeleteAssignmentGroup removes the group with its memberships and flag assignments
+func (s *SqlRepository) DeleteAssignmentGroup(id uint) error {
+	err := s.DB.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("assignment_group_id = ?", id).Delete(&model.AssignmentGroupMember{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("assignment_group_id = ?", id).Delete(&model.GroupAssignment{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", id).Delete(&model.AssignmentGroup{}).Error
+	})
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return errors.New("error when deleting assignment group")
+	}
+
+	return nil
+}
+
+// AddAssignmentGroupMembers ignores people that are already members
+func (s *SqlRepository) AddAssignmentGroupMembers(assignmentGroupId uint, personIds []uint) error {
+	var members []model.AssignmentGroupMember
+	for _, personId := range personIds {
+		members = append(members, model.AssignmentGroupMember{
+			AssignmentGroupID: assignmentGroupId,
+			PersonID:          personId,
+		})
+	}
+
+	if result := s.DB.Debug().Clauses(clause.OnConflict{DoNothing: true}).Create(&members); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when adding assignment group members")
+	}
+
+	return nil
+}
+
+func (s *SqlRepository) RemoveAssignmentGroupMember(assignmentGroupId uint, personId uint) error {
+	result := s.DB.Debug().Where("assignment_group_id = ? AND person_id = ?", assignmentGroupId, personId).Delete(&model.AssignmentGroupMember{})
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when removing assignment group member")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("person is not a member of the assignment group")
+	}
+
+	return nil
+}
+
+func (s *SqlRepository) ApplyGroupAssignment(assignmentGroupId uint, featureFlagId uint) error {
+	result := s.DB.Debug().Clauses(clause.OnConflict{DoNothing: true}).Create(&model.GroupAssignment{
+		AssignmentGroupID: assignmentGroupId,
+		FeatureFlagID:     featureFlagId,
+	})
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when assigning feature flag to assignment group")
+	}
+
+	return nil
+}
+
+func (s *SqlRepository) DeleteGroupAssignment(assignmentGroupId uint, featureFlagId uint) error {
+	result := s.DB.Debug().Where("assignment_group_id = ? AND feature_flag_id = ?", assignmentGroupId, featureFlagId).Delete(&model.GroupAssignment{})
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when removing feature flag from assignment group")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("feature flag is not assigned to the assignment group")
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	model "ff/internal/db/model"
+)
+
+// Assignment Groups Tests Cases
+func (s *TestSqlRepository) TestAssignmentGroups() {
+	featureFlag := model.FeatureFlag{
+		Name:        "SEGMENT_FLAG",
+		Description: "Test Description",
+		PersonID:    personOnDB[0].ID,
+	}
+	s.Require().NoError(s.db.Create(&featureFlag).Error)
+
+	s.Require().NoError(s.repo.AddAssignmentGroup(model.AssignmentGroup{Name: "QA team", Description: "People testing releases"}))
+	s.Require().NoError(s.repo.AddAssignmentGroup(model.AssignmentGroup{Name: "Beta customers"}))
+
+	assignmentGroups, totalCount, err := s.repo.GetAssignmentGroups(model.AssignmentGroupFilters{Name: "QA team"}, model.Pagination{Page: 1, Limit: 10})
+	s.Require().NoError(err)
+	s.Require().Equal(int64(1), totalCount)
+	qaTeam := assignmentGroups[0]
+
+	s.Run("Add members ignoring the ones already in the group", func() {
+		s.Require().NoError(s.repo.AddAssignmentGroupMembers(qaTeam.ID, []uint{personOnDB[0].ID}))
+		s.Require().NoError(s.repo.AddAssignmentGroupMembers(qaTeam.ID, []uint{personOnDB[0].ID, personOnDB[1].ID}))
+
+		result, _, err := s.repo.GetAssignmentGroups(model.AssignmentGroupFilters{ID: qaTeam.ID}, model.Pagination{Page: 1, Limit: 10})
+		s.Require().NoError(err)
+		s.Equal(2, len(result[0].People))
+	})
+
+	s.Run("Assign a feature flag to the group", func() {
+		s.Require().NoError(s.repo.ApplyGroupAssignment(qaTeam.ID, featureFlag.ID))
+
+		result, _, err := s.repo.GetAssignmentGroups(model.AssignmentGroupFilters{ID: qaTeam.ID}, model.Pagination{Page: 1, Limit: 10})
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(result[0].FeatureFlags))
+		s.Equal("SEGMENT_FLAG", result[0].FeatureFlags[0].Name)
+
+		segments, err := s.repo.getSegmentsByFeatureFlag(featureFlag.ID, []uint{personOnDB[0].ID, personOnDB[1].ID})
+		s.Require().NoError(err)
+		s.Equal([]string{"QA team"}, segments[personOnDB[0].ID])
+		s.Equal([]string{"QA team"}, segments[personOnDB[1].ID])
+	})
+
+	s.Run("Remove a member from the group", func() {
+		s.Require().NoError(s.repo.RemoveAssignmentGroupMember(qaTeam.ID, personOnDB[1].ID))
+
+		err := s.repo.RemoveAssignmentGroupMember(qaTeam.ID, personOnDB[1].ID)
+		s.EqualError(err, "person is not a member of the assignment group")
+
+		segments, err := s.repo.getSegmentsByFeatureFlag(featureFlag.ID, []uint{personOnDB[0].ID, personOnDB[1].ID})
+		s.Require().NoError(err)
+		s.Empty(segments[personOnDB[1].ID])
+	})
+
+	s.Run("Delete the group with its memberships and assignments", func() {
+		s.Require().NoError(s.repo.DeleteAssignmentGroup(qaTeam.ID))
+
+		_, totalCount, err := s.repo.GetAssignmentGroups(model.AssignmentGroupFilters{ID: qaTeam.ID}, model.Pagination{Page: 1, Limit: 10})
+		s.Require().NoError(err)
+		s.Equal(int64(0), totalCount)
+
+		var members int64
+		s.db.Model(&model.AssignmentGroupMember{}).Where("assignment_group_id = ?", qaTeam.ID).Count(&members)
+		s.Equal(int64(0), members)
+
+		err = s.repo.DeleteGroupAssignment(qaTeam.ID, featureFlag.ID)
+		s.EqualError(err, "feature flag is not assigned to the assignment group")
+	})
+}
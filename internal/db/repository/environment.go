@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"errors"
+	model "ff/internal/db/model"
+)
+
+func (s *SqlRepository) GetEnvironments() ([]model.Environment, error) {
+	var environments []model.Environment
+	if result := s.DB.Debug().Order("position").Order("id").Find(&environments); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return nil, errors.New("error when getting environments")
+	}
+
+	return environments, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	model "ff/internal/db/model"
+)
+
+// Environment Tests Cases
+func (s *TestSqlRepository) TestFeatureFlagEnvironments() {
+	featureFlag := model.FeatureFlag{
+		Name:        "ENVIRONMENT_FLAG",
+		Description: "Test Description",
+		IsActive:    true,
+		IsGlobal:    true,
+		PersonID:    personOnDB[0].ID,
+	}
+	s.Require().NoError(s.db.Create(&featureFlag).Error)
+
+	s.Run("Flag without state in the environment is inactive", func() {
+		featureFlags, total, err := s.repo.GetFeatureFlag(model.FeatureFlagFilters{ID: featureFlag.ID, Environment: "staging"}, model.Pagination{Page: 1, Limit: 1})
+		s.Require().NoError(err)
+		s.Require().Equal(int64(1), total)
+		s.False(featureFlags[0].IsActive)
+		s.False(featureFlags[0].IsGlobal)
+	})
+
+	s.Run("Update only changes the informed environment", func() {
+		err := s.repo.UpdateFeatureFlagById(featureFlag.ID, model.UpdateFeatureFlag{
+			Description: featureFlag.Description,
+			IsActive:    true,
+			Environment: "staging",
+		})
+		s.Require().NoError(err)
+
+		staging, err := s.repo.GetFeatureFlagByName(0, featureFlag.Name, "staging")
+		s.Require().NoError(err)
+		s.True(staging.IsActive)
+		s.False(staging.IsGlobal)
+
+		production, err := s.repo.GetFeatureFlagByName(0, featureFlag.Name, model.DefaultEnvironment)
+		s.Require().NoError(err)
+		s.True(production.IsActive)
+		s.True(production.IsGlobal)
+
+		development, err := s.repo.GetFeatureFlagByName(0, featureFlag.Name, "development")
+		s.Require().NoError(err)
+		s.False(development.IsActive)
+	})
+
+	s.Run("Filter by active state of the environment", func() {
+		isActive := true
+		_, total, err := s.repo.GetFeatureFlag(model.FeatureFlagFilters{ID: featureFlag.ID, IsActive: &isActive, Environment: "development"}, model.Pagination{Page: 1, Limit: 1})
+		s.Require().NoError(err)
+		s.Equal(int64(0), total)
+
+		_, total, err = s.repo.GetFeatureFlag(model.FeatureFlagFilters{ID: featureFlag.ID, IsActive: &isActive, Environment: "staging"}, model.Pagination{Page: 1, Limit: 1})
+		s.Require().NoError(err)
+		s.Equal(int64(1), total)
+	})
+
+	s.Run("Assignments are kept per environment", func() {
+		s.Require().NoError(s.repo.ApplyAssignment(model.Assignment{
+			FeatureFlagID: featureFlag.ID,
+			PersonID:      personOnDB[1].ID,
+			Environment:   "staging",
+		}))
+
+		staging, err := s.repo.GetAssignmentsByPersonAndFeatureFlagId(personOnDB[1].ID, featureFlag.ID, "staging")
+		s.Require().NoError(err)
+		s.NotZero(staging.ID)
+
+		production, err := s.repo.GetAssignmentsByPersonAndFeatureFlagId(personOnDB[1].ID, featureFlag.ID, model.DefaultEnvironment)
+		s.Require().NoError(err)
+		s.Zero(production.ID)
+
+		assignments, err := s.repo.GetAssignmentsByFeatureFlagId(featureFlag.ID, "staging")
+		s.Require().NoError(err)
+		s.Equal(1, len(assignments))
+
+		assignments, err = s.repo.GetAssignmentsByFeatureFlagId(featureFlag.ID, model.DefaultEnvironment)
+		s.Require().NoError(err)
+		s.Equal(0, len(assignments))
+	})
+}
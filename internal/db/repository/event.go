@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"errors"
+	model "ff/internal/db/model"
+	"time"
+)
+
+func (s *SqlRepository) AddFlagEvent(event model.FlagEvent) error {
+	if result := s.DB.Debug().Create(&event); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when creating flag event")
+	}
+
+	return nil
+}
+
+// GetFlagEvents returns the events after the given id in order, at most limit of them
+func (s *SqlRepository) GetFlagEvents(afterId uint, limit int) ([]model.FlagEvent, error) {
+	var events []model.FlagEvent
+
+	result := s.DB.Debug().Model(&model.FlagEvent{}).
+		Where("id > ?", afterId).
+		Order("id").
+		Limit(limit).
+		Find(&events)
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return nil, errors.New("error when getting flag events")
+	}
+
+	return events, nil
+}
+
+// GetFlagEventBounds returns the ids of the oldest and the latest event kept, both are zero without events
+func (s *SqlRepository) GetFlagEventBounds() (uint, uint, error) {
+	var bounds struct {
+		Oldest uint
+		Latest uint
+	}
+
+	result := s.DB.Debug().Model(&model.FlagEvent{}).
+		Select("COALESCE(MIN(id), 0) AS oldest, COALESCE(MAX(id), 0) AS latest").
+		Scan(&bounds)
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return 0, 0, errors.New("error when getting flag event bounds")
+	}
+
+	return bounds.Oldest, bounds.Latest, nil
+}
+
+// DeleteFlagEventsBefore removes the events created before the date. The latest one of each project
+// and environment is always kept, a reconnecting client can tell whether it missed anything and the
+// snapshot version of the project does not go back
+func (s *SqlRepository) DeleteFlagEventsBefore(date time.Time) error {
+	latest := s.DB.Model(&model.FlagEvent{}).Select("MAX(id) AS id").Group("project_id, environment")
+
+	// the latest ids are read through a derived table, MySQL can not read the table it deletes from
+	result := s.DB.Debug().
+		Where("created_at < ?", date).
+		Where("id NOT IN (?)", s.DB.Table("(?) AS latest", latest).Select("id")).
+		Delete(&model.FlagEvent{})
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when deleting flag events")
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	model "ff/internal/db/model"
+	"time"
+)
+
+// Flag Event Tests Cases
+func (s *TestSqlRepository) TestFlagEvents() {
+	s.Run("Bounds without events", func() {
+		oldest, latest, err := s.repo.GetFlagEventBounds()
+		s.Require().NoError(err)
+		s.Equal(uint(0), oldest)
+		s.Equal(uint(0), latest)
+	})
+
+	for _, environment := range []string{model.DefaultEnvironment, "staging", model.DefaultEnvironment} {
+		s.Require().NoError(s.repo.AddFlagEvent(model.FlagEvent{Type: "feature_flag.updated", ProjectID: 1, Environment: environment, FeatureFlagID: 1, Payload: "{}"}))
+	}
+
+	s.Run("Events after an id in order", func() {
+		events, err := s.repo.GetFlagEvents(1, 10)
+		s.Require().NoError(err)
+		s.Require().Equal(2, len(events))
+		s.Equal("staging", events[0].Environment)
+		s.True(events[0].ID < events[1].ID)
+
+		events, err = s.repo.GetFlagEvents(0, 1)
+		s.Require().NoError(err)
+		s.Equal(1, len(events))
+	})
+
+	events, err := s.repo.GetFlagEvents(0, 10)
+	s.Require().NoError(err)
+	s.Require().Equal(3, len(events))
+
+	s.Run("Snapshot version of the project in the environment", func() {
+		version, err := s.repo.GetSnapshotVersion(1, model.DefaultEnvironment)
+		s.Require().NoError(err)
+		s.Equal(events[2].ID, version)
+
+		version, err = s.repo.GetSnapshotVersion(1, "staging")
+		s.Require().NoError(err)
+		s.Equal(events[1].ID, version)
+
+		version, err = s.repo.GetSnapshotVersion(0, "staging")
+		s.Require().NoError(err)
+		s.Equal(events[1].ID, version)
+
+		version, err = s.repo.GetSnapshotVersion(2, model.DefaultEnvironment)
+		s.Require().NoError(err)
+		s.Zero(version)
+	})
+
+	s.Run("Old events are removed but the latest of each project and environment", func() {
+		s.Require().NoError(s.repo.DeleteFlagEventsBefore(time.Now().Add(time.Hour)))
+
+		oldest, latest, err := s.repo.GetFlagEventBounds()
+		s.Require().NoError(err)
+		s.Equal(events[1].ID, oldest)
+		s.Equal(events[2].ID, latest)
+
+		version, err := s.repo.GetSnapshotVersion(1, "staging")
+		s.Require().NoError(err)
+		s.Equal(events[1].ID, version)
+	})
+}
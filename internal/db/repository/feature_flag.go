@@ -3,6 +3,11 @@ package repository
 import (
 	"errors"
 	model "ff/internal/db/model"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func (s *SqlRepository) AddFeatureFlag(featureFlag model.FeatureFlag) error {
@@ -17,19 +22,24 @@ func (s *SqlRepository) AddFeatureFlag(featureFlag model.FeatureFlag) error {
 func (s *SqlRepository) GetFeatureFlag(filters model.FeatureFlagFilters, pagination model.Pagination) ([]model.FeatureFlag, int64, error) {
 	query := s.DB.Debug().Model(&model.FeatureFlag{}).InnerJoins("Person")
 
-	// apply filters
+	isActiveColumn, isGlobalColumn := environmentColumns("feature_flags", filters.Environment)
+	if !isDefaultEnvironment(filters.Environment) {
+		query.Joins("LEFT JOIN feature_flag_environments ffe ON ffe.feature_flag_id = feature_flags.id AND ffe.environment = ?", filters.Environment)
+	}
+
+	// apply filters, the name is matched ignoring the case whatever the collation of the database is
 	if filters.Name != "" {
-		query.Where("feature_flags.name LIKE ?", "%"+filters.Name+"%")
+		query.Where("LOWER(feature_flags.name) LIKE ?", "%"+strings.ToLower(filters.Name)+"%")
 	}
 
 	// this is an optional filter, it can be true/false or not be sent
 	if filters.IsActive != nil {
-		query.Where("feature_flags.is_active = ?", *filters.IsActive)
+		query.Where(isActiveColumn+" = ?", *filters.IsActive)
 	}
 
 	// this is an optional filter, it can be true/false or not be sent
 	if filters.IsGlobal != nil {
-		query.Where("feature_flags.is_global = ?", *filters.IsGlobal)
+		query.Where(isGlobalColumn+" = ?", *filters.IsGlobal)
 	}
 
 	if filters.ID != 0 {
@@ -40,6 +50,17 @@ func (s *SqlRepository) GetFeatureFlag(filters model.FeatureFlagFilters, paginat
 		query.Where("feature_flags.person_id = ?", filters.PersonID)
 	}
 
+	if filters.ProjectID != 0 {
+		query.Where("feature_flags.project_id = ?", filters.ProjectID)
+	}
+
+	// this is an optional filter, it can be true/false or not be sent
+	if filters.Archived != nil && *filters.Archived {
+		query.Where("feature_flags.archived_at IS NOT NULL")
+	} else if filters.Archived != nil {
+		query.Where("feature_flags.archived_at IS NULL")
+	}
+
 	// get total count
 	var totalCount int64
 	if err := query.Count(&totalCount).Error; err != nil {
@@ -52,34 +73,518 @@ func (s *SqlRepository) GetFeatureFlag(filters model.FeatureFlagFilters, paginat
 
 	// get feature flags
 	var featureFlags []model.FeatureFlag
-	if result := query.Find(&featureFlags); result.Error != nil {
+	if result := query.Preload("Rules", orderRulesByPosition).Preload("Variants", orderVariantsByPosition).Preload("Prerequisites", orderPrerequisitesByPosition).Preload("Prerequisites.Prerequisite").Find(&featureFlags); result.Error != nil {
 		s.Logger.Error().Err(result.Error)
 		return nil, 0, errors.New("error when getting feature flags")
 	}
 
+	if err := s.applyEnvironmentState(featureFlags, filters.Environment); err != nil {
+		return nil, 0, err
+	}
+
 	return featureFlags, totalCount, nil
 }
 
 func (s *SqlRepository) UpdateFeatureFlagById(id uint, featureFlag model.UpdateFeatureFlag) error {
 	updateData := map[string]interface{}{
-		"description":     featureFlag.Description,
-		"is_active":       featureFlag.IsActive, // Explicitly include even if false
-		"is_global":       featureFlag.IsGlobal, // Explicitly include even if false
-		"expiration_date": featureFlag.ExpirationDate,
+		"description":        featureFlag.Description,
+		"expiration_date":    featureFlag.ExpirationDate,
+		"rollout_percentage": featureFlag.RolloutPercentage,
+		"expired_at":         featureFlag.ExpiredAt,
+		"status_reason":      featureFlag.StatusReason,
+		"version":            gorm.Expr("version + 1"),
 	}
 
-	result := s.DB.Debug().
-		Model(&model.UpdateFeatureFlag{}). // Use an empty struct for the model
-		Where("id = ?", id).
-		Updates(updateData)
+	if isDefaultEnvironment(featureFlag.Environment) {
+		updateData["is_active"] = featureFlag.IsActive // Explicitly include even if false
+		updateData["is_global"] = featureFlag.IsGlobal // Explicitly include even if false
+	}
+
+	updated := false
+	conflict := false
+	err := s.DB.Debug().Transaction(func(tx *gorm.DB) error {
+		query := tx.
+			Model(&model.UpdateFeatureFlag{}). // Use an empty struct for the model
+			Where("id = ?", id)
+		if featureFlag.Version != 0 {
+			query = query.Where("version = ?", featureFlag.Version)
+		}
+
+		result := query.Updates(updateData)
+		if result.Error != nil {
+			return result.Error
+		}
+		updated = result.RowsAffected > 0
+
+		// the flag is there but its version moved on, someone else changed it in the meantime
+		if !updated && featureFlag.Version != 0 {
+			var count int64
+			if err := tx.Model(&model.FeatureFlag{}).Where("id = ?", id).Count(&count).Error; err != nil {
+				return err
+			}
+			conflict = count > 0
+			return nil
+		}
+
+		if isDefaultEnvironment(featureFlag.Environment) {
+			return nil
+		}
+
+		// the state of the other environments is always written, so it counts as updated
+		updated = true
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "feature_flag_id"}, {Name: "environment"}},
+			DoUpdates: clause.AssignmentColumns([]string{"is_active", "is_global", "updated_at"}),
+		}).Create(&model.FeatureFlagEnvironment{
+			FeatureFlagID: id,
+			Environment:   featureFlag.Environment,
+			IsActive:      featureFlag.IsActive,
+			IsGlobal:      featureFlag.IsGlobal,
+		}).Error
+	})
 
 	// result := s.DB.Debug().Model(&featureFlag).Where("id = ?", id).Updates(&featureFlag)
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return errors.New("error when updating feature flag")
+	}
+	if conflict {
+		return errors.New("feature flag was changed by someone else")
+	}
+	if !updated {
+		return errors.New("no feature flag updated")
+	}
+
+	return nil
+}
+
+// GetFeatureFlagByName looks the flag up in the project, a zero project id looks it up in every project
+func (s *SqlRepository) GetFeatureFlagByName(projectId uint, name string, environment string) (model.FeatureFlag, error) {
+	query := s.DB.Debug().Model(&model.FeatureFlag{}).Preload("Rules", orderRulesByPosition).Preload("Variants", orderVariantsByPosition).Preload("Prerequisites", orderPrerequisitesByPosition).Preload("Prerequisites.Prerequisite").Where("name = ?", name)
+	if projectId != 0 {
+		query.Where("project_id = ?", projectId)
+	}
+
+	var featureFlags []model.FeatureFlag
+	if result := query.Order("id").Limit(1).Find(&featureFlags); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return model.FeatureFlag{}, errors.New("error when getting feature flag")
+	}
+
+	if len(featureFlags) == 0 {
+		return model.FeatureFlag{}, nil
+	}
+
+	if err := s.applyEnvironmentState(featureFlags, environment); err != nil {
+		return model.FeatureFlag{}, err
+	}
+
+	return featureFlags[0], nil
+}
+
+func (s *SqlRepository) ReplaceRules(featureFlagId uint, rules []model.Rule) error {
+	err := s.DB.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := nextVersion(tx, featureFlagId); err != nil {
+			return err
+		}
+
+		if err := tx.Where("feature_flag_id = ?", featureFlagId).Delete(&model.Rule{}).Error; err != nil {
+			return err
+		}
+
+		if len(rules) == 0 {
+			return nil
+		}
+
+		for i := range rules {
+			rules[i].FeatureFlagID = featureFlagId
+		}
+
+		return tx.Create(&rules).Error
+	})
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return errors.New("error when updating feature flag rules")
+	}
+
+	return nil
+}
+
+func orderRulesByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("feature_flag_rules.position")
+}
+
+func (s *SqlRepository) ReplaceVariants(featureFlagId uint, defaultVariant string, variants []model.Variant) error {
+	err := s.DB.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.FeatureFlag{}).Where("id = ?", featureFlagId).Updates(map[string]interface{}{
+			"default_variant": defaultVariant,
+			"version":         gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("feature_flag_id = ?", featureFlagId).Delete(&model.Variant{}).Error; err != nil {
+			return err
+		}
+
+		for i := range variants {
+			variants[i].FeatureFlagID = featureFlagId
+		}
+
+		return tx.Create(&variants).Error
+	})
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return errors.New("error when updating feature flag variants")
+	}
+
+	return nil
+}
+
+func orderVariantsByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("feature_flag_variants.position")
+}
+
+func (s *SqlRepository) ReplacePrerequisites(featureFlagId uint, prerequisites []model.Prerequisite) error {
+	err := s.DB.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := nextVersion(tx, featureFlagId); err != nil {
+			return err
+		}
+
+		if err := tx.Where("feature_flag_id = ?", featureFlagId).Delete(&model.Prerequisite{}).Error; err != nil {
+			return err
+		}
+
+		if len(prerequisites) == 0 {
+			return nil
+		}
+
+		for i := range prerequisites {
+			prerequisites[i].FeatureFlagID = featureFlagId
+		}
+
+		return tx.Omit("Prerequisite").Create(&prerequisites).Error
+	})
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return errors.New("error when updating feature flag prerequisites")
+	}
+
+	return nil
+}
+
+func orderPrerequisitesByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("feature_flag_prerequisites.position")
+}
+
+// nextVersion moves the version of the flag on, every change of the flag goes through it
+func nextVersion(tx *gorm.DB, featureFlagId uint) error {
+	return tx.Model(&model.FeatureFlag{}).Where("id = ?", featureFlagId).UpdateColumn("version", gorm.Expr("version + 1")).Error
+}
+
+// GetDependentFeatureFlags returns the flags that declare the flag as a prerequisite
+func (s *SqlRepository) GetDependentFeatureFlags(featureFlagId uint) ([]model.FeatureFlag, error) {
+	var featureFlags []model.FeatureFlag
+
+	result := s.DB.Debug().Model(&model.FeatureFlag{}).
+		Joins("INNER JOIN feature_flag_prerequisites ffp ON ffp.feature_flag_id = feature_flags.id").
+		Where("ffp.prerequisite_id = ?", featureFlagId).
+		Order("feature_flags.id").
+		Find(&featureFlags)
 	if result.Error != nil {
 		s.Logger.Error().Err(result.Error)
-		return errors.New("error when updating feature flag")
+		return nil, errors.New("error when getting dependent feature flags")
 	}
+
+	return featureFlags, nil
+}
+
+func (s *SqlRepository) GetExpiredFeatureFlags(date string) ([]model.FeatureFlag, error) {
+	var featureFlags []model.FeatureFlag
+
+	// expiration dates are stored as YYYY-MM-DD, so comparing them as text keeps the date order
+	result := s.DB.Debug().Model(&model.FeatureFlag{}).
+		Where("expiration_date IS NOT NULL AND expiration_date <> '' AND expiration_date < ?", date).
+		Where("expired_at IS NULL AND archived_at IS NULL").
+		Order("id").
+		Find(&featureFlags)
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return nil, errors.New("error when getting expired feature flags")
+	}
+
+	return featureFlags, nil
+}
+
+// ExpireFeatureFlag returns false when the flag was already expired, by another worker or before
+func (s *SqlRepository) ExpireFeatureFlag(id uint, deactivate bool, reason string, expiredAt time.Time) (bool, error) {
+	updateData := map[string]interface{}{
+		"expired_at":    expiredAt,
+		"status_reason": reason,
+		"version":       gorm.Expr("version + 1"),
+	}
+
+	if deactivate {
+		updateData["is_active"] = false
+	}
+
+	// an expired flag is deactivated in every environment
+	expired := false
+	err := s.DB.Debug().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.FeatureFlag{}).Where("id = ? AND expired_at IS NULL", id).Updates(updateData)
+		if result.Error != nil {
+			return result.Error
+		}
+		expired = result.RowsAffected > 0
+
+		if !expired || !deactivate {
+			return nil
+		}
+
+		return tx.Model(&model.FeatureFlagEnvironment{}).Where("feature_flag_id = ?", id).Update("is_active", false).Error
+	})
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return false, errors.New("error when expiring feature flag")
+	}
+
+	return expired, nil
+}
+
+// RollbackFeatureFlag writes the rollback in one transaction based on the version of the update,
+// the version moves on once whatever the rollback changes
+func (s *SqlRepository) RollbackFeatureFlag(id uint, rollback model.RollbackFeatureFlag) error {
+	update := rollback.Update
+	updateData := map[string]interface{}{
+		"version": gorm.Expr("version + 1"),
+	}
+
+	if rollback.Shared {
+		updateData["description"] = update.Description
+		updateData["expiration_date"] = update.ExpirationDate
+		updateData["rollout_percentage"] = update.RolloutPercentage
+		updateData["expired_at"] = update.ExpiredAt
+		updateData["status_reason"] = update.StatusReason
+		updateData["default_variant"] = rollback.DefaultVariant
+	}
+
+	if isDefaultEnvironment(update.Environment) {
+		updateData["is_active"] = update.IsActive
+		updateData["is_global"] = update.IsGlobal
+	}
+
+	conflict := false
+	err := s.DB.Debug().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.FeatureFlag{}).Where("id = ?", id).Where("version = ?", update.Version).Updates(updateData)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// nothing is written yet, the flag changed since the rollback read it
+		if result.RowsAffected == 0 {
+			conflict = true
+			return nil
+		}
+
+		if !isDefaultEnvironment(update.Environment) {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "feature_flag_id"}, {Name: "environment"}},
+				DoUpdates: clause.AssignmentColumns([]string{"is_active", "is_global", "updated_at"}),
+			}).Create(&model.FeatureFlagEnvironment{
+				FeatureFlagID: id,
+				Environment:   update.Environment,
+				IsActive:      update.IsActive,
+				IsGlobal:      update.IsGlobal,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		if rollback.Shared {
+			if err := rollbackSharedRows(tx, id, rollback); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("feature_flag_id = ? AND environment = ?", id, update.Environment).Delete(&model.Assignment{}).Error; err != nil {
+			return err
+		}
+
+		if len(rollback.Assignments) == 0 {
+			return nil
+		}
+
+		for i := range rollback.Assignments {
+			rollback.Assignments[i].FeatureFlagID = id
+			rollback.Assignments[i].Environment = update.Environment
+		}
+
+		return tx.Create(&rollback.Assignments).Error
+	})
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return errors.New("error when rolling back feature flag")
+	}
+	if conflict {
+		return errors.New("feature flag was changed by someone else")
+	}
+
+	return nil
+}
+
+// rollbackSharedRows replaces the rules, the variants and the prerequisites of the flag
+func rollbackSharedRows(tx *gorm.DB, id uint, rollback model.RollbackFeatureFlag) error {
+	if err := tx.Where("feature_flag_id = ?", id).Delete(&model.Rule{}).Error; err != nil {
+		return err
+	}
+	for i := range rollback.Rules {
+		rollback.Rules[i].FeatureFlagID = id
+	}
+	if len(rollback.Rules) > 0 {
+		if err := tx.Create(&rollback.Rules).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Where("feature_flag_id = ?", id).Delete(&model.Variant{}).Error; err != nil {
+		return err
+	}
+	for i := range rollback.Variants {
+		rollback.Variants[i].FeatureFlagID = id
+	}
+	if len(rollback.Variants) > 0 {
+		if err := tx.Create(&rollback.Variants).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Where("feature_flag_id = ?", id).Delete(&model.Prerequisite{}).Error; err != nil {
+		return err
+	}
+	for i := range rollback.Prerequisites {
+		rollback.Prerequisites[i].FeatureFlagID = id
+	}
+	if len(rollback.Prerequisites) > 0 {
+		return tx.Omit("Prerequisite").Create(&rollback.Prerequisites).Error
+	}
+
+	return nil
+}
+
+// ArchiveFeatureFlag sets the archive date of the flag, a nil date restores it
+func (s *SqlRepository) ArchiveFeatureFlag(id uint, archivedAt *time.Time) error {
+	result := s.DB.Debug().Model(&model.FeatureFlag{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"archived_at": archivedAt,
+		"version":     gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when archiving feature flag")
+	}
+
 	if result.RowsAffected == 0 {
-		return errors.New("no feature flag updated")
+		return errors.New("feature flag not found")
+	}
+
+	return nil
+}
+
+// DeleteFeatureFlag removes the flag with everything referencing it, the assignments, the group
+// assignments, the scheduled changes and the prerequisites of the flags depending on it. The
+// revisions are kept as the history of the flag
+func (s *SqlRepository) DeleteFeatureFlag(id uint) error {
+	err := s.DB.Debug().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("feature_flag_id = ?", id).Delete(&model.Assignment{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("feature_flag_id = ?", id).Delete(&model.GroupAssignment{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("feature_flag_id = ?", id).Delete(&model.ScheduledChange{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("feature_flag_id = ? OR prerequisite_id = ?", id, id).Delete(&model.Prerequisite{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("feature_flag_id = ?", id).Delete(&model.Rule{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("feature_flag_id = ?", id).Delete(&model.Variant{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("feature_flag_id = ?", id).Delete(&model.FeatureFlagEnvironment{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ?", id).Delete(&model.FeatureFlag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("feature flag not found")
+	}
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return errors.New("error when deleting feature flag")
+	}
+
+	return nil
+}
+
+func isDefaultEnvironment(environment string) bool {
+	return environment == "" || environment == model.DefaultEnvironment
+}
+
+// environmentColumns returns the is_active and is_global columns of the environment,
+// other environments are read from the feature_flag_environments table joined as ffe
+func environmentColumns(table string, environment string) (string, string) {
+	if isDefaultEnvironment(environment) {
+		return table + ".is_active", table + ".is_global"
+	}
+
+	return "COALESCE(ffe.is_active, false)", "COALESCE(ffe.is_global, false)"
+}
+
+// applyEnvironmentState replaces the default environment state of the flags by the state
+// in the environment, a flag never changed in the environment is inactive there
+func (s *SqlRepository) applyEnvironmentState(featureFlags []model.FeatureFlag, environment string) error {
+	if isDefaultEnvironment(environment) || len(featureFlags) == 0 {
+		return nil
+	}
+
+	var ids []uint
+	for _, featureFlag := range featureFlags {
+		ids = append(ids, featureFlag.ID)
+	}
+
+	var states []model.FeatureFlagEnvironment
+	if err := s.DB.Debug().Where("feature_flag_id IN ? AND environment = ?", ids, environment).Find(&states).Error; err != nil {
+		s.Logger.Error().Err(err)
+		return errors.New("error when getting feature flag environments")
+	}
+
+	for i := range featureFlags {
+		featureFlags[i].IsActive = false
+		featureFlags[i].IsGlobal = false
+
+		for _, state := range states {
+			if state.FeatureFlagID == featureFlags[i].ID {
+				featureFlags[i].IsActive = state.IsActive
+				featureFlags[i].IsGlobal = state.IsGlobal
+			}
+		}
 	}
 
 	return nil
@@ -1,19 +1,22 @@
 package repository
 
 import (
+	"ff/config/database"
 	model "ff/internal/db/model"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/suite"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 // TestSqlRepository is a test suite for SqlRepository
 type TestSqlRepository struct {
 	suite.Suite
+	driver string
+	dsn    string
 	db     *gorm.DB
 	logger *zerolog.Logger
 	repo   *SqlRepository
@@ -22,21 +25,45 @@ type TestSqlRepository struct {
 var personOnDB []model.Person
 
 func TestFeatureFlagRepository(t *testing.T) {
-	suite.Run(t, new(TestSqlRepository))
+	suite.Run(t, &TestSqlRepository{driver: database.DriverSQLite, dsn: "file::memory:?cache=shared"})
+}
+
+// The suite runs against PostgreSQL and MySQL when a database is informed, `make test-dialects`
+// starts both in containers. Every table of the database is dropped between the tests
+func TestFeatureFlagRepositoryPostgres(t *testing.T) {
+	runDialect(t, database.DriverPostgres, "TEST_POSTGRES_DSN")
+}
+
+func TestFeatureFlagRepositoryMySQL(t *testing.T) {
+	runDialect(t, database.DriverMySQL, "TEST_MYSQL_DSN")
+}
+
+func runDialect(t *testing.T, driver string, env string) {
+	dsn := os.Getenv(env)
+	if dsn == "" {
+		t.Skipf("%s is not set", env)
+	}
+
+	suite.Run(t, &TestSqlRepository{driver: driver, dsn: dsn})
 }
 
 func (s *TestSqlRepository) SetupTest() {
-	// Create an in-memory SQLite database for testing
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	dialector, err := database.Dialector(s.driver, s.dsn)
 	s.Require().NoError(err)
 
-	// Run migrations
-	err = db.AutoMigrate(&model.FeatureFlag{}, &model.Person{})
+	db, err := gorm.Open(dialector, &gorm.Config{})
 	s.Require().NoError(err)
 
 	// Create a test logger
 	logger := zerolog.New(os.Stdout)
 
+	// Run migrations on empty tables
+	s.Require().NoError(db.Migrator().DropTable(database.Models()...))
+	migrator, err := database.NewMigrator(db, s.driver, &logger)
+	s.Require().NoError(err)
+	_, err = migrator.Up()
+	s.Require().NoError(err)
+
 	// Initialize the repository
 	s.db = db
 	s.logger = &logger
@@ -267,9 +294,10 @@ func (s *TestSqlRepository) TestUpdateFeatureFlagById() {
 		s.Require().NoError(result.Error)
 
 		updatedFeatureFlag := model.UpdateFeatureFlag{
-			Description:    featureFlag.Description,
-			IsActive:       false,
-			ExpirationDate: featureFlag.ExpirationDate,
+			Description:       featureFlag.Description,
+			IsActive:          false,
+			ExpirationDate:    featureFlag.ExpirationDate,
+			RolloutPercentage: 25,
 		}
 		err = s.repo.UpdateFeatureFlagById(featureFlagOnDB.ID, updatedFeatureFlag)
 		s.Require().NoError(err)
@@ -281,6 +309,7 @@ func (s *TestSqlRepository) TestUpdateFeatureFlagById() {
 		s.Equal(featureFlag.Description, savedFlag.Description)
 		// Verify the feature flag was updated
 		s.Equal(updatedFeatureFlag.IsActive, savedFlag.IsActive)
+		s.Equal(updatedFeatureFlag.RolloutPercentage, savedFlag.RolloutPercentage)
 		// s.Equal(featureFlag.Person.ID, savedFlag.Person.ID)
 	})
 
@@ -294,4 +323,414 @@ func (s *TestSqlRepository) TestUpdateFeatureFlagById() {
 		s.Require().Error(err)
 		s.Equal("no feature flag updated", err.Error())
 	})
+
+	s.Run("Update based on an older version is refused", func() {
+		err := s.repo.AddFeatureFlag(model.FeatureFlag{Name: "VERSIONED_FLAG", Description: "Test Description", PersonID: personOnDB[0].ID})
+		s.Require().NoError(err)
+
+		featureFlagOnDB, err := s.repo.GetFeatureFlagByName(0, "VERSIONED_FLAG", "")
+		s.Require().NoError(err)
+		s.Equal(uint(1), featureFlagOnDB.Version)
+
+		err = s.repo.UpdateFeatureFlagById(featureFlagOnDB.ID, model.UpdateFeatureFlag{Description: "First", Version: 1})
+		s.Require().NoError(err)
+
+		err = s.repo.UpdateFeatureFlagById(featureFlagOnDB.ID, model.UpdateFeatureFlag{Description: "Second", Version: 1})
+		s.EqualError(err, "feature flag was changed by someone else")
+
+		s.Require().NoError(s.repo.ReplaceRules(featureFlagOnDB.ID, nil))
+
+		savedFlag, err := s.repo.GetFeatureFlagByName(0, "VERSIONED_FLAG", "")
+		s.Require().NoError(err)
+		s.Equal("First", savedFlag.Description)
+		s.Equal(uint(3), savedFlag.Version)
+
+		err = s.repo.UpdateFeatureFlagById(featureFlagOnDB.ID, model.UpdateFeatureFlag{Description: "Third", Environment: "staging", IsActive: true, Version: 2})
+		s.EqualError(err, "feature flag was changed by someone else")
+
+		var environments int64
+		s.Require().NoError(s.db.Model(&model.FeatureFlagEnvironment{}).Where("feature_flag_id = ?", featureFlagOnDB.ID).Count(&environments).Error)
+		s.Equal(int64(0), environments)
+	})
+}
+
+// Get Feature Flag By Name Tests Cases
+func (s *TestSqlRepository) TestGetFeatureFlagByName() {
+	featureFlagsOnDB := []model.FeatureFlag{
+		{
+			Name:        "TEST_FLAG",
+			Description: "Test Description 1",
+			IsActive:    true,
+			PersonID:    personOnDB[0].ID,
+		},
+		{
+			Name:        "TEST_FLAG_V2",
+			Description: "Test Description 2",
+			IsActive:    false,
+			PersonID:    personOnDB[0].ID,
+		}}
+
+	s.db.CreateInBatches(featureFlagsOnDB, len(featureFlagsOnDB))
+
+	s.Run("Get feature flag by exact name", func() {
+		featureFlag, err := s.repo.GetFeatureFlagByName(0, "TEST_FLAG", "")
+		s.Require().NoError(err)
+		s.Require().NotZero(featureFlag.ID)
+		s.Equal("TEST_FLAG", featureFlag.Name)
+		s.Equal("Test Description 1", featureFlag.Description)
+	})
+
+	s.Run("Get non existing feature flag by name", func() {
+		featureFlag, err := s.repo.GetFeatureFlagByName(0, "TEST", "")
+		s.Require().NoError(err)
+		s.Equal(uint(0), featureFlag.ID)
+	})
+}
+
+// Replace Rules Tests Cases
+func (s *TestSqlRepository) TestReplaceRules() {
+	featureFlag := model.FeatureFlag{
+		Name:        "TEST_RULES_FLAG",
+		Description: "Test Description",
+		IsActive:    true,
+		PersonID:    personOnDB[0].ID,
+		Rules: []model.Rule{
+			{Position: 0, Attribute: "country", Operator: "in", Values: []string{"BR", "PT"}, Result: true},
+		},
+	}
+
+	err := s.repo.AddFeatureFlag(featureFlag)
+	s.Require().NoError(err)
+
+	s.Run("Rules are created with the feature flag", func() {
+		savedFlag, err := s.repo.GetFeatureFlagByName(0, featureFlag.Name, "")
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(savedFlag.Rules))
+		s.Equal([]string{"BR", "PT"}, savedFlag.Rules[0].Values)
+	})
+
+	s.Run("Successfully replace rules keeping the order", func() {
+		savedFlag, err := s.repo.GetFeatureFlagByName(0, featureFlag.Name, "")
+		s.Require().NoError(err)
+
+		err = s.repo.ReplaceRules(savedFlag.ID, []model.Rule{
+			{Position: 1, Attribute: "plan", Operator: "equals", Values: []string{"enterprise"}, Result: true},
+			{Position: 0, Attribute: "email", Operator: "ends_with", Values: []string{"@ourcompany.com"}, Result: true},
+		})
+		s.Require().NoError(err)
+
+		savedFlag, err = s.repo.GetFeatureFlagByName(0, featureFlag.Name, "")
+		s.Require().NoError(err)
+		s.Require().Equal(2, len(savedFlag.Rules))
+		s.Equal("email", savedFlag.Rules[0].Attribute)
+		s.Equal("plan", savedFlag.Rules[1].Attribute)
+	})
+
+	s.Run("Successfully remove all rules", func() {
+		savedFlag, err := s.repo.GetFeatureFlagByName(0, featureFlag.Name, "")
+		s.Require().NoError(err)
+
+		err = s.repo.ReplaceRules(savedFlag.ID, nil)
+		s.Require().NoError(err)
+
+		savedFlag, err = s.repo.GetFeatureFlagByName(0, featureFlag.Name, "")
+		s.Require().NoError(err)
+		s.Equal(0, len(savedFlag.Rules))
+	})
+}
+
+// Replace Variants Tests Cases
+func (s *TestSqlRepository) TestReplaceVariants() {
+	featureFlag := model.FeatureFlag{
+		Name:           "TEST_VARIANTS_FLAG",
+		Description:    "Test Description",
+		IsActive:       true,
+		PersonID:       personOnDB[0].ID,
+		Type:           "string",
+		DefaultVariant: "control",
+		Variants: []model.Variant{
+			{Position: 0, Name: "control", Value: "control", Weight: 100},
+		},
+	}
+
+	err := s.repo.AddFeatureFlag(featureFlag)
+	s.Require().NoError(err)
+
+	s.Run("Successfully replace variants and default variant", func() {
+		savedFlag, err := s.repo.GetFeatureFlagByName(0, featureFlag.Name, "")
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(savedFlag.Variants))
+
+		err = s.repo.ReplaceVariants(savedFlag.ID, "blue_button", []model.Variant{
+			{Position: 0, Name: "blue_button", Value: "blue", Weight: 40},
+			{Position: 1, Name: "green_button", Value: "green", Weight: 60},
+		})
+		s.Require().NoError(err)
+
+		savedFlag, err = s.repo.GetFeatureFlagByName(0, featureFlag.Name, "")
+		s.Require().NoError(err)
+		s.Equal("blue_button", savedFlag.DefaultVariant)
+		s.Equal("string", savedFlag.Type)
+		s.Require().Equal(2, len(savedFlag.Variants))
+		s.Equal("blue_button", savedFlag.Variants[0].Name)
+		s.Equal(60, savedFlag.Variants[1].Weight)
+	})
+}
+
+func (s *TestSqlRepository) TestReplacePrerequisites() {
+	for _, name := range []string{"NEW_CHECKOUT", "NEW_CHECKOUT_PAYPAL"} {
+		err := s.repo.AddFeatureFlag(model.FeatureFlag{Name: name, Description: "Test Description", IsActive: true, PersonID: personOnDB[0].ID})
+		s.Require().NoError(err)
+	}
+
+	s.Run("Successfully replace prerequisites and find dependents", func() {
+		checkout, err := s.repo.GetFeatureFlagByName(0, "NEW_CHECKOUT", "")
+		s.Require().NoError(err)
+		paypal, err := s.repo.GetFeatureFlagByName(0, "NEW_CHECKOUT_PAYPAL", "")
+		s.Require().NoError(err)
+
+		err = s.repo.ReplacePrerequisites(paypal.ID, []model.Prerequisite{{Position: 0, PrerequisiteID: checkout.ID, Variant: "treatment"}})
+		s.Require().NoError(err)
+
+		paypal, err = s.repo.GetFeatureFlagByName(0, "NEW_CHECKOUT_PAYPAL", "")
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(paypal.Prerequisites))
+		s.Equal("NEW_CHECKOUT", paypal.Prerequisites[0].Prerequisite.Name)
+		s.Equal("treatment", paypal.Prerequisites[0].Variant)
+
+		dependents, err := s.repo.GetDependentFeatureFlags(checkout.ID)
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(dependents))
+		s.Equal("NEW_CHECKOUT_PAYPAL", dependents[0].Name)
+
+		err = s.repo.ReplacePrerequisites(paypal.ID, nil)
+		s.Require().NoError(err)
+
+		dependents, err = s.repo.GetDependentFeatureFlags(checkout.ID)
+		s.Require().NoError(err)
+		s.Empty(dependents)
+	})
+}
+
+func (s *TestSqlRepository) TestRollbackFeatureFlag() {
+	featureFlag := model.FeatureFlag{
+		Name:           "TEST_ROLLBACK_FLAG",
+		Description:    "Test Description",
+		IsActive:       true,
+		PersonID:       personOnDB[0].ID,
+		Type:           "string",
+		DefaultVariant: "control",
+		Variants: []model.Variant{
+			{Position: 0, Name: "control", Value: "control", Weight: 100},
+		},
+	}
+	s.Require().NoError(s.repo.AddFeatureFlag(featureFlag))
+	featureFlag, err := s.repo.GetFeatureFlagByName(0, featureFlag.Name, "")
+	s.Require().NoError(err)
+	s.Require().NoError(s.repo.ApplyAssignment(model.Assignment{PersonID: personOnDB[0].ID, FeatureFlagID: featureFlag.ID, Kind: model.AssignmentInclude, Environment: "staging"}))
+
+	s.Run("Environment rollback leaves the shared parts alone", func() {
+		err := s.repo.RollbackFeatureFlag(featureFlag.ID, model.RollbackFeatureFlag{
+			Update:      model.UpdateFeatureFlag{IsGlobal: true, Environment: "staging", Version: featureFlag.Version},
+			Assignments: []model.Assignment{{PersonID: personOnDB[1].ID, Kind: model.AssignmentExclude}},
+		})
+		s.Require().NoError(err)
+
+		staging, err := s.repo.GetFeatureFlagByName(0, featureFlag.Name, "staging")
+		s.Require().NoError(err)
+		s.False(staging.IsActive)
+		s.True(staging.IsGlobal)
+		s.Equal("Test Description", staging.Description)
+		s.Equal(featureFlag.Version+1, staging.Version)
+
+		assignments, err := s.repo.GetAssignmentsByFeatureFlagId(featureFlag.ID, "staging")
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(assignments))
+		s.Equal(personOnDB[1].ID, assignments[0].PersonID)
+		s.Equal(model.AssignmentExclude, assignments[0].Kind)
+
+		production, err := s.repo.GetFeatureFlagByName(0, featureFlag.Name, "")
+		s.Require().NoError(err)
+		s.True(production.IsActive)
+		s.False(production.IsGlobal)
+	})
+
+	s.Run("Default environment rollback replaces the shared parts", func() {
+		current, err := s.repo.GetFeatureFlagByName(0, featureFlag.Name, "")
+		s.Require().NoError(err)
+
+		err = s.repo.RollbackFeatureFlag(featureFlag.ID, model.RollbackFeatureFlag{
+			Update:         model.UpdateFeatureFlag{Description: "Rolled back", RolloutPercentage: 20, Environment: model.DefaultEnvironment, Version: current.Version},
+			Shared:         true,
+			DefaultVariant: "blue",
+			Variants:       []model.Variant{{Position: 0, Name: "blue", Value: "blue", Weight: 100}},
+			Rules:          []model.Rule{{Position: 0, Attribute: "country", Operator: "eq", Values: []string{"BR"}}},
+		})
+		s.Require().NoError(err)
+
+		rolledBack, err := s.repo.GetFeatureFlagByName(0, featureFlag.Name, "")
+		s.Require().NoError(err)
+		s.False(rolledBack.IsActive)
+		s.Equal("Rolled back", rolledBack.Description)
+		s.Equal(20, rolledBack.RolloutPercentage)
+		s.Equal("blue", rolledBack.DefaultVariant)
+		s.Require().Equal(1, len(rolledBack.Variants))
+		s.Equal("blue", rolledBack.Variants[0].Name)
+		s.Equal(1, len(rolledBack.Rules))
+
+		// the assignments of the other environments are kept
+		assignments, err := s.repo.GetAssignmentsByFeatureFlagId(featureFlag.ID, "staging")
+		s.Require().NoError(err)
+		s.Equal(1, len(assignments))
+	})
+
+	s.Run("Stale version writes nothing", func() {
+		err := s.repo.RollbackFeatureFlag(featureFlag.ID, model.RollbackFeatureFlag{
+			Update: model.UpdateFeatureFlag{Description: "Stale", Environment: model.DefaultEnvironment, Version: featureFlag.Version},
+			Shared: true,
+		})
+		s.EqualError(err, "feature flag was changed by someone else")
+
+		current, err := s.repo.GetFeatureFlagByName(0, featureFlag.Name, "")
+		s.Require().NoError(err)
+		s.Equal("Rolled back", current.Description)
+		s.Equal(1, len(current.Variants))
+	})
+}
+
+// Expire Feature Flags Tests Cases
+func (s *TestSqlRepository) TestExpireFeatureFlags() {
+	featureFlagsOnDB := []model.FeatureFlag{
+		{
+			Name:           "EXPIRED_FLAG",
+			Description:    "Test Description 1",
+			IsActive:       true,
+			ExpirationDate: "2024-10-01",
+			PersonID:       personOnDB[0].ID,
+		},
+		{
+			Name:           "VALID_FLAG",
+			Description:    "Test Description 2",
+			IsActive:       true,
+			ExpirationDate: "2024-10-10",
+			PersonID:       personOnDB[0].ID,
+		},
+		{
+			Name:        "NO_EXPIRATION_FLAG",
+			Description: "Test Description 3",
+			IsActive:    true,
+			PersonID:    personOnDB[0].ID,
+		}}
+
+	s.db.CreateInBatches(featureFlagsOnDB, len(featureFlagsOnDB))
+
+	s.Run("Get only flags expired before the date", func() {
+		featureFlags, err := s.repo.GetExpiredFeatureFlags("2024-10-10")
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(featureFlags))
+		s.Equal("EXPIRED_FLAG", featureFlags[0].Name)
+	})
+
+	s.Run("Expired flags are deactivated and not returned again", func() {
+		featureFlags, err := s.repo.GetExpiredFeatureFlags("2024-10-10")
+		s.Require().NoError(err)
+
+		expired, err := s.repo.ExpireFeatureFlag(featureFlags[0].ID, true, "expired on 2024-10-01", time.Now())
+		s.Require().NoError(err)
+		s.True(expired)
+
+		// a second worker does not expire it again
+		expired, err = s.repo.ExpireFeatureFlag(featureFlags[0].ID, true, "expired on 2024-10-01", time.Now())
+		s.Require().NoError(err)
+		s.False(expired)
+
+		savedFlag, err := s.repo.GetFeatureFlagByName(0, "EXPIRED_FLAG", "")
+		s.Require().NoError(err)
+		s.False(savedFlag.IsActive)
+		s.NotNil(savedFlag.ExpiredAt)
+		s.Equal("expired on 2024-10-01", savedFlag.StatusReason)
+
+		featureFlags, err = s.repo.GetExpiredFeatureFlags("2024-10-10")
+		s.Require().NoError(err)
+		s.Equal(0, len(featureFlags))
+	})
+}
+
+// Archive and Delete Feature Flags Tests Cases
+func (s *TestSqlRepository) TestArchiveFeatureFlag() {
+	for _, name := range []string{"NEW_CHECKOUT", "NEW_CHECKOUT_PAYPAL"} {
+		err := s.repo.AddFeatureFlag(model.FeatureFlag{Name: name, Description: "Test Description", IsActive: true, PersonID: personOnDB[0].ID})
+		s.Require().NoError(err)
+	}
+
+	checkout, err := s.repo.GetFeatureFlagByName(0, "NEW_CHECKOUT", "")
+	s.Require().NoError(err)
+	paypal, err := s.repo.GetFeatureFlagByName(0, "NEW_CHECKOUT_PAYPAL", "")
+	s.Require().NoError(err)
+
+	archived := true
+	notArchived := false
+	onePage := model.Pagination{Limit: 10, Page: 1}
+
+	s.Run("Archived flags are filtered out", func() {
+		archivedAt := time.Now()
+		s.Require().NoError(s.repo.ArchiveFeatureFlag(checkout.ID, &archivedAt))
+
+		featureFlags, total, err := s.repo.GetFeatureFlag(model.FeatureFlagFilters{Archived: &notArchived}, onePage)
+		s.Require().NoError(err)
+		s.Equal(int64(1), total)
+		s.Equal("NEW_CHECKOUT_PAYPAL", featureFlags[0].Name)
+
+		featureFlags, total, err = s.repo.GetFeatureFlag(model.FeatureFlagFilters{Archived: &archived}, onePage)
+		s.Require().NoError(err)
+		s.Equal(int64(1), total)
+		s.Equal("NEW_CHECKOUT", featureFlags[0].Name)
+		s.NotNil(featureFlags[0].ArchivedAt)
+
+		_, total, err = s.repo.GetFeatureFlag(model.FeatureFlagFilters{}, onePage)
+		s.Require().NoError(err)
+		s.Equal(int64(2), total)
+
+		snapshot, err := s.repo.GetFeatureFlagSnapshot(0, "")
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(snapshot.FeatureFlags))
+		s.Equal("NEW_CHECKOUT_PAYPAL", snapshot.FeatureFlags[0].Name)
+	})
+
+	s.Run("Restored flags are listed again", func() {
+		s.Require().NoError(s.repo.ArchiveFeatureFlag(checkout.ID, nil))
+
+		_, total, err := s.repo.GetFeatureFlag(model.FeatureFlagFilters{Archived: &notArchived}, onePage)
+		s.Require().NoError(err)
+		s.Equal(int64(2), total)
+	})
+
+	s.Run("Archive a non existing feature flag", func() {
+		err := s.repo.ArchiveFeatureFlag(999, nil)
+		s.EqualError(err, "feature flag not found")
+	})
+
+	s.Run("Delete the flag with its assignments and prerequisites", func() {
+		s.Require().NoError(s.repo.ReplacePrerequisites(paypal.ID, []model.Prerequisite{{Position: 0, PrerequisiteID: checkout.ID}}))
+		s.Require().NoError(s.repo.ApplyAssignment(model.Assignment{PersonID: personOnDB[0].ID, FeatureFlagID: checkout.ID, Kind: model.AssignmentInclude, Environment: model.DefaultEnvironment}))
+
+		s.Require().NoError(s.repo.DeleteFeatureFlag(checkout.ID))
+
+		_, total, err := s.repo.GetFeatureFlag(model.FeatureFlagFilters{ID: checkout.ID}, onePage)
+		s.Require().NoError(err)
+		s.Equal(int64(0), total)
+
+		var assignments int64
+		s.Require().NoError(s.db.Model(&model.Assignment{}).Where("feature_flag_id = ?", checkout.ID).Count(&assignments).Error)
+		s.Equal(int64(0), assignments)
+
+		paypal, err := s.repo.GetFeatureFlagByName(0, "NEW_CHECKOUT_PAYPAL", "")
+		s.Require().NoError(err)
+		s.Empty(paypal.Prerequisites)
+	})
+
+	s.Run("Delete a non existing feature flag", func() {
+		err := s.repo.DeleteFeatureFlag(999)
+		s.EqualError(err, "feature flag not found")
+	})
 }
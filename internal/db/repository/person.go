@@ -4,29 +4,50 @@ import (
 	"errors"
 	model "ff/internal/db/model"
 	p_entity "ff/internal/person/entity"
+	"fmt"
+	"strings"
 )
 
+// segmentAssignmentQuery checks if a person is enabled for a feature flag through a segment,
+// it expects the person id and the feature flag id
+const segmentAssignmentQuery = "EXISTS (SELECT 1 FROM assignment_group_members agm INNER JOIN feature_flag_group_assignments fga ON fga.assignment_group_id = agm.assignment_group_id WHERE agm.person_id = %s AND fga.feature_flag_id = %s)"
+
 func (s *SqlRepository) GetPeopleAssignmentByFeatureFlag(pagination model.Pagination, filters p_entity.PersonFilters) ([]model.PersonWithAssignment, int64, error) {
-	var featureFlag model.FeatureFlag
-	err := s.DB.Debug().Model(&model.FeatureFlag{}).Where("id = ?", filters.FeatureFlagID).Find(&featureFlag).Error
+	environment := filters.Environment
+	if environment == "" {
+		environment = model.DefaultEnvironment
+	}
+
+	featureFlags := []model.FeatureFlag{}
+	err := s.DB.Debug().Model(&model.FeatureFlag{}).Where("id = ?", filters.FeatureFlagID).Limit(1).Find(&featureFlags).Error
 	if err != nil {
 		s.Logger.Error().Err(err)
 		return nil, 0, errors.New("error when getting feature flag")
 	}
 
+	if err := s.applyEnvironmentState(featureFlags, environment); err != nil {
+		return nil, 0, err
+	}
+
+	var featureFlag model.FeatureFlag
+	if len(featureFlags) > 0 {
+		featureFlag = featureFlags[0]
+	}
+
 	query := s.DB.Debug().
 		Table("person p").
-		Select("p.id, p.name, p.email, IF(ffa.id IS NULL, false, true) AS is_assigned").
-		Joins("LEFT JOIN feature_flag_assignments ffa ON ffa.person_id = p.id AND ffa.feature_flag_id = ?", filters.FeatureFlagID).
-		Joins("LEFT JOIN feature_flags ff ON ff.id = ffa.feature_flag_id").
+		Select("p.id, p.name, p.email, CASE WHEN ffa.kind = ? THEN true ELSE false END AS is_assigned, CASE WHEN ffa.kind = ? THEN true ELSE false END AS is_excluded", model.AssignmentInclude, model.AssignmentExclude).
+		Joins("LEFT JOIN feature_flag_assignments ffa ON ffa.person_id = p.id AND ffa.feature_flag_id = ? AND ffa.environment = ?", filters.FeatureFlagID, environment).
 		Order("p.id")
 
 	if filters.Name != "" {
-		query = query.Where("p.name LIKE ?", "%"+filters.Name+"%")
+		query = query.Where("LOWER(p.name) LIKE ?", "%"+strings.ToLower(filters.Name)+"%")
 	}
 
 	if filters.IsAssigned != nil && *filters.IsAssigned {
-		query.Where("(ff.is_global = false AND ffa.id IS NOT NULL)")
+		query.Where("((? = false AND ffa.kind = ?) OR "+fmt.Sprintf(segmentAssignmentQuery, "p.id", "?")+")", featureFlag.IsGlobal, model.AssignmentInclude, filters.FeatureFlagID)
+		// excluded people are never assigned, not even by a segment
+		query.Where("(ffa.id IS NULL OR ffa.kind <> ?)", model.AssignmentExclude)
 	}
 
 	// get total count
@@ -46,6 +67,18 @@ func (s *SqlRepository) GetPeopleAssignmentByFeatureFlag(pagination model.Pagina
 		return nil, 0, errors.New("error when getting people")
 	}
 
+	var personIds []uint
+	for _, person := range people {
+		personIds = append(personIds, person.ID)
+	}
+
+	segments := map[uint][]string{}
+	if len(personIds) > 0 {
+		if segments, err = s.getSegmentsByFeatureFlag(filters.FeatureFlagID, personIds); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	var response []model.PersonWithAssignment
 	for _, person := range people {
 		response = append(response, model.PersonWithAssignment{
@@ -53,26 +86,102 @@ func (s *SqlRepository) GetPeopleAssignmentByFeatureFlag(pagination model.Pagina
 			Name:       person.Name,
 			Email:      person.Email,
 			IsAssigned: person.IsAssigned,
-			IsGlobal:   featureFlag.IsGlobal || person.IsAssigned,
+			IsExcluded: person.IsExcluded,
+			IsGlobal:   (featureFlag.IsGlobal || person.IsAssigned) && !person.IsExcluded,
+			Segments:   segments[person.ID],
 		})
 	}
 
 	return response, totalCount, nil
 }
 
-func (s *SqlRepository) GetAssignedFeatureFlagsByPersonId(id uint) ([]model.AssignedFeatureFlag, error) {
+func (s *SqlRepository) GetAssignedFeatureFlagsByPersonId(id uint, projectId uint, environment string) ([]model.AssignedFeatureFlag, error) {
 	var featureFlags []model.AssignedFeatureFlag
 
-	err := s.DB.Debug().Model(&model.AssignedFeatureFlag{}).Table("feature_flags ff").
-		Select("ff.id, ff.name, ff.is_active, ff.is_global, if(ffa.id is null, false, true) is_assigned").
-		Joins("LEFT JOIN feature_flag_assignments ffa ON ffa.feature_flag_id = ff.id AND ffa.person_id = ?", id).
-		Order("ff.id").
-		Scan(&featureFlags).Error
+	if environment == "" {
+		environment = model.DefaultEnvironment
+	}
+
+	isActiveColumn, isGlobalColumn := environmentColumns("ff", environment)
+	query := s.DB.Debug().Model(&model.AssignedFeatureFlag{}).Table("feature_flags ff").
+		Select("ff.id, ff.name, "+isActiveColumn+" is_active, "+isGlobalColumn+" is_global, ff.expiration_date, ff.rollout_percentage, ff.type, ff.default_variant, ffa.variant assigned_variant, "+
+			"CASE WHEN ffa.kind = ? THEN true ELSE false END is_assigned, CASE WHEN ffa.kind = ? THEN true ELSE false END is_excluded, "+
+			"CASE WHEN "+fmt.Sprintf(segmentAssignmentQuery, "?", "ff.id")+" THEN true ELSE false END is_assigned_via_segment", model.AssignmentInclude, model.AssignmentExclude, id).
+		Joins("LEFT JOIN feature_flag_assignments ffa ON ffa.feature_flag_id = ff.id AND ffa.person_id = ? AND ffa.environment = ?", id, environment)
+
+	if !isDefaultEnvironment(environment) {
+		query.Joins("LEFT JOIN feature_flag_environments ffe ON ffe.feature_flag_id = ff.id AND ffe.environment = ?", environment)
+	}
+
+	if projectId != 0 {
+		query.Where("ff.project_id = ?", projectId)
+	}
+
+	// archived flags are off for everybody
+	query.Where("ff.archived_at IS NULL")
+
+	err := query.Order("ff.id").Scan(&featureFlags).Error
 
 	if err != nil {
 		s.Logger.Error().Err(err)
 		return nil, errors.New("error when getting feature flag by people")
 	}
 
+	if len(featureFlags) == 0 {
+		return featureFlags, nil
+	}
+
+	ids := make([]uint, len(featureFlags))
+	for i, featureFlag := range featureFlags {
+		ids[i] = featureFlag.ID
+	}
+
+	// variants are loaded apart since the list above is a raw scan, only the ones of the listed flags
+	var variants []model.Variant
+	if err := s.DB.Debug().Where("feature_flag_id IN ?", ids).Order("feature_flag_id, position").Find(&variants).Error; err != nil {
+		s.Logger.Error().Err(err)
+		return nil, errors.New("error when getting feature flag variants")
+	}
+
+	variantsByFeatureFlag := make(map[uint][]model.Variant)
+	for _, variant := range variants {
+		variantsByFeatureFlag[variant.FeatureFlagID] = append(variantsByFeatureFlag[variant.FeatureFlagID], variant)
+	}
+
+	for i := range featureFlags {
+		featureFlags[i].Variants = variantsByFeatureFlag[featureFlags[i].ID]
+	}
+
 	return featureFlags, nil
 }
+
+// getSegmentsByFeatureFlag returns, for each person, the names of the assignment
+// groups (segments) that enable the feature flag for them
+func (s *SqlRepository) getSegmentsByFeatureFlag(featureFlagId uint, personIds []uint) (map[uint][]string, error) {
+	type personSegment struct {
+		PersonID uint
+		Name     string
+	}
+
+	var rows []personSegment
+	err := s.DB.Debug().
+		Table("assignment_group_members agm").
+		Select("agm.person_id, ag.name").
+		Joins("INNER JOIN assignment_groups ag ON ag.id = agm.assignment_group_id").
+		Joins("INNER JOIN feature_flag_group_assignments fga ON fga.assignment_group_id = agm.assignment_group_id").
+		Where("fga.feature_flag_id = ?", featureFlagId).
+		Where("agm.person_id IN ?", personIds).
+		Order("ag.name").
+		Scan(&rows).Error
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return nil, errors.New("error when getting segments")
+	}
+
+	segments := make(map[uint][]string)
+	for _, row := range rows {
+		segments[row.PersonID] = append(segments[row.PersonID], row.Name)
+	}
+
+	return segments, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	model "ff/internal/db/model"
+	p_entity "ff/internal/person/entity"
+)
+
+// Person Assignment Tests Cases
+func (s *TestSqlRepository) TestExcludedAssignments() {
+	featureFlag := model.FeatureFlag{
+		Name:        "GLOBAL_FLAG",
+		Description: "Test Description",
+		IsActive:    true,
+		IsGlobal:    true,
+		PersonID:    personOnDB[0].ID,
+	}
+	s.Require().NoError(s.db.Create(&featureFlag).Error)
+
+	s.Require().NoError(s.repo.ApplyAssignment(model.Assignment{
+		PersonID:      personOnDB[1].ID,
+		FeatureFlagID: featureFlag.ID,
+		Kind:          model.AssignmentExclude,
+		Environment:   model.DefaultEnvironment,
+	}))
+
+	s.Run("Excluded person is reported as excluded", func() {
+		featureFlags, err := s.repo.GetAssignedFeatureFlagsByPersonId(personOnDB[1].ID, 0, "")
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(featureFlags))
+		s.True(featureFlags[0].IsGlobal)
+		s.True(featureFlags[0].IsExcluded)
+		s.False(featureFlags[0].IsAssigned)
+
+		featureFlags, err = s.repo.GetAssignedFeatureFlagsByPersonId(personOnDB[0].ID, 0, "")
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(featureFlags))
+		s.False(featureFlags[0].IsExcluded)
+	})
+
+	s.Run("Excluded person is not assigned to the global flag", func() {
+		people, total, err := s.repo.GetPeopleAssignmentByFeatureFlag(model.Pagination{Page: 1, Limit: 10}, p_entity.PersonFilters{FeatureFlagID: featureFlag.ID})
+		s.Require().NoError(err)
+		s.Require().Equal(int64(2), total)
+		s.True(people[0].IsGlobal)
+		s.False(people[0].IsExcluded)
+		s.False(people[1].IsGlobal)
+		s.True(people[1].IsExcluded)
+	})
+}
+
+// only the variants of the listed flags are loaded, those of other projects are left out
+func (s *TestSqlRepository) TestAssignedFeatureFlagVariants() {
+	s.Require().NoError(s.repo.AddProject(model.Project{Key: "checkout", Name: "Checkout"}))
+	projects, _, err := s.repo.GetProjects(model.ProjectFilters{}, model.Pagination{Page: 1, Limit: 10})
+	s.Require().NoError(err)
+	checkout := projects[1]
+
+	s.Require().NoError(s.repo.AddFeatureFlag(model.FeatureFlag{
+		Name:           "BUTTON_COLOR",
+		Description:    "Test Description",
+		PersonID:       personOnDB[0].ID,
+		DefaultVariant: "control",
+		Variants:       []model.Variant{{Name: "control", Position: 0}, {Name: "blue", Position: 1}},
+	}))
+	s.Require().NoError(s.repo.AddFeatureFlag(model.FeatureFlag{
+		Name:           "NEW_CHECKOUT",
+		Description:    "Test Description",
+		PersonID:       personOnDB[0].ID,
+		ProjectID:      checkout.ID,
+		DefaultVariant: "off",
+		Variants:       []model.Variant{{Name: "off", Position: 0}},
+	}))
+
+	featureFlags, err := s.repo.GetAssignedFeatureFlagsByPersonId(personOnDB[0].ID, checkout.ID, "")
+	s.Require().NoError(err)
+	s.Require().Equal(1, len(featureFlags))
+	s.Require().Equal(1, len(featureFlags[0].Variants))
+	s.Equal("off", featureFlags[0].Variants[0].Name)
+}
+
+// switching an assignment replaces the row of the person, an empty kind removes it
+func (s *TestSqlRepository) TestSwitchAssignment() {
+	featureFlag := model.FeatureFlag{
+		Name:        "SWITCH_FLAG",
+		Description: "Test Description",
+		IsActive:    true,
+		PersonID:    personOnDB[0].ID,
+	}
+	s.Require().NoError(s.db.Create(&featureFlag).Error)
+
+	assignment := model.Assignment{
+		PersonID:      personOnDB[1].ID,
+		FeatureFlagID: featureFlag.ID,
+		Kind:          model.AssignmentInclude,
+		Environment:   model.DefaultEnvironment,
+	}
+
+	s.Run("Included person is excluded", func() {
+		s.Require().NoError(s.repo.SwitchAssignment(assignment))
+		assignment.Kind = model.AssignmentExclude
+		s.Require().NoError(s.repo.SwitchAssignment(assignment))
+
+		var assignments []model.Assignment
+		s.Require().NoError(s.db.Where("feature_flag_id = ?", featureFlag.ID).Find(&assignments).Error)
+		s.Require().Equal(1, len(assignments))
+		s.Equal(model.AssignmentExclude, assignments[0].Kind)
+	})
+
+	s.Run("Empty kind removes the assignment", func() {
+		assignment.Kind = ""
+		s.Require().NoError(s.repo.SwitchAssignment(assignment))
+
+		var count int64
+		s.Require().NoError(s.db.Model(&model.Assignment{}).Where("feature_flag_id = ?", featureFlag.ID).Count(&count).Error)
+		s.Equal(int64(0), count)
+	})
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"errors"
+	model "ff/internal/db/model"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+func (s *SqlRepository) AddProject(project model.Project) error {
+	if result := s.DB.Debug().Create(&project); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when creating project")
+	}
+
+	return nil
+}
+
+func (s *SqlRepository) GetProjects(filters model.ProjectFilters, pagination model.Pagination) ([]model.Project, int64, error) {
+	query := s.DB.Debug().Model(&model.Project{})
+
+	if filters.ID != 0 {
+		query.Where("projects.id = ?", filters.ID)
+	}
+
+	// keys are unique, so the filter is exact
+	if filters.Key != "" {
+		query.Where("projects.key = ?", filters.Key)
+	}
+
+	// get total count
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// apply pagination
+	offset := (pagination.Page - 1) * pagination.Limit
+	query.Offset(offset).Limit(pagination.Limit)
+
+	var projects []model.Project
+	if result := query.Order("projects.id").Find(&projects); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return nil, 0, errors.New("error when getting projects")
+	}
+
+	return projects, totalCount, nil
+}
+
+func (s *SqlRepository) GetProjectMembers(projectId uint) ([]model.ProjectMember, error) {
+	var members []model.ProjectMember
+	if result := s.DB.Debug().Preload("Person").Where("project_id = ?", projectId).Order("person_id").Find(&members); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return nil, errors.New("error when getting project members")
+	}
+
+	return members, nil
+}
+
+// SetProjectMember adds the person to the project or changes the role of a current member
+func (s *SqlRepository) SetProjectMember(member model.ProjectMember) error {
+	result := s.DB.Debug().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}, {Name: "person_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role"}),
+	}).Create(&member)
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when setting project member")
+	}
+
+	return nil
+}
+
+func (s *SqlRepository) RemoveProjectMember(projectId uint, personId uint) error {
+	result := s.DB.Debug().Where("project_id = ? AND person_id = ?", projectId, personId).Delete(&model.ProjectMember{})
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when removing project member")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("person is not a member of the project")
+	}
+
+	return nil
+}
+
+func (s *SqlRepository) AddApiKey(apiKey model.ApiKey) error {
+	if result := s.DB.Debug().Create(&apiKey); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when creating api key")
+	}
+
+	return nil
+}
+
+func (s *SqlRepository) GetApiKeys(projectId uint) ([]model.ApiKey, error) {
+	var apiKeys []model.ApiKey
+	if result := s.DB.Debug().Where("project_id = ?", projectId).Order("id").Find(&apiKeys); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return nil, errors.New("error when getting api keys")
+	}
+
+	return apiKeys, nil
+}
+
+// GetApiKeyByHash returns an empty api key when there is none with the hash
+func (s *SqlRepository) GetApiKeyByHash(keyHash string) (model.ApiKey, error) {
+	var apiKeys []model.ApiKey
+	if result := s.DB.Debug().Preload("Project").Where("key_hash = ?", keyHash).Limit(1).Find(&apiKeys); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return model.ApiKey{}, errors.New("error when getting api key")
+	}
+
+	if len(apiKeys) == 0 {
+		return model.ApiKey{}, nil
+	}
+
+	return apiKeys[0], nil
+}
+
+func (s *SqlRepository) RevokeApiKey(projectId uint, id uint, revokedAt time.Time) error {
+	result := s.DB.Debug().Model(&model.ApiKey{}).
+		Where("id = ? AND project_id = ? AND revoked_at IS NULL", id, projectId).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when revoking api key")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("api key not found")
+	}
+
+	return nil
+}
+
+func (s *SqlRepository) TouchApiKey(id uint, usedAt time.Time) error {
+	if result := s.DB.Debug().Model(&model.ApiKey{}).Where("id = ?", id).Update("last_used_at", usedAt); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when updating api key")
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	model "ff/internal/db/model"
+	"time"
+)
+
+// Project Tests Cases
+func (s *TestSqlRepository) TestProjects() {
+	s.Require().NoError(s.repo.AddProject(model.Project{Key: "checkout", Name: "Checkout"}))
+	s.Require().NoError(s.repo.AddProject(model.Project{Key: "payments", Name: "Payments"}))
+
+	projects, total, err := s.repo.GetProjects(model.ProjectFilters{}, model.Pagination{Page: 1, Limit: 10})
+	s.Require().NoError(err)
+	// the default project is created by the first migration
+	s.Require().Equal(int64(3), total)
+	s.Equal(model.DefaultProject, projects[0].Key)
+	checkout, payments := projects[1], projects[2]
+
+	s.Run("Same flag name in different projects", func() {
+		s.Require().NoError(s.repo.AddFeatureFlag(model.FeatureFlag{Name: "NEW_CHECKOUT", Description: "Checkout", IsActive: true, PersonID: personOnDB[0].ID, ProjectID: checkout.ID}))
+		s.Require().NoError(s.repo.AddFeatureFlag(model.FeatureFlag{Name: "NEW_CHECKOUT", Description: "Payments", PersonID: personOnDB[0].ID, ProjectID: payments.ID}))
+
+		err := s.repo.AddFeatureFlag(model.FeatureFlag{Name: "NEW_CHECKOUT", Description: "Duplicate", PersonID: personOnDB[0].ID, ProjectID: checkout.ID})
+		s.Require().Error(err)
+
+		featureFlags, total, err := s.repo.GetFeatureFlag(model.FeatureFlagFilters{ProjectID: payments.ID}, model.Pagination{Page: 1, Limit: 10})
+		s.Require().NoError(err)
+		s.Require().Equal(int64(1), total)
+		s.Equal("Payments", featureFlags[0].Description)
+
+		featureFlag, err := s.repo.GetFeatureFlagByName(checkout.ID, "NEW_CHECKOUT", "")
+		s.Require().NoError(err)
+		s.Equal("Checkout", featureFlag.Description)
+		s.True(featureFlag.IsActive)
+	})
+
+	s.Run("Set and remove project members", func() {
+		s.Require().NoError(s.repo.SetProjectMember(model.ProjectMember{ProjectID: checkout.ID, PersonID: personOnDB[0].ID, Role: "viewer"}))
+		s.Require().NoError(s.repo.SetProjectMember(model.ProjectMember{ProjectID: checkout.ID, PersonID: personOnDB[0].ID, Role: "admin"}))
+
+		members, err := s.repo.GetProjectMembers(checkout.ID)
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(members))
+		s.Equal("admin", members[0].Role)
+		s.Equal(personOnDB[0].Name, members[0].Person.Name)
+
+		s.Require().NoError(s.repo.RemoveProjectMember(checkout.ID, personOnDB[0].ID))
+		err = s.repo.RemoveProjectMember(checkout.ID, personOnDB[0].ID)
+		s.Require().Error(err)
+		s.Equal("person is not a member of the project", err.Error())
+	})
+
+	s.Run("Find and revoke api keys", func() {
+		s.Require().NoError(s.repo.AddApiKey(model.ApiKey{ProjectID: checkout.ID, Name: "backend", Prefix: "ffk_1234", KeyHash: "hash"}))
+
+		apiKey, err := s.repo.GetApiKeyByHash("hash")
+		s.Require().NoError(err)
+		s.Require().NotZero(apiKey.ID)
+		s.Equal("checkout", apiKey.Project.Key)
+
+		s.Require().NoError(s.repo.RevokeApiKey(checkout.ID, apiKey.ID, time.Now()))
+		err = s.repo.RevokeApiKey(checkout.ID, apiKey.ID, time.Now())
+		s.Require().Error(err)
+		s.Equal("api key not found", err.Error())
+
+		apiKey, err = s.repo.GetApiKeyByHash("unknown")
+		s.Require().NoError(err)
+		s.Zero(apiKey.ID)
+	})
+}
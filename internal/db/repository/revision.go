@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"errors"
+	model "ff/internal/db/model"
+)
+
+func (s *SqlRepository) AddFeatureFlagRevision(revision model.FeatureFlagRevision) error {
+	if result := s.DB.Debug().Create(&revision); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when creating feature flag revision")
+	}
+
+	return nil
+}
+
+// GetFeatureFlagRevisions returns the revisions of the flag with the person who made them, newest first
+func (s *SqlRepository) GetFeatureFlagRevisions(filters model.FeatureFlagRevisionFilters, pagination model.Pagination) ([]model.FeatureFlagRevision, int64, error) {
+	query := s.DB.Debug().Model(&model.FeatureFlagRevision{}).Where("feature_flag_id = ?", filters.FeatureFlagID)
+
+	if filters.Environment != "" {
+		query.Where("environment = ?", filters.Environment)
+	}
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (pagination.Page - 1) * pagination.Limit
+	query.Offset(offset).Limit(pagination.Limit)
+
+	var revisions []model.FeatureFlagRevision
+	if result := query.Preload("Person").Order("id DESC").Find(&revisions); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return nil, 0, errors.New("error when getting feature flag revisions")
+	}
+
+	return revisions, totalCount, nil
+}
+
+func (s *SqlRepository) GetFeatureFlagRevision(featureFlagId uint, id uint) (model.FeatureFlagRevision, error) {
+	var revisions []model.FeatureFlagRevision
+	result := s.DB.Debug().Model(&model.FeatureFlagRevision{}).
+		Where("feature_flag_id = ?", featureFlagId).
+		Where("id = ?", id).
+		Preload("Person").
+		Limit(1).
+		Find(&revisions)
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return model.FeatureFlagRevision{}, errors.New("error when getting feature flag revision")
+	}
+
+	if len(revisions) == 0 {
+		return model.FeatureFlagRevision{}, errors.New("revision not found")
+	}
+
+	return revisions[0], nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	model "ff/internal/db/model"
+)
+
+// Feature Flag Revisions Tests Cases
+func (s *TestSqlRepository) TestFeatureFlagRevisions() {
+	featureFlag := model.FeatureFlag{
+		Name:        "REVISED_FLAG",
+		Description: "Test Description",
+		PersonID:    personOnDB[0].ID,
+	}
+	s.Require().NoError(s.db.Create(&featureFlag).Error)
+
+	revisions := []model.FeatureFlagRevision{
+		{FeatureFlagID: featureFlag.ID, Event: "feature_flag.created", Environment: "production", Snapshot: `{"featureFlag":{"isActive":false}}`, PersonID: &personOnDB[0].ID},
+		{FeatureFlagID: featureFlag.ID, Event: "feature_flag.status_toggled", Environment: "production", Snapshot: `{"featureFlag":{"isActive":true}}`},
+		{FeatureFlagID: featureFlag.ID, Event: "assignment.applied", Environment: "staging", Snapshot: `{"featureFlag":{"isActive":false}}`, PersonID: &personOnDB[0].ID},
+	}
+	for _, revision := range revisions {
+		s.Require().NoError(s.repo.AddFeatureFlagRevision(revision))
+	}
+
+	s.Run("List revisions newest first with the person who made them", func() {
+		result, totalCount, err := s.repo.GetFeatureFlagRevisions(model.FeatureFlagRevisionFilters{FeatureFlagID: featureFlag.ID}, model.Pagination{Page: 1, Limit: 2})
+		s.Require().NoError(err)
+		s.Equal(int64(3), totalCount)
+		s.Require().Equal(2, len(result))
+		s.Equal("assignment.applied", result[0].Event)
+		s.Equal(personOnDB[0].Name, result[0].Person.Name)
+		s.Nil(result[1].Person)
+		s.Nil(result[1].PersonID)
+	})
+
+	s.Run("Filter revisions by environment", func() {
+		result, totalCount, err := s.repo.GetFeatureFlagRevisions(model.FeatureFlagRevisionFilters{FeatureFlagID: featureFlag.ID, Environment: "production"}, model.Pagination{Page: 1, Limit: 10})
+		s.Require().NoError(err)
+		s.Equal(int64(2), totalCount)
+		s.Equal("feature_flag.status_toggled", result[0].Event)
+	})
+
+	s.Run("Get a revision of the flag", func() {
+		result, _, err := s.repo.GetFeatureFlagRevisions(model.FeatureFlagRevisionFilters{FeatureFlagID: featureFlag.ID, Environment: "staging"}, model.Pagination{Page: 1, Limit: 1})
+		s.Require().NoError(err)
+
+		revision, err := s.repo.GetFeatureFlagRevision(featureFlag.ID, result[0].ID)
+		s.Require().NoError(err)
+		s.Equal(`{"featureFlag":{"isActive":false}}`, revision.Snapshot)
+
+		_, err = s.repo.GetFeatureFlagRevision(featureFlag.ID+1000, result[0].ID)
+		s.EqualError(err, "revision not found")
+	})
+
+	s.Run("Revisions are kept when the flag is deleted", func() {
+		s.Require().NoError(s.repo.DeleteFeatureFlag(featureFlag.ID))
+
+		_, totalCount, err := s.repo.GetFeatureFlagRevisions(model.FeatureFlagRevisionFilters{FeatureFlagID: featureFlag.ID}, model.Pagination{Page: 1, Limit: 10})
+		s.Require().NoError(err)
+		s.Equal(int64(len(revisions)), totalCount)
+	})
+}
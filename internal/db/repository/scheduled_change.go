@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+	model "ff/internal/db/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// statuses mirrored from the scheduled change entity
+const (
+	scheduledChangePending   = "pending"
+	scheduledChangeCancelled = "cancelled"
+)
+
+func (s *SqlRepository) AddScheduledChange(scheduledChange model.ScheduledChange) error {
+	if result := s.DB.Debug().Create(&scheduledChange); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when creating scheduled change")
+	}
+
+	return nil
+}
+
+func (s *SqlRepository) GetScheduledChanges(filters model.ScheduledChangeFilters) ([]model.ScheduledChange, error) {
+	query := s.DB.Debug().Model(&model.ScheduledChange{}).Joins("Person")
+
+	if filters.FeatureFlagID != 0 {
+		query.Where("feature_flag_scheduled_changes.feature_flag_id = ?", filters.FeatureFlagID)
+	}
+
+	if filters.Status != "" {
+		query.Where("feature_flag_scheduled_changes.status = ?", filters.Status)
+	}
+
+	if filters.Environment != "" {
+		query.Where("feature_flag_scheduled_changes.environment = ?", filters.Environment)
+	}
+
+	var scheduledChanges []model.ScheduledChange
+	if result := query.Order("feature_flag_scheduled_changes.execute_at").Find(&scheduledChanges); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return nil, errors.New("error when getting scheduled changes")
+	}
+
+	return scheduledChanges, nil
+}
+
+// ClaimDueScheduledChanges claims the pending changes due at now until lockedUntil, in the order they
+// are applied. The rows locked by another worker are skipped where the database supports it, on
+// SQLite the single writer keeps two workers from claiming the same rows
+func (s *SqlRepository) ClaimDueScheduledChanges(now time.Time, lockedUntil time.Time) ([]model.ScheduledChange, error) {
+	var scheduledChanges []model.ScheduledChange
+
+	err := s.DB.Debug().Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		err := tx.Model(&model.ScheduledChange{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", scheduledChangePending).
+			Where("execute_at <= ?", now.UTC()).
+			Where("locked_until IS NULL OR locked_until <= ?", now.UTC()).
+			Order("execute_at").
+			Order("id").
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		if err := tx.Model(&model.ScheduledChange{}).Where("id IN ?", ids).Update("locked_until", lockedUntil.UTC()).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id IN ?", ids).Order("execute_at").Order("id").Find(&scheduledChanges).Error
+	})
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return nil, errors.New("error when claiming due scheduled changes")
+	}
+
+	return scheduledChanges, nil
+}
+
+// CancelScheduledChange only cancels pending changes, applied or failed ones are kept as history
+func (s *SqlRepository) CancelScheduledChange(featureFlagId uint, id uint) error {
+	result := s.DB.Debug().Model(&model.ScheduledChange{}).
+		Where("id = ? AND feature_flag_id = ? AND status = ?", id, featureFlagId, scheduledChangePending).
+		Update("status", scheduledChangeCancelled)
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when cancelling scheduled change")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("scheduled change not found")
+	}
+
+	return nil
+}
+
+func (s *SqlRepository) UpdateScheduledChangeStatus(id uint, status string, appliedAt *time.Time, errorMessage string) error {
+	updateData := map[string]interface{}{
+		"status":        status,
+		"applied_at":    appliedAt,
+		"error_message": errorMessage,
+		"locked_until":  nil,
+	}
+
+	if result := s.DB.Debug().Model(&model.ScheduledChange{}).Where("id = ?", id).Updates(updateData); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when updating scheduled change")
+	}
+
+	return nil
+}
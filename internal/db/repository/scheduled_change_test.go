@@ -0,0 +1,84 @@
+package repository
+
+import (
+	model "ff/internal/db/model"
+	"time"
+)
+
+// Scheduled Changes Tests Cases
+func (s *TestSqlRepository) TestScheduledChanges() {
+	featureFlag := model.FeatureFlag{
+		Name:        "SCHEDULED_FLAG",
+		Description: "Test Description",
+		PersonID:    personOnDB[0].ID,
+	}
+	s.Require().NoError(s.db.Create(&featureFlag).Error)
+
+	now := time.Date(2024, 10, 10, 0, 0, 0, 0, time.UTC)
+	scheduledChanges := []model.ScheduledChange{
+		{FeatureFlagID: featureFlag.ID, Operation: "deactivate", Force: true, ExecuteAt: now.Add(time.Hour), Timezone: "UTC", Status: "pending", PersonID: personOnDB[0].ID},
+		{FeatureFlagID: featureFlag.ID, Operation: "activate", ExecuteAt: now.Add(-time.Hour), Timezone: "UTC", Status: "pending", PersonID: personOnDB[0].ID},
+		{FeatureFlagID: featureFlag.ID, Operation: "change_rollout", RolloutPercentage: 50, ExecuteAt: now.Add(-2 * time.Hour), Timezone: "UTC", Status: "applied", PersonID: personOnDB[0].ID},
+	}
+	for _, scheduledChange := range scheduledChanges {
+		s.Require().NoError(s.repo.AddScheduledChange(scheduledChange))
+	}
+
+	s.Run("List scheduled changes ordered by execution time", func() {
+		result, err := s.repo.GetScheduledChanges(model.ScheduledChangeFilters{FeatureFlagID: featureFlag.ID})
+		s.Require().NoError(err)
+		s.Require().Equal(3, len(result))
+		s.Equal("change_rollout", result[0].Operation)
+		s.Equal("deactivate", result[2].Operation)
+		s.True(result[2].Force)
+		s.False(result[0].Force)
+		s.Equal(personOnDB[0].Name, result[0].Person.Name)
+	})
+
+	s.Run("Filter scheduled changes by status", func() {
+		result, err := s.repo.GetScheduledChanges(model.ScheduledChangeFilters{FeatureFlagID: featureFlag.ID, Status: "pending"})
+		s.Require().NoError(err)
+		s.Equal(2, len(result))
+	})
+
+	s.Run("Claim only pending changes that are due", func() {
+		result, err := s.repo.ClaimDueScheduledChanges(now, now.Add(time.Minute))
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(result))
+		s.Equal("activate", result[0].Operation)
+	})
+
+	s.Run("Claimed changes are skipped until the claim runs out", func() {
+		result, err := s.repo.ClaimDueScheduledChanges(now, now.Add(time.Minute))
+		s.Require().NoError(err)
+		s.Equal(0, len(result))
+
+		result, err = s.repo.ClaimDueScheduledChanges(now.Add(2*time.Minute), now.Add(3*time.Minute))
+		s.Require().NoError(err)
+		s.Equal(1, len(result))
+	})
+
+	s.Run("Mark a scheduled change as applied", func() {
+		due, err := s.repo.ClaimDueScheduledChanges(now.Add(4*time.Minute), now.Add(5*time.Minute))
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(due))
+
+		err = s.repo.UpdateScheduledChangeStatus(due[0].ID, "applied", &now, "")
+		s.Require().NoError(err)
+
+		due, err = s.repo.ClaimDueScheduledChanges(now.Add(10*time.Minute), now.Add(11*time.Minute))
+		s.Require().NoError(err)
+		s.Equal(0, len(due))
+	})
+
+	s.Run("Cancel only pending scheduled changes", func() {
+		pending, err := s.repo.GetScheduledChanges(model.ScheduledChangeFilters{FeatureFlagID: featureFlag.ID, Status: "pending"})
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(pending))
+
+		s.Require().NoError(s.repo.CancelScheduledChange(featureFlag.ID, pending[0].ID))
+
+		err = s.repo.CancelScheduledChange(featureFlag.ID, pending[0].ID)
+		s.EqualError(err, "scheduled change not found")
+	})
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	model "ff/internal/db/model"
+
+	"gorm.io/gorm"
+)
+
+// GetFeatureFlagSnapshot returns every flag of the project in the environment with its
+// assignments and segment members, a zero project id returns the flags of every project.
+// Archived flags are left out, the clients treat a missing flag as off
+func (s *SqlRepository) GetFeatureFlagSnapshot(projectId uint, environment string) (model.FeatureFlagSnapshot, error) {
+	query := s.snapshotQuery().Where("archived_at IS NULL")
+	if projectId != 0 {
+		query.Where("project_id = ?", projectId)
+	}
+
+	return s.loadFeatureFlagSnapshot(query, environment)
+}
+
+// GetFeatureFlagSnapshotById returns the snapshot of a single flag, it is empty when the flag does not exist
+func (s *SqlRepository) GetFeatureFlagSnapshotById(featureFlagId uint, environment string) (model.FeatureFlagSnapshot, error) {
+	return s.loadFeatureFlagSnapshot(s.snapshotQuery().Where("id = ?", featureFlagId), environment)
+}
+
+func (s *SqlRepository) snapshotQuery() *gorm.DB {
+	return s.DB.Debug().Model(&model.FeatureFlag{}).
+		Preload("Rules", orderRulesByPosition).
+		Preload("Variants", orderVariantsByPosition).
+		Preload("Prerequisites", orderPrerequisitesByPosition).
+		Preload("Prerequisites.Prerequisite")
+}
+
+func (s *SqlRepository) loadFeatureFlagSnapshot(query *gorm.DB, environment string) (model.FeatureFlagSnapshot, error) {
+	if environment == "" {
+		environment = model.DefaultEnvironment
+	}
+
+	var snapshot model.FeatureFlagSnapshot
+	if result := query.Order("id").Find(&snapshot.FeatureFlags); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return model.FeatureFlagSnapshot{}, errors.New("error when getting feature flags")
+	}
+
+	if len(snapshot.FeatureFlags) == 0 {
+		return snapshot, nil
+	}
+
+	if err := s.applyEnvironmentState(snapshot.FeatureFlags, environment); err != nil {
+		return model.FeatureFlagSnapshot{}, err
+	}
+
+	var ids []uint
+	for _, featureFlag := range snapshot.FeatureFlags {
+		ids = append(ids, featureFlag.ID)
+	}
+
+	err := s.DB.Debug().
+		Where("feature_flag_id IN ? AND environment = ?", ids, environment).
+		Order("feature_flag_id, person_id").
+		Find(&snapshot.Assignments).Error
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return model.FeatureFlagSnapshot{}, errors.New("error when getting assignments")
+	}
+
+	err = s.DB.Debug().
+		Table("feature_flag_group_assignments fga").
+		Select("DISTINCT fga.feature_flag_id, agm.person_id").
+		Joins("INNER JOIN assignment_group_members agm ON agm.assignment_group_id = fga.assignment_group_id").
+		Where("fga.feature_flag_id IN ?", ids).
+		Order("fga.feature_flag_id, agm.person_id").
+		Scan(&snapshot.SegmentMembers).Error
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return model.FeatureFlagSnapshot{}, errors.New("error when getting segment members")
+	}
+
+	return snapshot, nil
+}
+
+// GetSnapshotVersion returns the id of the latest flag event of the project in the environment,
+// every change of a flag is recorded as an event so the id only grows when the snapshot changes.
+// A zero project is every project
+func (s *SqlRepository) GetSnapshotVersion(projectId uint, environment string) (uint, error) {
+	query := s.DB.Debug().Model(&model.FlagEvent{}).Where("environment = ?", environment)
+	if projectId != 0 {
+		query.Where("project_id = ?", projectId)
+	}
+
+	var version uint
+	if result := query.Select("COALESCE(MAX(id), 0)").Scan(&version); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return 0, errors.New("error when getting snapshot version")
+	}
+
+	return version, nil
+}
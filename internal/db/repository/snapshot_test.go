@@ -0,0 +1,55 @@
+package repository
+
+import (
+	model "ff/internal/db/model"
+)
+
+// Snapshot Tests Cases
+func (s *TestSqlRepository) TestFeatureFlagSnapshot() {
+	featureFlag := model.FeatureFlag{
+		Name:        "SNAPSHOT_FLAG",
+		Description: "Test Description",
+		IsActive:    true,
+		PersonID:    personOnDB[0].ID,
+		Rules:       []model.Rule{{Attribute: "country", Operator: "equals", Values: []string{"BR"}, Result: true}},
+	}
+	s.Require().NoError(s.db.Create(&featureFlag).Error)
+
+	group := model.AssignmentGroup{Name: "Beta customers"}
+	s.Require().NoError(s.db.Create(&group).Error)
+	s.Require().NoError(s.repo.AddAssignmentGroupMembers(group.ID, []uint{personOnDB[1].ID}))
+	s.Require().NoError(s.repo.ApplyGroupAssignment(group.ID, featureFlag.ID))
+
+	s.Require().NoError(s.repo.ApplyAssignment(model.Assignment{PersonID: personOnDB[0].ID, FeatureFlagID: featureFlag.ID, Kind: model.AssignmentInclude, Environment: model.DefaultEnvironment}))
+	s.Require().NoError(s.repo.ApplyAssignment(model.Assignment{PersonID: personOnDB[1].ID, FeatureFlagID: featureFlag.ID, Kind: model.AssignmentExclude, Environment: "staging"}))
+
+	s.Run("Snapshot of the default environment", func() {
+		snapshot, err := s.repo.GetFeatureFlagSnapshot(0, "")
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(snapshot.FeatureFlags))
+		s.True(snapshot.FeatureFlags[0].IsActive)
+		s.Equal(1, len(snapshot.FeatureFlags[0].Rules))
+
+		s.Require().Equal(1, len(snapshot.Assignments))
+		s.Equal(personOnDB[0].ID, snapshot.Assignments[0].PersonID)
+
+		s.Require().Equal(1, len(snapshot.SegmentMembers))
+		s.Equal(model.SegmentMember{FeatureFlagID: featureFlag.ID, PersonID: personOnDB[1].ID}, snapshot.SegmentMembers[0])
+	})
+
+	s.Run("Snapshot of another environment", func() {
+		snapshot, err := s.repo.GetFeatureFlagSnapshot(0, "staging")
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(snapshot.FeatureFlags))
+		s.False(snapshot.FeatureFlags[0].IsActive)
+
+		s.Require().Equal(1, len(snapshot.Assignments))
+		s.Equal(model.AssignmentExclude, snapshot.Assignments[0].Kind)
+	})
+
+	s.Run("Snapshot of a project without flags", func() {
+		snapshot, err := s.repo.GetFeatureFlagSnapshot(99, "")
+		s.Require().NoError(err)
+		s.Empty(snapshot.FeatureFlags)
+	})
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"errors"
+	model "ff/internal/db/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *SqlRepository) AddWebhook(webhook model.Webhook) (model.Webhook, error) {
+	if result := s.DB.Debug().Create(&webhook); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return model.Webhook{}, errors.New("error when creating webhook")
+	}
+
+	return webhook, nil
+}
+
+func (s *SqlRepository) GetWebhooks(projectId uint) ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+	if result := s.DB.Debug().Where("project_id = ?", projectId).Order("id").Find(&webhooks); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return nil, errors.New("error when getting webhooks")
+	}
+
+	return webhooks, nil
+}
+
+// GetWebhook returns an empty webhook when the project has none with the id
+func (s *SqlRepository) GetWebhook(projectId uint, id uint) (model.Webhook, error) {
+	var webhooks []model.Webhook
+	if result := s.DB.Debug().Where("id = ? AND project_id = ?", id, projectId).Limit(1).Find(&webhooks); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return model.Webhook{}, errors.New("error when getting webhook")
+	}
+
+	if len(webhooks) == 0 {
+		return model.Webhook{}, nil
+	}
+
+	return webhooks[0], nil
+}
+
+func (s *SqlRepository) UpdateWebhook(webhook model.Webhook) error {
+	result := s.DB.Debug().Model(&model.Webhook{}).
+		Where("id = ? AND project_id = ?", webhook.ID, webhook.ProjectID).
+		Select("url", "secret", "event_types", "is_active").
+		Updates(&webhook)
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when updating webhook")
+	}
+
+	return nil
+}
+
+// DeleteWebhook removes the webhook with its deliveries and their attempts
+func (s *SqlRepository) DeleteWebhook(projectId uint, id uint) error {
+	err := s.DB.Debug().Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&model.Webhook{}).Where("id = ? AND project_id = ?", id, projectId).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// children first, the foreign keys are enforced by every database but SQLite
+		deliveries := tx.Model(&model.WebhookDelivery{}).Select("id").Where("webhook_id = ?", id)
+		if err := tx.Where("delivery_id IN (?)", deliveries).Delete(&model.WebhookAttempt{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("webhook_id = ?", id).Delete(&model.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", id).Delete(&model.Webhook{}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("webhook not found")
+	}
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return errors.New("error when deleting webhook")
+	}
+
+	return nil
+}
+
+func (s *SqlRepository) AddWebhookDeliveries(deliveries []model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if result := s.DB.Debug().Create(&deliveries); result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return errors.New("error when creating webhook deliveries")
+	}
+
+	return nil
+}
+
+// ClaimDueWebhookDeliveries claims the pending deliveries whose next attempt is due until lockedUntil,
+// oldest first. The rows locked by another worker are skipped where the database supports it, on
+// SQLite the single writer keeps two workers from claiming the same rows
+func (s *SqlRepository) ClaimDueWebhookDeliveries(now time.Time, limit int, lockedUntil time.Time) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+
+	err := s.DB.Debug().Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		err := tx.Model(&model.WebhookDelivery{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+			Where("locked_until IS NULL OR locked_until <= ?", now).
+			Order("next_attempt_at, id").
+			Limit(limit).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		if err := tx.Model(&model.WebhookDelivery{}).Where("id IN ?", ids).Update("locked_until", lockedUntil).Error; err != nil {
+			return err
+		}
+
+		return tx.Preload("Webhook").Where("id IN ?", ids).Order("next_attempt_at, id").Find(&deliveries).Error
+	})
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return nil, errors.New("error when claiming webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+// GetWebhookDeliveries returns the deliveries of the webhook with their attempts, newest first
+func (s *SqlRepository) GetWebhookDeliveries(webhookId uint, pagination model.Pagination) ([]model.WebhookDelivery, int64, error) {
+	query := s.DB.Debug().Model(&model.WebhookDelivery{}).Where("webhook_id = ?", webhookId)
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (pagination.Page - 1) * pagination.Limit
+	query.Offset(offset).Limit(pagination.Limit)
+
+	var deliveries []model.WebhookDelivery
+	result := query.Preload("AttemptLogs", func(db *gorm.DB) *gorm.DB {
+		return db.Order("webhook_attempts.id")
+	}).Order("id DESC").Find(&deliveries)
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return nil, 0, errors.New("error when getting webhook deliveries")
+	}
+
+	return deliveries, totalCount, nil
+}
+
+// RecordWebhookAttempt saves the attempt and the state of the delivery after it, the claim is released
+func (s *SqlRepository) RecordWebhookAttempt(delivery model.WebhookDelivery, attempt model.WebhookAttempt) error {
+	err := s.DB.Debug().Transaction(func(tx *gorm.DB) error {
+		attempt.DeliveryID = delivery.ID
+		if err := tx.Create(&attempt).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"locked_until":    nil,
+		}).Error
+	})
+	if err != nil {
+		s.Logger.Error().Err(err)
+		return errors.New("error when recording webhook attempt")
+	}
+
+	return nil
+}
+
+// GetFeatureFlagProjectId returns zero when there is no flag with the id
+func (s *SqlRepository) GetFeatureFlagProjectId(featureFlagId uint) (uint, error) {
+	var projectIds []uint
+	result := s.DB.Debug().Model(&model.FeatureFlag{}).Where("id = ?", featureFlagId).Limit(1).Pluck("project_id", &projectIds)
+	if result.Error != nil {
+		s.Logger.Error().Err(result.Error)
+		return 0, errors.New("error when getting feature flag project")
+	}
+
+	if len(projectIds) == 0 {
+		return 0, nil
+	}
+
+	return projectIds[0], nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	model "ff/internal/db/model"
+	"time"
+)
+
+// Webhook Tests Cases
+func (s *TestSqlRepository) TestWebhooks() {
+	now := time.Now()
+
+	webhook, err := s.repo.AddWebhook(model.Webhook{ProjectID: 1, URL: "https://hooks.example.com", Secret: "s3cr3t", IsActive: true})
+	s.Require().NoError(err)
+	s.NotZero(webhook.ID)
+
+	s.Run("Webhook is scoped to its project", func() {
+		found, err := s.repo.GetWebhook(1, webhook.ID)
+		s.Require().NoError(err)
+		s.Equal("s3cr3t", found.Secret)
+
+		found, err = s.repo.GetWebhook(2, webhook.ID)
+		s.Require().NoError(err)
+		s.Zero(found.ID)
+	})
+
+	s.Run("Update webhook", func() {
+		webhook.EventTypes = "feature_flag.created"
+		webhook.IsActive = false
+		s.Require().NoError(s.repo.UpdateWebhook(webhook))
+
+		webhooks, err := s.repo.GetWebhooks(1)
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(webhooks))
+		s.Equal("feature_flag.created", webhooks[0].EventTypes)
+		s.False(webhooks[0].IsActive)
+	})
+
+	s.Require().NoError(s.repo.AddWebhookDeliveries([]model.WebhookDelivery{
+		{WebhookID: webhook.ID, EventType: "feature_flag.created", Payload: "{}", Status: model.WebhookDeliveryPending, NextAttemptAt: now.Add(-time.Minute)},
+		{WebhookID: webhook.ID, EventType: "feature_flag.created", Payload: "{}", Status: model.WebhookDeliveryPending, NextAttemptAt: now.Add(time.Hour)},
+	}))
+
+	s.Run("Only due deliveries are claimed", func() {
+		deliveries, err := s.repo.ClaimDueWebhookDeliveries(now, 10, now.Add(time.Minute))
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(deliveries))
+		s.Require().NotNil(deliveries[0].Webhook)
+		s.Equal("https://hooks.example.com", deliveries[0].Webhook.URL)
+	})
+
+	s.Run("Claimed deliveries are skipped until the claim runs out", func() {
+		deliveries, err := s.repo.ClaimDueWebhookDeliveries(now, 10, now.Add(time.Minute))
+		s.Require().NoError(err)
+		s.Equal(0, len(deliveries))
+
+		deliveries, err = s.repo.ClaimDueWebhookDeliveries(now.Add(2*time.Minute), 10, now.Add(3*time.Minute))
+		s.Require().NoError(err)
+		s.Equal(1, len(deliveries))
+	})
+
+	s.Run("Attempt is recorded with the delivery state", func() {
+		deliveries, err := s.repo.ClaimDueWebhookDeliveries(now.Add(4*time.Minute), 10, now.Add(5*time.Minute))
+		s.Require().NoError(err)
+		s.Require().Equal(1, len(deliveries))
+
+		delivery := deliveries[0]
+		delivery.Status = model.WebhookDeliverySucceeded
+		delivery.Attempts = 1
+		s.Require().NoError(s.repo.RecordWebhookAttempt(delivery, model.WebhookAttempt{StatusCode: 200, DurationMs: 12}))
+
+		deliveries, err = s.repo.ClaimDueWebhookDeliveries(now.Add(10*time.Minute), 10, now.Add(11*time.Minute))
+		s.Require().NoError(err)
+		s.Equal(0, len(deliveries))
+
+		deliveries, total, err := s.repo.GetWebhookDeliveries(webhook.ID, model.Pagination{Page: 1, Limit: 10})
+		s.Require().NoError(err)
+		s.Equal(int64(2), total)
+		s.Require().Equal(2, len(deliveries))
+		s.Equal(model.WebhookDeliverySucceeded, deliveries[1].Status)
+		s.Require().Equal(1, len(deliveries[1].AttemptLogs))
+		s.Equal(200, deliveries[1].AttemptLogs[0].StatusCode)
+	})
+
+	s.Run("Delete webhook with its deliveries", func() {
+		s.EqualError(s.repo.DeleteWebhook(2, webhook.ID), "webhook not found")
+		s.Require().NoError(s.repo.DeleteWebhook(1, webhook.ID))
+
+		var deliveries, attempts int64
+		s.db.Model(&model.WebhookDelivery{}).Count(&deliveries)
+		s.db.Model(&model.WebhookAttempt{}).Count(&attempts)
+		s.Zero(deliveries)
+		s.Zero(attempts)
+	})
+}
@@ -0,0 +1,7 @@
+package entity
+
+type EnvironmentResponse struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
@@ -0,0 +1,60 @@
+package environment
+
+import (
+	"ff/internal/db/model"
+	environmentEntity "ff/internal/environment/entity"
+
+	"github.com/rs/zerolog"
+)
+
+type EnvironmentRepository interface {
+	GetEnvironments() ([]model.Environment, error)
+}
+
+type EnvironmentService struct {
+	Repository EnvironmentRepository
+	Logger     *zerolog.Logger
+}
+
+func LoadService(r EnvironmentRepository, l *zerolog.Logger) *EnvironmentService {
+	return &EnvironmentService{
+		Logger:     l,
+		Repository: r,
+	}
+}
+
+func (es *EnvironmentService) GetEnvironments() ([]environmentEntity.EnvironmentResponse, error) {
+	es.Logger.Info().Msg("Getting Environments")
+
+	environments, err := es.Repository.GetEnvironments()
+	if err != nil {
+		return nil, err
+	}
+
+	var environmentResponses []environmentEntity.EnvironmentResponse
+	for _, envDB := range environments {
+		environmentResponses = append(environmentResponses, environmentEntity.EnvironmentResponse{
+			ID:       envDB.ID,
+			Name:     envDB.Name,
+			Position: envDB.Position,
+		})
+	}
+
+	return environmentResponses, nil
+}
+
+// EnvironmentExists reports whether the name is one of the configured environments
+func (es *EnvironmentService) EnvironmentExists(name string) (bool, error) {
+	environments, err := es.Repository.GetEnvironments()
+	if err != nil {
+		return false, err
+	}
+
+	for _, environment := range environments {
+		if environment.Name == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,66 @@
+package entity
+
+import "errors"
+
+const (
+	ReasonFlagInactive = "FLAG_INACTIVE"
+	ReasonGlobal       = "GLOBAL"
+	ReasonAssigned     = "ASSIGNED"
+	ReasonExcluded     = "EXCLUDED"
+	ReasonSegment      = "SEGMENT"
+	ReasonRollout      = "ROLLOUT"
+	ReasonTargeting    = "TARGETING_MATCH"
+	ReasonExpired      = "EXPIRED"
+	ReasonArchived     = "ARCHIVED"
+	ReasonPrerequisite = "PREREQUISITE_FAILED"
+	ReasonNotFound     = "NOT_FOUND"
+	ReasonDefault      = "DEFAULT"
+)
+
+type EvaluationContext struct {
+	PersonID   uint                   `json:"personId"`
+	Email      string                 `json:"email"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// AttributesMap merges the person id and email into the caller attributes so
+// targeting rules can reference them as "personId" and "email"
+func (ec *EvaluationContext) AttributesMap() map[string]interface{} {
+	attributes := make(map[string]interface{}, len(ec.Attributes)+2)
+	for key, value := range ec.Attributes {
+		attributes[key] = value
+	}
+
+	if ec.PersonID != 0 {
+		attributes["personId"] = ec.PersonID
+	}
+
+	if ec.Email != "" {
+		attributes["email"] = ec.Email
+	}
+
+	return attributes
+}
+
+type EvaluationRequest struct {
+	FlagName    string            `json:"flagName"`
+	Context     EvaluationContext `json:"context"`
+	Environment string            `json:"-"`
+	ProjectID   uint              `json:"-"`
+}
+
+func (er *EvaluationRequest) Validate() error {
+	if er.FlagName == "" {
+		return errors.New("FlagName|Flag name is required")
+	}
+
+	return nil
+}
+
+type EvaluationResponse struct {
+	FlagName     string      `json:"flagName"`
+	Value        bool        `json:"value"`
+	Reason       string      `json:"reason"`
+	Variant      string      `json:"variant,omitempty"`
+	VariantValue interface{} `json:"variantValue,omitempty"`
+}
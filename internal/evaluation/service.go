@@ -0,0 +1,193 @@
+package evaluation
+
+import (
+	"errors"
+	"time"
+
+	"ff/internal/db/model"
+	evaluationEntity "ff/internal/evaluation/entity"
+	featureflag "ff/internal/feature_flag"
+	ff_entity "ff/internal/feature_flag/entity"
+	"ff/internal/rollout"
+	"ff/internal/targeting"
+
+	"github.com/rs/zerolog"
+)
+
+// FeatureFlagReader and AssignmentReader are the only lookups an evaluation needs, the
+// repositories satisfy them and so does the in-memory snapshot store used by the client SDK
+type FeatureFlagReader interface {
+	GetFeatureFlagByName(projectId uint, name string, environment string) (model.FeatureFlag, error)
+}
+
+type AssignmentReader interface {
+	GetAssignedFeatureFlagsByPersonId(id uint, projectId uint, environment string) ([]model.AssignedFeatureFlag, error)
+}
+
+type EvaluationService struct {
+	FeatureFlagRepository FeatureFlagReader
+	PersonRepository      AssignmentReader
+	Logger                *zerolog.Logger
+}
+
+func LoadService(ffr FeatureFlagReader, pr AssignmentReader, l *zerolog.Logger) *EvaluationService {
+	return &EvaluationService{
+		Logger:                l,
+		FeatureFlagRepository: ffr,
+		PersonRepository:      pr,
+	}
+}
+
+func (es *EvaluationService) Evaluate(request evaluationEntity.EvaluationRequest) (evaluationEntity.EvaluationResponse, error) {
+	es.Logger.Info().Msg("Evaluating a Feature Flag")
+
+	if err := request.Validate(); err != nil {
+		return evaluationEntity.EvaluationResponse{}, errors.New(err.Error())
+	}
+
+	featureFlag, err := es.FeatureFlagRepository.GetFeatureFlagByName(request.ProjectID, request.FlagName, request.Environment)
+	if err != nil {
+		return evaluationEntity.EvaluationResponse{}, err
+	}
+
+	return es.evaluateFeatureFlag(request, featureFlag, map[uint]bool{})
+}
+
+// evaluateFeatureFlag evaluates a flag already looked up, visited holds the flags
+// being evaluated as prerequisites so a cycle left in the database can not loop forever
+func (es *EvaluationService) evaluateFeatureFlag(request evaluationEntity.EvaluationRequest, featureFlag model.FeatureFlag, visited map[uint]bool) (evaluationEntity.EvaluationResponse, error) {
+	response := evaluationEntity.EvaluationResponse{
+		FlagName: request.FlagName,
+	}
+
+	if featureFlag.ID == 0 {
+		response.Reason = evaluationEntity.ReasonNotFound
+		return response, nil
+	}
+
+	isAssigned := false
+	isInSegment := false
+	isExcluded := false
+	assignedVariant := ""
+	if request.Context.PersonID != 0 {
+		assignedFeatureFlags, err := es.PersonRepository.GetAssignedFeatureFlagsByPersonId(request.Context.PersonID, request.ProjectID, request.Environment)
+		if err != nil {
+			return evaluationEntity.EvaluationResponse{}, err
+		}
+
+		for _, assigned := range assignedFeatureFlags {
+			if assigned.ID == featureFlag.ID {
+				isAssigned = assigned.IsAssigned
+				isInSegment = assigned.IsAssignedViaSegment
+				isExcluded = assigned.IsExcluded
+				assignedVariant = assigned.AssignedVariant
+			}
+		}
+	}
+
+	response.Value, response.Reason = evaluate(featureFlag, request.Context, isAssigned, isInSegment, isExcluded, time.Now())
+
+	// prerequisites can only turn off a flag that would be on
+	if response.Value {
+		prerequisitesMet, err := es.prerequisitesMet(request, featureFlag, visited)
+		if err != nil {
+			return evaluationEntity.EvaluationResponse{}, err
+		}
+
+		if !prerequisitesMet {
+			response.Value, response.Reason = false, evaluationEntity.ReasonPrerequisite
+		}
+	}
+
+	// the pinned variant only applies when the flag is on because of the assignment
+	if response.Reason != evaluationEntity.ReasonAssigned {
+		assignedVariant = ""
+	}
+
+	if variant, ok := rollout.ServedVariant(featureFlag.Name, featureFlag.DefaultVariant, featureFlag.Variants, request.Context.PersonID, assignedVariant, response.Value); ok {
+		response.Variant = variant.Name
+		response.VariantValue = ff_entity.DecodeVariantValue(featureFlag.Type, variant.Value)
+	}
+
+	return response, nil
+}
+
+// prerequisitesMet evaluates every prerequisite for the same context, each one has to be on
+// and serve the required variant, if any
+func (es *EvaluationService) prerequisitesMet(request evaluationEntity.EvaluationRequest, featureFlag model.FeatureFlag, visited map[uint]bool) (bool, error) {
+	if len(featureFlag.Prerequisites) == 0 {
+		return true, nil
+	}
+
+	visited[featureFlag.ID] = true
+	defer delete(visited, featureFlag.ID)
+
+	for _, prerequisite := range featureFlag.Prerequisites {
+		if visited[prerequisite.PrerequisiteID] || prerequisite.Prerequisite == nil {
+			return false, nil
+		}
+
+		prerequisiteFlag, err := es.FeatureFlagRepository.GetFeatureFlagByName(featureFlag.ProjectID, prerequisite.Prerequisite.Name, request.Environment)
+		if err != nil {
+			return false, err
+		}
+
+		prerequisiteRequest := request
+		prerequisiteRequest.FlagName = prerequisite.Prerequisite.Name
+
+		response, err := es.evaluateFeatureFlag(prerequisiteRequest, prerequisiteFlag, visited)
+		if err != nil {
+			return false, err
+		}
+
+		if !response.Value || (prerequisite.Variant != "" && response.Variant != prerequisite.Variant) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// evaluate holds the decision order shared by every evaluation, an archived, inactive or
+// expired flag is always off, even when it is global or assigned. An exclusion wins over
+// everything else, direct and segment assignments win over targeting rules, and the first
+// matching rule wins over global and rollout
+func evaluate(featureFlag model.FeatureFlag, context evaluationEntity.EvaluationContext, isAssigned bool, isInSegment bool, isExcluded bool, now time.Time) (bool, string) {
+	if featureFlag.ArchivedAt != nil {
+		return false, evaluationEntity.ReasonArchived
+	}
+
+	if !featureFlag.IsActive {
+		return false, evaluationEntity.ReasonFlagInactive
+	}
+
+	if ff_entity.IsExpired(featureFlag.ExpirationDate, now) {
+		return false, evaluationEntity.ReasonExpired
+	}
+
+	if isExcluded {
+		return false, evaluationEntity.ReasonExcluded
+	}
+
+	if isAssigned {
+		return true, evaluationEntity.ReasonAssigned
+	}
+
+	if isInSegment {
+		return true, evaluationEntity.ReasonSegment
+	}
+
+	if rule, ok := targeting.MatchRules(featureflag.RulesFromModel(featureFlag.Rules), context.AttributesMap()); ok {
+		return rule.Result, evaluationEntity.ReasonTargeting
+	}
+
+	if featureFlag.IsGlobal {
+		return true, evaluationEntity.ReasonGlobal
+	}
+
+	if rollout.IsInRollout(featureFlag.Name, context.PersonID, featureFlag.RolloutPercentage) {
+		return true, evaluationEntity.ReasonRollout
+	}
+
+	return false, evaluationEntity.ReasonDefault
+}
@@ -0,0 +1,381 @@
+package evaluation
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"ff/internal/db/model"
+	evaluationEntity "ff/internal/evaluation/entity"
+	p_entity "ff/internal/person/entity"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockFeatureFlagRepository is a mock of FeatureFlagRepository
+type MockFeatureFlagRepository struct {
+	mock.Mock
+}
+
+func (m *MockFeatureFlagRepository) AddFeatureFlag(flag model.FeatureFlag) error {
+	args := m.Called(flag)
+	return args.Error(0)
+}
+
+func (m *MockFeatureFlagRepository) GetFeatureFlag(filters model.FeatureFlagFilters, pagination model.Pagination) ([]model.FeatureFlag, int64, error) {
+	args := m.Called(filters, pagination)
+	return args.Get(0).([]model.FeatureFlag), int64(args.Get(1).(int)), args.Error(2)
+}
+
+func (m *MockFeatureFlagRepository) UpdateFeatureFlagById(id uint, featureFlag model.UpdateFeatureFlag) error {
+	args := m.Called(id, featureFlag)
+	return args.Error(0)
+}
+
+func (m *MockFeatureFlagRepository) GetFeatureFlagByName(projectId uint, name string, environment string) (model.FeatureFlag, error) {
+	args := m.Called(projectId, name, environment)
+	return args.Get(0).(model.FeatureFlag), args.Error(1)
+}
+
+func (m *MockFeatureFlagRepository) ReplaceRules(featureFlagId uint, rules []model.Rule) error {
+	args := m.Called(featureFlagId, rules)
+	return args.Error(0)
+}
+
+func (m *MockFeatureFlagRepository) ReplaceVariants(featureFlagId uint, defaultVariant string, variants []model.Variant) error {
+	args := m.Called(featureFlagId, defaultVariant, variants)
+	return args.Error(0)
+}
+
+func (m *MockFeatureFlagRepository) ReplacePrerequisites(featureFlagId uint, prerequisites []model.Prerequisite) error {
+	args := m.Called(featureFlagId, prerequisites)
+	return args.Error(0)
+}
+
+func (m *MockFeatureFlagRepository) GetDependentFeatureFlags(featureFlagId uint) ([]model.FeatureFlag, error) {
+	args := m.Called(featureFlagId)
+	return args.Get(0).([]model.FeatureFlag), args.Error(1)
+}
+
+func (m *MockFeatureFlagRepository) GetExpiredFeatureFlags(date string) ([]model.FeatureFlag, error) {
+	args := m.Called(date)
+	return args.Get(0).([]model.FeatureFlag), args.Error(1)
+}
+
+func (m *MockFeatureFlagRepository) ExpireFeatureFlag(id uint, deactivate bool, reason string, expiredAt time.Time) (bool, error) {
+	args := m.Called(id, deactivate, reason, expiredAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockFeatureFlagRepository) ArchiveFeatureFlag(id uint, archivedAt *time.Time) error {
+	args := m.Called(id, archivedAt)
+	return args.Error(0)
+}
+
+func (m *MockFeatureFlagRepository) DeleteFeatureFlag(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// MockPersonRepository is a mock of PersonRepository
+type MockPersonRepository struct {
+	mock.Mock
+}
+
+func (m *MockPersonRepository) GetPeopleAssignmentByFeatureFlag(pagination model.Pagination, filters p_entity.PersonFilters) ([]model.PersonWithAssignment, int64, error) {
+	args := m.Called(pagination, filters)
+	return args.Get(0).([]model.PersonWithAssignment), int64(args.Get(1).(int)), args.Error(2)
+}
+
+func (m *MockPersonRepository) GetAssignedFeatureFlagsByPersonId(id uint, projectId uint, environment string) ([]model.AssignedFeatureFlag, error) {
+	args := m.Called(id, projectId, environment)
+	return args.Get(0).([]model.AssignedFeatureFlag), args.Error(1)
+}
+
+// Evaluate Tests Cases
+func TestEvaluate(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(time.DateOnly)
+	yesterday := time.Now().AddDate(0, 0, -1).Format(time.DateOnly)
+
+	testCases := []struct {
+		name        string
+		featureFlag model.FeatureFlag
+		isAssigned  bool
+		isInSegment bool
+		isExcluded  bool
+		value       bool
+		reason      string
+	}{
+		{
+			name:        "Flag not found",
+			featureFlag: model.FeatureFlag{},
+			reason:      evaluationEntity.ReasonNotFound,
+		},
+		{
+			name:        "Inactive flag is off even when global",
+			featureFlag: model.FeatureFlag{ID: 1, Name: "TEST_FLAG", IsActive: false, IsGlobal: true},
+			reason:      evaluationEntity.ReasonFlagInactive,
+		},
+		{
+			name:        "Expired flag is off",
+			featureFlag: model.FeatureFlag{ID: 1, Name: "TEST_FLAG", IsActive: true, IsGlobal: true, ExpirationDate: yesterday},
+			reason:      evaluationEntity.ReasonExpired,
+		},
+		{
+			name:        "Global flag is on",
+			featureFlag: model.FeatureFlag{ID: 1, Name: "TEST_FLAG", IsActive: true, IsGlobal: true, ExpirationDate: tomorrow},
+			value:       true,
+			reason:      evaluationEntity.ReasonGlobal,
+		},
+		{
+			name:        "Assigned flag is on",
+			featureFlag: model.FeatureFlag{ID: 1, Name: "TEST_FLAG", IsActive: true},
+			isAssigned:  true,
+			value:       true,
+			reason:      evaluationEntity.ReasonAssigned,
+		},
+		{
+			name:        "Flag assigned to a segment of the person is on",
+			featureFlag: model.FeatureFlag{ID: 1, Name: "TEST_FLAG", IsActive: true},
+			isInSegment: true,
+			value:       true,
+			reason:      evaluationEntity.ReasonSegment,
+		},
+		{
+			name:        "Excluded person is off even when global",
+			featureFlag: model.FeatureFlag{ID: 1, Name: "TEST_FLAG", IsActive: true, IsGlobal: true, RolloutPercentage: 100},
+			isExcluded:  true,
+			reason:      evaluationEntity.ReasonExcluded,
+		},
+		{
+			name:        "Flag in full rollout is on",
+			featureFlag: model.FeatureFlag{ID: 1, Name: "TEST_FLAG", IsActive: true, RolloutPercentage: 100},
+			value:       true,
+			reason:      evaluationEntity.ReasonRollout,
+		},
+		{
+			name:        "Not assigned flag is off",
+			featureFlag: model.FeatureFlag{ID: 1, Name: "TEST_FLAG", IsActive: true},
+			reason:      evaluationEntity.ReasonDefault,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockFeatureFlagRepo := new(MockFeatureFlagRepository)
+			mockPersonRepo := new(MockPersonRepository)
+			logger := zerolog.New(os.Stdout)
+			service := LoadService(mockFeatureFlagRepo, mockPersonRepo, &logger)
+
+			mockFeatureFlagRepo.On("GetFeatureFlagByName", uint(0), "TEST_FLAG", "").Return(tc.featureFlag, nil)
+			mockPersonRepo.On("GetAssignedFeatureFlagsByPersonId", uint(1), uint(0), "").Return([]model.AssignedFeatureFlag{{
+				ID:                   1,
+				Name:                 "TEST_FLAG",
+				IsAssigned:           tc.isAssigned,
+				IsAssignedViaSegment: tc.isInSegment,
+				IsExcluded:           tc.isExcluded,
+			}}, nil)
+
+			response, err := service.Evaluate(evaluationEntity.EvaluationRequest{
+				FlagName: "TEST_FLAG",
+				Context:  evaluationEntity.EvaluationContext{PersonID: 1},
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "TEST_FLAG", response.FlagName)
+			assert.Equal(t, tc.value, response.Value)
+			assert.Equal(t, tc.reason, response.Reason)
+		})
+	}
+
+	t.Run("Targeting rule is evaluated against the context", func(t *testing.T) {
+		mockFeatureFlagRepo := new(MockFeatureFlagRepository)
+		mockPersonRepo := new(MockPersonRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockFeatureFlagRepo, mockPersonRepo, &logger)
+
+		mockFeatureFlagRepo.On("GetFeatureFlagByName", uint(0), "TEST_FLAG", "").Return(model.FeatureFlag{
+			ID:       1,
+			Name:     "TEST_FLAG",
+			IsActive: true,
+			IsGlobal: true,
+			Rules: []model.Rule{
+				{Attribute: "email", Operator: "ends_with", Values: []string{"@competitor.com"}, Result: false},
+			},
+		}, nil)
+
+		response, err := service.Evaluate(evaluationEntity.EvaluationRequest{
+			FlagName: "TEST_FLAG",
+			Context:  evaluationEntity.EvaluationContext{Email: "john@competitor.com"},
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, response.Value)
+		assert.Equal(t, evaluationEntity.ReasonTargeting, response.Reason)
+
+		response, err = service.Evaluate(evaluationEntity.EvaluationRequest{
+			FlagName: "TEST_FLAG",
+			Context:  evaluationEntity.EvaluationContext{Email: "jane@ourcompany.com"},
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, response.Value)
+		assert.Equal(t, evaluationEntity.ReasonGlobal, response.Reason)
+	})
+
+	t.Run("Multivariate flag serves the pinned variant", func(t *testing.T) {
+		mockFeatureFlagRepo := new(MockFeatureFlagRepository)
+		mockPersonRepo := new(MockPersonRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockFeatureFlagRepo, mockPersonRepo, &logger)
+
+		featureFlag := model.FeatureFlag{
+			ID:             1,
+			Name:           "TEST_FLAG",
+			IsActive:       true,
+			Type:           "number",
+			DefaultVariant: "off",
+			Variants: []model.Variant{
+				{Name: "off", Value: "0", Weight: 50},
+				{Name: "ten", Value: "10", Weight: 50},
+			},
+		}
+
+		mockFeatureFlagRepo.On("GetFeatureFlagByName", uint(0), "TEST_FLAG", "").Return(featureFlag, nil)
+		mockPersonRepo.On("GetAssignedFeatureFlagsByPersonId", uint(1), uint(0), "").Return([]model.AssignedFeatureFlag{{
+			ID:              1,
+			Name:            "TEST_FLAG",
+			IsAssigned:      true,
+			AssignedVariant: "ten",
+		}}, nil)
+		mockPersonRepo.On("GetAssignedFeatureFlagsByPersonId", uint(2), uint(0), "").Return([]model.AssignedFeatureFlag{}, nil)
+
+		response, err := service.Evaluate(evaluationEntity.EvaluationRequest{
+			FlagName: "TEST_FLAG",
+			Context:  evaluationEntity.EvaluationContext{PersonID: 1},
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, response.Value)
+		assert.Equal(t, "ten", response.Variant)
+		assert.Equal(t, float64(10), response.VariantValue)
+
+		response, err = service.Evaluate(evaluationEntity.EvaluationRequest{
+			FlagName: "TEST_FLAG",
+			Context:  evaluationEntity.EvaluationContext{PersonID: 2},
+		})
+
+		assert.NoError(t, err)
+		assert.False(t, response.Value)
+		assert.Equal(t, "off", response.Variant)
+		assert.Equal(t, float64(0), response.VariantValue)
+	})
+
+	t.Run("Flag is off when a prerequisite is off", func(t *testing.T) {
+		mockFeatureFlagRepo := new(MockFeatureFlagRepository)
+		mockPersonRepo := new(MockPersonRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockFeatureFlagRepo, mockPersonRepo, &logger)
+
+		mockFeatureFlagRepo.On("GetFeatureFlagByName", uint(0), "NEW_CHECKOUT_PAYPAL", "").Return(model.FeatureFlag{
+			ID:            2,
+			Name:          "NEW_CHECKOUT_PAYPAL",
+			ProjectID:     1,
+			IsActive:      true,
+			IsGlobal:      true,
+			Prerequisites: []model.Prerequisite{{FeatureFlagID: 2, PrerequisiteID: 1, Prerequisite: &model.FeatureFlag{ID: 1, Name: "NEW_CHECKOUT"}}},
+		}, nil)
+		mockFeatureFlagRepo.On("GetFeatureFlagByName", uint(1), "NEW_CHECKOUT", "").Return(model.FeatureFlag{ID: 1, Name: "NEW_CHECKOUT", ProjectID: 1, IsActive: false, IsGlobal: true}, nil).Once()
+
+		response, err := service.Evaluate(evaluationEntity.EvaluationRequest{FlagName: "NEW_CHECKOUT_PAYPAL"})
+
+		assert.NoError(t, err)
+		assert.False(t, response.Value)
+		assert.Equal(t, evaluationEntity.ReasonPrerequisite, response.Reason)
+
+		mockFeatureFlagRepo.On("GetFeatureFlagByName", uint(1), "NEW_CHECKOUT", "").Return(model.FeatureFlag{ID: 1, Name: "NEW_CHECKOUT", ProjectID: 1, IsActive: true, IsGlobal: true}, nil).Once()
+
+		response, err = service.Evaluate(evaluationEntity.EvaluationRequest{FlagName: "NEW_CHECKOUT_PAYPAL"})
+
+		assert.NoError(t, err)
+		assert.True(t, response.Value)
+		assert.Equal(t, evaluationEntity.ReasonGlobal, response.Reason)
+	})
+
+	t.Run("Prerequisite must serve the required variant", func(t *testing.T) {
+		mockFeatureFlagRepo := new(MockFeatureFlagRepository)
+		mockPersonRepo := new(MockPersonRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockFeatureFlagRepo, mockPersonRepo, &logger)
+
+		mockFeatureFlagRepo.On("GetFeatureFlagByName", uint(0), "NEW_CHECKOUT_PAYPAL", "").Return(model.FeatureFlag{
+			ID:            2,
+			Name:          "NEW_CHECKOUT_PAYPAL",
+			IsActive:      true,
+			IsGlobal:      true,
+			Prerequisites: []model.Prerequisite{{FeatureFlagID: 2, PrerequisiteID: 1, Variant: "treatment", Prerequisite: &model.FeatureFlag{ID: 1, Name: "NEW_CHECKOUT"}}},
+		}, nil)
+		mockFeatureFlagRepo.On("GetFeatureFlagByName", uint(0), "NEW_CHECKOUT", "").Return(model.FeatureFlag{
+			ID:             1,
+			Name:           "NEW_CHECKOUT",
+			IsActive:       true,
+			IsGlobal:       true,
+			Type:           "string",
+			DefaultVariant: "control",
+			Variants:       []model.Variant{{Name: "control", Value: "old", Weight: 100}, {Name: "treatment", Value: "new", Weight: 0}},
+		}, nil)
+
+		response, err := service.Evaluate(evaluationEntity.EvaluationRequest{FlagName: "NEW_CHECKOUT_PAYPAL"})
+
+		assert.NoError(t, err)
+		assert.False(t, response.Value)
+		assert.Equal(t, evaluationEntity.ReasonPrerequisite, response.Reason)
+	})
+
+	t.Run("Archived flag is off", func(t *testing.T) {
+		mockFeatureFlagRepo := new(MockFeatureFlagRepository)
+		mockPersonRepo := new(MockPersonRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockFeatureFlagRepo, mockPersonRepo, &logger)
+
+		archivedAt := time.Now()
+		mockFeatureFlagRepo.On("GetFeatureFlagByName", uint(0), "TEST_FLAG", "").Return(model.FeatureFlag{ID: 1, Name: "TEST_FLAG", IsActive: true, IsGlobal: true, ArchivedAt: &archivedAt}, nil)
+
+		response, err := service.Evaluate(evaluationEntity.EvaluationRequest{FlagName: "TEST_FLAG"})
+
+		assert.NoError(t, err)
+		assert.False(t, response.Value)
+		assert.Equal(t, evaluationEntity.ReasonArchived, response.Reason)
+	})
+
+	t.Run("Missing flag name", func(t *testing.T) {
+		mockFeatureFlagRepo := new(MockFeatureFlagRepository)
+		mockPersonRepo := new(MockPersonRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockFeatureFlagRepo, mockPersonRepo, &logger)
+
+		_, err := service.Evaluate(evaluationEntity.EvaluationRequest{})
+
+		assert.Error(t, err)
+		assert.Equal(t, "FlagName|Flag name is required", err.Error())
+		mockFeatureFlagRepo.AssertNotCalled(t, "GetFeatureFlagByName")
+	})
+
+	t.Run("Anonymous context does not look up assignments", func(t *testing.T) {
+		mockFeatureFlagRepo := new(MockFeatureFlagRepository)
+		mockPersonRepo := new(MockPersonRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockFeatureFlagRepo, mockPersonRepo, &logger)
+
+		mockFeatureFlagRepo.On("GetFeatureFlagByName", uint(0), "TEST_FLAG", "").Return(model.FeatureFlag{ID: 1, Name: "TEST_FLAG", IsActive: true}, nil)
+
+		response, err := service.Evaluate(evaluationEntity.EvaluationRequest{FlagName: "TEST_FLAG"})
+
+		assert.NoError(t, err)
+		assert.False(t, response.Value)
+		assert.Equal(t, evaluationEntity.ReasonDefault, response.Reason)
+		mockPersonRepo.AssertNotCalled(t, "GetAssignedFeatureFlagsByPersonId")
+	})
+}
@@ -2,18 +2,30 @@ package entity
 
 import (
 	"errors"
+	assignmentEntity "ff/internal/assignment/entity"
 	personEntity "ff/internal/person/entity"
 	"regexp"
 	"time"
 )
 
 type FeatureFlag struct {
-	ID             uint   `json:"id"`
-	Name           string `json:"name"`
-	Description    string `json:"description"`
-	IsActive       bool   `json:"isActive"`
-	IsGlobal       bool   `json:"isGlobal"`
-	ExpirationDate string `json:"expirationDate"`
+	ID                uint      `json:"id"`
+	Name              string    `json:"name"`
+	Description       string    `json:"description"`
+	IsActive          bool      `json:"isActive"`
+	IsGlobal          bool      `json:"isGlobal"`
+	ExpirationDate    string    `json:"expirationDate"`
+	RolloutPercentage int       `json:"rolloutPercentage"`
+	Rules             []Rule    `json:"rules"`
+	Type              string    `json:"type"`
+	DefaultVariant    string    `json:"defaultVariant"`
+	Variants          []Variant `json:"variants"`
+	// Prerequisites must be on for the flag to be served
+	Prerequisites []Prerequisite `json:"prerequisites"`
+	// Environment receives IsActive and IsGlobal, it comes from the path or header
+	Environment string `json:"-"`
+	// ProjectID owns the flag, it comes from the path, header or api key
+	ProjectID uint `json:"-"`
 }
 
 func (ff *FeatureFlag) Validate() error {
@@ -37,14 +49,41 @@ func (ff *FeatureFlag) Validate() error {
 		}
 	}
 
+	if ff.RolloutPercentage < 0 || ff.RolloutPercentage > 100 {
+		return errors.New("RolloutPercentage|Rollout percentage must be between 0 and 100")
+	}
+
+	for _, rule := range ff.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := ValidateVariants(ff.Type, ff.DefaultVariant, ff.Variants); err != nil {
+		return err
+	}
+
+	if err := ValidatePrerequisites(ff.Prerequisites); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 type UpdateFeatureFlag struct {
-	Description    string `json:"description"`
-	IsActive       bool   `json:"isActive"`
-	IsGlobal       bool   `json:"isGlobal"`
-	ExpirationDate string `json:"expirationDate"`
+	Description       string `json:"description"`
+	IsActive          bool   `json:"isActive"`
+	IsGlobal          bool   `json:"isGlobal"`
+	ExpirationDate    string `json:"expirationDate"`
+	RolloutPercentage int    `json:"rolloutPercentage"`
+	Environment       string `json:"-"`
+	ProjectID         uint   `json:"-"`
+	// Force deactivates the flag even when other flags depend on it
+	Force bool `json:"force"`
+	// Version is the version of the flag the change is based on, zero skips the check
+	Version uint `json:"version"`
+	// PersonID made the change, it is kept in the revision of the flag
+	PersonID uint `json:"-"`
 }
 
 func (ff *UpdateFeatureFlag) Validate() error {
@@ -58,19 +97,33 @@ func (ff *UpdateFeatureFlag) Validate() error {
 		}
 	}
 
+	if ff.RolloutPercentage < 0 || ff.RolloutPercentage > 100 {
+		return errors.New("RolloutPercentage|Rollout percentage must be between 0 and 100")
+	}
+
 	return nil
 }
 
 type FeatureFlagResponse struct {
-	ID             string                      `json:"id"`
-	Name           string                      `json:"name"`
-	Description    string                      `json:"description"`
-	IsActive       bool                        `json:"isActive"`
-	IsGlobal       bool                        `json:"isGlobal"`
-	ExpirationDate string                      `json:"expirationDate"`
-	CreatedAt      string                      `json:"createdAt"`
-	UpdatedAt      string                      `json:"updatedAt"`
-	Person         personEntity.PersonResponse `json:"person"`
+	ID                string                      `json:"id"`
+	Name              string                      `json:"name"`
+	Description       string                      `json:"description"`
+	IsActive          bool                        `json:"isActive"`
+	IsGlobal          bool                        `json:"isGlobal"`
+	ExpirationDate    string                      `json:"expirationDate"`
+	RolloutPercentage int                         `json:"rolloutPercentage"`
+	CreatedAt         string                      `json:"createdAt"`
+	UpdatedAt         string                      `json:"updatedAt"`
+	Person            personEntity.PersonResponse `json:"person"`
+	Rules             []Rule                      `json:"rules"`
+	Type              string                      `json:"type"`
+	DefaultVariant    string                      `json:"defaultVariant"`
+	Variants          []Variant                   `json:"variants"`
+	Prerequisites     []Prerequisite              `json:"prerequisites"`
+	Environment       string                      `json:"environment"`
+	ProjectID         uint                        `json:"projectId"`
+	ArchivedAt        string                      `json:"archivedAt,omitempty"`
+	Version           uint                        `json:"version"`
 }
 
 // type AssignedFeatureFlagResponse struct {
@@ -85,4 +138,50 @@ type FeatureFlagFilters struct {
 	PersonID uint   `json:"personId"`
 	IsActive *bool  `json:"isActive"`
 	IsGlobal *bool  `json:"isGlobal"`
+	// Environment selects the environment state, the default environment when empty
+	Environment string `json:"-"`
+	// ProjectID scopes the flags to a project, every project when zero
+	ProjectID uint `json:"-"`
+	// Archived lists the archived flags instead of the others
+	Archived bool `json:"archived"`
+}
+
+// ArchiveFeatureFlag hides the flag and turns it off until it is restored
+// RollbackFeatureFlag brings the flag back to the state of a revision. The status and the assignments
+// are rolled back in the environment, the default environment also rolls back what the environments
+// share: the description, the dates, the rollout, the rules, the variants and the prerequisites
+type RollbackFeatureFlag struct {
+	FeatureFlag FeatureFlagResponse
+	Assignments []assignmentEntity.Assignment
+	Environment string
+	ProjectID   uint
+	// Force deactivates the flag even when other flags depend on it
+	Force bool
+	// Version is the version of the flag the rollback is based on
+	Version uint
+}
+
+type ArchiveFeatureFlag struct {
+	ProjectID uint `json:"-"`
+	// PersonID archived the flag, it is kept in the revision of the flag
+	PersonID uint `json:"-"`
+	// Force archives the flag even when other flags depend on it
+	Force bool `json:"force"`
+}
+
+// IsExpired reports whether the expiration date (YYYY-MM-DD) is before the day of now,
+// the flag remains valid during the whole expiration day
+func IsExpired(expirationDate string, now time.Time) bool {
+	if expirationDate == "" {
+		return false
+	}
+
+	date, err := time.Parse(time.DateOnly, expirationDate)
+	if err != nil {
+		return false
+	}
+
+	today, _ := time.Parse(time.DateOnly, now.Format(time.DateOnly))
+
+	return date.Before(today)
 }
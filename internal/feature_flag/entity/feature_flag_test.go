@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsExpired(t *testing.T) {
+	now := time.Date(2024, 10, 10, 15, 0, 0, 0, time.UTC)
+
+	assert.False(t, IsExpired("", now))
+	assert.False(t, IsExpired("2024-10-10", now))
+	assert.False(t, IsExpired("2024-10-11", now))
+	assert.True(t, IsExpired("2024-10-09", now))
+}
@@ -0,0 +1,47 @@
+package entity
+
+import "errors"
+
+// Prerequisite references another flag of the project by name, an empty variant
+// only requires the prerequisite to be on
+type Prerequisite struct {
+	FlagName string `json:"flagName"`
+	Variant  string `json:"variant,omitempty"`
+}
+
+func (p *Prerequisite) Validate() error {
+	if p.FlagName == "" {
+		return errors.New("Prerequisites|Prerequisite flag name is required")
+	}
+
+	return nil
+}
+
+// ValidatePrerequisites checks each prerequisite and that no flag is repeated
+func ValidatePrerequisites(prerequisites []Prerequisite) error {
+	names := make(map[string]bool)
+	for _, prerequisite := range prerequisites {
+		if err := prerequisite.Validate(); err != nil {
+			return err
+		}
+
+		if names[prerequisite.FlagName] {
+			return errors.New("Prerequisites|Prerequisite flags must be unique")
+		}
+
+		names[prerequisite.FlagName] = true
+	}
+
+	return nil
+}
+
+type UpdateFeatureFlagPrerequisites struct {
+	Prerequisites []Prerequisite `json:"prerequisites"`
+	ProjectID     uint           `json:"-"`
+	// PersonID made the change, it is kept in the revision of the flag
+	PersonID uint `json:"-"`
+}
+
+func (up *UpdateFeatureFlagPrerequisites) Validate() error {
+	return ValidatePrerequisites(up.Prerequisites)
+}
@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	OperatorEquals     = "equals"
+	OperatorIn         = "in"
+	OperatorContains   = "contains"
+	OperatorStartsWith = "starts_with"
+	OperatorEndsWith   = "ends_with"
+	OperatorRegex      = "regex"
+	OperatorGreater    = "gt"
+	OperatorGreaterEq  = "gte"
+	OperatorLess       = "lt"
+	OperatorLessEq     = "lte"
+	OperatorBefore     = "before"
+	OperatorAfter      = "after"
+)
+
+type Rule struct {
+	Attribute string   `json:"attribute"`
+	Operator  string   `json:"operator"`
+	Values    []string `json:"values"`
+	Result    bool     `json:"result"`
+}
+
+func (r *Rule) Validate() error {
+	if r.Attribute == "" {
+		return errors.New("Rules|Rule attribute is required")
+	}
+
+	if len(r.Values) == 0 {
+		return errors.New("Rules|Rule values are required")
+	}
+
+	switch r.Operator {
+	case OperatorEquals, OperatorIn, OperatorContains, OperatorStartsWith, OperatorEndsWith:
+		return nil
+	case OperatorRegex:
+		for _, value := range r.Values {
+			if _, err := regexp.Compile(value); err != nil {
+				return errors.New("Rules|Rule value must be a valid regular expression")
+			}
+		}
+	case OperatorGreater, OperatorGreaterEq, OperatorLess, OperatorLessEq:
+		if len(r.Values) != 1 {
+			return errors.New("Rules|Numeric rule must have a single value")
+		}
+		if _, err := strconv.ParseFloat(r.Values[0], 64); err != nil {
+			return errors.New("Rules|Numeric rule value must be a number")
+		}
+	case OperatorBefore, OperatorAfter:
+		if len(r.Values) != 1 {
+			return errors.New("Rules|Date rule must have a single value")
+		}
+		if _, err := ParseDate(r.Values[0]); err != nil {
+			return errors.New("Rules|Date rule value must be in YYYY-MM-DD or RFC3339 format")
+		}
+	default:
+		return errors.New("Rules|Rule operator is invalid")
+	}
+
+	return nil
+}
+
+// ParseDate accepts both YYYY-MM-DD and RFC3339 dates, used by date rules
+func ParseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+type UpdateFeatureFlagRules struct {
+	Rules     []Rule `json:"rules"`
+	ProjectID uint   `json:"-"`
+	// PersonID made the change, it is kept in the revision of the flag
+	PersonID uint `json:"-"`
+}
+
+func (ur *UpdateFeatureFlagRules) Validate() error {
+	for _, rule := range ur.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+const (
+	TypeBoolean = "boolean"
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeJSON    = "json"
+)
+
+type Variant struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Weight int    `json:"weight"`
+}
+
+func (v *Variant) Validate(flagType string) error {
+	if v.Name == "" {
+		return errors.New("Variants|Variant name is required")
+	}
+
+	if v.Weight < 0 || v.Weight > 100 {
+		return errors.New("Variants|Variant weight must be between 0 and 100")
+	}
+
+	switch flagType {
+	case TypeNumber:
+		if _, err := strconv.ParseFloat(v.Value, 64); err != nil {
+			return errors.New("Variants|Variant value must be a number")
+		}
+	case TypeJSON:
+		if !json.Valid([]byte(v.Value)) {
+			return errors.New("Variants|Variant value must be a valid JSON")
+		}
+	}
+
+	return nil
+}
+
+// ValidateVariants checks the variant list of a flag type, boolean flags have no
+// variants while the other types need weights adding up to 100 and a default
+// variant, served whenever the flag is off
+func ValidateVariants(flagType, defaultVariant string, variants []Variant) error {
+	switch flagType {
+	case "", TypeBoolean:
+		if len(variants) > 0 || defaultVariant != "" {
+			return errors.New("Variants|Boolean flags do not have variants")
+		}
+		return nil
+	case TypeString, TypeNumber, TypeJSON:
+	default:
+		return errors.New("Type|Type must be boolean, string, number or json")
+	}
+
+	if len(variants) == 0 {
+		return errors.New("Variants|Variants are required")
+	}
+
+	names := make(map[string]bool)
+	totalWeight := 0
+	for _, variant := range variants {
+		if err := variant.Validate(flagType); err != nil {
+			return err
+		}
+
+		if names[variant.Name] {
+			return errors.New("Variants|Variant names must be unique")
+		}
+
+		names[variant.Name] = true
+		totalWeight += variant.Weight
+	}
+
+	if totalWeight != 100 {
+		return errors.New("Variants|Variant weights must add up to 100")
+	}
+
+	if !names[defaultVariant] {
+		return errors.New("Variants|Default variant must be one of the variants")
+	}
+
+	return nil
+}
+
+// DecodeVariantValue converts the stored variant value to the flag type
+func DecodeVariantValue(flagType, value string) interface{} {
+	switch flagType {
+	case TypeNumber:
+		number, _ := strconv.ParseFloat(value, 64)
+		return number
+	case TypeJSON:
+		var decoded interface{}
+		_ = json.Unmarshal([]byte(value), &decoded)
+		return decoded
+	}
+
+	return value
+}
+
+type UpdateFeatureFlagVariants struct {
+	DefaultVariant string    `json:"defaultVariant"`
+	Variants       []Variant `json:"variants"`
+	ProjectID      uint      `json:"-"`
+	// PersonID made the change, it is kept in the revision of the flag
+	PersonID uint `json:"-"`
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateVariants(t *testing.T) {
+	variants := []Variant{
+		{Name: "control", Value: "control", Weight: 50},
+		{Name: "blue_button", Value: "blue", Weight: 50},
+	}
+
+	testCases := []struct {
+		name           string
+		flagType       string
+		defaultVariant string
+		variants       []Variant
+		err            string
+	}{
+		{"boolean without variants", TypeBoolean, "", nil, ""},
+		{"empty type is boolean", "", "", nil, ""},
+		{"boolean with variants", TypeBoolean, "control", variants, "Variants|Boolean flags do not have variants"},
+		{"invalid type", "date", "control", variants, "Type|Type must be boolean, string, number or json"},
+		{"string variants", TypeString, "control", variants, ""},
+		{"missing variants", TypeString, "control", nil, "Variants|Variants are required"},
+		{"unknown default variant", TypeString, "red_button", variants, "Variants|Default variant must be one of the variants"},
+		{"weights not adding up", TypeString, "control", []Variant{{Name: "control", Weight: 50}}, "Variants|Variant weights must add up to 100"},
+		{"duplicated names", TypeString, "control", []Variant{{Name: "control", Weight: 50}, {Name: "control", Weight: 50}}, "Variants|Variant names must be unique"},
+		{"invalid number", TypeNumber, "one", []Variant{{Name: "one", Value: "one", Weight: 100}}, "Variants|Variant value must be a number"},
+		{"invalid json", TypeJSON, "config", []Variant{{Name: "config", Value: "{", Weight: 100}}, "Variants|Variant value must be a valid JSON"},
+		{"json variants", TypeJSON, "config", []Variant{{Name: "config", Value: `{"color":"blue"}`, Weight: 100}}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateVariants(tc.flagType, tc.defaultVariant, tc.variants)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestDecodeVariantValue(t *testing.T) {
+	assert.Equal(t, "blue", DecodeVariantValue(TypeString, "blue"))
+	assert.Equal(t, float64(10), DecodeVariantValue(TypeNumber, "10"))
+	assert.Equal(t, map[string]interface{}{"color": "blue"}, DecodeVariantValue(TypeJSON, `{"color":"blue"}`))
+}
@@ -2,11 +2,16 @@ package featureflag
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"ff/internal/db/model"
 	featureFlagEntity "ff/internal/feature_flag/entity"
 	personEntity "ff/internal/person/entity"
+	streamEntity "ff/internal/stream/entity"
+	webhookEntity "ff/internal/webhook/entity"
 
 	"github.com/rs/zerolog"
 )
@@ -15,10 +20,40 @@ type FeatureFlagRepository interface {
 	AddFeatureFlag(featureFlag model.FeatureFlag) error
 	GetFeatureFlag(filters model.FeatureFlagFilters, pagination model.Pagination) ([]model.FeatureFlag, int64, error)
 	UpdateFeatureFlagById(id uint, featureFlag model.UpdateFeatureFlag) error
+	GetFeatureFlagByName(projectId uint, name string, environment string) (model.FeatureFlag, error)
+	ReplaceRules(featureFlagId uint, rules []model.Rule) error
+	ReplaceVariants(featureFlagId uint, defaultVariant string, variants []model.Variant) error
+	ReplacePrerequisites(featureFlagId uint, prerequisites []model.Prerequisite) error
+	GetDependentFeatureFlags(featureFlagId uint) ([]model.FeatureFlag, error)
+	GetExpiredFeatureFlags(date string) ([]model.FeatureFlag, error)
+	ExpireFeatureFlag(id uint, deactivate bool, reason string, expiredAt time.Time) (bool, error)
+	ArchiveFeatureFlag(id uint, archivedAt *time.Time) error
+	RollbackFeatureFlag(id uint, rollback model.RollbackFeatureFlag) error
+	DeleteFeatureFlag(id uint) error
+}
+
+// Publisher is told about every change of a flag, the flag state is shared by the
+// environments so a change is published to all of them
+type Publisher interface {
+	PublishFeatureFlagChange(eventType string, featureFlagId uint, environment string)
+	PublishFeatureFlagDeletion(featureFlagId uint, projectId uint, name string)
+}
+
+// Notifier is told about every change of a flag with the flag before and after it
+type Notifier interface {
+	NotifyChange(change webhookEntity.Change)
+}
+
+// Recorder keeps a revision of the flag after every change, with the person who made it
+type Recorder interface {
+	RecordRevision(event string, featureFlagId uint, environment string, personId uint)
 }
 
 type FeatureFlagService struct {
 	Repository FeatureFlagRepository
+	Publisher  Publisher
+	Notifier   Notifier
+	Recorder   Recorder
 	Logger     *zerolog.Logger
 }
 
@@ -36,40 +71,98 @@ func (ffs *FeatureFlagService) CreateFeatureFlag(request featureFlagEntity.Featu
 		return errors.New(err.Error())
 	}
 
-	_, totalCount, err := ffs.Repository.GetFeatureFlag(model.FeatureFlagFilters{
-		Name: request.Name,
-	}, model.Pagination{
-		Limit: 1,
-		Page:  1,
-	})
+	// names are unique inside a project, other projects can have a flag with the same name
+	existing, err := ffs.Repository.GetFeatureFlagByName(request.ProjectID, request.Name, "")
 	if err != nil {
 		return err
 	}
 
-	if totalCount > 0 {
+	if existing.ID != 0 {
 		return errors.New("feature flag already exists")
 	}
 
-	return ffs.Repository.AddFeatureFlag(model.FeatureFlag{
-		ID:             request.ID,
-		Name:           request.Name,
-		Description:    request.Description,
-		IsActive:       request.IsActive,
-		IsGlobal:       request.IsGlobal,
-		ExpirationDate: request.ExpirationDate,
-		PersonID:       personId,
-	})
+	if request.Type == "" {
+		request.Type = featureFlagEntity.TypeBoolean
+	}
+
+	// a new flag can not be part of a cycle, nothing depends on it yet
+	prerequisites, err := ffs.resolvePrerequisites(0, request.Name, request.ProjectID, request.Prerequisites)
+	if err != nil {
+		return err
+	}
+
+	featureFlag := model.FeatureFlag{
+		ID:                request.ID,
+		Name:              request.Name,
+		Description:       request.Description,
+		IsActive:          request.IsActive,
+		IsGlobal:          request.IsGlobal,
+		ExpirationDate:    request.ExpirationDate,
+		RolloutPercentage: request.RolloutPercentage,
+		PersonID:          personId,
+		ProjectID:         request.ProjectID,
+		Rules:             RulesToModel(request.Rules),
+		Type:              request.Type,
+		DefaultVariant:    request.DefaultVariant,
+		Variants:          VariantsToModel(request.Variants),
+		Prerequisites:     prerequisites,
+	}
+
+	// the flag starts inactive in every environment but the one it was created in
+	if request.Environment != "" && request.Environment != model.DefaultEnvironment {
+		featureFlag.IsActive = false
+		featureFlag.IsGlobal = false
+		featureFlag.Environments = []model.FeatureFlagEnvironment{{
+			Environment: request.Environment,
+			IsActive:    request.IsActive,
+			IsGlobal:    request.IsGlobal,
+		}}
+	}
+
+	if err := ffs.Repository.AddFeatureFlag(featureFlag); err != nil {
+		return err
+	}
+
+	if ffs.Publisher != nil || ffs.Notifier != nil || ffs.Recorder != nil {
+		created, err := ffs.Repository.GetFeatureFlagByName(request.ProjectID, request.Name, "")
+		if err != nil {
+			return err
+		}
+		ffs.publish(streamEntity.EventFeatureFlagCreated, created.ID)
+		ffs.record(webhookEntity.EventFeatureFlagCreated, created.ID, request.Environment, personId)
+
+		if ffs.Notifier != nil {
+			ffs.Notifier.NotifyChange(webhookEntity.Change{
+				Event:         webhookEntity.EventFeatureFlagCreated,
+				ProjectID:     request.ProjectID,
+				Environment:   request.Environment,
+				FeatureFlagID: created.ID,
+				After:         ffs.currentFeatureFlag(created.ID, request.ProjectID, request.Environment),
+			})
+		}
+	}
+
+	return nil
 }
 
 func (ffs *FeatureFlagService) GetFeatureFlag(pagination model.Pagination, filters featureFlagEntity.FeatureFlagFilters) ([]featureFlagEntity.FeatureFlagResponse, int64, error) {
 	ffs.Logger.Info().Msg("Getting Feature Flag")
 
 	filter := model.FeatureFlagFilters{
-		ID:       filters.ID,
-		Name:     filters.Name,
-		IsActive: filters.IsActive,
-		IsGlobal: filters.IsGlobal,
-		PersonID: filters.PersonID,
+		ID:          filters.ID,
+		Name:        filters.Name,
+		IsActive:    filters.IsActive,
+		IsGlobal:    filters.IsGlobal,
+		PersonID:    filters.PersonID,
+		ProjectID:   filters.ProjectID,
+		Environment: filters.Environment,
+		// archived flags are only listed when asked for
+		Archived: &filters.Archived,
+	}
+
+	environment := filters.Environment
+	if environment == "" {
+		environment = model.DefaultEnvironment
 	}
 
 	featureFlags, totalCount, err := ffs.Repository.GetFeatureFlag(filter, pagination)
@@ -79,26 +172,52 @@ func (ffs *FeatureFlagService) GetFeatureFlag(pagination model.Pagination, filte
 
 	var featureFlagResponses []featureFlagEntity.FeatureFlagResponse
 	for _, ffDB := range featureFlags {
-		featureFlagResponses = append(featureFlagResponses, featureFlagEntity.FeatureFlagResponse{
-			ID:             strconv.Itoa(int(ffDB.ID)),
-			Name:           ffDB.Name,
-			Description:    ffDB.Description,
-			IsActive:       ffDB.IsActive,
-			IsGlobal:       ffDB.IsGlobal,
-			ExpirationDate: ffDB.ExpirationDate,
-			CreatedAt:      ffDB.CreatedAt.Format("2006-01-02 15:04:05"),
-			UpdatedAt:      ffDB.UpdatedAt.Format("2006-01-02 15:04:05"),
-			Person: personEntity.PersonResponse{
-				ID:    ffDB.Person.ID,
-				Name:  ffDB.Person.Name,
-				Email: ffDB.Person.Email,
-			},
-		})
+		featureFlagResponses = append(featureFlagResponses, FeatureFlagFromModel(ffDB, environment))
 	}
 
 	return featureFlagResponses, totalCount, nil
 }
 
+// FeatureFlagFromModel returns the flag as the API returns it, in the environment it was read in
+func FeatureFlagFromModel(ffDB model.FeatureFlag, environment string) featureFlagEntity.FeatureFlagResponse {
+	// the person is not loaded by every query
+	var person personEntity.PersonResponse
+	if ffDB.Person != nil {
+		person = personEntity.PersonResponse{
+			ID:    ffDB.Person.ID,
+			Name:  ffDB.Person.Name,
+			Email: ffDB.Person.Email,
+		}
+	}
+
+	var archivedAt string
+	if ffDB.ArchivedAt != nil {
+		archivedAt = ffDB.ArchivedAt.Format("2006-01-02 15:04:05")
+	}
+
+	return featureFlagEntity.FeatureFlagResponse{
+		ID:                strconv.Itoa(int(ffDB.ID)),
+		Name:              ffDB.Name,
+		Description:       ffDB.Description,
+		IsActive:          ffDB.IsActive,
+		IsGlobal:          ffDB.IsGlobal,
+		ExpirationDate:    ffDB.ExpirationDate,
+		RolloutPercentage: ffDB.RolloutPercentage,
+		CreatedAt:         ffDB.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:         ffDB.UpdatedAt.Format("2006-01-02 15:04:05"),
+		Person:            person,
+		Rules:             RulesFromModel(ffDB.Rules),
+		Type:              ffDB.Type,
+		DefaultVariant:    ffDB.DefaultVariant,
+		Variants:          VariantsFromModel(ffDB.Variants),
+		Prerequisites:     PrerequisitesFromModel(ffDB.Prerequisites),
+		Environment:       environment,
+		ProjectID:         ffDB.ProjectID,
+		ArchivedAt:        archivedAt,
+		Version:           ffDB.Version,
+	}
+}
+
 func (ffs *FeatureFlagService) UpdateFeatureFlagById(id uint, request featureFlagEntity.UpdateFeatureFlag) error {
 	ffs.Logger.Info().Msg("Updating a Feature Flag")
 
@@ -106,8 +225,87 @@ func (ffs *FeatureFlagService) UpdateFeatureFlagById(id uint, request featureFla
 		return errors.New(err.Error())
 	}
 
-	_, countTotal, err := ffs.Repository.GetFeatureFlag(model.FeatureFlagFilters{
-		ID: id,
+	featureFlags, countTotal, err := ffs.Repository.GetFeatureFlag(model.FeatureFlagFilters{
+		ID:          id,
+		ProjectID:   request.ProjectID,
+		Environment: request.Environment,
+	}, model.Pagination{
+		Limit: 1,
+		Page:  1,
+	})
+	if err != nil {
+		return err
+	}
+
+	if countTotal == 0 {
+		return errors.New("feature flag not found")
+	}
+
+	if len(featureFlags) > 0 && featureFlags[0].ArchivedAt != nil {
+		return errors.New("feature flag is archived")
+	}
+
+	// the change was based on a flag someone else changed since, the repository checks it again
+	// while writing for the changes landing in between
+	if len(featureFlags) > 0 && request.Version != 0 && featureFlags[0].Version != request.Version {
+		return errors.New("feature flag was changed by someone else")
+	}
+
+	// deactivating a prerequisite turns its dependent flags off, so it has to be forced
+	if len(featureFlags) > 0 && featureFlags[0].IsActive && !request.IsActive && !request.Force {
+		dependents, err := ffs.Repository.GetDependentFeatureFlags(id)
+		if err != nil {
+			return err
+		}
+
+		if len(dependents) > 0 {
+			var names []string
+			for _, dependent := range dependents {
+				names = append(names, dependent.Name)
+			}
+
+			return fmt.Errorf("feature flag is a prerequisite of %s, force the update to deactivate it", strings.Join(names, ", "))
+		}
+	}
+
+	updateFeatureFlag := model.UpdateFeatureFlag{
+		Description:       request.Description,
+		IsActive:          request.IsActive,
+		IsGlobal:          request.IsGlobal,
+		ExpirationDate:    request.ExpirationDate,
+		RolloutPercentage: request.RolloutPercentage,
+		Environment:       request.Environment,
+		Version:           request.Version,
+	}
+
+	// the expiry mark is kept while the date is still in the past, moving the date
+	// forward makes the flag eligible to expire again
+	if len(featureFlags) > 0 && featureFlagEntity.IsExpired(request.ExpirationDate, time.Now()) {
+		updateFeatureFlag.ExpiredAt = featureFlags[0].ExpiredAt
+		updateFeatureFlag.StatusReason = featureFlags[0].StatusReason
+	}
+
+	if err := ffs.Repository.UpdateFeatureFlagById(id, updateFeatureFlag); err != nil {
+		return err
+	}
+
+	ffs.publish(streamEntity.EventFeatureFlagUpdated, id)
+	ffs.notifyUpdate(featureFlags, request.Environment)
+	ffs.record(revisionEvent(featureFlags, request), id, request.Environment, request.PersonID)
+
+	return nil
+}
+
+func (ffs *FeatureFlagService) UpdateFeatureFlagRules(id uint, request featureFlagEntity.UpdateFeatureFlagRules) error {
+	ffs.Logger.Info().Msg("Updating Feature Flag rules")
+
+	if err := request.Validate(); err != nil {
+		return errors.New(err.Error())
+	}
+
+	featureFlags, countTotal, err := ffs.Repository.GetFeatureFlag(model.FeatureFlagFilters{
+		ID:        id,
+		ProjectID: request.ProjectID,
 	}, model.Pagination{
 		Limit: 1,
 		Page:  1,
@@ -120,10 +318,702 @@ func (ffs *FeatureFlagService) UpdateFeatureFlagById(id uint, request featureFla
 		return errors.New("feature flag not found")
 	}
 
-	return ffs.Repository.UpdateFeatureFlagById(id, model.UpdateFeatureFlag{
-		Description:    request.Description,
-		IsActive:       request.IsActive,
-		IsGlobal:       request.IsGlobal,
-		ExpirationDate: request.ExpirationDate,
+	if len(featureFlags) > 0 && featureFlags[0].ArchivedAt != nil {
+		return errors.New("feature flag is archived")
+	}
+
+	if err := ffs.Repository.ReplaceRules(id, RulesToModel(request.Rules)); err != nil {
+		return err
+	}
+
+	ffs.publish(streamEntity.EventFeatureFlagUpdated, id)
+	ffs.notifyUpdate(featureFlags, "")
+	ffs.record(webhookEntity.EventFeatureFlagUpdated, id, "", request.PersonID)
+
+	return nil
+}
+
+func (ffs *FeatureFlagService) UpdateFeatureFlagVariants(id uint, request featureFlagEntity.UpdateFeatureFlagVariants) error {
+	ffs.Logger.Info().Msg("Updating Feature Flag variants")
+
+	featureFlags, countTotal, err := ffs.Repository.GetFeatureFlag(model.FeatureFlagFilters{
+		ID:        id,
+		ProjectID: request.ProjectID,
+	}, model.Pagination{
+		Limit: 1,
+		Page:  1,
 	})
+	if err != nil {
+		return err
+	}
+
+	if countTotal == 0 {
+		return errors.New("feature flag not found")
+	}
+
+	if len(featureFlags) > 0 && featureFlags[0].ArchivedAt != nil {
+		return errors.New("feature flag is archived")
+	}
+
+	if featureFlags[0].Type == featureFlagEntity.TypeBoolean {
+		return errors.New("Variants|Boolean flags do not have variants")
+	}
+
+	// the type is fixed on creation, variants are validated against it
+	if err := featureFlagEntity.ValidateVariants(featureFlags[0].Type, request.DefaultVariant, request.Variants); err != nil {
+		return errors.New(err.Error())
+	}
+
+	if err := ffs.Repository.ReplaceVariants(id, request.DefaultVariant, VariantsToModel(request.Variants)); err != nil {
+		return err
+	}
+
+	ffs.publish(streamEntity.EventFeatureFlagUpdated, id)
+	ffs.notifyUpdate(featureFlags, "")
+	ffs.record(webhookEntity.EventFeatureFlagUpdated, id, "", request.PersonID)
+
+	return nil
+}
+
+func (ffs *FeatureFlagService) UpdateFeatureFlagPrerequisites(id uint, request featureFlagEntity.UpdateFeatureFlagPrerequisites) error {
+	ffs.Logger.Info().Msg("Updating Feature Flag prerequisites")
+
+	if err := request.Validate(); err != nil {
+		return errors.New(err.Error())
+	}
+
+	featureFlags, countTotal, err := ffs.Repository.GetFeatureFlag(model.FeatureFlagFilters{
+		ID:        id,
+		ProjectID: request.ProjectID,
+	}, model.Pagination{
+		Limit: 1,
+		Page:  1,
+	})
+	if err != nil {
+		return err
+	}
+
+	if countTotal == 0 {
+		return errors.New("feature flag not found")
+	}
+
+	if len(featureFlags) > 0 && featureFlags[0].ArchivedAt != nil {
+		return errors.New("feature flag is archived")
+	}
+
+	prerequisites, err := ffs.resolvePrerequisites(id, featureFlags[0].Name, featureFlags[0].ProjectID, request.Prerequisites)
+	if err != nil {
+		return err
+	}
+
+	if err := ffs.Repository.ReplacePrerequisites(id, prerequisites); err != nil {
+		return err
+	}
+
+	ffs.publish(streamEntity.EventFeatureFlagUpdated, id)
+	ffs.notifyUpdate(featureFlags, "")
+	ffs.record(webhookEntity.EventFeatureFlagUpdated, id, "", request.PersonID)
+
+	return nil
+}
+
+// RollbackFeatureFlag writes the flag of a revision back in one go, checked as the manual updates
+// check it. The change is published and notified once, the revision service records it
+func (ffs *FeatureFlagService) RollbackFeatureFlag(id uint, request featureFlagEntity.RollbackFeatureFlag) error {
+	ffs.Logger.Info().Msg("Rolling back a Feature Flag")
+
+	if request.Environment == "" {
+		request.Environment = model.DefaultEnvironment
+	}
+
+	featureFlags, _, err := ffs.Repository.GetFeatureFlag(model.FeatureFlagFilters{
+		ID:          id,
+		ProjectID:   request.ProjectID,
+		Environment: request.Environment,
+	}, model.Pagination{
+		Limit: 1,
+		Page:  1,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(featureFlags) == 0 {
+		return errors.New("feature flag not found")
+	}
+	current := featureFlags[0]
+
+	if current.ArchivedAt != nil {
+		return errors.New("feature flag is archived")
+	}
+
+	if current.Version != request.Version {
+		return errors.New("feature flag was changed by someone else")
+	}
+
+	target := request.FeatureFlag
+
+	// deactivating a prerequisite turns its dependent flags off, so it has to be forced
+	if current.IsActive && !target.IsActive && !request.Force {
+		dependents, err := ffs.Repository.GetDependentFeatureFlags(id)
+		if err != nil {
+			return err
+		}
+
+		if len(dependents) > 0 {
+			var names []string
+			for _, dependent := range dependents {
+				names = append(names, dependent.Name)
+			}
+
+			return fmt.Errorf("feature flag is a prerequisite of %s, force the update to deactivate it", strings.Join(names, ", "))
+		}
+	}
+
+	rollback := model.RollbackFeatureFlag{
+		Update: model.UpdateFeatureFlag{
+			IsActive:    target.IsActive,
+			IsGlobal:    target.IsGlobal,
+			Environment: request.Environment,
+			Version:     request.Version,
+		},
+		Shared: request.Environment == model.DefaultEnvironment,
+	}
+
+	// the assignments pin the variants the flag has after the rollback
+	variants := current.Variants
+
+	if rollback.Shared {
+		update := featureFlagEntity.UpdateFeatureFlag{
+			Description:       target.Description,
+			ExpirationDate:    target.ExpirationDate,
+			RolloutPercentage: target.RolloutPercentage,
+		}
+		if err := update.Validate(); err != nil {
+			return errors.New(err.Error())
+		}
+
+		rollback.Update.Description = target.Description
+		rollback.Update.ExpirationDate = target.ExpirationDate
+		rollback.Update.RolloutPercentage = target.RolloutPercentage
+
+		// the expiry mark is kept while the date is still in the past
+		if featureFlagEntity.IsExpired(target.ExpirationDate, time.Now()) {
+			rollback.Update.ExpiredAt = current.ExpiredAt
+			rollback.Update.StatusReason = current.StatusReason
+		}
+
+		// the type is fixed on creation, the variants of the revision are validated against it
+		if current.Type != featureFlagEntity.TypeBoolean {
+			if err := featureFlagEntity.ValidateVariants(current.Type, target.DefaultVariant, target.Variants); err != nil {
+				return errors.New(err.Error())
+			}
+
+			rollback.DefaultVariant = target.DefaultVariant
+			rollback.Variants = VariantsToModel(target.Variants)
+			variants = rollback.Variants
+		}
+
+		rules := featureFlagEntity.UpdateFeatureFlagRules{Rules: target.Rules}
+		if err := rules.Validate(); err != nil {
+			return errors.New(err.Error())
+		}
+		rollback.Rules = RulesToModel(target.Rules)
+
+		prerequisites := featureFlagEntity.UpdateFeatureFlagPrerequisites{Prerequisites: target.Prerequisites}
+		if err := prerequisites.Validate(); err != nil {
+			return errors.New(err.Error())
+		}
+		if rollback.Prerequisites, err = ffs.resolvePrerequisites(id, current.Name, current.ProjectID, target.Prerequisites); err != nil {
+			return err
+		}
+	}
+
+	for _, assignment := range request.Assignments {
+		// an excluded person is never served a variant
+		if assignment.Kind == model.AssignmentExclude {
+			assignment.Variant = ""
+		}
+
+		if assignment.Variant != "" && !hasVariant(variants, assignment.Variant) {
+			return fmt.Errorf("variant %s is not a variant of the feature flag %d", assignment.Variant, id)
+		}
+
+		kind := assignment.Kind
+		if kind == "" {
+			kind = model.AssignmentInclude
+		}
+
+		rollback.Assignments = append(rollback.Assignments, model.Assignment{
+			PersonID: assignment.PersonID,
+			Variant:  assignment.Variant,
+			Kind:     kind,
+		})
+	}
+
+	if err := ffs.Repository.RollbackFeatureFlag(id, rollback); err != nil {
+		return err
+	}
+
+	// only the environment changed unless the shared parts were rolled back too
+	if ffs.Publisher != nil {
+		environment := request.Environment
+		if rollback.Shared {
+			environment = ""
+		}
+		ffs.Publisher.PublishFeatureFlagChange(streamEntity.EventFeatureFlagUpdated, id, environment)
+	}
+	ffs.notifyUpdate(featureFlags, request.Environment)
+
+	return nil
+}
+
+// ArchiveFeatureFlag hides the flag from the lists and turns it off everywhere, archiving a
+// prerequisite turns its dependent flags off, so it has to be forced
+func (ffs *FeatureFlagService) ArchiveFeatureFlag(id uint, request featureFlagEntity.ArchiveFeatureFlag) error {
+	ffs.Logger.Info().Msg("Archiving a Feature Flag")
+
+	featureFlag, err := ffs.findFeatureFlag(id, request.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	if featureFlag.ArchivedAt != nil {
+		return errors.New("feature flag is already archived")
+	}
+
+	if !request.Force {
+		dependents, err := ffs.Repository.GetDependentFeatureFlags(id)
+		if err != nil {
+			return err
+		}
+
+		var names []string
+		for _, dependent := range dependents {
+			if dependent.ArchivedAt == nil {
+				names = append(names, dependent.Name)
+			}
+		}
+
+		if len(names) > 0 {
+			return fmt.Errorf("feature flag is a prerequisite of %s, force the archive to turn them off", strings.Join(names, ", "))
+		}
+	}
+
+	archivedAt := time.Now()
+	if err := ffs.Repository.ArchiveFeatureFlag(id, &archivedAt); err != nil {
+		return err
+	}
+
+	ffs.publish(streamEntity.EventFeatureFlagArchived, id)
+	ffs.notifyLifecycle(webhookEntity.EventFeatureFlagArchived, featureFlag, true)
+	ffs.record(webhookEntity.EventFeatureFlagArchived, id, "", request.PersonID)
+
+	return nil
+}
+
+// RestoreFeatureFlag brings an archived flag back in the state it was archived in
+func (ffs *FeatureFlagService) RestoreFeatureFlag(id uint, projectId uint, personId uint) error {
+	ffs.Logger.Info().Msg("Restoring a Feature Flag")
+
+	featureFlag, err := ffs.findFeatureFlag(id, projectId)
+	if err != nil {
+		return err
+	}
+
+	if featureFlag.ArchivedAt == nil {
+		return errors.New("feature flag is not archived")
+	}
+
+	if err := ffs.Repository.ArchiveFeatureFlag(id, nil); err != nil {
+		return err
+	}
+
+	ffs.publish(streamEntity.EventFeatureFlagRestored, id)
+	ffs.notifyLifecycle(webhookEntity.EventFeatureFlagRestored, featureFlag, true)
+	ffs.record(webhookEntity.EventFeatureFlagRestored, id, "", personId)
+
+	return nil
+}
+
+// DeleteFeatureFlag removes an archived flag for good with its assignments, the flags
+// depending on it lose it as a prerequisite
+func (ffs *FeatureFlagService) DeleteFeatureFlag(id uint, projectId uint, personId uint) error {
+	ffs.Logger.Info().Msg("Deleting a Feature Flag")
+
+	featureFlag, err := ffs.findFeatureFlag(id, projectId)
+	if err != nil {
+		return err
+	}
+
+	if featureFlag.ArchivedAt == nil {
+		return errors.New("feature flag must be archived before it is deleted")
+	}
+
+	// the last revision keeps the flag as it was deleted, it can not be read once it is gone
+	ffs.record(webhookEntity.EventFeatureFlagDeleted, id, "", personId)
+
+	if err := ffs.Repository.DeleteFeatureFlag(id); err != nil {
+		return err
+	}
+
+	if ffs.Publisher != nil {
+		ffs.Publisher.PublishFeatureFlagDeletion(id, featureFlag.ProjectID, featureFlag.Name)
+	}
+	ffs.notifyLifecycle(webhookEntity.EventFeatureFlagDeleted, featureFlag, false)
+
+	return nil
+}
+
+// findFeatureFlag returns the flag of the project, archived or not
+func (ffs *FeatureFlagService) findFeatureFlag(id uint, projectId uint) (model.FeatureFlag, error) {
+	featureFlags, countTotal, err := ffs.Repository.GetFeatureFlag(model.FeatureFlagFilters{
+		ID:        id,
+		ProjectID: projectId,
+	}, model.Pagination{
+		Limit: 1,
+		Page:  1,
+	})
+	if err != nil {
+		return model.FeatureFlag{}, err
+	}
+
+	if countTotal == 0 || len(featureFlags) == 0 {
+		return model.FeatureFlag{}, errors.New("feature flag not found")
+	}
+
+	return featureFlags[0], nil
+}
+
+// resolvePrerequisites looks the prerequisites up by name in the project of the flag and
+// rejects the ones that would make the flag depend on itself, directly or through other flags
+func (ffs *FeatureFlagService) resolvePrerequisites(featureFlagId uint, name string, projectId uint, prerequisites []featureFlagEntity.Prerequisite) ([]model.Prerequisite, error) {
+	var modelPrerequisites []model.Prerequisite
+	for i, prerequisite := range prerequisites {
+		if prerequisite.FlagName == name {
+			return nil, errors.New("Prerequisites|A flag can not be its own prerequisite")
+		}
+
+		prerequisiteFlag, err := ffs.Repository.GetFeatureFlagByName(projectId, prerequisite.FlagName, "")
+		if err != nil {
+			return nil, err
+		}
+
+		if prerequisiteFlag.ID == 0 {
+			return nil, fmt.Errorf("Prerequisites|Prerequisite flag %s not found", prerequisite.FlagName)
+		}
+
+		if prerequisiteFlag.ArchivedAt != nil {
+			return nil, fmt.Errorf("Prerequisites|Prerequisite flag %s is archived", prerequisite.FlagName)
+		}
+
+		if prerequisite.Variant != "" && !hasVariant(prerequisiteFlag.Variants, prerequisite.Variant) {
+			return nil, fmt.Errorf("Prerequisites|Variant %s is not a variant of %s", prerequisite.Variant, prerequisite.FlagName)
+		}
+
+		if featureFlagId != 0 {
+			isCycle, err := ffs.dependsOn(prerequisiteFlag, featureFlagId, map[uint]bool{})
+			if err != nil {
+				return nil, err
+			}
+
+			if isCycle {
+				return nil, errors.New("Prerequisites|Prerequisites can not form a cycle")
+			}
+		}
+
+		modelPrerequisites = append(modelPrerequisites, model.Prerequisite{
+			Position:       i,
+			PrerequisiteID: prerequisiteFlag.ID,
+			Variant:        prerequisite.Variant,
+		})
+	}
+
+	return modelPrerequisites, nil
+}
+
+// dependsOn walks the prerequisites of the flag looking for the target flag
+func (ffs *FeatureFlagService) dependsOn(featureFlag model.FeatureFlag, targetId uint, visited map[uint]bool) (bool, error) {
+	if featureFlag.ID == targetId {
+		return true, nil
+	}
+
+	for _, prerequisite := range featureFlag.Prerequisites {
+		if prerequisite.PrerequisiteID == targetId {
+			return true, nil
+		}
+
+		if visited[prerequisite.PrerequisiteID] {
+			continue
+		}
+		visited[prerequisite.PrerequisiteID] = true
+
+		featureFlags, _, err := ffs.Repository.GetFeatureFlag(model.FeatureFlagFilters{
+			ID: prerequisite.PrerequisiteID,
+		}, model.Pagination{
+			Limit: 1,
+			Page:  1,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		if len(featureFlags) == 0 {
+			continue
+		}
+
+		isCycle, err := ffs.dependsOn(featureFlags[0], targetId, visited)
+		if err != nil || isCycle {
+			return isCycle, err
+		}
+	}
+
+	return false, nil
+}
+
+func hasVariant(variants []model.Variant, name string) bool {
+	for _, variant := range variants {
+		if variant.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ffs *FeatureFlagService) publish(eventType string, featureFlagId uint) {
+	if ffs.Publisher != nil {
+		ffs.Publisher.PublishFeatureFlagChange(eventType, featureFlagId, "")
+	}
+}
+
+func (ffs *FeatureFlagService) record(event string, featureFlagId uint, environment string, personId uint) {
+	if ffs.Recorder != nil {
+		ffs.Recorder.RecordRevision(event, featureFlagId, environment, personId)
+	}
+}
+
+// revisionEvent names the update after the status or the global toggle when it is all that changed
+func revisionEvent(featureFlags []model.FeatureFlag, request featureFlagEntity.UpdateFeatureFlag) string {
+	if len(featureFlags) == 0 {
+		return webhookEntity.EventFeatureFlagUpdated
+	}
+
+	environment := request.Environment
+	if environment == "" {
+		environment = model.DefaultEnvironment
+	}
+
+	before := FeatureFlagFromModel(featureFlags[0], environment)
+	if before.Description != request.Description ||
+		before.ExpirationDate != request.ExpirationDate ||
+		before.RolloutPercentage != request.RolloutPercentage {
+		return webhookEntity.EventFeatureFlagUpdated
+	}
+
+	if before.IsActive != request.IsActive && before.IsGlobal == request.IsGlobal {
+		return webhookEntity.EventFeatureFlagStatusToggled
+	}
+
+	if before.IsGlobal != request.IsGlobal && before.IsActive == request.IsActive {
+		return webhookEntity.EventFeatureFlagGlobalToggled
+	}
+
+	return webhookEntity.EventFeatureFlagUpdated
+}
+
+// notifyUpdate tells the notifier about the update of the flag, a change of its status or
+// of its global state is notified on its own as well
+func (ffs *FeatureFlagService) notifyUpdate(featureFlags []model.FeatureFlag, environment string) {
+	if ffs.Notifier == nil || len(featureFlags) == 0 {
+		return
+	}
+
+	if environment == "" {
+		environment = model.DefaultEnvironment
+	}
+
+	before := FeatureFlagFromModel(featureFlags[0], environment)
+	after := ffs.currentFeatureFlag(featureFlags[0].ID, featureFlags[0].ProjectID, environment)
+
+	change := webhookEntity.Change{
+		Event:         webhookEntity.EventFeatureFlagUpdated,
+		ProjectID:     featureFlags[0].ProjectID,
+		Environment:   environment,
+		FeatureFlagID: featureFlags[0].ID,
+		Before:        before,
+		After:         after,
+	}
+	ffs.Notifier.NotifyChange(change)
+
+	if after == nil {
+		return
+	}
+
+	if before.IsActive != after.IsActive {
+		change.Event = webhookEntity.EventFeatureFlagStatusToggled
+		ffs.Notifier.NotifyChange(change)
+	}
+
+	if before.IsGlobal != after.IsGlobal {
+		change.Event = webhookEntity.EventFeatureFlagGlobalToggled
+		ffs.Notifier.NotifyChange(change)
+	}
+}
+
+// notifyLifecycle tells the notifier about the archive, restore or deletion of the flag,
+// a deleted flag has nothing after the change
+func (ffs *FeatureFlagService) notifyLifecycle(event string, featureFlag model.FeatureFlag, exists bool) {
+	if ffs.Notifier == nil {
+		return
+	}
+
+	change := webhookEntity.Change{
+		Event:         event,
+		ProjectID:     featureFlag.ProjectID,
+		Environment:   model.DefaultEnvironment,
+		FeatureFlagID: featureFlag.ID,
+		Before:        FeatureFlagFromModel(featureFlag, model.DefaultEnvironment),
+	}
+
+	if exists {
+		change.After = ffs.currentFeatureFlag(featureFlag.ID, featureFlag.ProjectID, model.DefaultEnvironment)
+	}
+
+	ffs.Notifier.NotifyChange(change)
+}
+
+// currentFeatureFlag reads the flag as the API returns it, nil when it can not be read
+func (ffs *FeatureFlagService) currentFeatureFlag(id uint, projectId uint, environment string) *featureFlagEntity.FeatureFlagResponse {
+	featureFlags, _, err := ffs.Repository.GetFeatureFlag(model.FeatureFlagFilters{
+		ID:          id,
+		ProjectID:   projectId,
+		Environment: environment,
+	}, model.Pagination{
+		Limit: 1,
+		Page:  1,
+	})
+	if err != nil || len(featureFlags) == 0 {
+		ffs.Logger.Error().Err(err).Uint("id", id).Msg("Error when reading the feature flag to notify")
+		return nil
+	}
+
+	if environment == "" {
+		environment = model.DefaultEnvironment
+	}
+
+	featureFlag := FeatureFlagFromModel(featureFlags[0], environment)
+
+	return &featureFlag
+}
+
+// ExpireFeatureFlags marks every flag whose expiration date has passed as expired,
+// deactivating it when deactivate is true. It returns how many flags were expired
+func (ffs *FeatureFlagService) ExpireFeatureFlags(now time.Time, deactivate bool) (int, error) {
+	ffs.Logger.Info().Msg("Expiring Feature Flags")
+
+	featureFlags, err := ffs.Repository.GetExpiredFeatureFlags(now.Format(time.DateOnly))
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, featureFlag := range featureFlags {
+		reason := fmt.Sprintf("expired on %s", featureFlag.ExpirationDate)
+		if deactivate {
+			reason = fmt.Sprintf("deactivated by the expiry worker, expired on %s", featureFlag.ExpirationDate)
+		}
+
+		// the flag is only expired once, another replica may have got to it first
+		claimed, err := ffs.Repository.ExpireFeatureFlag(featureFlag.ID, deactivate, reason, now)
+		if err != nil {
+			return expired, err
+		}
+		if !claimed {
+			continue
+		}
+		expired++
+
+		ffs.Logger.Warn().Str("name", featureFlag.Name).Str("expirationDate", featureFlag.ExpirationDate).Msg(reason)
+		ffs.publish(streamEntity.EventFeatureFlagUpdated, featureFlag.ID)
+		ffs.notifyUpdate([]model.FeatureFlag{featureFlag}, "")
+		ffs.record(webhookEntity.EventFeatureFlagUpdated, featureFlag.ID, "", 0)
+	}
+
+	return expired, nil
+}
+
+// RulesToModel keeps the request order in the rule position
+func RulesToModel(rules []featureFlagEntity.Rule) []model.Rule {
+	var modelRules []model.Rule
+	for i, rule := range rules {
+		modelRules = append(modelRules, model.Rule{
+			Position:  i,
+			Attribute: rule.Attribute,
+			Operator:  rule.Operator,
+			Values:    rule.Values,
+			Result:    rule.Result,
+		})
+	}
+
+	return modelRules
+}
+
+func RulesFromModel(rules []model.Rule) []featureFlagEntity.Rule {
+	var entityRules []featureFlagEntity.Rule
+	for _, rule := range rules {
+		entityRules = append(entityRules, featureFlagEntity.Rule{
+			Attribute: rule.Attribute,
+			Operator:  rule.Operator,
+			Values:    rule.Values,
+			Result:    rule.Result,
+		})
+	}
+
+	return entityRules
+}
+
+// VariantsToModel keeps the request order in the variant position
+func VariantsToModel(variants []featureFlagEntity.Variant) []model.Variant {
+	var modelVariants []model.Variant
+	for i, variant := range variants {
+		modelVariants = append(modelVariants, model.Variant{
+			Position: i,
+			Name:     variant.Name,
+			Value:    variant.Value,
+			Weight:   variant.Weight,
+		})
+	}
+
+	return modelVariants
+}
+
+func VariantsFromModel(variants []model.Variant) []featureFlagEntity.Variant {
+	var entityVariants []featureFlagEntity.Variant
+	for _, variant := range variants {
+		entityVariants = append(entityVariants, featureFlagEntity.Variant{
+			Name:   variant.Name,
+			Value:  variant.Value,
+			Weight: variant.Weight,
+		})
+	}
+
+	return entityVariants
+}
+
+func PrerequisitesFromModel(prerequisites []model.Prerequisite) []featureFlagEntity.Prerequisite {
+	var entityPrerequisites []featureFlagEntity.Prerequisite
+	for _, prerequisite := range prerequisites {
+		entityPrerequisite := featureFlagEntity.Prerequisite{
+			Variant: prerequisite.Variant,
+		}
+
+		if prerequisite.Prerequisite != nil {
+			entityPrerequisite.FlagName = prerequisite.Prerequisite.Name
+		}
+
+		entityPrerequisites = append(entityPrerequisites, entityPrerequisite)
+	}
+
+	return entityPrerequisites
 }
@@ -1,12 +1,16 @@
 package featureflag
 
 import (
+	"errors"
 	"os"
 	"testing"
 	"time"
 
+	assignmentEntity "ff/internal/assignment/entity"
 	"ff/internal/db/model"
 	featureFlagEntity "ff/internal/feature_flag/entity"
+	streamEntity "ff/internal/stream/entity"
+	webhookEntity "ff/internal/webhook/entity"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
@@ -33,6 +37,87 @@ func (m *MockRepository) UpdateFeatureFlagById(id uint, featureFlag model.Update
 	return args.Error(0)
 }
 
+func (m *MockRepository) GetFeatureFlagByName(projectId uint, name string, environment string) (model.FeatureFlag, error) {
+	args := m.Called(projectId, name, environment)
+	return args.Get(0).(model.FeatureFlag), args.Error(1)
+}
+
+func (m *MockRepository) ReplaceRules(featureFlagId uint, rules []model.Rule) error {
+	args := m.Called(featureFlagId, rules)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ReplaceVariants(featureFlagId uint, defaultVariant string, variants []model.Variant) error {
+	args := m.Called(featureFlagId, defaultVariant, variants)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ReplacePrerequisites(featureFlagId uint, prerequisites []model.Prerequisite) error {
+	args := m.Called(featureFlagId, prerequisites)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetDependentFeatureFlags(featureFlagId uint) ([]model.FeatureFlag, error) {
+	args := m.Called(featureFlagId)
+	return args.Get(0).([]model.FeatureFlag), args.Error(1)
+}
+
+func (m *MockRepository) GetExpiredFeatureFlags(date string) ([]model.FeatureFlag, error) {
+	args := m.Called(date)
+	return args.Get(0).([]model.FeatureFlag), args.Error(1)
+}
+
+func (m *MockRepository) ExpireFeatureFlag(id uint, deactivate bool, reason string, expiredAt time.Time) (bool, error) {
+	args := m.Called(id, deactivate, reason, expiredAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) RollbackFeatureFlag(id uint, rollback model.RollbackFeatureFlag) error {
+	args := m.Called(id, rollback)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ArchiveFeatureFlag(id uint, archivedAt *time.Time) error {
+	args := m.Called(id, archivedAt)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteFeatureFlag(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// MockPublisher is a mock of the stream service
+type MockPublisher struct {
+	mock.Mock
+}
+
+func (m *MockPublisher) PublishFeatureFlagChange(eventType string, featureFlagId uint, environment string) {
+	m.Called(eventType, featureFlagId, environment)
+}
+
+func (m *MockPublisher) PublishFeatureFlagDeletion(featureFlagId uint, projectId uint, name string) {
+	m.Called(featureFlagId, projectId, name)
+}
+
+// MockNotifier is a mock of the webhook service
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) NotifyChange(change webhookEntity.Change) {
+	m.Called(change)
+}
+
+// MockRecorder is a mock of the revision service
+type MockRecorder struct {
+	mock.Mock
+}
+
+func (m *MockRecorder) RecordRevision(event string, featureFlagId uint, environment string, personId uint) {
+	m.Called(event, featureFlagId, environment, personId)
+}
+
 // Create Feature Flag Tests Cases
 func TestCreateFeatureFlag(t *testing.T) {
 	t.Run("Successfully create feature flag", func(t *testing.T) {
@@ -46,11 +131,9 @@ func TestCreateFeatureFlag(t *testing.T) {
 			IsActive:    true,
 		}
 
-		filtersMock := mock.AnythingOfType("model.FeatureFlagFilters")
-		paginationMock := mock.AnythingOfType("model.Pagination")
 		featureFlagMock := mock.AnythingOfType("model.FeatureFlag")
 
-		mockRepo.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{}, 0, nil)
+		mockRepo.On("GetFeatureFlagByName", uint(0), request.Name, "").Return(model.FeatureFlag{}, nil)
 		mockRepo.On("AddFeatureFlag", featureFlagMock).Return(nil)
 
 		err := service.CreateFeatureFlag(request, 1)
@@ -72,12 +155,38 @@ func TestCreateFeatureFlag(t *testing.T) {
 			ExpirationDate: expirationDate,
 		}
 
-		filtersMock := mock.AnythingOfType("model.FeatureFlagFilters")
-		paginationMock := mock.AnythingOfType("model.Pagination")
 		featureFlagMock := mock.AnythingOfType("model.FeatureFlag")
 
-		mockRepo.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{}, 0, nil)
+		mockRepo.On("GetFeatureFlagByName", uint(0), request.Name, "").Return(model.FeatureFlag{}, nil)
+		mockRepo.On("AddFeatureFlag", featureFlagMock).Return(nil)
+		err := service.CreateFeatureFlag(request, 1)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Create feature flag in a non default environment", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		request := featureFlagEntity.FeatureFlag{
+			Name:        "TEST_FLAG_V1",
+			Description: "Test Description",
+			IsActive:    true,
+			Environment: "staging",
+		}
+
+		featureFlagMock := mock.MatchedBy(func(featureFlag model.FeatureFlag) bool {
+			return !featureFlag.IsActive &&
+				len(featureFlag.Environments) == 1 &&
+				featureFlag.Environments[0].Environment == "staging" &&
+				featureFlag.Environments[0].IsActive
+		})
+
+		mockRepo.On("GetFeatureFlagByName", uint(0), request.Name, "").Return(model.FeatureFlag{}, nil)
 		mockRepo.On("AddFeatureFlag", featureFlagMock).Return(nil)
+
 		err := service.CreateFeatureFlag(request, 1)
 
 		assert.NoError(t, err)
@@ -97,10 +206,7 @@ func TestCreateFeatureFlag(t *testing.T) {
 			ExpirationDate: expirationDate,
 		}
 
-		filtersMock := mock.AnythingOfType("model.FeatureFlagFilters")
-		paginationMock := mock.AnythingOfType("model.Pagination")
-
-		mockRepo.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{}, 1, nil)
+		mockRepo.On("GetFeatureFlagByName", uint(0), request.Name, "").Return(model.FeatureFlag{ID: 1, Name: request.Name}, nil)
 
 		err := service.CreateFeatureFlag(request, 1)
 
@@ -109,6 +215,28 @@ func TestCreateFeatureFlag(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("A flag whose name contains the new name is not a duplicate", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		request := featureFlagEntity.FeatureFlag{
+			Name:        "CHECKOUT",
+			Description: "Test Description",
+			ProjectID:   1,
+		}
+
+		// NEW_CHECKOUT exists in the project, it matches CHECKOUT only with a LIKE
+		mockRepo.On("GetFeatureFlagByName", uint(1), "CHECKOUT", "").Return(model.FeatureFlag{}, nil)
+		mockRepo.On("AddFeatureFlag", mock.AnythingOfType("model.FeatureFlag")).Return(nil)
+
+		err := service.CreateFeatureFlag(request, 1)
+
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "GetFeatureFlag", mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("Invalid feature flag name", func(t *testing.T) {
 		mockRepo := new(MockRepository)
 		logger := zerolog.New(os.Stdout)
@@ -123,7 +251,7 @@ func TestCreateFeatureFlag(t *testing.T) {
 		err := service.CreateFeatureFlag(request, 1)
 
 		assert.Error(t, err)
-		assert.Equal(t, "name is required", err.Error())
+		assert.Equal(t, "Name|Name is required", err.Error())
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -141,7 +269,7 @@ func TestCreateFeatureFlag(t *testing.T) {
 		err := service.CreateFeatureFlag(request, 1)
 
 		assert.Error(t, err)
-		assert.Equal(t, "name must be uppercase and contain only letters, numbers, underscores", err.Error())
+		assert.Equal(t, "Name|Name must be uppercase and contain only letters, numbers, underscores", err.Error())
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -159,7 +287,7 @@ func TestCreateFeatureFlag(t *testing.T) {
 		err := service.CreateFeatureFlag(request, 1)
 
 		assert.Error(t, err)
-		assert.Equal(t, "description is required", err.Error())
+		assert.Equal(t, "Description|Description is required", err.Error())
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -168,7 +296,7 @@ func TestCreateFeatureFlag(t *testing.T) {
 		logger := zerolog.New(os.Stdout)
 		service := LoadService(mockRepo, &logger)
 
-		request := featureFlagEntityFeatureFlag{
+		request := featureFlagEntity.FeatureFlag{
 			Name:           "TEST_FLAG_V1",
 			Description:    "Test Description",
 			IsActive:       true,
@@ -178,7 +306,7 @@ func TestCreateFeatureFlag(t *testing.T) {
 		err := service.CreateFeatureFlag(request, 1)
 
 		assert.Error(t, err)
-		assert.Equal(t, "expirationDate must be in YYYY-MM-DD format", err.Error())
+		assert.Equal(t, "ExpirationDate|Expiration date must be in YYYY-MM-DD format", err.Error())
 		mockRepo.AssertExpectations(t)
 	})
 }
@@ -222,14 +350,12 @@ func TestGetFeatureFlag(t *testing.T) {
 
 		mockRepo.On("GetFeatureFlag", filtersMock, paginationMock).Return(featureFlagMock, 1, nil)
 
-		featureFlag, totalCount, err := service.GetFeatureFlag(
-			pagination.Page,
-			pagination.Limit,
-			filters.Name,
-			filters.IsActive,
-			filters.ID,
-			filters.PersonID,
-		)
+		featureFlag, totalCount, err := service.GetFeatureFlag(pagination, featureFlagEntity.FeatureFlagFilters{
+			ID:       filters.ID,
+			Name:     filters.Name,
+			IsActive: filters.IsActive,
+			PersonID: filters.PersonID,
+		})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, featureFlag)
@@ -259,14 +385,12 @@ func TestGetFeatureFlag(t *testing.T) {
 
 		mockRepo.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{}, 0, nil)
 
-		featureFlag, _, err := service.GetFeatureFlag(
-			pagination.Page,
-			pagination.Limit,
-			filters.Name,
-			filters.IsActive,
-			filters.ID,
-			filters.PersonID,
-		)
+		featureFlag, _, err := service.GetFeatureFlag(pagination, featureFlagEntity.FeatureFlagFilters{
+			ID:       filters.ID,
+			Name:     filters.Name,
+			IsActive: filters.IsActive,
+			PersonID: filters.PersonID,
+		})
 
 		assert.NoError(t, err)
 		assert.Nil(t, featureFlag)
@@ -335,7 +459,7 @@ func TestUpdateFeatureFlagById(t *testing.T) {
 		err := service.UpdateFeatureFlagById(1, updateFeatureFlag)
 
 		assert.Error(t, err)
-		assert.Equal(t, "description is required", err.Error())
+		assert.Equal(t, "Description|Description is required", err.Error())
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -352,7 +476,801 @@ func TestUpdateFeatureFlagById(t *testing.T) {
 		err := service.UpdateFeatureFlagById(1, updateFeatureFlag)
 
 		assert.Error(t, err)
-		assert.Equal(t, "expirationDate must be in YYYY-MM-DD format", err.Error())
+		assert.Equal(t, "ExpirationDate|Expiration date must be in YYYY-MM-DD format", err.Error())
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// Deactivate Prerequisite Tests Cases
+func TestDeactivatePrerequisite(t *testing.T) {
+	filters := model.FeatureFlagFilters{ID: 1}
+	onePage := model.Pagination{Limit: 1, Page: 1}
+	activeFlag := []model.FeatureFlag{{ID: 1, Name: "NEW_CHECKOUT", IsActive: true}}
+	dependents := []model.FeatureFlag{{ID: 2, Name: "NEW_CHECKOUT_PAYPAL"}, {ID: 3, Name: "NEW_CHECKOUT_PIX"}}
+
+	t.Run("Deactivating a prerequisite is blocked", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", filters, onePage).Return(activeFlag, 1, nil)
+		mockRepo.On("GetDependentFeatureFlags", uint(1)).Return(dependents, nil)
+
+		err := service.UpdateFeatureFlagById(1, featureFlagEntity.UpdateFeatureFlag{Description: "Description"})
+
+		assert.EqualError(t, err, "feature flag is a prerequisite of NEW_CHECKOUT_PAYPAL, NEW_CHECKOUT_PIX, force the update to deactivate it")
+		mockRepo.AssertNotCalled(t, "UpdateFeatureFlagById", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Stale version is refused before the prerequisites are checked", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", filters, onePage).Return([]model.FeatureFlag{{ID: 1, Name: "NEW_CHECKOUT", IsActive: true, Version: 3}}, 1, nil)
+
+		err := service.UpdateFeatureFlagById(1, featureFlagEntity.UpdateFeatureFlag{Description: "Description", Version: 2})
+
+		assert.EqualError(t, err, "feature flag was changed by someone else")
+		mockRepo.AssertNotCalled(t, "GetDependentFeatureFlags", mock.Anything)
+		mockRepo.AssertNotCalled(t, "UpdateFeatureFlagById", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Forced deactivation of a prerequisite", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", filters, onePage).Return(activeFlag, 1, nil)
+		mockRepo.On("UpdateFeatureFlagById", uint(1), model.UpdateFeatureFlag{Description: "Description"}).Return(nil)
+
+		err := service.UpdateFeatureFlagById(1, featureFlagEntity.UpdateFeatureFlag{Description: "Description", Force: true})
+
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "GetDependentFeatureFlags", mock.Anything)
+	})
+}
+
+// Update Feature Flag Prerequisites Tests Cases
+func TestUpdateFeatureFlagPrerequisites(t *testing.T) {
+	onePage := model.Pagination{Limit: 1, Page: 1}
+	paypal := model.FeatureFlag{ID: 2, Name: "NEW_CHECKOUT_PAYPAL", ProjectID: 1}
+
+	t.Run("Successfully update prerequisites", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 2, ProjectID: 1}, onePage).Return([]model.FeatureFlag{paypal}, 1, nil)
+		mockRepo.On("GetFeatureFlagByName", uint(1), "NEW_CHECKOUT", "").Return(model.FeatureFlag{
+			ID:       1,
+			Name:     "NEW_CHECKOUT",
+			Variants: []model.Variant{{Name: "control"}, {Name: "treatment"}},
+		}, nil)
+		mockRepo.On("ReplacePrerequisites", uint(2), []model.Prerequisite{{PrerequisiteID: 1, Variant: "treatment"}}).Return(nil)
+
+		err := service.UpdateFeatureFlagPrerequisites(2, featureFlagEntity.UpdateFeatureFlagPrerequisites{
+			Prerequisites: []featureFlagEntity.Prerequisite{{FlagName: "NEW_CHECKOUT", Variant: "treatment"}},
+			ProjectID:     1,
+		})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Prerequisites can not form a cycle", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		// NEW_CHECKOUT requires NEW_CHECKOUT_BETA, which requires NEW_CHECKOUT_PAYPAL
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 2, ProjectID: 1}, onePage).Return([]model.FeatureFlag{paypal}, 1, nil)
+		mockRepo.On("GetFeatureFlagByName", uint(1), "NEW_CHECKOUT", "").Return(model.FeatureFlag{
+			ID:            1,
+			Name:          "NEW_CHECKOUT",
+			Prerequisites: []model.Prerequisite{{FeatureFlagID: 1, PrerequisiteID: 3}},
+		}, nil)
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 3}, onePage).Return([]model.FeatureFlag{{
+			ID:            3,
+			Name:          "NEW_CHECKOUT_BETA",
+			Prerequisites: []model.Prerequisite{{FeatureFlagID: 3, PrerequisiteID: 2}},
+		}}, 1, nil)
+
+		err := service.UpdateFeatureFlagPrerequisites(2, featureFlagEntity.UpdateFeatureFlagPrerequisites{
+			Prerequisites: []featureFlagEntity.Prerequisite{{FlagName: "NEW_CHECKOUT"}},
+			ProjectID:     1,
+		})
+
+		assert.EqualError(t, err, "Prerequisites|Prerequisites can not form a cycle")
+		mockRepo.AssertNotCalled(t, "ReplacePrerequisites", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Flag can not be its own prerequisite", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 2, ProjectID: 1}, onePage).Return([]model.FeatureFlag{paypal}, 1, nil)
+
+		err := service.UpdateFeatureFlagPrerequisites(2, featureFlagEntity.UpdateFeatureFlagPrerequisites{
+			Prerequisites: []featureFlagEntity.Prerequisite{{FlagName: "NEW_CHECKOUT_PAYPAL"}},
+			ProjectID:     1,
+		})
+
+		assert.EqualError(t, err, "Prerequisites|A flag can not be its own prerequisite")
+	})
+
+	t.Run("Prerequisite flag not found", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 2, ProjectID: 1}, onePage).Return([]model.FeatureFlag{paypal}, 1, nil)
+		mockRepo.On("GetFeatureFlagByName", uint(1), "UNKNOWN_FLAG", "").Return(model.FeatureFlag{}, nil)
+
+		err := service.UpdateFeatureFlagPrerequisites(2, featureFlagEntity.UpdateFeatureFlagPrerequisites{
+			Prerequisites: []featureFlagEntity.Prerequisite{{FlagName: "UNKNOWN_FLAG"}},
+			ProjectID:     1,
+		})
+
+		assert.EqualError(t, err, "Prerequisites|Prerequisite flag UNKNOWN_FLAG not found")
+	})
+
+	t.Run("Required variant must exist", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 2, ProjectID: 1}, onePage).Return([]model.FeatureFlag{paypal}, 1, nil)
+		mockRepo.On("GetFeatureFlagByName", uint(1), "NEW_CHECKOUT", "").Return(model.FeatureFlag{ID: 1, Name: "NEW_CHECKOUT"}, nil)
+
+		err := service.UpdateFeatureFlagPrerequisites(2, featureFlagEntity.UpdateFeatureFlagPrerequisites{
+			Prerequisites: []featureFlagEntity.Prerequisite{{FlagName: "NEW_CHECKOUT", Variant: "treatment"}},
+			ProjectID:     1,
+		})
+
+		assert.EqualError(t, err, "Prerequisites|Variant treatment is not a variant of NEW_CHECKOUT")
+	})
+}
+
+// Expire Feature Flags Tests Cases
+func TestExpireFeatureFlags(t *testing.T) {
+	now := time.Date(2024, 10, 10, 3, 0, 0, 0, time.UTC)
+	expiredFlags := []model.FeatureFlag{
+		{ID: 1, Name: "FLAG_ONE", IsActive: true, ExpirationDate: "2024-10-01"},
+		{ID: 2, Name: "FLAG_TWO", IsActive: true, ExpirationDate: "2024-10-09"},
+	}
+
+	t.Run("Deactivate expired feature flags", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetExpiredFeatureFlags", "2024-10-10").Return(expiredFlags, nil)
+		mockRepo.On("ExpireFeatureFlag", uint(1), true, "deactivated by the expiry worker, expired on 2024-10-01", now).Return(true, nil)
+		mockRepo.On("ExpireFeatureFlag", uint(2), true, "deactivated by the expiry worker, expired on 2024-10-09", now).Return(true, nil)
+
+		total, err := service.ExpireFeatureFlags(now, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Only mark expired feature flags", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetExpiredFeatureFlags", "2024-10-10").Return(expiredFlags[:1], nil)
+		mockRepo.On("ExpireFeatureFlag", uint(1), false, "expired on 2024-10-01", now).Return(true, nil)
+
+		total, err := service.ExpireFeatureFlags(now, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("Feature flag expired by another worker is skipped", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockPublisher := new(MockPublisher)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Publisher = mockPublisher
+
+		mockRepo.On("GetExpiredFeatureFlags", "2024-10-10").Return(expiredFlags, nil)
+		mockRepo.On("ExpireFeatureFlag", uint(1), true, "deactivated by the expiry worker, expired on 2024-10-01", now).Return(false, nil)
+		mockRepo.On("ExpireFeatureFlag", uint(2), true, "deactivated by the expiry worker, expired on 2024-10-09", now).Return(true, nil)
+		mockPublisher.On("PublishFeatureFlagChange", streamEntity.EventFeatureFlagUpdated, uint(2), "").Return()
+
+		total, err := service.ExpireFeatureFlags(now, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		mockPublisher.AssertExpectations(t)
+		mockPublisher.AssertNotCalled(t, "PublishFeatureFlagChange", streamEntity.EventFeatureFlagUpdated, uint(1), "")
+	})
+}
+
+func TestPublishFeatureFlagChanges(t *testing.T) {
+	filtersMock := mock.AnythingOfType("model.FeatureFlagFilters")
+	paginationMock := mock.AnythingOfType("model.Pagination")
+
+	t.Run("Created feature flag is published to every environment", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockPublisher := new(MockPublisher)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Publisher = mockPublisher
+
+		mockRepo.On("GetFeatureFlagByName", uint(1), "TEST_FLAG_V1", "").Return(model.FeatureFlag{}, nil).Once()
+		mockRepo.On("AddFeatureFlag", mock.AnythingOfType("model.FeatureFlag")).Return(nil)
+		mockRepo.On("GetFeatureFlagByName", uint(1), "TEST_FLAG_V1", "").Return(model.FeatureFlag{ID: 5, Name: "TEST_FLAG_V1"}, nil)
+		mockPublisher.On("PublishFeatureFlagChange", streamEntity.EventFeatureFlagCreated, uint(5), "").Return()
+
+		err := service.CreateFeatureFlag(featureFlagEntity.FeatureFlag{
+			Name:        "TEST_FLAG_V1",
+			Description: "Test Description",
+			ProjectID:   1,
+		}, 1)
+
+		assert.NoError(t, err)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("Updated feature flag is published", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockPublisher := new(MockPublisher)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Publisher = mockPublisher
+
+		mockRepo.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{{ID: 5, IsActive: true}}, 1, nil)
+		mockRepo.On("UpdateFeatureFlagById", uint(5), mock.AnythingOfType("model.UpdateFeatureFlag")).Return(nil)
+		mockPublisher.On("PublishFeatureFlagChange", streamEntity.EventFeatureFlagUpdated, uint(5), "").Return()
+
+		err := service.UpdateFeatureFlagById(5, featureFlagEntity.UpdateFeatureFlag{
+			Description: "Description",
+			IsActive:    true,
+		})
+
+		assert.NoError(t, err)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("Failed update is not published", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockPublisher := new(MockPublisher)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Publisher = mockPublisher
+
+		mockRepo.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{{ID: 5, IsActive: true}}, 1, nil)
+		mockRepo.On("UpdateFeatureFlagById", uint(5), mock.AnythingOfType("model.UpdateFeatureFlag")).Return(errors.New("no feature flag updated"))
+
+		err := service.UpdateFeatureFlagById(5, featureFlagEntity.UpdateFeatureFlag{
+			Description: "Description",
+			IsActive:    true,
+		})
+
+		assert.Error(t, err)
+		mockPublisher.AssertNotCalled(t, "PublishFeatureFlagChange", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestNotifyFeatureFlagChanges(t *testing.T) {
+	filtersMock := mock.AnythingOfType("model.FeatureFlagFilters")
+	paginationMock := mock.AnythingOfType("model.Pagination")
+
+	t.Run("Created feature flag is notified with its state", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockNotifier := new(MockNotifier)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Notifier = mockNotifier
+
+		mockRepo.On("GetFeatureFlagByName", uint(1), "TEST_FLAG_V1", "").Return(model.FeatureFlag{}, nil).Once()
+		mockRepo.On("AddFeatureFlag", mock.AnythingOfType("model.FeatureFlag")).Return(nil)
+		mockRepo.On("GetFeatureFlagByName", uint(1), "TEST_FLAG_V1", "").Return(model.FeatureFlag{ID: 5, Name: "TEST_FLAG_V1"}, nil)
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 5, ProjectID: 1}, paginationMock).Return([]model.FeatureFlag{{ID: 5, Name: "TEST_FLAG_V1", ProjectID: 1}}, 1, nil)
+		mockNotifier.On("NotifyChange", mock.MatchedBy(func(change webhookEntity.Change) bool {
+			after, ok := change.After.(*featureFlagEntity.FeatureFlagResponse)
+			return change.Event == webhookEntity.EventFeatureFlagCreated && change.FeatureFlagID == 5 && change.Before == nil &&
+				ok && after.Name == "TEST_FLAG_V1"
+		})).Return()
+
+		err := service.CreateFeatureFlag(featureFlagEntity.FeatureFlag{
+			Name:        "TEST_FLAG_V1",
+			Description: "Test Description",
+			ProjectID:   1,
+		}, 1)
+
+		assert.NoError(t, err)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Status and global toggles are notified next to the update", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockNotifier := new(MockNotifier)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Notifier = mockNotifier
+
+		mockRepo.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{{ID: 5, ProjectID: 1, IsActive: false, IsGlobal: false}}, 1, nil).Once()
+		mockRepo.On("UpdateFeatureFlagById", uint(5), mock.AnythingOfType("model.UpdateFeatureFlag")).Return(nil)
+		mockRepo.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{{ID: 5, ProjectID: 1, IsActive: true, IsGlobal: true}}, 1, nil).Once()
+
+		var events []string
+		mockNotifier.On("NotifyChange", mock.MatchedBy(func(change webhookEntity.Change) bool {
+			before := change.Before.(featureFlagEntity.FeatureFlagResponse)
+			after := change.After.(*featureFlagEntity.FeatureFlagResponse)
+			return !before.IsActive && after.IsActive && change.Environment == "staging"
+		})).Run(func(args mock.Arguments) {
+			events = append(events, args.Get(0).(webhookEntity.Change).Event)
+		}).Return()
+
+		err := service.UpdateFeatureFlagById(5, featureFlagEntity.UpdateFeatureFlag{
+			Description: "Description",
+			IsActive:    true,
+			IsGlobal:    true,
+			Environment: "staging",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			webhookEntity.EventFeatureFlagUpdated,
+			webhookEntity.EventFeatureFlagStatusToggled,
+			webhookEntity.EventFeatureFlagGlobalToggled,
+		}, events)
+	})
+
+	t.Run("Failed update is not notified", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockNotifier := new(MockNotifier)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Notifier = mockNotifier
+
+		mockRepo.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{{ID: 5}}, 1, nil)
+		mockRepo.On("ReplaceRules", uint(5), mock.Anything).Return(errors.New("error when replacing rules"))
+
+		err := service.UpdateFeatureFlagRules(5, featureFlagEntity.UpdateFeatureFlagRules{})
+
+		assert.Error(t, err)
+		mockNotifier.AssertNotCalled(t, "NotifyChange", mock.Anything)
+	})
+}
+
+// Record Feature Flag Revisions Tests Cases
+func TestRecordFeatureFlagRevisions(t *testing.T) {
+	filtersMock := mock.AnythingOfType("model.FeatureFlagFilters")
+	paginationMock := mock.AnythingOfType("model.Pagination")
+
+	t.Run("Created feature flag is recorded with its creator", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRecorder := new(MockRecorder)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Recorder = mockRecorder
+
+		mockRepo.On("GetFeatureFlagByName", uint(1), "TEST_FLAG_V1", "").Return(model.FeatureFlag{}, nil).Once()
+		mockRepo.On("AddFeatureFlag", mock.AnythingOfType("model.FeatureFlag")).Return(nil)
+		mockRepo.On("GetFeatureFlagByName", uint(1), "TEST_FLAG_V1", "").Return(model.FeatureFlag{ID: 5, Name: "TEST_FLAG_V1"}, nil)
+		mockRecorder.On("RecordRevision", webhookEntity.EventFeatureFlagCreated, uint(5), "staging", uint(3)).Return()
+
+		err := service.CreateFeatureFlag(featureFlagEntity.FeatureFlag{
+			Name:        "TEST_FLAG_V1",
+			Description: "Test Description",
+			ProjectID:   1,
+			Environment: "staging",
+		}, 3)
+
+		assert.NoError(t, err)
+		mockRecorder.AssertExpectations(t)
+	})
+
+	testCases := []struct {
+		name          string
+		request       featureFlagEntity.UpdateFeatureFlag
+		expectedEvent string
+	}{
+		{
+			name:          "Status change alone is recorded as a status toggle",
+			request:       featureFlagEntity.UpdateFeatureFlag{Description: "Description", IsActive: true, PersonID: 3},
+			expectedEvent: webhookEntity.EventFeatureFlagStatusToggled,
+		},
+		{
+			name:          "Global change alone is recorded as a global toggle",
+			request:       featureFlagEntity.UpdateFeatureFlag{Description: "Description", IsGlobal: true, PersonID: 3},
+			expectedEvent: webhookEntity.EventFeatureFlagGlobalToggled,
+		},
+		{
+			name:          "Other changes are recorded as an update",
+			request:       featureFlagEntity.UpdateFeatureFlag{Description: "Description", IsActive: true, RolloutPercentage: 50, PersonID: 3},
+			expectedEvent: webhookEntity.EventFeatureFlagUpdated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			mockRecorder := new(MockRecorder)
+			logger := zerolog.New(os.Stdout)
+			service := LoadService(mockRepo, &logger)
+			service.Recorder = mockRecorder
+
+			mockRepo.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{{ID: 5, Description: "Description"}}, 1, nil)
+			mockRepo.On("UpdateFeatureFlagById", uint(5), mock.AnythingOfType("model.UpdateFeatureFlag")).Return(nil)
+			mockRecorder.On("RecordRevision", tc.expectedEvent, uint(5), "", uint(3)).Return()
+
+			err := service.UpdateFeatureFlagById(5, tc.request)
+
+			assert.NoError(t, err)
+			mockRecorder.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Archive and restore are recorded with the person", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRecorder := new(MockRecorder)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Recorder = mockRecorder
+
+		archivedAt := time.Now()
+		mockRepo.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{{ID: 5}}, 1, nil).Once()
+		mockRepo.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{{ID: 5, ArchivedAt: &archivedAt}}, 1, nil).Once()
+		mockRepo.On("ArchiveFeatureFlag", uint(5), mock.Anything).Return(nil)
+		mockRecorder.On("RecordRevision", webhookEntity.EventFeatureFlagArchived, uint(5), "", uint(3)).Return()
+		mockRecorder.On("RecordRevision", webhookEntity.EventFeatureFlagRestored, uint(5), "", uint(3)).Return()
+
+		assert.NoError(t, service.ArchiveFeatureFlag(5, featureFlagEntity.ArchiveFeatureFlag{ProjectID: 1, PersonID: 3, Force: true}))
+		assert.NoError(t, service.RestoreFeatureFlag(5, 1, 3))
+		mockRecorder.AssertExpectations(t)
+	})
+
+	t.Run("Failed update is not recorded", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRecorder := new(MockRecorder)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Recorder = mockRecorder
+
+		mockRepo.On("GetFeatureFlag", filtersMock, paginationMock).Return([]model.FeatureFlag{{ID: 5}}, 1, nil)
+		mockRepo.On("ReplaceRules", uint(5), mock.Anything).Return(errors.New("error when replacing rules"))
+
+		err := service.UpdateFeatureFlagRules(5, featureFlagEntity.UpdateFeatureFlagRules{PersonID: 3})
+
+		assert.Error(t, err)
+		mockRecorder.AssertNotCalled(t, "RecordRevision", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// Rollback Feature Flag Tests Cases
+func TestRollbackFeatureFlag(t *testing.T) {
+	onePage := model.Pagination{Limit: 1, Page: 1}
+	current := model.FeatureFlag{
+		ID:          1,
+		Name:        "BUTTON_COLOR",
+		Description: "Now",
+		IsActive:    true,
+		Type:        featureFlagEntity.TypeString,
+		ProjectID:   1,
+		Version:     4,
+		Variants:    []model.Variant{{Name: "red", Value: "#f00"}},
+	}
+	target := featureFlagEntity.FeatureFlagResponse{
+		Description:    "Then",
+		IsActive:       true,
+		IsGlobal:       true,
+		Type:           featureFlagEntity.TypeString,
+		DefaultVariant: "green",
+		Variants:       []featureFlagEntity.Variant{{Name: "green", Value: "#0f0", Weight: 100}},
+	}
+
+	t.Run("Default environment rolls back the shared parts in one write", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockPublisher := new(MockPublisher)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Publisher = mockPublisher
+
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 1, ProjectID: 1, Environment: model.DefaultEnvironment}, onePage).Return([]model.FeatureFlag{current}, 1, nil)
+		mockRepo.On("RollbackFeatureFlag", uint(1), mock.MatchedBy(func(rollback model.RollbackFeatureFlag) bool {
+			return rollback.Shared && rollback.Update.Version == 4 && rollback.Update.Description == "Then" && rollback.Update.IsGlobal &&
+				rollback.DefaultVariant == "green" && len(rollback.Variants) == 1 &&
+				len(rollback.Assignments) == 1 && rollback.Assignments[0].Variant == "green" && rollback.Assignments[0].Kind == model.AssignmentInclude
+		})).Return(nil).Once()
+		mockPublisher.On("PublishFeatureFlagChange", streamEntity.EventFeatureFlagUpdated, uint(1), "").Return().Once()
+
+		err := service.RollbackFeatureFlag(1, featureFlagEntity.RollbackFeatureFlag{
+			FeatureFlag: target,
+			Assignments: []assignmentEntity.Assignment{{PersonID: 2, Variant: "green"}},
+			ProjectID:   1,
+			Version:     4,
+		})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("Other environment only rolls back its status and assignments", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockPublisher := new(MockPublisher)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Publisher = mockPublisher
+
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 1, ProjectID: 1, Environment: "staging"}, onePage).Return([]model.FeatureFlag{current}, 1, nil)
+		mockRepo.On("RollbackFeatureFlag", uint(1), mock.MatchedBy(func(rollback model.RollbackFeatureFlag) bool {
+			return !rollback.Shared && rollback.Update.Environment == "staging" && rollback.Update.IsGlobal &&
+				rollback.Update.Description == "" && rollback.Variants == nil && len(rollback.Assignments) == 1
+		})).Return(nil)
+		mockPublisher.On("PublishFeatureFlagChange", streamEntity.EventFeatureFlagUpdated, uint(1), "staging").Return()
+
+		err := service.RollbackFeatureFlag(1, featureFlagEntity.RollbackFeatureFlag{
+			FeatureFlag: target,
+			Assignments: []assignmentEntity.Assignment{{PersonID: 2, Variant: "red"}},
+			Environment: "staging",
+			ProjectID:   1,
+			Version:     4,
+		})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("Assignment pinning a variant the flag does not have", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", mock.Anything, onePage).Return([]model.FeatureFlag{current}, 1, nil)
+
+		err := service.RollbackFeatureFlag(1, featureFlagEntity.RollbackFeatureFlag{
+			FeatureFlag: target,
+			Assignments: []assignmentEntity.Assignment{{PersonID: 2, Variant: "green"}},
+			Environment: "staging",
+			ProjectID:   1,
+			Version:     4,
+		})
+
+		assert.EqualError(t, err, "variant green is not a variant of the feature flag 1")
+		mockRepo.AssertNotCalled(t, "RollbackFeatureFlag", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Flag changed since the rollback read it", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", mock.Anything, onePage).Return([]model.FeatureFlag{current}, 1, nil)
+
+		err := service.RollbackFeatureFlag(1, featureFlagEntity.RollbackFeatureFlag{FeatureFlag: target, ProjectID: 1, Version: 3})
+
+		assert.EqualError(t, err, "feature flag was changed by someone else")
+		mockRepo.AssertNotCalled(t, "RollbackFeatureFlag", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Deactivating a prerequisite has to be forced", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		inactive := target
+		inactive.IsActive = false
+		mockRepo.On("GetFeatureFlag", mock.Anything, onePage).Return([]model.FeatureFlag{current}, 1, nil)
+		mockRepo.On("GetDependentFeatureFlags", uint(1)).Return([]model.FeatureFlag{{ID: 2, Name: "CHECKOUT"}}, nil)
+
+		err := service.RollbackFeatureFlag(1, featureFlagEntity.RollbackFeatureFlag{FeatureFlag: inactive, ProjectID: 1, Version: 4})
+
+		assert.EqualError(t, err, "feature flag is a prerequisite of CHECKOUT, force the update to deactivate it")
+		mockRepo.AssertNotCalled(t, "RollbackFeatureFlag", mock.Anything, mock.Anything)
+	})
+}
+
+// Archive Feature Flag Tests Cases
+func TestArchiveFeatureFlag(t *testing.T) {
+	filters := model.FeatureFlagFilters{ID: 1, ProjectID: 1}
+	onePage := model.Pagination{Limit: 1, Page: 1}
+	archivedAt := time.Now()
+
+	t.Run("Successfully archive feature flag", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockPublisher := new(MockPublisher)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Publisher = mockPublisher
+
+		mockRepo.On("GetFeatureFlag", filters, onePage).Return([]model.FeatureFlag{{ID: 1, Name: "NEW_CHECKOUT", IsActive: true}}, 1, nil)
+		mockRepo.On("GetDependentFeatureFlags", uint(1)).Return([]model.FeatureFlag{{ID: 2, Name: "OLD_CHECKOUT", ArchivedAt: &archivedAt}}, nil)
+		mockRepo.On("ArchiveFeatureFlag", uint(1), mock.MatchedBy(func(date *time.Time) bool { return date != nil })).Return(nil)
+		mockPublisher.On("PublishFeatureFlagChange", streamEntity.EventFeatureFlagArchived, uint(1), "").Return()
+
+		err := service.ArchiveFeatureFlag(1, featureFlagEntity.ArchiveFeatureFlag{ProjectID: 1})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("Archiving a prerequisite is blocked", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", filters, onePage).Return([]model.FeatureFlag{{ID: 1, Name: "NEW_CHECKOUT"}}, 1, nil)
+		mockRepo.On("GetDependentFeatureFlags", uint(1)).Return([]model.FeatureFlag{{ID: 2, Name: "NEW_CHECKOUT_PIX"}}, nil)
+
+		err := service.ArchiveFeatureFlag(1, featureFlagEntity.ArchiveFeatureFlag{ProjectID: 1})
+
+		assert.EqualError(t, err, "feature flag is a prerequisite of NEW_CHECKOUT_PIX, force the archive to turn them off")
+		mockRepo.AssertNotCalled(t, "ArchiveFeatureFlag", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Forced archive of a prerequisite", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", filters, onePage).Return([]model.FeatureFlag{{ID: 1, Name: "NEW_CHECKOUT"}}, 1, nil)
+		mockRepo.On("ArchiveFeatureFlag", uint(1), mock.AnythingOfType("*time.Time")).Return(nil)
+
+		err := service.ArchiveFeatureFlag(1, featureFlagEntity.ArchiveFeatureFlag{ProjectID: 1, Force: true})
+
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "GetDependentFeatureFlags", mock.Anything)
+	})
+
+	t.Run("Feature flag already archived", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", filters, onePage).Return([]model.FeatureFlag{{ID: 1, ArchivedAt: &archivedAt}}, 1, nil)
+
+		err := service.ArchiveFeatureFlag(1, featureFlagEntity.ArchiveFeatureFlag{ProjectID: 1})
+
+		assert.EqualError(t, err, "feature flag is already archived")
+	})
+
+	t.Run("Feature flag not found", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", filters, onePage).Return([]model.FeatureFlag{}, 0, nil)
+
+		err := service.ArchiveFeatureFlag(1, featureFlagEntity.ArchiveFeatureFlag{ProjectID: 1})
+
+		assert.EqualError(t, err, "feature flag not found")
+	})
+
+	t.Run("Archived feature flag can not be updated", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", mock.AnythingOfType("model.FeatureFlagFilters"), onePage).Return([]model.FeatureFlag{{ID: 1, ArchivedAt: &archivedAt}}, 1, nil)
+
+		err := service.UpdateFeatureFlagById(1, featureFlagEntity.UpdateFeatureFlag{Description: "Description", IsActive: true})
+
+		assert.EqualError(t, err, "feature flag is archived")
+		mockRepo.AssertNotCalled(t, "UpdateFeatureFlagById", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Successfully restore feature flag", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockPublisher := new(MockPublisher)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Publisher = mockPublisher
+
+		mockRepo.On("GetFeatureFlag", filters, onePage).Return([]model.FeatureFlag{{ID: 1, ArchivedAt: &archivedAt}}, 1, nil)
+		mockRepo.On("ArchiveFeatureFlag", uint(1), (*time.Time)(nil)).Return(nil)
+		mockPublisher.On("PublishFeatureFlagChange", streamEntity.EventFeatureFlagRestored, uint(1), "").Return()
+
+		err := service.RestoreFeatureFlag(1, 1, 7)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("Feature flag not archived can not be restored", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", filters, onePage).Return([]model.FeatureFlag{{ID: 1}}, 1, nil)
+
+		err := service.RestoreFeatureFlag(1, 1, 7)
+
+		assert.EqualError(t, err, "feature flag is not archived")
+		mockRepo.AssertNotCalled(t, "ArchiveFeatureFlag", mock.Anything, mock.Anything)
+	})
+}
+
+// Delete Feature Flag Tests Cases
+func TestDeleteFeatureFlag(t *testing.T) {
+	filters := model.FeatureFlagFilters{ID: 1, ProjectID: 1}
+	onePage := model.Pagination{Limit: 1, Page: 1}
+	archivedAt := time.Now()
+
+	t.Run("Successfully delete feature flag", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockPublisher := new(MockPublisher)
+		mockNotifier := new(MockNotifier)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Publisher = mockPublisher
+		service.Notifier = mockNotifier
+
+		mockRepo.On("GetFeatureFlag", filters, onePage).Return([]model.FeatureFlag{{ID: 1, Name: "NEW_CHECKOUT", ProjectID: 1, ArchivedAt: &archivedAt}}, 1, nil)
+		mockRepo.On("DeleteFeatureFlag", uint(1)).Return(nil)
+		mockPublisher.On("PublishFeatureFlagDeletion", uint(1), uint(1), "NEW_CHECKOUT").Return()
+		mockNotifier.On("NotifyChange", mock.MatchedBy(func(change webhookEntity.Change) bool {
+			before, ok := change.Before.(featureFlagEntity.FeatureFlagResponse)
+			return change.Event == webhookEntity.EventFeatureFlagDeleted && change.After == nil &&
+				ok && before.Name == "NEW_CHECKOUT" && before.ArchivedAt != ""
+		})).Return()
+
+		err := service.DeleteFeatureFlag(1, 1, 7)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Deleted feature flag is recorded before it is gone", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRecorder := new(MockRecorder)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Recorder = mockRecorder
+
+		mockRepo.On("GetFeatureFlag", filters, onePage).Return([]model.FeatureFlag{{ID: 1, Name: "NEW_CHECKOUT", ProjectID: 1, ArchivedAt: &archivedAt}}, 1, nil)
+		mockRecorder.On("RecordRevision", webhookEntity.EventFeatureFlagDeleted, uint(1), "", uint(7)).Return()
+		mockRepo.On("DeleteFeatureFlag", uint(1)).Return(nil).Run(func(mock.Arguments) {
+			mockRecorder.AssertExpectations(t)
+		})
+
+		err := service.DeleteFeatureFlag(1, 1, 7)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Feature flag must be archived first", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetFeatureFlag", filters, onePage).Return([]model.FeatureFlag{{ID: 1}}, 1, nil)
+
+		err := service.DeleteFeatureFlag(1, 1, 7)
+
+		assert.EqualError(t, err, "feature flag must be archived before it is deleted")
+		mockRepo.AssertNotCalled(t, "DeleteFeatureFlag", mock.Anything)
+	})
+
+	t.Run("Failed deletion is not published", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockPublisher := new(MockPublisher)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+		service.Publisher = mockPublisher
+
+		mockRepo.On("GetFeatureFlag", filters, onePage).Return([]model.FeatureFlag{{ID: 1, ArchivedAt: &archivedAt}}, 1, nil)
+		mockRepo.On("DeleteFeatureFlag", uint(1)).Return(errors.New("error when deleting feature flag"))
+
+		err := service.DeleteFeatureFlag(1, 1, 7)
+
+		assert.Error(t, err)
+		mockPublisher.AssertNotCalled(t, "PublishFeatureFlagDeletion", mock.Anything, mock.Anything, mock.Anything)
+	})
 }
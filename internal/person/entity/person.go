@@ -7,21 +7,27 @@ type PersonResponse struct {
 }
 
 type PersonWithAssignmentResponse struct {
-	ID         string `json:"id"`
-	Name       string `json:"name"`
-	Email      string `json:"email"`
-	IsAssigned bool   `json:"isAssigned"`
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Email      string   `json:"email"`
+	IsAssigned bool     `json:"isAssigned"`
+	IsExcluded bool     `json:"isExcluded"`
+	Segments   []string `json:"segments,omitempty"`
 }
 
 type AssignedFeatureFlagResponse struct {
-	ID         uint   `json:"id"`
-	Name       string `json:"name"`
-	IsActive   bool   `json:"isActive"`
-	IsAssigned bool   `json:"isAssigned"`
+	ID           uint        `json:"id"`
+	Name         string      `json:"name"`
+	IsActive     bool        `json:"isActive"`
+	IsAssigned   bool        `json:"isAssigned"`
+	ViaSegment   bool        `json:"viaSegment,omitempty"`
+	Variant      string      `json:"variant,omitempty"`
+	VariantValue interface{} `json:"variantValue,omitempty"`
 }
 
 type PersonFilters struct {
 	FeatureFlagID uint   `json:"id"`
 	Name          string `json:"name"`
 	IsAssigned    *bool  `json:"isAssigned"`
+	Environment   string `json:"-"`
 }
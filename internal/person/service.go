@@ -2,15 +2,18 @@ package person
 
 import (
 	"ff/internal/db/model"
+	ff_entity "ff/internal/feature_flag/entity"
 	p_entity "ff/internal/person/entity"
+	"ff/internal/rollout"
 	"strconv"
+	"time"
 
 	"github.com/rs/zerolog"
 )
 
 type PersonRepository interface {
 	GetPeopleAssignmentByFeatureFlag(pagination model.Pagination, filters p_entity.PersonFilters) ([]model.PersonWithAssignment, int64, error)
-	GetAssignedFeatureFlagsByPersonId(id uint) ([]model.AssignedFeatureFlag, error)
+	GetAssignedFeatureFlagsByPersonId(id uint, projectId uint, environment string) ([]model.AssignedFeatureFlag, error)
 }
 
 type PeopleService struct {
@@ -40,29 +43,49 @@ func (ps *PeopleService) GetPeopleAssignmentByFeatureFlag(pagination model.Pagin
 			Name:       pDB.Name,
 			Email:      pDB.Email,
 			IsAssigned: pDB.IsGlobal,
+			IsExcluded: pDB.IsExcluded,
+			Segments:   pDB.Segments,
 		})
 	}
 
 	return personResponses, totalCount, nil
 }
 
-func (ps *PeopleService) GetAssignedFeatureFlagsByPersonId(id uint) ([]p_entity.AssignedFeatureFlagResponse, error) {
+func (ps *PeopleService) GetAssignedFeatureFlagsByPersonId(id uint, projectId uint, environment string) ([]p_entity.AssignedFeatureFlagResponse, error) {
 	ps.Logger.Info().Msg("Getting assigned feature flags by person id")
 
-	featureFlags, err := ps.Repository.GetAssignedFeatureFlagsByPersonId(id)
+	featureFlags, err := ps.Repository.GetAssignedFeatureFlagsByPersonId(id, projectId, environment)
 	if err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
+
 	var featureFlagResponses []p_entity.AssignedFeatureFlagResponse
 	for _, ffDB := range featureFlags {
-		if ffDB.IsAssigned || ffDB.IsGlobal {
-			featureFlagResponses = append(featureFlagResponses, p_entity.AssignedFeatureFlagResponse{
+		// an expired flag is reported as inactive even before the expiry worker runs
+		isActive := ffDB.IsActive && !ff_entity.IsExpired(ffDB.ExpirationDate, now)
+		// an excluded person is off whatever the flag rules are
+		if ffDB.IsExcluded {
+			continue
+		}
+
+		isInRollout := rollout.IsInRollout(ffDB.Name, id, ffDB.RolloutPercentage)
+		if ffDB.IsAssigned || ffDB.IsAssignedViaSegment || ffDB.IsGlobal || isInRollout {
+			response := p_entity.AssignedFeatureFlagResponse{
 				ID:         ffDB.ID,
 				Name:       ffDB.Name,
-				IsActive:   ffDB.IsActive,
-				IsAssigned: ffDB.IsGlobal || ffDB.IsAssigned,
-			})
+				IsActive:   isActive,
+				IsAssigned: ffDB.IsGlobal || ffDB.IsAssigned || ffDB.IsAssignedViaSegment || isInRollout,
+				ViaSegment: !ffDB.IsAssigned && ffDB.IsAssignedViaSegment,
+			}
+
+			if variant, ok := rollout.ServedVariant(ffDB.Name, ffDB.DefaultVariant, ffDB.Variants, id, ffDB.AssignedVariant, isActive); ok {
+				response.Variant = variant.Name
+				response.VariantValue = ff_entity.DecodeVariantValue(ffDB.Type, variant.Value)
+			}
+
+			featureFlagResponses = append(featureFlagResponses, response)
 		}
 
 	}
@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"errors"
+	personEntity "ff/internal/person/entity"
+	"regexp"
+)
+
+// roles of a project member, each one includes the permissions of the previous one
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+var roleLevels = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// RoleAllows reports whether the role has at least the permissions of the required role
+func RoleAllows(role string, required string) bool {
+	return roleLevels[role] >= roleLevels[required]
+}
+
+var keyRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type Project struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (p *Project) Validate() error {
+	if p.Key == "" {
+		return errors.New("Key|Key is required")
+	}
+
+	if len(p.Key) > 64 || !keyRegex.MatchString(p.Key) {
+		return errors.New("Key|Key must be lowercase and contain only letters, numbers and hyphens (at most 64 characters)")
+	}
+
+	if p.Name == "" {
+		return errors.New("Name|Name is required")
+	}
+
+	return nil
+}
+
+type ProjectMember struct {
+	PersonID uint   `json:"personId"`
+	Role     string `json:"role"`
+}
+
+func (pm *ProjectMember) Validate() error {
+	if pm.PersonID == 0 {
+		return errors.New("PersonID|Person id is required")
+	}
+
+	if _, ok := roleLevels[pm.Role]; !ok {
+		return errors.New("Role|Role must be viewer, editor or admin")
+	}
+
+	return nil
+}
+
+type ApiKey struct {
+	Name string `json:"name"`
+}
+
+func (ak *ApiKey) Validate() error {
+	if ak.Name == "" {
+		return errors.New("Name|Name is required")
+	}
+
+	return nil
+}
+
+type ProjectResponse struct {
+	ID          uint   `json:"id"`
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"createdAt"`
+	UpdatedAt   string `json:"updatedAt"`
+}
+
+type ProjectMemberResponse struct {
+	Person personEntity.PersonResponse `json:"person"`
+	Role   string                      `json:"role"`
+}
+
+type ApiKeyResponse struct {
+	ID     uint   `json:"id"`
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+	// Key is only returned once, when the key is created
+	Key        string `json:"key,omitempty"`
+	LastUsedAt string `json:"lastUsedAt,omitempty"`
+	RevokedAt  string `json:"revokedAt,omitempty"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+type ProjectFilters struct {
+	ID  uint   `json:"id"`
+	Key string `json:"key"`
+}
@@ -0,0 +1,298 @@
+package project
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"ff/internal/db/model"
+	personEntity "ff/internal/person/entity"
+	projectEntity "ff/internal/project/entity"
+
+	"github.com/rs/zerolog"
+)
+
+// ApiKeyPrefix starts every generated key, so a leaked key is easy to recognize
+const ApiKeyPrefix = "ffk_"
+
+type ProjectRepository interface {
+	AddProject(project model.Project) error
+	GetProjects(filters model.ProjectFilters, pagination model.Pagination) ([]model.Project, int64, error)
+	GetProjectMembers(projectId uint) ([]model.ProjectMember, error)
+	SetProjectMember(member model.ProjectMember) error
+	RemoveProjectMember(projectId uint, personId uint) error
+	AddApiKey(apiKey model.ApiKey) error
+	GetApiKeys(projectId uint) ([]model.ApiKey, error)
+	GetApiKeyByHash(keyHash string) (model.ApiKey, error)
+	RevokeApiKey(projectId uint, id uint, revokedAt time.Time) error
+	TouchApiKey(id uint, usedAt time.Time) error
+}
+
+type ProjectService struct {
+	Repository ProjectRepository
+	Logger     *zerolog.Logger
+}
+
+func LoadService(r ProjectRepository, l *zerolog.Logger) *ProjectService {
+	return &ProjectService{
+		Logger:     l,
+		Repository: r,
+	}
+}
+
+func (ps *ProjectService) CreateProject(request projectEntity.Project, personId uint) error {
+	ps.Logger.Info().Msg("Creating a new Project")
+
+	if err := request.Validate(); err != nil {
+		return errors.New(err.Error())
+	}
+
+	_, totalCount, err := ps.Repository.GetProjects(model.ProjectFilters{
+		Key: request.Key,
+	}, model.Pagination{
+		Limit: 1,
+		Page:  1,
+	})
+	if err != nil {
+		return err
+	}
+
+	if totalCount > 0 {
+		return errors.New("project already exists")
+	}
+
+	if err := ps.Repository.AddProject(model.Project{
+		Key:         request.Key,
+		Name:        request.Name,
+		Description: request.Description,
+	}); err != nil {
+		return err
+	}
+
+	project, err := ps.GetProjectByKey(request.Key)
+	if err != nil {
+		return err
+	}
+
+	// whoever creates the project is its first admin
+	return ps.Repository.SetProjectMember(model.ProjectMember{
+		ProjectID: project.ID,
+		PersonID:  personId,
+		Role:      projectEntity.RoleAdmin,
+	})
+}
+
+func (ps *ProjectService) GetProjects(pagination model.Pagination, filters projectEntity.ProjectFilters) ([]projectEntity.ProjectResponse, int64, error) {
+	ps.Logger.Info().Msg("Getting Projects")
+
+	projects, totalCount, err := ps.Repository.GetProjects(model.ProjectFilters{
+		ID:  filters.ID,
+		Key: filters.Key,
+	}, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var projectResponses []projectEntity.ProjectResponse
+	for _, projectDB := range projects {
+		projectResponses = append(projectResponses, projectFromModel(projectDB))
+	}
+
+	return projectResponses, totalCount, nil
+}
+
+func (ps *ProjectService) GetProjectByKey(key string) (projectEntity.ProjectResponse, error) {
+	projects, _, err := ps.Repository.GetProjects(model.ProjectFilters{
+		Key: key,
+	}, model.Pagination{
+		Limit: 1,
+		Page:  1,
+	})
+	if err != nil {
+		return projectEntity.ProjectResponse{}, err
+	}
+
+	if len(projects) == 0 {
+		return projectEntity.ProjectResponse{}, errors.New("project not found")
+	}
+
+	return projectFromModel(projects[0]), nil
+}
+
+func (ps *ProjectService) GetProjectMembers(projectId uint) ([]projectEntity.ProjectMemberResponse, error) {
+	ps.Logger.Info().Msg("Getting Project members")
+
+	members, err := ps.Repository.GetProjectMembers(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	var memberResponses []projectEntity.ProjectMemberResponse
+	for _, member := range members {
+		response := projectEntity.ProjectMemberResponse{
+			Role: member.Role,
+		}
+
+		if member.Person != nil {
+			response.Person = personEntity.PersonResponse{
+				ID:    member.Person.ID,
+				Name:  member.Person.Name,
+				Email: member.Person.Email,
+			}
+		}
+
+		memberResponses = append(memberResponses, response)
+	}
+
+	return memberResponses, nil
+}
+
+func (ps *ProjectService) SetProjectMember(projectId uint, request projectEntity.ProjectMember) error {
+	ps.Logger.Info().Msg("Setting a Project member")
+
+	if err := request.Validate(); err != nil {
+		return errors.New(err.Error())
+	}
+
+	return ps.Repository.SetProjectMember(model.ProjectMember{
+		ProjectID: projectId,
+		PersonID:  request.PersonID,
+		Role:      request.Role,
+	})
+}
+
+func (ps *ProjectService) RemoveProjectMember(projectId uint, personId uint) error {
+	ps.Logger.Info().Msg("Removing a Project member")
+
+	return ps.Repository.RemoveProjectMember(projectId, personId)
+}
+
+// CheckPermission allows the person when the project has no members (it is open)
+// or when the person is a member with at least the required role
+func (ps *ProjectService) CheckPermission(projectId uint, personId uint, role string) error {
+	members, err := ps.Repository.GetProjectMembers(projectId)
+	if err != nil {
+		return err
+	}
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	for _, member := range members {
+		if member.PersonID == personId && personId != 0 {
+			if projectEntity.RoleAllows(member.Role, role) {
+				return nil
+			}
+			break
+		}
+	}
+
+	return errors.New("you are not allowed to perform this action in the project")
+}
+
+// CreateApiKey returns the generated key, it can not be read again afterwards
+func (ps *ProjectService) CreateApiKey(projectId uint, request projectEntity.ApiKey) (projectEntity.ApiKeyResponse, error) {
+	ps.Logger.Info().Msg("Creating a new API key")
+
+	if err := request.Validate(); err != nil {
+		return projectEntity.ApiKeyResponse{}, errors.New(err.Error())
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return projectEntity.ApiKeyResponse{}, err
+	}
+
+	key := ApiKeyPrefix + hex.EncodeToString(secret)
+	apiKey := model.ApiKey{
+		ProjectID: projectId,
+		Name:      request.Name,
+		Prefix:    key[:len(ApiKeyPrefix)+8],
+		KeyHash:   hashApiKey(key),
+	}
+
+	if err := ps.Repository.AddApiKey(apiKey); err != nil {
+		return projectEntity.ApiKeyResponse{}, err
+	}
+
+	return projectEntity.ApiKeyResponse{
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		Key:       key,
+		CreatedAt: time.Now().Format("2006-01-02 15:04:05"),
+	}, nil
+}
+
+func (ps *ProjectService) GetApiKeys(projectId uint) ([]projectEntity.ApiKeyResponse, error) {
+	ps.Logger.Info().Msg("Getting API keys")
+
+	apiKeys, err := ps.Repository.GetApiKeys(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiKeyResponses []projectEntity.ApiKeyResponse
+	for _, apiKey := range apiKeys {
+		response := projectEntity.ApiKeyResponse{
+			ID:        apiKey.ID,
+			Name:      apiKey.Name,
+			Prefix:    apiKey.Prefix,
+			CreatedAt: apiKey.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+
+		if apiKey.LastUsedAt != nil {
+			response.LastUsedAt = apiKey.LastUsedAt.Format("2006-01-02 15:04:05")
+		}
+
+		if apiKey.RevokedAt != nil {
+			response.RevokedAt = apiKey.RevokedAt.Format("2006-01-02 15:04:05")
+		}
+
+		apiKeyResponses = append(apiKeyResponses, response)
+	}
+
+	return apiKeyResponses, nil
+}
+
+func (ps *ProjectService) RevokeApiKey(projectId uint, id uint) error {
+	ps.Logger.Info().Msg("Revoking an API key")
+
+	return ps.Repository.RevokeApiKey(projectId, id, time.Now())
+}
+
+// AuthenticateApiKey returns the project the key belongs to
+func (ps *ProjectService) AuthenticateApiKey(key string) (projectEntity.ProjectResponse, error) {
+	apiKey, err := ps.Repository.GetApiKeyByHash(hashApiKey(key))
+	if err != nil {
+		return projectEntity.ProjectResponse{}, err
+	}
+
+	if apiKey.ID == 0 || apiKey.RevokedAt != nil || apiKey.Project == nil {
+		return projectEntity.ProjectResponse{}, errors.New("invalid api key")
+	}
+
+	if err := ps.Repository.TouchApiKey(apiKey.ID, time.Now()); err != nil {
+		ps.Logger.Error().Err(err).Uint("id", apiKey.ID).Msg("Error when updating the api key usage")
+	}
+
+	return projectFromModel(*apiKey.Project), nil
+}
+
+func hashApiKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+func projectFromModel(projectDB model.Project) projectEntity.ProjectResponse {
+	return projectEntity.ProjectResponse{
+		ID:          projectDB.ID,
+		Key:         projectDB.Key,
+		Name:        projectDB.Name,
+		Description: projectDB.Description,
+		CreatedAt:   projectDB.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:   projectDB.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
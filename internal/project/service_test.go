@@ -0,0 +1,227 @@
+package project
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"ff/internal/db/model"
+	projectEntity "ff/internal/project/entity"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock of ProjectRepository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) AddProject(project model.Project) error {
+	args := m.Called(project)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetProjects(filters model.ProjectFilters, pagination model.Pagination) ([]model.Project, int64, error) {
+	args := m.Called(filters, pagination)
+	return args.Get(0).([]model.Project), int64(args.Get(1).(int)), args.Error(2)
+}
+
+func (m *MockRepository) GetProjectMembers(projectId uint) ([]model.ProjectMember, error) {
+	args := m.Called(projectId)
+	return args.Get(0).([]model.ProjectMember), args.Error(1)
+}
+
+func (m *MockRepository) SetProjectMember(member model.ProjectMember) error {
+	args := m.Called(member)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RemoveProjectMember(projectId uint, personId uint) error {
+	args := m.Called(projectId, personId)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AddApiKey(apiKey model.ApiKey) error {
+	args := m.Called(apiKey)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetApiKeys(projectId uint) ([]model.ApiKey, error) {
+	args := m.Called(projectId)
+	return args.Get(0).([]model.ApiKey), args.Error(1)
+}
+
+func (m *MockRepository) GetApiKeyByHash(keyHash string) (model.ApiKey, error) {
+	args := m.Called(keyHash)
+	return args.Get(0).(model.ApiKey), args.Error(1)
+}
+
+func (m *MockRepository) RevokeApiKey(projectId uint, id uint, revokedAt time.Time) error {
+	args := m.Called(projectId, id, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockRepository) TouchApiKey(id uint, usedAt time.Time) error {
+	args := m.Called(id, usedAt)
+	return args.Error(0)
+}
+
+var onePage = model.Pagination{Limit: 1, Page: 1}
+
+// Create Project Tests Cases
+func TestCreateProject(t *testing.T) {
+	t.Run("Successfully create a project with its creator as admin", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetProjects", model.ProjectFilters{Key: "checkout"}, onePage).Return([]model.Project{}, 0, nil).Once()
+		mockRepo.On("AddProject", model.Project{Key: "checkout", Name: "Checkout"}).Return(nil)
+		mockRepo.On("GetProjects", model.ProjectFilters{Key: "checkout"}, onePage).Return([]model.Project{{ID: 2, Key: "checkout", Name: "Checkout"}}, 1, nil).Once()
+		mockRepo.On("SetProjectMember", model.ProjectMember{ProjectID: 2, PersonID: 1, Role: projectEntity.RoleAdmin}).Return(nil)
+
+		err := service.CreateProject(projectEntity.Project{Key: "checkout", Name: "Checkout"}, 1)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Duplicate project", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetProjects", model.ProjectFilters{Key: "checkout"}, onePage).Return([]model.Project{{ID: 2, Key: "checkout"}}, 1, nil)
+
+		err := service.CreateProject(projectEntity.Project{Key: "checkout", Name: "Checkout"}, 1)
+
+		assert.EqualError(t, err, "project already exists")
+		mockRepo.AssertNotCalled(t, "AddProject", mock.Anything)
+	})
+
+	t.Run("Invalid project key", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		err := service.CreateProject(projectEntity.Project{Key: "Checkout Team", Name: "Checkout"}, 1)
+
+		assert.Error(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "Key|"))
+		mockRepo.AssertNotCalled(t, "GetProjects", mock.Anything, mock.Anything)
+	})
+}
+
+// Check Permission Tests Cases
+func TestCheckPermission(t *testing.T) {
+	members := []model.ProjectMember{
+		{ProjectID: 2, PersonID: 1, Role: projectEntity.RoleAdmin},
+		{ProjectID: 2, PersonID: 2, Role: projectEntity.RoleViewer},
+	}
+
+	testCases := []struct {
+		name     string
+		members  []model.ProjectMember
+		personId uint
+		role     string
+		allowed  bool
+	}{
+		{name: "Project without members is open", members: []model.ProjectMember{}, personId: 0, role: projectEntity.RoleEditor, allowed: true},
+		{name: "Admin can edit", members: members, personId: 1, role: projectEntity.RoleEditor, allowed: true},
+		{name: "Viewer can read", members: members, personId: 2, role: projectEntity.RoleViewer, allowed: true},
+		{name: "Viewer can not edit", members: members, personId: 2, role: projectEntity.RoleEditor, allowed: false},
+		{name: "Non member can not read", members: members, personId: 3, role: projectEntity.RoleViewer, allowed: false},
+		{name: "Anonymous can not read", members: members, personId: 0, role: projectEntity.RoleViewer, allowed: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			logger := zerolog.New(os.Stdout)
+			service := LoadService(mockRepo, &logger)
+
+			mockRepo.On("GetProjectMembers", uint(2)).Return(tc.members, nil)
+
+			err := service.CheckPermission(2, tc.personId, tc.role)
+
+			if tc.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// API Key Tests Cases
+func TestApiKeys(t *testing.T) {
+	t.Run("Created key authenticates its project", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		var stored model.ApiKey
+		mockRepo.On("AddApiKey", mock.AnythingOfType("model.ApiKey")).Run(func(args mock.Arguments) {
+			stored = args.Get(0).(model.ApiKey)
+		}).Return(nil)
+
+		apiKey, err := service.CreateApiKey(2, projectEntity.ApiKey{Name: "backend"})
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(apiKey.Key, ApiKeyPrefix))
+		assert.True(t, strings.HasPrefix(apiKey.Key, apiKey.Prefix))
+		assert.NotEqual(t, apiKey.Key, stored.KeyHash)
+
+		stored.ID = 5
+		stored.Project = &model.Project{ID: 2, Key: "checkout"}
+		mockRepo.On("GetApiKeyByHash", stored.KeyHash).Return(stored, nil)
+		mockRepo.On("TouchApiKey", uint(5), mock.AnythingOfType("time.Time")).Return(nil)
+
+		project, err := service.AuthenticateApiKey(apiKey.Key)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "checkout", project.Key)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Revoked key is rejected", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		revokedAt := time.Now()
+		mockRepo.On("GetApiKeyByHash", mock.Anything).Return(model.ApiKey{ID: 5, RevokedAt: &revokedAt, Project: &model.Project{ID: 2}}, nil)
+
+		_, err := service.AuthenticateApiKey("ffk_revoked")
+
+		assert.EqualError(t, err, "invalid api key")
+		mockRepo.AssertNotCalled(t, "TouchApiKey", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown key is rejected", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetApiKeyByHash", mock.Anything).Return(model.ApiKey{}, nil)
+
+		_, err := service.AuthenticateApiKey("ffk_unknown")
+
+		assert.EqualError(t, err, "invalid api key")
+	})
+
+	t.Run("Repository error is returned", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		logger := zerolog.New(os.Stdout)
+		service := LoadService(mockRepo, &logger)
+
+		mockRepo.On("GetApiKeyByHash", mock.Anything).Return(model.ApiKey{}, errors.New("error when getting api key"))
+
+		_, err := service.AuthenticateApiKey("ffk_unknown")
+
+		assert.EqualError(t, err, "error when getting api key")
+	})
+}
@@ -0,0 +1,14 @@
+package entity
+
+// Status tells whether the relay has flags to serve and how fresh they are
+type Status struct {
+	Source       string `json:"source"`
+	Version      uint   `json:"version"`
+	ProjectID    uint   `json:"projectId"`
+	Environment  string `json:"environment"`
+	FeatureFlags int    `json:"featureFlags"`
+	// SyncedAt is the last successful sync, empty while none happened
+	SyncedAt string `json:"syncedAt,omitempty"`
+	// LastError is the error of the last sync, empty when it succeeded
+	LastError string `json:"lastError,omitempty"`
+}
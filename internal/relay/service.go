@@ -0,0 +1,206 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ff/internal/db/model"
+	ff_entity "ff/internal/feature_flag/entity"
+	person "ff/internal/person"
+	p_entity "ff/internal/person/entity"
+	relayEntity "ff/internal/relay/entity"
+	"ff/internal/snapshot"
+	snapshotEntity "ff/internal/snapshot/entity"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrNotSynced is returned while the relay has no snapshot to serve yet
+var ErrNotSynced = errors.New("relay has not synced a snapshot yet")
+
+// Source is where the relay syncs the snapshot of its project and environment from
+type Source interface {
+	Name() string
+	Snapshot(ctx context.Context) (snapshotEntity.Snapshot, error)
+}
+
+// RelayService keeps the last known snapshot in memory and answers the read-only routes
+// of the consumers from it, so they keep working while the upstream is down
+type RelayService struct {
+	Source Source
+	// ProjectKey is the key of the relayed project, the default project when empty
+	ProjectKey string
+	Logger     *zerolog.Logger
+
+	mu        sync.RWMutex
+	store     *snapshot.Store
+	people    *person.PeopleService
+	syncedAt  time.Time
+	lastError string
+}
+
+func LoadService(s Source, l *zerolog.Logger) *RelayService {
+	return &RelayService{
+		Logger: l,
+		Source: s,
+	}
+}
+
+// Sync replaces the snapshot served by the relay, the last known one is kept when the
+// source fails
+func (rs *RelayService) Sync(ctx context.Context) error {
+	flagSnapshot, err := rs.Source.Snapshot(ctx)
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if err != nil {
+		rs.lastError = err.Error()
+		return err
+	}
+
+	rs.syncedAt = time.Now()
+	rs.lastError = ""
+
+	if rs.store != nil && flagSnapshot.Version != 0 && rs.store.Snapshot().Version == flagSnapshot.Version {
+		return nil
+	}
+
+	rs.Logger.Info().Uint("version", flagSnapshot.Version).Int("featureFlags", len(flagSnapshot.FeatureFlags)).Msg("Relaying a new snapshot")
+
+	// the people lookups of the relay are answered from the snapshot, they are not logged
+	nop := zerolog.Nop()
+	rs.store = snapshot.NewStore(flagSnapshot)
+	rs.people = person.LoadService(&storeRepository{Store: rs.store}, &nop)
+
+	return nil
+}
+
+func (rs *RelayService) Status() relayEntity.Status {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	status := relayEntity.Status{
+		Source:    rs.Source.Name(),
+		LastError: rs.lastError,
+	}
+
+	if rs.store != nil {
+		flagSnapshot := rs.store.Snapshot()
+		status.Version = flagSnapshot.Version
+		status.ProjectID = flagSnapshot.ProjectID
+		status.Environment = flagSnapshot.Environment
+		status.FeatureFlags = len(flagSnapshot.FeatureFlags)
+		status.SyncedAt = rs.syncedAt.Format(time.RFC3339)
+	}
+
+	return status
+}
+
+// Serves reports whether the relay answers for the project and environment a consumer
+// asked for, an empty one stands for the relayed one
+func (rs *RelayService) Serves(project string, environment string) bool {
+	projectKey := rs.ProjectKey
+	if projectKey == "" {
+		projectKey = model.DefaultProject
+	}
+	if project != "" && project != projectKey {
+		return false
+	}
+
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+
+	if environment == "" || rs.store == nil {
+		return true
+	}
+
+	return environment == rs.store.Snapshot().Environment
+}
+
+// GetFeatureFlags filters the flags of the snapshot as the API does. The snapshot only
+// carries what evaluations need, the description, owner and dates are left empty
+func (rs *RelayService) GetFeatureFlags(pagination model.Pagination, filters ff_entity.FeatureFlagFilters) ([]ff_entity.FeatureFlagResponse, int64, error) {
+	rs.mu.RLock()
+	store := rs.store
+	rs.mu.RUnlock()
+
+	if store == nil {
+		return nil, 0, ErrNotSynced
+	}
+
+	flagSnapshot := store.Snapshot()
+
+	var featureFlags []ff_entity.FeatureFlagResponse
+	for _, featureFlag := range flagSnapshot.FeatureFlags {
+		if filters.ID != 0 && featureFlag.ID != filters.ID {
+			continue
+		}
+		if filters.Name != "" && !strings.Contains(strings.ToLower(featureFlag.Name), strings.ToLower(filters.Name)) {
+			continue
+		}
+		if filters.IsActive != nil && featureFlag.IsActive != *filters.IsActive {
+			continue
+		}
+		if filters.IsGlobal != nil && featureFlag.IsGlobal != *filters.IsGlobal {
+			continue
+		}
+
+		featureFlags = append(featureFlags, ff_entity.FeatureFlagResponse{
+			ID:                strconv.Itoa(int(featureFlag.ID)),
+			Name:              featureFlag.Name,
+			IsActive:          featureFlag.IsActive,
+			IsGlobal:          featureFlag.IsGlobal,
+			ExpirationDate:    featureFlag.ExpirationDate,
+			RolloutPercentage: featureFlag.RolloutPercentage,
+			Rules:             featureFlag.Rules,
+			Type:              featureFlag.Type,
+			DefaultVariant:    featureFlag.DefaultVariant,
+			Variants:          featureFlag.Variants,
+			Prerequisites:     featureFlag.Prerequisites,
+			Environment:       flagSnapshot.Environment,
+			ProjectID:         flagSnapshot.ProjectID,
+		})
+	}
+
+	totalCount := int64(len(featureFlags))
+
+	offset := (pagination.Page - 1) * pagination.Limit
+	if offset >= len(featureFlags) {
+		return []ff_entity.FeatureFlagResponse{}, totalCount, nil
+	}
+
+	end := offset + pagination.Limit
+	if end > len(featureFlags) {
+		end = len(featureFlags)
+	}
+
+	return featureFlags[offset:end], totalCount, nil
+}
+
+// GetAssignedFeatureFlagsByPersonId answers with the same flags the API would for the person
+func (rs *RelayService) GetAssignedFeatureFlagsByPersonId(id uint) ([]p_entity.AssignedFeatureFlagResponse, error) {
+	rs.mu.RLock()
+	people := rs.people
+	rs.mu.RUnlock()
+
+	if people == nil {
+		return nil, ErrNotSynced
+	}
+
+	return people.GetAssignedFeatureFlagsByPersonId(id, 0, "")
+}
+
+// storeRepository answers the people lookups from the snapshot, only the assigned flags
+// of a person can be relayed
+type storeRepository struct {
+	*snapshot.Store
+}
+
+func (s *storeRepository) GetPeopleAssignmentByFeatureFlag(pagination model.Pagination, filters p_entity.PersonFilters) ([]model.PersonWithAssignment, int64, error) {
+	return nil, 0, errors.New("people are not relayed")
+}
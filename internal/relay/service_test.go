@@ -0,0 +1,242 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"ff/internal/db/model"
+	ff_entity "ff/internal/feature_flag/entity"
+	projectEntity "ff/internal/project/entity"
+	snapshotEntity "ff/internal/snapshot/entity"
+	"ff/pkg/ffclient"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSource is a mock of Source
+type MockSource struct {
+	mock.Mock
+}
+
+func (m *MockSource) Name() string {
+	return "mock"
+}
+
+func (m *MockSource) Snapshot(ctx context.Context) (snapshotEntity.Snapshot, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(snapshotEntity.Snapshot), args.Error(1)
+}
+
+var testSnapshot = snapshotEntity.Snapshot{
+	Version:     4,
+	ProjectID:   1,
+	Environment: "staging",
+	FeatureFlags: []snapshotEntity.FeatureFlag{
+		{ID: 1, Name: "new-checkout", IsActive: true, Assignments: []snapshotEntity.Assignment{{PersonID: 7, Kind: model.AssignmentInclude}}},
+		{ID: 2, Name: "dark-mode", IsActive: true, IsGlobal: true},
+		{ID: 3, Name: "old-checkout", IsActive: false, IsGlobal: true},
+	},
+}
+
+func loadTestService(source Source) *RelayService {
+	logger := zerolog.New(os.Stdout)
+
+	return LoadService(source, &logger)
+}
+
+func TestSync(t *testing.T) {
+	t.Run("Nothing is served before the first sync", func(t *testing.T) {
+		service := loadTestService(new(MockSource))
+
+		_, _, err := service.GetFeatureFlags(model.Pagination{Page: 1, Limit: 10}, ff_entity.FeatureFlagFilters{})
+		assert.ErrorIs(t, err, ErrNotSynced)
+
+		_, err = service.GetAssignedFeatureFlagsByPersonId(7)
+		assert.ErrorIs(t, err, ErrNotSynced)
+
+		assert.Empty(t, service.Status().SyncedAt)
+	})
+
+	t.Run("Last known snapshot is kept when the source fails", func(t *testing.T) {
+		source := new(MockSource)
+		service := loadTestService(source)
+
+		source.On("Snapshot", mock.Anything).Return(testSnapshot, nil).Once()
+		source.On("Snapshot", mock.Anything).Return(snapshotEntity.Snapshot{}, errors.New("upstream is down"))
+
+		require.NoError(t, service.Sync(context.Background()))
+		assert.EqualError(t, service.Sync(context.Background()), "upstream is down")
+
+		featureFlags, total, err := service.GetFeatureFlags(model.Pagination{Page: 1, Limit: 10}, ff_entity.FeatureFlagFilters{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, "new-checkout", featureFlags[0].Name)
+		assert.Equal(t, "staging", featureFlags[0].Environment)
+
+		status := service.Status()
+		assert.Equal(t, uint(4), status.Version)
+		assert.Equal(t, 3, status.FeatureFlags)
+		assert.NotEmpty(t, status.SyncedAt)
+		assert.Equal(t, "upstream is down", status.LastError)
+	})
+}
+
+func TestGetFeatureFlags(t *testing.T) {
+	source := new(MockSource)
+	service := loadTestService(source)
+
+	source.On("Snapshot", mock.Anything).Return(testSnapshot, nil)
+	require.NoError(t, service.Sync(context.Background()))
+
+	isGlobal := true
+	tests := []struct {
+		name       string
+		pagination model.Pagination
+		filters    ff_entity.FeatureFlagFilters
+		expected   []string
+		total      int64
+	}{
+		{name: "Filter by name", pagination: model.Pagination{Page: 1, Limit: 10}, filters: ff_entity.FeatureFlagFilters{Name: "CHECKOUT"}, expected: []string{"new-checkout", "old-checkout"}, total: 2},
+		{name: "Filter by global", pagination: model.Pagination{Page: 1, Limit: 10}, filters: ff_entity.FeatureFlagFilters{IsGlobal: &isGlobal}, expected: []string{"dark-mode", "old-checkout"}, total: 2},
+		{name: "Second page", pagination: model.Pagination{Page: 2, Limit: 2}, expected: []string{"old-checkout"}, total: 3},
+		{name: "Page after the last", pagination: model.Pagination{Page: 3, Limit: 2}, expected: []string{}, total: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			featureFlags, total, err := service.GetFeatureFlags(tt.pagination, tt.filters)
+			require.NoError(t, err)
+
+			names := []string{}
+			for _, featureFlag := range featureFlags {
+				names = append(names, featureFlag.Name)
+			}
+
+			assert.Equal(t, tt.expected, names)
+			assert.Equal(t, tt.total, total)
+		})
+	}
+}
+
+func TestGetAssignedFeatureFlagsByPersonId(t *testing.T) {
+	source := new(MockSource)
+	service := loadTestService(source)
+
+	source.On("Snapshot", mock.Anything).Return(testSnapshot, nil)
+	require.NoError(t, service.Sync(context.Background()))
+
+	featureFlags, err := service.GetAssignedFeatureFlagsByPersonId(7)
+	require.NoError(t, err)
+
+	active := map[string]bool{}
+	for _, featureFlag := range featureFlags {
+		active[featureFlag.Name] = featureFlag.IsActive
+	}
+	assert.Equal(t, map[string]bool{"new-checkout": true, "dark-mode": true, "old-checkout": false}, active)
+
+	featureFlags, err = service.GetAssignedFeatureFlagsByPersonId(8)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(featureFlags))
+}
+
+func TestServes(t *testing.T) {
+	source := new(MockSource)
+	service := loadTestService(source)
+
+	assert.True(t, service.Serves("", "staging"))
+
+	source.On("Snapshot", mock.Anything).Return(testSnapshot, nil)
+	require.NoError(t, service.Sync(context.Background()))
+
+	assert.True(t, service.Serves("", ""))
+	assert.True(t, service.Serves(model.DefaultProject, "staging"))
+	assert.False(t, service.Serves("", model.DefaultEnvironment))
+	assert.False(t, service.Serves("mobile", ""))
+}
+
+func TestAPISource(t *testing.T) {
+	var down atomic.Bool
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		assert.Equal(t, "ff_key", r.Header.Get("X-Api-Key"))
+		assert.Equal(t, "staging", r.Header.Get("X-Environment"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(testSnapshot)
+	}))
+	defer upstream.Close()
+
+	client, err := ffclient.New(ffclient.Config{BaseURL: upstream.URL, ApiKey: "ff_key", Environment: "staging"})
+	require.NoError(t, err)
+
+	service := loadTestService(&APISource{Client: client})
+	require.NoError(t, service.Sync(context.Background()))
+
+	down.Store(true)
+	assert.Error(t, service.Sync(context.Background()))
+
+	featureFlags, err := service.GetAssignedFeatureFlagsByPersonId(7)
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(featureFlags))
+}
+
+// MockSnapshotService is a mock of SnapshotService
+type MockSnapshotService struct {
+	mock.Mock
+}
+
+func (m *MockSnapshotService) GetSnapshot(projectId uint, environment string) (snapshotEntity.Snapshot, error) {
+	args := m.Called(projectId, environment)
+	return args.Get(0).(snapshotEntity.Snapshot), args.Error(1)
+}
+
+func (m *MockSnapshotService) GetSnapshotVersion(projectId uint, environment string) (uint, error) {
+	args := m.Called(projectId, environment)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+// MockProjectService is a mock of ProjectService
+type MockProjectService struct {
+	mock.Mock
+}
+
+func (m *MockProjectService) GetProjectByKey(key string) (projectEntity.ProjectResponse, error) {
+	args := m.Called(key)
+	return args.Get(0).(projectEntity.ProjectResponse), args.Error(1)
+}
+
+func TestDatabaseSource(t *testing.T) {
+	snapshots := new(MockSnapshotService)
+	projects := new(MockProjectService)
+	source := &DatabaseSource{Snapshots: snapshots, Projects: projects, Environment: "staging"}
+
+	projects.On("GetProjectByKey", model.DefaultProject).Return(projectEntity.ProjectResponse{}, errors.New("project not found")).Once()
+	projects.On("GetProjectByKey", model.DefaultProject).Return(projectEntity.ProjectResponse{ID: 1}, nil).Once()
+	snapshots.On("GetSnapshotVersion", uint(1), "staging").Return(uint(4), nil)
+	snapshots.On("GetSnapshot", uint(1), "staging").Return(testSnapshot, nil).Once()
+
+	_, err := source.Snapshot(context.Background())
+	assert.EqualError(t, err, "project not found")
+
+	// the project is resolved once and an unchanged snapshot is not built again
+	for i := 0; i < 2; i++ {
+		flagSnapshot, err := source.Snapshot(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint(4), flagSnapshot.Version)
+	}
+
+	projects.AssertExpectations(t)
+	snapshots.AssertExpectations(t)
+}
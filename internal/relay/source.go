@@ -0,0 +1,101 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"ff/internal/db/model"
+	projectEntity "ff/internal/project/entity"
+	snapshotEntity "ff/internal/snapshot/entity"
+	"ff/pkg/ffclient"
+)
+
+const (
+	SourceAPI      = "api"
+	SourceDatabase = "db"
+)
+
+// APISource syncs the snapshot from the main API with the Go client, an unchanged
+// snapshot costs a 304
+type APISource struct {
+	Client *ffclient.Client
+}
+
+func (s *APISource) Name() string {
+	return SourceAPI
+}
+
+func (s *APISource) Snapshot(ctx context.Context) (snapshotEntity.Snapshot, error) {
+	if err := s.Client.Refresh(ctx); err != nil {
+		return snapshotEntity.Snapshot{}, err
+	}
+
+	flagSnapshot, ok := s.Client.Snapshot()
+	if !ok {
+		return snapshotEntity.Snapshot{}, errors.New("no snapshot was received")
+	}
+
+	return flagSnapshot, nil
+}
+
+type SnapshotService interface {
+	GetSnapshot(projectId uint, environment string) (snapshotEntity.Snapshot, error)
+	GetSnapshotVersion(projectId uint, environment string) (uint, error)
+}
+
+type ProjectService interface {
+	GetProjectByKey(key string) (projectEntity.ProjectResponse, error)
+}
+
+// DatabaseSource builds the snapshot straight from the database, a project that could not
+// be resolved is looked up again on the next sync
+type DatabaseSource struct {
+	Snapshots   SnapshotService
+	Projects    ProjectService
+	ProjectKey  string
+	Environment string
+
+	mu        sync.Mutex
+	projectId uint
+	last      *snapshotEntity.Snapshot
+}
+
+func (s *DatabaseSource) Name() string {
+	return SourceDatabase
+}
+
+func (s *DatabaseSource) Snapshot(ctx context.Context) (snapshotEntity.Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.projectId == 0 {
+		key := s.ProjectKey
+		if key == "" {
+			key = model.DefaultProject
+		}
+
+		project, err := s.Projects.GetProjectByKey(key)
+		if err != nil {
+			return snapshotEntity.Snapshot{}, err
+		}
+		s.projectId = project.ID
+	}
+
+	// the snapshot is only rebuilt when something changed since the last sync
+	version, err := s.Snapshots.GetSnapshotVersion(s.projectId, s.Environment)
+	if err != nil {
+		return snapshotEntity.Snapshot{}, err
+	}
+	if s.last != nil && s.last.Version == version {
+		return *s.last, nil
+	}
+
+	flagSnapshot, err := s.Snapshots.GetSnapshot(s.projectId, s.Environment)
+	if err != nil {
+		return snapshotEntity.Snapshot{}, err
+	}
+	s.last = &flagSnapshot
+
+	return flagSnapshot, nil
+}
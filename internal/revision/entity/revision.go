@@ -0,0 +1,52 @@
+package entity
+
+import (
+	assignmentEntity "ff/internal/assignment/entity"
+	featureFlagEntity "ff/internal/feature_flag/entity"
+	personEntity "ff/internal/person/entity"
+)
+
+// EventRolledBack is the event of the revision recorded by a rollback
+const EventRolledBack = "feature_flag.rolled_back"
+
+// Snapshot is the flag and its assignments in the environment of the revision
+type Snapshot struct {
+	FeatureFlag featureFlagEntity.FeatureFlagResponse `json:"featureFlag"`
+	Assignments []assignmentEntity.Assignment         `json:"assignments"`
+}
+
+type RevisionFilters struct {
+	// Environment selects the revisions of an environment, every environment when empty
+	Environment string
+}
+
+type RevisionResponse struct {
+	ID            uint                         `json:"id"`
+	FeatureFlagID uint                         `json:"featureFlagId"`
+	Event         string                       `json:"event"`
+	Environment   string                       `json:"environment"`
+	Snapshot      Snapshot                     `json:"snapshot"`
+	Person        *personEntity.PersonResponse `json:"person"`
+	CreatedAt     string                       `json:"createdAt"`
+}
+
+// Change is a field that is different between two revisions, assignments are named after
+// the person they belong to
+type Change struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+type DiffResponse struct {
+	From    RevisionResponse `json:"from"`
+	To      RevisionResponse `json:"to"`
+	Changes []Change         `json:"changes"`
+}
+
+type Rollback struct {
+	ProjectID uint `json:"-"`
+	PersonID  uint `json:"-"`
+	// Force deactivates the flag even when other flags depend on it
+	Force bool `json:"force"`
+}
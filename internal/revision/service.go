@@ -0,0 +1,439 @@
+package revision
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	assignmentEntity "ff/internal/assignment/entity"
+	"ff/internal/db/model"
+	featureflag "ff/internal/feature_flag"
+	featureFlagEntity "ff/internal/feature_flag/entity"
+	personEntity "ff/internal/person/entity"
+	revisionEntity "ff/internal/revision/entity"
+
+	"github.com/rs/zerolog"
+)
+
+type RevisionRepository interface {
+	AddFeatureFlagRevision(revision model.FeatureFlagRevision) error
+	GetFeatureFlagRevisions(filters model.FeatureFlagRevisionFilters, pagination model.Pagination) ([]model.FeatureFlagRevision, int64, error)
+	GetFeatureFlagRevision(featureFlagId uint, id uint) (model.FeatureFlagRevision, error)
+	GetFeatureFlag(filters model.FeatureFlagFilters, pagination model.Pagination) ([]model.FeatureFlag, int64, error)
+	GetAssignmentsByFeatureFlagId(featureFlagId uint, environment string) ([]model.Assignment, error)
+}
+
+// FeatureFlagService is the part of the feature flag service used to roll a flag back,
+// so a rollback goes through the same validation as a manual update
+type FeatureFlagService interface {
+	RollbackFeatureFlag(id uint, request featureFlagEntity.RollbackFeatureFlag) error
+}
+
+// ignoredFields change with every revision, they are left out of the diffs
+var ignoredFields = map[string]bool{
+	"updatedAt": true,
+	"version":   true,
+}
+
+type RevisionService struct {
+	Repository         RevisionRepository
+	FeatureFlagService FeatureFlagService
+	Logger             *zerolog.Logger
+}
+
+func LoadService(r RevisionRepository, ffs FeatureFlagService, l *zerolog.Logger) *RevisionService {
+	return &RevisionService{
+		Logger:             l,
+		Repository:         r,
+		FeatureFlagService: ffs,
+	}
+}
+
+// RecordRevision keeps the flag as it is after a change made by the person, a zero person
+// is a change made by the application. Failures are logged, the change itself already happened
+func (rs *RevisionService) RecordRevision(event string, featureFlagId uint, environment string, personId uint) {
+	rs.Logger.Info().Str("event", event).Uint("featureFlagId", featureFlagId).Msg("Recording a Feature Flag revision")
+
+	if environment == "" {
+		environment = model.DefaultEnvironment
+	}
+
+	snapshot, err := rs.snapshot(featureFlagId, 0, environment)
+	if err != nil {
+		rs.Logger.Error().Err(err).Uint("featureFlagId", featureFlagId).Msg("Error when reading the flag to record")
+		return
+	}
+
+	payload, err := json.Marshal(snapshot)
+	if err != nil {
+		rs.Logger.Error().Err(err).Uint("featureFlagId", featureFlagId).Msg("Error when encoding the flag to record")
+		return
+	}
+
+	revision := model.FeatureFlagRevision{
+		FeatureFlagID: featureFlagId,
+		Event:         event,
+		Environment:   environment,
+		Snapshot:      string(payload),
+	}
+
+	if personId != 0 {
+		revision.PersonID = &personId
+	}
+
+	if err := rs.Repository.AddFeatureFlagRevision(revision); err != nil {
+		rs.Logger.Error().Err(err).Uint("featureFlagId", featureFlagId).Msg("Error when storing the flag revision")
+	}
+}
+
+func (rs *RevisionService) GetRevisions(featureFlagId uint, projectId uint, pagination model.Pagination, filters revisionEntity.RevisionFilters) ([]revisionEntity.RevisionResponse, int64, error) {
+	rs.Logger.Info().Msg("Getting Feature Flag revisions")
+
+	if err := rs.checkProject(featureFlagId, projectId); err != nil {
+		return nil, 0, err
+	}
+
+	revisions, totalCount, err := rs.Repository.GetFeatureFlagRevisions(model.FeatureFlagRevisionFilters{
+		FeatureFlagID: featureFlagId,
+		Environment:   filters.Environment,
+	}, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var revisionResponses []revisionEntity.RevisionResponse
+	for _, revisionDB := range revisions {
+		revisionResponse, err := revisionFromModel(revisionDB)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		revisionResponses = append(revisionResponses, revisionResponse)
+	}
+
+	return revisionResponses, totalCount, nil
+}
+
+// DiffRevisions returns the fields that changed from one revision to the other
+func (rs *RevisionService) DiffRevisions(featureFlagId uint, projectId uint, fromId uint, toId uint) (revisionEntity.DiffResponse, error) {
+	rs.Logger.Info().Msg("Comparing Feature Flag revisions")
+
+	if err := rs.checkProject(featureFlagId, projectId); err != nil {
+		return revisionEntity.DiffResponse{}, err
+	}
+
+	from, err := rs.getRevision(featureFlagId, fromId)
+	if err != nil {
+		return revisionEntity.DiffResponse{}, err
+	}
+
+	to, err := rs.getRevision(featureFlagId, toId)
+	if err != nil {
+		return revisionEntity.DiffResponse{}, err
+	}
+
+	return revisionEntity.DiffResponse{
+		From:    from,
+		To:      to,
+		Changes: diffSnapshots(from.Snapshot, to.Snapshot),
+	}, nil
+}
+
+// RollbackRevision brings the flag and its assignments back to the revision in one change, recorded
+// as a single revision. A revision of another environment than the default one only rolls back the
+// status and the assignments of its environment, the rest of the flag is shared by the environments
+func (rs *RevisionService) RollbackRevision(featureFlagId uint, id uint, request revisionEntity.Rollback) error {
+	rs.Logger.Info().Msg("Rolling back a Feature Flag")
+
+	revision, err := rs.Repository.GetFeatureFlagRevision(featureFlagId, id)
+	if err != nil {
+		return err
+	}
+
+	var target revisionEntity.Snapshot
+	if err := json.Unmarshal([]byte(revision.Snapshot), &target); err != nil {
+		return fmt.Errorf("revision %d can not be read", id)
+	}
+
+	current, err := rs.snapshot(featureFlagId, request.ProjectID, revision.Environment)
+	if err != nil {
+		return err
+	}
+
+	if current.FeatureFlag.ArchivedAt != "" {
+		return errors.New("feature flag is archived")
+	}
+
+	if matches(target, current, revision.Environment) {
+		return errors.New("feature flag already matches the revision")
+	}
+
+	if err := rs.FeatureFlagService.RollbackFeatureFlag(featureFlagId, featureFlagEntity.RollbackFeatureFlag{
+		FeatureFlag: target.FeatureFlag,
+		Assignments: target.Assignments,
+		Environment: revision.Environment,
+		ProjectID:   request.ProjectID,
+		Force:       request.Force,
+		Version:     current.FeatureFlag.Version,
+	}); err != nil {
+		return err
+	}
+
+	rs.RecordRevision(revisionEntity.EventRolledBack, featureFlagId, revision.Environment, request.PersonID)
+
+	return nil
+}
+
+// matches reports whether the flag already is as the revision in what the rollback would change
+func matches(target revisionEntity.Snapshot, current revisionEntity.Snapshot, environment string) bool {
+	want, have := target.FeatureFlag, current.FeatureFlag
+
+	if want.IsActive != have.IsActive || want.IsGlobal != have.IsGlobal ||
+		!sameJSON(assignmentsByPerson(target.Assignments), assignmentsByPerson(current.Assignments)) {
+		return false
+	}
+
+	if environment != model.DefaultEnvironment {
+		return true
+	}
+
+	return want.Description == have.Description &&
+		want.ExpirationDate == have.ExpirationDate &&
+		want.RolloutPercentage == have.RolloutPercentage &&
+		(want.Type == featureFlagEntity.TypeBoolean || (want.DefaultVariant == have.DefaultVariant && sameJSON(want.Variants, have.Variants))) &&
+		sameJSON(want.Rules, have.Rules) &&
+		sameJSON(want.Prerequisites, have.Prerequisites)
+}
+
+// snapshot reads the flag and its assignments in the environment, archived or not
+func (rs *RevisionService) snapshot(featureFlagId uint, projectId uint, environment string) (revisionEntity.Snapshot, error) {
+	featureFlag, err := rs.findFeatureFlag(featureFlagId, projectId, environment)
+	if err != nil {
+		return revisionEntity.Snapshot{}, err
+	}
+
+	assignments, err := rs.Repository.GetAssignmentsByFeatureFlagId(featureFlagId, environment)
+	if err != nil {
+		return revisionEntity.Snapshot{}, err
+	}
+
+	snapshot := revisionEntity.Snapshot{
+		FeatureFlag: featureflag.FeatureFlagFromModel(featureFlag, environment),
+		Assignments: []assignmentEntity.Assignment{},
+	}
+
+	for _, assignment := range assignments {
+		snapshot.Assignments = append(snapshot.Assignments, assignmentEntity.Assignment{
+			PersonID:      assignment.PersonID,
+			FeatureFlagID: assignment.FeatureFlagID,
+			Variant:       assignment.Variant,
+			Kind:          assignment.Kind,
+		})
+	}
+
+	return snapshot, nil
+}
+
+// checkProject makes sure the revisions of the flag belong to the project. The revisions outlive the
+// flag, so the project is read from the newest of them and only from the flag when it has none
+func (rs *RevisionService) checkProject(featureFlagId uint, projectId uint) error {
+	revisions, _, err := rs.Repository.GetFeatureFlagRevisions(model.FeatureFlagRevisionFilters{
+		FeatureFlagID: featureFlagId,
+	}, model.Pagination{
+		Limit: 1,
+		Page:  1,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(revisions) == 0 {
+		_, err := rs.findFeatureFlag(featureFlagId, projectId, "")
+		return err
+	}
+
+	var snapshot revisionEntity.Snapshot
+	if err := json.Unmarshal([]byte(revisions[0].Snapshot), &snapshot); err != nil {
+		return fmt.Errorf("revision %d can not be read", revisions[0].ID)
+	}
+
+	if projectId != 0 && snapshot.FeatureFlag.ProjectID != projectId {
+		return errors.New("feature flag not found")
+	}
+
+	return nil
+}
+
+func (rs *RevisionService) findFeatureFlag(featureFlagId uint, projectId uint, environment string) (model.FeatureFlag, error) {
+	featureFlags, _, err := rs.Repository.GetFeatureFlag(model.FeatureFlagFilters{
+		ID:          featureFlagId,
+		ProjectID:   projectId,
+		Environment: environment,
+	}, model.Pagination{
+		Limit: 1,
+		Page:  1,
+	})
+	if err != nil {
+		return model.FeatureFlag{}, err
+	}
+
+	if len(featureFlags) == 0 {
+		return model.FeatureFlag{}, errors.New("feature flag not found")
+	}
+
+	return featureFlags[0], nil
+}
+
+func (rs *RevisionService) getRevision(featureFlagId uint, id uint) (revisionEntity.RevisionResponse, error) {
+	revision, err := rs.Repository.GetFeatureFlagRevision(featureFlagId, id)
+	if err != nil {
+		return revisionEntity.RevisionResponse{}, err
+	}
+
+	return revisionFromModel(revision)
+}
+
+func revisionFromModel(revisionDB model.FeatureFlagRevision) (revisionEntity.RevisionResponse, error) {
+	response := revisionEntity.RevisionResponse{
+		ID:            revisionDB.ID,
+		FeatureFlagID: revisionDB.FeatureFlagID,
+		Event:         revisionDB.Event,
+		Environment:   revisionDB.Environment,
+		CreatedAt:     revisionDB.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	if err := json.Unmarshal([]byte(revisionDB.Snapshot), &response.Snapshot); err != nil {
+		return revisionEntity.RevisionResponse{}, fmt.Errorf("revision %d can not be read", revisionDB.ID)
+	}
+
+	if revisionDB.Person != nil {
+		response.Person = &personEntity.PersonResponse{
+			ID:    revisionDB.Person.ID,
+			Name:  revisionDB.Person.Name,
+			Email: revisionDB.Person.Email,
+		}
+	}
+
+	return response, nil
+}
+
+// diffSnapshots compares the flags field by field as the API returns them, and the
+// assignments person by person
+func diffSnapshots(from revisionEntity.Snapshot, to revisionEntity.Snapshot) []revisionEntity.Change {
+	fromFields := jsonFields(from.FeatureFlag)
+	toFields := jsonFields(to.FeatureFlag)
+
+	names := map[string]bool{}
+	for name := range fromFields {
+		names[name] = true
+	}
+	for name := range toFields {
+		names[name] = true
+	}
+
+	var sortedNames []string
+	for name := range names {
+		if !ignoredFields[name] {
+			sortedNames = append(sortedNames, name)
+		}
+	}
+	sort.Strings(sortedNames)
+
+	changes := []revisionEntity.Change{}
+	for _, name := range sortedNames {
+		if !sameValue(fromFields[name], toFields[name]) {
+			changes = append(changes, revisionEntity.Change{
+				Field: name,
+				From:  fromFields[name],
+				To:    toFields[name],
+			})
+		}
+	}
+
+	fromAssignments := assignmentsByPerson(from.Assignments)
+	toAssignments := assignmentsByPerson(to.Assignments)
+
+	var personIds []uint
+	for personId := range fromAssignments {
+		personIds = append(personIds, personId)
+	}
+	for personId := range toAssignments {
+		if _, ok := fromAssignments[personId]; !ok {
+			personIds = append(personIds, personId)
+		}
+	}
+	sort.Slice(personIds, func(i, j int) bool { return personIds[i] < personIds[j] })
+
+	for _, personId := range personIds {
+		before, hadBefore := fromAssignments[personId]
+		after, hasAfter := toAssignments[personId]
+		if hadBefore && hasAfter && before == after {
+			continue
+		}
+
+		change := revisionEntity.Change{Field: fmt.Sprintf("assignments.%d", personId)}
+		if hadBefore {
+			change.From = before
+		}
+		if hasAfter {
+			change.To = after
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+func assignmentsByPerson(assignments []assignmentEntity.Assignment) map[uint]assignmentEntity.Assignment {
+	byPerson := map[uint]assignmentEntity.Assignment{}
+	for _, assignment := range assignments {
+		byPerson[assignment.PersonID] = assignment
+	}
+
+	return byPerson
+}
+
+// jsonFields returns the value as a map of its JSON fields
+func jsonFields(value interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(payload, &fields)
+
+	return fields
+}
+
+// sameJSON compares the values as they are encoded, the revisions are read back from JSON
+func sameJSON(a interface{}, b interface{}) bool {
+	var decodedA, decodedB interface{}
+
+	payloadA, _ := json.Marshal(a)
+	payloadB, _ := json.Marshal(b)
+	_ = json.Unmarshal(payloadA, &decodedA)
+	_ = json.Unmarshal(payloadB, &decodedB)
+
+	return sameValue(decodedA, decodedB)
+}
+
+// sameValue compares decoded JSON values, a missing list is the same as an empty one
+func sameValue(a interface{}, b interface{}) bool {
+	if isEmptyList(a) && isEmptyList(b) {
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func isEmptyList(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	list, ok := value.([]interface{})
+	return ok && len(list) == 0
+}
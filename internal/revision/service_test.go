@@ -0,0 +1,356 @@
+package revision
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	assignmentEntity "ff/internal/assignment/entity"
+	"ff/internal/db/model"
+	featureFlagEntity "ff/internal/feature_flag/entity"
+	revisionEntity "ff/internal/revision/entity"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock of RevisionRepository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) AddFeatureFlagRevision(revision model.FeatureFlagRevision) error {
+	args := m.Called(revision)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetFeatureFlagRevisions(filters model.FeatureFlagRevisionFilters, pagination model.Pagination) ([]model.FeatureFlagRevision, int64, error) {
+	args := m.Called(filters, pagination)
+	return args.Get(0).([]model.FeatureFlagRevision), int64(args.Get(1).(int)), args.Error(2)
+}
+
+func (m *MockRepository) GetFeatureFlagRevision(featureFlagId uint, id uint) (model.FeatureFlagRevision, error) {
+	args := m.Called(featureFlagId, id)
+	return args.Get(0).(model.FeatureFlagRevision), args.Error(1)
+}
+
+func (m *MockRepository) GetFeatureFlag(filters model.FeatureFlagFilters, pagination model.Pagination) ([]model.FeatureFlag, int64, error) {
+	args := m.Called(filters, pagination)
+	return args.Get(0).([]model.FeatureFlag), int64(args.Get(1).(int)), args.Error(2)
+}
+
+func (m *MockRepository) GetAssignmentsByFeatureFlagId(featureFlagId uint, environment string) ([]model.Assignment, error) {
+	args := m.Called(featureFlagId, environment)
+	return args.Get(0).([]model.Assignment), args.Error(1)
+}
+
+// MockFeatureFlagService is a mock of FeatureFlagService
+type MockFeatureFlagService struct {
+	mock.Mock
+}
+
+func (m *MockFeatureFlagService) RollbackFeatureFlag(id uint, request featureFlagEntity.RollbackFeatureFlag) error {
+	args := m.Called(id, request)
+	return args.Error(0)
+}
+
+var featureFlagOnDB = model.FeatureFlag{
+	ID:                1,
+	Name:              "TEST_FLAG",
+	Description:       "Test Description",
+	IsActive:          true,
+	RolloutPercentage: 10,
+	ProjectID:         1,
+	Type:              featureFlagEntity.TypeBoolean,
+	Version:           4,
+}
+
+var onePage = model.Pagination{Limit: 1, Page: 1}
+
+func loadTestService() (*RevisionService, *MockRepository, *MockFeatureFlagService) {
+	mockRepo := new(MockRepository)
+	mockFeatureFlagService := new(MockFeatureFlagService)
+	logger := zerolog.New(os.Stdout)
+
+	return LoadService(mockRepo, mockFeatureFlagService, &logger), mockRepo, mockFeatureFlagService
+}
+
+// revisionOnDB is a revision of the flag with a different status and assignments
+func revisionOnDB(id uint, isActive bool, assignments []assignmentEntity.Assignment) model.FeatureFlagRevision {
+	featureFlag := featureFlagOnDB
+	featureFlag.IsActive = isActive
+
+	snapshot, _ := json.Marshal(revisionEntity.Snapshot{
+		FeatureFlag: featureFlagEntity.FeatureFlagResponse{
+			ID:                "1",
+			Name:              featureFlag.Name,
+			Description:       featureFlag.Description,
+			IsActive:          featureFlag.IsActive,
+			RolloutPercentage: featureFlag.RolloutPercentage,
+			Type:              featureFlag.Type,
+			Environment:       model.DefaultEnvironment,
+			ProjectID:         featureFlag.ProjectID,
+			Version:           id,
+		},
+		Assignments: assignments,
+	})
+
+	return model.FeatureFlagRevision{
+		ID:            id,
+		FeatureFlagID: 1,
+		Event:         "feature_flag.updated",
+		Environment:   model.DefaultEnvironment,
+		Snapshot:      string(snapshot),
+		CreatedAt:     time.Date(2024, 10, 10, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// Record Revision Tests Cases
+func TestRecordRevision(t *testing.T) {
+	t.Run("Store the flag and its assignments with the person", func(t *testing.T) {
+		service, mockRepo, _ := loadTestService()
+
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 1, Environment: "staging"}, onePage).Return([]model.FeatureFlag{featureFlagOnDB}, 1, nil)
+		mockRepo.On("GetAssignmentsByFeatureFlagId", uint(1), "staging").Return([]model.Assignment{{PersonID: 2, FeatureFlagID: 1, Kind: model.AssignmentInclude}}, nil)
+		mockRepo.On("AddFeatureFlagRevision", mock.MatchedBy(func(revision model.FeatureFlagRevision) bool {
+			var snapshot revisionEntity.Snapshot
+			if err := json.Unmarshal([]byte(revision.Snapshot), &snapshot); err != nil {
+				return false
+			}
+			return revision.Event == "feature_flag.status_toggled" && revision.Environment == "staging" &&
+				revision.PersonID != nil && *revision.PersonID == 3 &&
+				snapshot.FeatureFlag.Name == "TEST_FLAG" && len(snapshot.Assignments) == 1 && snapshot.Assignments[0].PersonID == 2
+		})).Return(nil)
+
+		service.RecordRevision("feature_flag.status_toggled", 1, "staging", 3)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Changes made by the application have no person", func(t *testing.T) {
+		service, mockRepo, _ := loadTestService()
+
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 1, Environment: model.DefaultEnvironment}, onePage).Return([]model.FeatureFlag{featureFlagOnDB}, 1, nil)
+		mockRepo.On("GetAssignmentsByFeatureFlagId", uint(1), model.DefaultEnvironment).Return([]model.Assignment{}, nil)
+		mockRepo.On("AddFeatureFlagRevision", mock.MatchedBy(func(revision model.FeatureFlagRevision) bool {
+			return revision.Environment == model.DefaultEnvironment && revision.PersonID == nil
+		})).Return(nil)
+
+		service.RecordRevision("feature_flag.updated", 1, "", 0)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Flag that can not be read is not recorded", func(t *testing.T) {
+		service, mockRepo, _ := loadTestService()
+
+		mockRepo.On("GetFeatureFlag", mock.Anything, onePage).Return([]model.FeatureFlag{}, 0, nil)
+
+		service.RecordRevision("feature_flag.updated", 1, "", 3)
+
+		mockRepo.AssertNotCalled(t, "AddFeatureFlagRevision", mock.Anything)
+	})
+}
+
+// Get Revisions Tests Cases
+func TestGetRevisions(t *testing.T) {
+	newest := model.FeatureFlagRevisionFilters{FeatureFlagID: 1}
+
+	t.Run("List the revisions of the flag", func(t *testing.T) {
+		service, mockRepo, _ := loadTestService()
+
+		mockRepo.On("GetFeatureFlagRevisions", newest, onePage).Return([]model.FeatureFlagRevision{revisionOnDB(2, false, nil)}, 2, nil)
+		mockRepo.On("GetFeatureFlagRevisions", model.FeatureFlagRevisionFilters{FeatureFlagID: 1, Environment: "staging"}, model.Pagination{Page: 1, Limit: 10}).
+			Return([]model.FeatureFlagRevision{revisionOnDB(2, false, nil), revisionOnDB(1, true, nil)}, 2, nil)
+
+		revisions, totalCount, err := service.GetRevisions(1, 1, model.Pagination{Page: 1, Limit: 10}, revisionEntity.RevisionFilters{Environment: "staging"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), totalCount)
+		assert.Equal(t, uint(2), revisions[0].ID)
+		assert.False(t, revisions[0].Snapshot.FeatureFlag.IsActive)
+		assert.Nil(t, revisions[0].Person)
+		assert.Equal(t, "2024-10-10 00:00:00", revisions[0].CreatedAt)
+	})
+
+	t.Run("Revisions of a deleted flag are read from their own project", func(t *testing.T) {
+		service, mockRepo, _ := loadTestService()
+
+		deleted := revisionOnDB(3, false, nil)
+		deleted.Event = "feature_flag.deleted"
+
+		mockRepo.On("GetFeatureFlagRevisions", newest, onePage).Return([]model.FeatureFlagRevision{deleted}, 3, nil)
+		mockRepo.On("GetFeatureFlagRevisions", newest, model.Pagination{Page: 1, Limit: 10}).
+			Return([]model.FeatureFlagRevision{deleted, revisionOnDB(2, false, nil), revisionOnDB(1, true, nil)}, 3, nil)
+
+		revisions, totalCount, err := service.GetRevisions(1, 1, model.Pagination{Page: 1, Limit: 10}, revisionEntity.RevisionFilters{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), totalCount)
+		assert.Equal(t, "feature_flag.deleted", revisions[0].Event)
+		mockRepo.AssertNotCalled(t, "GetFeatureFlag", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Flag of another project is not found", func(t *testing.T) {
+		service, mockRepo, _ := loadTestService()
+
+		mockRepo.On("GetFeatureFlagRevisions", newest, onePage).Return([]model.FeatureFlagRevision{revisionOnDB(2, false, nil)}, 2, nil)
+
+		_, _, err := service.GetRevisions(1, 2, model.Pagination{Page: 1, Limit: 10}, revisionEntity.RevisionFilters{})
+
+		assert.EqualError(t, err, "feature flag not found")
+		mockRepo.AssertNumberOfCalls(t, "GetFeatureFlagRevisions", 1)
+	})
+
+	t.Run("Flag without revisions is looked up in the project", func(t *testing.T) {
+		service, mockRepo, _ := loadTestService()
+
+		mockRepo.On("GetFeatureFlagRevisions", newest, onePage).Return([]model.FeatureFlagRevision{}, 0, nil)
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 1, ProjectID: 2}, onePage).Return([]model.FeatureFlag{}, 0, nil)
+
+		_, _, err := service.GetRevisions(1, 2, model.Pagination{Page: 1, Limit: 10}, revisionEntity.RevisionFilters{})
+
+		assert.EqualError(t, err, "feature flag not found")
+	})
+}
+
+// Diff Revisions Tests Cases
+func TestDiffRevisions(t *testing.T) {
+	newest := model.FeatureFlagRevisionFilters{FeatureFlagID: 1}
+
+	t.Run("Only the changed fields and assignments are returned", func(t *testing.T) {
+		service, mockRepo, _ := loadTestService()
+
+		mockRepo.On("GetFeatureFlagRevisions", newest, onePage).Return([]model.FeatureFlagRevision{revisionOnDB(2, false, nil)}, 2, nil)
+		mockRepo.On("GetFeatureFlagRevision", uint(1), uint(1)).Return(revisionOnDB(1, true, []assignmentEntity.Assignment{
+			{PersonID: 2, FeatureFlagID: 1, Kind: "include"},
+		}), nil)
+		mockRepo.On("GetFeatureFlagRevision", uint(1), uint(2)).Return(revisionOnDB(2, false, []assignmentEntity.Assignment{
+			{PersonID: 3, FeatureFlagID: 1, Kind: "exclude"},
+		}), nil)
+
+		diff, err := service.DiffRevisions(1, 1, 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), diff.From.ID)
+		assert.Equal(t, uint(2), diff.To.ID)
+		// the version always changes, it is left out
+		assert.Equal(t, []revisionEntity.Change{
+			{Field: "isActive", From: true, To: false},
+			{Field: "assignments.2", From: assignmentEntity.Assignment{PersonID: 2, FeatureFlagID: 1, Kind: "include"}},
+			{Field: "assignments.3", To: assignmentEntity.Assignment{PersonID: 3, FeatureFlagID: 1, Kind: "exclude"}},
+		}, diff.Changes)
+	})
+
+	t.Run("Unknown revision", func(t *testing.T) {
+		service, mockRepo, _ := loadTestService()
+
+		mockRepo.On("GetFeatureFlagRevisions", newest, onePage).Return([]model.FeatureFlagRevision{revisionOnDB(2, false, nil)}, 2, nil)
+		mockRepo.On("GetFeatureFlagRevision", uint(1), uint(9)).Return(model.FeatureFlagRevision{}, errors.New("revision not found"))
+
+		_, err := service.DiffRevisions(1, 1, 9, 2)
+
+		assert.EqualError(t, err, "revision not found")
+	})
+}
+
+// Rollback Revision Tests Cases
+func TestRollbackRevision(t *testing.T) {
+	t.Run("Rolled back in one change based on the current version and recorded once", func(t *testing.T) {
+		service, mockRepo, mockFeatureFlagService := loadTestService()
+
+		revision := revisionOnDB(2, false, []assignmentEntity.Assignment{
+			{PersonID: 3, FeatureFlagID: 1, Kind: "exclude"},
+		})
+		var target revisionEntity.Snapshot
+		_ = json.Unmarshal([]byte(revision.Snapshot), &target)
+
+		mockRepo.On("GetFeatureFlagRevision", uint(1), uint(2)).Return(revision, nil)
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 1, ProjectID: 1, Environment: model.DefaultEnvironment}, onePage).Return([]model.FeatureFlag{featureFlagOnDB}, 1, nil)
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 1, Environment: model.DefaultEnvironment}, onePage).Return([]model.FeatureFlag{featureFlagOnDB}, 1, nil)
+		mockRepo.On("GetAssignmentsByFeatureFlagId", uint(1), model.DefaultEnvironment).Return([]model.Assignment{{PersonID: 2, FeatureFlagID: 1, Kind: "include"}}, nil)
+		mockFeatureFlagService.On("RollbackFeatureFlag", uint(1), featureFlagEntity.RollbackFeatureFlag{
+			FeatureFlag: target.FeatureFlag,
+			Assignments: target.Assignments,
+			Environment: model.DefaultEnvironment,
+			ProjectID:   1,
+			Force:       true,
+			Version:     4,
+		}).Return(nil)
+		mockRepo.On("AddFeatureFlagRevision", mock.MatchedBy(func(revision model.FeatureFlagRevision) bool {
+			return revision.Event == revisionEntity.EventRolledBack && revision.PersonID != nil && *revision.PersonID == 7
+		})).Return(nil).Once()
+
+		err := service.RollbackRevision(1, 2, revisionEntity.Rollback{ProjectID: 1, PersonID: 7, Force: true})
+
+		assert.NoError(t, err)
+		mockFeatureFlagService.AssertExpectations(t)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Revision of an environment only compares the environment", func(t *testing.T) {
+		service, mockRepo, mockFeatureFlagService := loadTestService()
+
+		// the description differs, but it is shared by the environments
+		revision := revisionOnDB(2, true, nil)
+		revision.Environment = "staging"
+		changed := featureFlagOnDB
+		changed.Description = "Changed since"
+
+		mockRepo.On("GetFeatureFlagRevision", uint(1), uint(2)).Return(revision, nil)
+		mockRepo.On("GetFeatureFlag", model.FeatureFlagFilters{ID: 1, ProjectID: 1, Environment: "staging"}, onePage).Return([]model.FeatureFlag{changed}, 1, nil)
+		mockRepo.On("GetAssignmentsByFeatureFlagId", uint(1), "staging").Return([]model.Assignment{}, nil)
+
+		err := service.RollbackRevision(1, 2, revisionEntity.Rollback{ProjectID: 1, PersonID: 7})
+
+		assert.EqualError(t, err, "feature flag already matches the revision")
+		mockFeatureFlagService.AssertNotCalled(t, "RollbackFeatureFlag", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Revision matching the flag is refused", func(t *testing.T) {
+		service, mockRepo, mockFeatureFlagService := loadTestService()
+
+		mockRepo.On("GetFeatureFlagRevision", uint(1), uint(2)).Return(revisionOnDB(2, true, nil), nil)
+		mockRepo.On("GetFeatureFlag", mock.Anything, onePage).Return([]model.FeatureFlag{featureFlagOnDB}, 1, nil)
+		mockRepo.On("GetAssignmentsByFeatureFlagId", uint(1), model.DefaultEnvironment).Return([]model.Assignment{}, nil)
+
+		err := service.RollbackRevision(1, 2, revisionEntity.Rollback{ProjectID: 1, PersonID: 7})
+
+		assert.EqualError(t, err, "feature flag already matches the revision")
+		mockFeatureFlagService.AssertNotCalled(t, "RollbackFeatureFlag", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Archived flag is not rolled back", func(t *testing.T) {
+		service, mockRepo, mockFeatureFlagService := loadTestService()
+
+		archivedAt := time.Now()
+		archived := featureFlagOnDB
+		archived.ArchivedAt = &archivedAt
+
+		mockRepo.On("GetFeatureFlagRevision", uint(1), uint(2)).Return(revisionOnDB(2, false, nil), nil)
+		mockRepo.On("GetFeatureFlag", mock.Anything, onePage).Return([]model.FeatureFlag{archived}, 1, nil)
+		mockRepo.On("GetAssignmentsByFeatureFlagId", uint(1), model.DefaultEnvironment).Return([]model.Assignment{}, nil)
+
+		err := service.RollbackRevision(1, 2, revisionEntity.Rollback{ProjectID: 1, PersonID: 7})
+
+		assert.EqualError(t, err, "feature flag is archived")
+		mockFeatureFlagService.AssertNotCalled(t, "RollbackFeatureFlag", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Rollback refused by the feature flag service is not recorded", func(t *testing.T) {
+		service, mockRepo, mockFeatureFlagService := loadTestService()
+
+		mockRepo.On("GetFeatureFlagRevision", uint(1), uint(2)).Return(revisionOnDB(2, false, nil), nil)
+		mockRepo.On("GetFeatureFlag", mock.Anything, onePage).Return([]model.FeatureFlag{featureFlagOnDB}, 1, nil)
+		mockRepo.On("GetAssignmentsByFeatureFlagId", uint(1), model.DefaultEnvironment).Return([]model.Assignment{}, nil)
+		mockFeatureFlagService.On("RollbackFeatureFlag", uint(1), mock.Anything).Return(errors.New("feature flag is a prerequisite of OTHER_FLAG, force the update to deactivate it"))
+
+		err := service.RollbackRevision(1, 2, revisionEntity.Rollback{ProjectID: 1, PersonID: 7})
+
+		assert.EqualError(t, err, "feature flag is a prerequisite of OTHER_FLAG, force the update to deactivate it")
+		mockRepo.AssertNotCalled(t, "AddFeatureFlagRevision", mock.Anything)
+	})
+}
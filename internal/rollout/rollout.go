@@ -0,0 +1,82 @@
+package rollout
+
+import (
+	"crypto/sha1"
+	"encoding/binary"
+	"strconv"
+
+	"ff/internal/db/model"
+)
+
+// Bucket places a person in one of 100 buckets (0-99) for a given flag. The hash
+// only depends on the flag name and the person id, so a person keeps the same
+// bucket while the rollout percentage grows
+func Bucket(flagName string, personId uint) int {
+	hash := sha1.Sum([]byte(flagName + ":" + strconv.FormatUint(uint64(personId), 10)))
+
+	return int(binary.BigEndian.Uint32(hash[:4]) % 100)
+}
+
+// IsInRollout reports whether the person falls inside the rollout percentage of the flag
+func IsInRollout(flagName string, personId uint, percentage int) bool {
+	if percentage <= 0 || personId == 0 {
+		return false
+	}
+
+	if percentage >= 100 {
+		return true
+	}
+
+	return Bucket(flagName, personId) < percentage
+}
+
+// ChooseVariant returns the pinned variant when it exists, otherwise it picks one
+// by weight with a hash salted differently from Bucket, so being inside a rollout
+// does not bias the variant a person gets
+func ChooseVariant(flagName string, personId uint, variants []model.Variant, pinned string) (model.Variant, bool) {
+	if len(variants) == 0 {
+		return model.Variant{}, false
+	}
+
+	if variant, ok := FindVariant(variants, pinned); ok {
+		return variant, true
+	}
+
+	hash := sha1.Sum([]byte(flagName + ":variant:" + strconv.FormatUint(uint64(personId), 10)))
+	bucket := int(binary.BigEndian.Uint32(hash[:4]) % 100)
+
+	cumulative := 0
+	for _, variant := range variants {
+		cumulative += variant.Weight
+		if bucket < cumulative {
+			return variant, true
+		}
+	}
+
+	return variants[len(variants)-1], true
+}
+
+// FindVariant returns the variant with the given name
+func FindVariant(variants []model.Variant, name string) (model.Variant, bool) {
+	for _, variant := range variants {
+		if variant.Name == name {
+			return variant, true
+		}
+	}
+
+	return model.Variant{}, false
+}
+
+// ServedVariant returns the variant served to a person, the default variant when
+// the flag is off for them. Boolean flags have no variants and return false
+func ServedVariant(flagName, defaultVariant string, variants []model.Variant, personId uint, pinned string, enabled bool) (model.Variant, bool) {
+	if len(variants) == 0 {
+		return model.Variant{}, false
+	}
+
+	if !enabled {
+		return FindVariant(variants, defaultVariant)
+	}
+
+	return ChooseVariant(flagName, personId, variants, pinned)
+}
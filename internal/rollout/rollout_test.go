@@ -0,0 +1,110 @@
+package rollout
+
+import (
+	"testing"
+
+	"ff/internal/db/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucket(t *testing.T) {
+	t.Run("Bucket is deterministic", func(t *testing.T) {
+		assert.Equal(t, Bucket("NEW_CHECKOUT", 42), Bucket("NEW_CHECKOUT", 42))
+	})
+
+	t.Run("Bucket is between 0 and 99", func(t *testing.T) {
+		for personId := uint(1); personId <= 1000; personId++ {
+			bucket := Bucket("NEW_CHECKOUT", personId)
+			assert.GreaterOrEqual(t, bucket, 0)
+			assert.Less(t, bucket, 100)
+		}
+	})
+}
+
+func TestIsInRollout(t *testing.T) {
+	t.Run("Zero and full percentage", func(t *testing.T) {
+		assert.False(t, IsInRollout("NEW_CHECKOUT", 1, 0))
+		assert.True(t, IsInRollout("NEW_CHECKOUT", 1, 100))
+	})
+
+	t.Run("Anonymous person is never in rollout", func(t *testing.T) {
+		assert.False(t, IsInRollout("NEW_CHECKOUT", 0, 100))
+	})
+
+	t.Run("Person stays enabled while percentage grows", func(t *testing.T) {
+		for personId := uint(1); personId <= 1000; personId++ {
+			if IsInRollout("NEW_CHECKOUT", personId, 5) {
+				assert.True(t, IsInRollout("NEW_CHECKOUT", personId, 25))
+			}
+			if IsInRollout("NEW_CHECKOUT", personId, 25) {
+				assert.True(t, IsInRollout("NEW_CHECKOUT", personId, 50))
+			}
+		}
+	})
+
+	t.Run("Percentage is roughly honoured", func(t *testing.T) {
+		enabled := 0
+		for personId := uint(1); personId <= 10000; personId++ {
+			if IsInRollout("NEW_CHECKOUT", personId, 25) {
+				enabled++
+			}
+		}
+		assert.InDelta(t, 2500, enabled, 250)
+	})
+}
+
+func TestChooseVariant(t *testing.T) {
+	variants := []model.Variant{
+		{Name: "control", Value: "control", Weight: 50},
+		{Name: "blue_button", Value: "blue", Weight: 30},
+		{Name: "green_button", Value: "green", Weight: 20},
+	}
+
+	t.Run("Pinned variant wins", func(t *testing.T) {
+		variant, ok := ChooseVariant("NEW_BUTTON", 1, variants, "green_button")
+		assert.True(t, ok)
+		assert.Equal(t, "green_button", variant.Name)
+	})
+
+	t.Run("Unknown pinned variant falls back to weights", func(t *testing.T) {
+		variant, ok := ChooseVariant("NEW_BUTTON", 1, variants, "red_button")
+		assert.True(t, ok)
+		assert.NotEqual(t, "red_button", variant.Name)
+	})
+
+	t.Run("Weights are roughly honoured", func(t *testing.T) {
+		counts := map[string]int{}
+		for personId := uint(1); personId <= 10000; personId++ {
+			variant, _ := ChooseVariant("NEW_BUTTON", personId, variants, "")
+			counts[variant.Name]++
+		}
+		assert.InDelta(t, 5000, counts["control"], 300)
+		assert.InDelta(t, 3000, counts["blue_button"], 300)
+		assert.InDelta(t, 2000, counts["green_button"], 300)
+	})
+
+	t.Run("No variants", func(t *testing.T) {
+		_, ok := ChooseVariant("NEW_BUTTON", 1, nil, "")
+		assert.False(t, ok)
+	})
+}
+
+func TestServedVariant(t *testing.T) {
+	variants := []model.Variant{
+		{Name: "off", Value: "off", Weight: 0},
+		{Name: "on", Value: "on", Weight: 100},
+	}
+
+	t.Run("Default variant when the flag is off", func(t *testing.T) {
+		variant, ok := ServedVariant("NEW_BUTTON", "off", variants, 1, "", false)
+		assert.True(t, ok)
+		assert.Equal(t, "off", variant.Name)
+	})
+
+	t.Run("Weighted variant when the flag is on", func(t *testing.T) {
+		variant, ok := ServedVariant("NEW_BUTTON", "off", variants, 1, "", true)
+		assert.True(t, ok)
+		assert.Equal(t, "on", variant.Name)
+	})
+}
@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"errors"
+	personEntity "ff/internal/person/entity"
+	"time"
+)
+
+const (
+	OperationActivate      = "activate"
+	OperationDeactivate    = "deactivate"
+	OperationSetGlobal     = "set_global"
+	OperationChangeRollout = "change_rollout"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusApplied   = "applied"
+	StatusCancelled = "cancelled"
+	StatusFailed    = "failed"
+)
+
+// ExecuteAtLayout is the same format sent by an HTML datetime-local input,
+// the time is read in the timezone of the scheduled change
+const ExecuteAtLayout = "2006-01-02T15:04"
+
+type ScheduledChange struct {
+	Operation         string `json:"operation"`
+	IsGlobal          bool   `json:"isGlobal"`
+	RolloutPercentage int    `json:"rolloutPercentage"`
+	ExecuteAt         string `json:"executeAt"`
+	Timezone          string `json:"timezone"`
+	Environment       string `json:"-"`
+	ProjectID         uint   `json:"-"`
+	// Force deactivates the flag even when other flags depend on it
+	Force bool `json:"force"`
+}
+
+func (sc *ScheduledChange) Validate() error {
+	if sc.Operation != OperationActivate &&
+		sc.Operation != OperationDeactivate &&
+		sc.Operation != OperationSetGlobal &&
+		sc.Operation != OperationChangeRollout {
+		return errors.New("Operation|Operation must be activate, deactivate, set_global or change_rollout")
+	}
+
+	if sc.Operation == OperationChangeRollout && (sc.RolloutPercentage < 0 || sc.RolloutPercentage > 100) {
+		return errors.New("RolloutPercentage|Rollout percentage must be between 0 and 100")
+	}
+
+	if _, err := sc.ExecuteAtTime(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ExecuteAtTime reads ExecuteAt in the informed timezone (UTC when empty)
+func (sc *ScheduledChange) ExecuteAtTime() (time.Time, error) {
+	if sc.Timezone == "" {
+		sc.Timezone = "UTC"
+	}
+
+	location, err := time.LoadLocation(sc.Timezone)
+	if err != nil {
+		return time.Time{}, errors.New("Timezone|Timezone must be a valid IANA timezone (e.g. America/Sao_Paulo)")
+	}
+
+	executeAt, err := time.ParseInLocation(ExecuteAtLayout, sc.ExecuteAt, location)
+	if err != nil {
+		return time.Time{}, errors.New("ExecuteAt|Execution time must be in YYYY-MM-DDTHH:MM format")
+	}
+
+	return executeAt, nil
+}
+
+type ScheduledChangeFilters struct {
+	Status      string
+	Environment string
+}
+
+type ScheduledChangeResponse struct {
+	ID                uint                        `json:"id"`
+	FeatureFlagID     uint                        `json:"featureFlagId"`
+	Operation         string                      `json:"operation"`
+	IsGlobal          bool                        `json:"isGlobal"`
+	RolloutPercentage int                         `json:"rolloutPercentage"`
+	Force             bool                        `json:"force"`
+	ExecuteAt         string                      `json:"executeAt"`
+	Timezone          string                      `json:"timezone"`
+	Environment       string                      `json:"environment"`
+	Status            string                      `json:"status"`
+	AppliedAt         string                      `json:"appliedAt,omitempty"`
+	ErrorMessage      string                      `json:"errorMessage,omitempty"`
+	CreatedAt         string                      `json:"createdAt"`
+	Person            personEntity.PersonResponse `json:"person"`
+}
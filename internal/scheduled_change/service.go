@@ -0,0 +1,245 @@
+package scheduledchange
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"ff/internal/db/model"
+	featureFlagEntity "ff/internal/feature_flag/entity"
+	personEntity "ff/internal/person/entity"
+	scheduledChangeEntity "ff/internal/scheduled_change/entity"
+
+	"github.com/rs/zerolog"
+)
+
+// claimLease keeps the other replicas away from the changes being applied, a claim left by a
+// stopped worker runs out and the change is picked up again
+const claimLease = 5 * time.Minute
+
+type ScheduledChangeRepository interface {
+	AddScheduledChange(scheduledChange model.ScheduledChange) error
+	GetScheduledChanges(filters model.ScheduledChangeFilters) ([]model.ScheduledChange, error)
+	ClaimDueScheduledChanges(now time.Time, lockedUntil time.Time) ([]model.ScheduledChange, error)
+	CancelScheduledChange(featureFlagId uint, id uint) error
+	UpdateScheduledChangeStatus(id uint, status string, appliedAt *time.Time, errorMessage string) error
+}
+
+// FeatureFlagService is the part of the feature flag service used to apply the changes,
+// so a scheduled change goes through the same validation as a manual update
+type FeatureFlagService interface {
+	GetFeatureFlag(pagination model.Pagination, filters featureFlagEntity.FeatureFlagFilters) ([]featureFlagEntity.FeatureFlagResponse, int64, error)
+	UpdateFeatureFlagById(id uint, request featureFlagEntity.UpdateFeatureFlag) error
+}
+
+type ScheduledChangeService struct {
+	Repository         ScheduledChangeRepository
+	FeatureFlagService FeatureFlagService
+	Logger             *zerolog.Logger
+}
+
+func LoadService(r ScheduledChangeRepository, ffs FeatureFlagService, l *zerolog.Logger) *ScheduledChangeService {
+	return &ScheduledChangeService{
+		Logger:             l,
+		Repository:         r,
+		FeatureFlagService: ffs,
+	}
+}
+
+func (scs *ScheduledChangeService) CreateScheduledChange(featureFlagId uint, request scheduledChangeEntity.ScheduledChange, personId uint) error {
+	scs.Logger.Info().Msg("Creating a new Scheduled Change")
+
+	if err := request.Validate(); err != nil {
+		return errors.New(err.Error())
+	}
+
+	executeAt, _ := request.ExecuteAtTime()
+	if !executeAt.After(time.Now()) {
+		return errors.New("ExecuteAt|Execution time must be in the future")
+	}
+
+	if _, err := scs.getFeatureFlag(featureFlagId, request.ProjectID, request.Environment); err != nil {
+		return err
+	}
+
+	if request.Environment == "" {
+		request.Environment = model.DefaultEnvironment
+	}
+
+	return scs.Repository.AddScheduledChange(model.ScheduledChange{
+		FeatureFlagID:     featureFlagId,
+		Operation:         request.Operation,
+		IsGlobal:          request.IsGlobal,
+		RolloutPercentage: request.RolloutPercentage,
+		Force:             request.Force,
+		ExecuteAt:         executeAt.UTC(),
+		Timezone:          request.Timezone,
+		Environment:       request.Environment,
+		Status:            scheduledChangeEntity.StatusPending,
+		PersonID:          personId,
+	})
+}
+
+func (scs *ScheduledChangeService) GetScheduledChanges(featureFlagId uint, projectId uint, filters scheduledChangeEntity.ScheduledChangeFilters) ([]scheduledChangeEntity.ScheduledChangeResponse, error) {
+	scs.Logger.Info().Msg("Getting Scheduled Changes")
+
+	if _, err := scs.getFeatureFlag(featureFlagId, projectId, filters.Environment); err != nil {
+		return nil, err
+	}
+
+	scheduledChanges, err := scs.Repository.GetScheduledChanges(model.ScheduledChangeFilters{
+		FeatureFlagID: featureFlagId,
+		Status:        filters.Status,
+		Environment:   filters.Environment,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var scheduledChangeResponses []scheduledChangeEntity.ScheduledChangeResponse
+	for _, scDB := range scheduledChanges {
+		response := scheduledChangeEntity.ScheduledChangeResponse{
+			ID:                scDB.ID,
+			FeatureFlagID:     scDB.FeatureFlagID,
+			Operation:         scDB.Operation,
+			IsGlobal:          scDB.IsGlobal,
+			RolloutPercentage: scDB.RolloutPercentage,
+			Force:             scDB.Force,
+			ExecuteAt:         inTimezone(scDB.ExecuteAt, scDB.Timezone).Format(scheduledChangeEntity.ExecuteAtLayout),
+			Timezone:          scDB.Timezone,
+			Environment:       scDB.Environment,
+			Status:            scDB.Status,
+			ErrorMessage:      scDB.ErrorMessage,
+			CreatedAt:         scDB.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+
+		if scDB.AppliedAt != nil {
+			response.AppliedAt = scDB.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+
+		if scDB.Person != nil {
+			response.Person = personEntity.PersonResponse{
+				ID:    scDB.Person.ID,
+				Name:  scDB.Person.Name,
+				Email: scDB.Person.Email,
+			}
+		}
+
+		scheduledChangeResponses = append(scheduledChangeResponses, response)
+	}
+
+	return scheduledChangeResponses, nil
+}
+
+func (scs *ScheduledChangeService) CancelScheduledChange(featureFlagId uint, projectId uint, id uint) error {
+	scs.Logger.Info().Msg("Cancelling a Scheduled Change")
+
+	if _, err := scs.getFeatureFlag(featureFlagId, projectId, ""); err != nil {
+		return err
+	}
+
+	return scs.Repository.CancelScheduledChange(featureFlagId, id)
+}
+
+// ApplyScheduledChanges applies every pending change due at now, in execution order.
+// A change that cannot be applied is marked as failed and does not stop the others,
+// it returns how many changes were applied
+func (scs *ScheduledChangeService) ApplyScheduledChanges(now time.Time) (int, error) {
+	scs.Logger.Info().Msg("Applying Scheduled Changes")
+
+	// the changes are applied one by one, two changes of the same flag keep their order
+	scheduledChanges, err := scs.Repository.ClaimDueScheduledChanges(now, now.Add(claimLease))
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, scheduledChange := range scheduledChanges {
+		if err := scs.applyScheduledChange(scheduledChange); err != nil {
+			scs.Logger.Error().Err(err).Uint("id", scheduledChange.ID).Str("operation", scheduledChange.Operation).Msg("Scheduled change failed")
+
+			if err := scs.Repository.UpdateScheduledChangeStatus(scheduledChange.ID, scheduledChangeEntity.StatusFailed, nil, err.Error()); err != nil {
+				return applied, err
+			}
+			continue
+		}
+
+		appliedAt := now
+		if err := scs.Repository.UpdateScheduledChangeStatus(scheduledChange.ID, scheduledChangeEntity.StatusApplied, &appliedAt, ""); err != nil {
+			return applied, err
+		}
+		applied++
+	}
+
+	return applied, nil
+}
+
+func (scs *ScheduledChangeService) applyScheduledChange(scheduledChange model.ScheduledChange) error {
+	featureFlag, err := scs.getFeatureFlag(scheduledChange.FeatureFlagID, 0, scheduledChange.Environment)
+	if err != nil {
+		return err
+	}
+
+	request := featureFlagEntity.UpdateFeatureFlag{
+		Description:       featureFlag.Description,
+		IsActive:          featureFlag.IsActive,
+		IsGlobal:          featureFlag.IsGlobal,
+		ExpirationDate:    featureFlag.ExpirationDate,
+		RolloutPercentage: featureFlag.RolloutPercentage,
+		Environment:       scheduledChange.Environment,
+		Force:             scheduledChange.Force,
+		// a change made between the read above and the update is not overwritten
+		Version: featureFlag.Version,
+		// the revision is kept with the person who scheduled the change
+		PersonID: scheduledChange.PersonID,
+	}
+
+	switch scheduledChange.Operation {
+	case scheduledChangeEntity.OperationActivate:
+		request.IsActive = true
+	case scheduledChangeEntity.OperationDeactivate:
+		request.IsActive = false
+	case scheduledChangeEntity.OperationSetGlobal:
+		request.IsGlobal = scheduledChange.IsGlobal
+	case scheduledChangeEntity.OperationChangeRollout:
+		request.RolloutPercentage = scheduledChange.RolloutPercentage
+	default:
+		return fmt.Errorf("unknown operation %s", scheduledChange.Operation)
+	}
+
+	// the flag may already be in the scheduled state, nothing to update is not a failure
+	if err := scs.FeatureFlagService.UpdateFeatureFlagById(scheduledChange.FeatureFlagID, request); err != nil && err.Error() != "no feature flag updated" {
+		return err
+	}
+
+	return nil
+}
+
+func (scs *ScheduledChangeService) getFeatureFlag(featureFlagId uint, projectId uint, environment string) (featureFlagEntity.FeatureFlagResponse, error) {
+	featureFlags, countTotal, err := scs.FeatureFlagService.GetFeatureFlag(model.Pagination{
+		Limit: 1,
+		Page:  1,
+	}, featureFlagEntity.FeatureFlagFilters{
+		ID:          featureFlagId,
+		ProjectID:   projectId,
+		Environment: environment,
+	})
+	if err != nil {
+		return featureFlagEntity.FeatureFlagResponse{}, err
+	}
+
+	if countTotal == 0 || len(featureFlags) == 0 {
+		return featureFlagEntity.FeatureFlagResponse{}, errors.New("feature flag not found")
+	}
+
+	return featureFlags[0], nil
+}
+
+func inTimezone(t time.Time, timezone string) time.Time {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return t.UTC()
+	}
+
+	return t.In(location)
+}
@@ -0,0 +1,285 @@
+package scheduledchange
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"ff/internal/db/model"
+	featureFlagEntity "ff/internal/feature_flag/entity"
+	scheduledChangeEntity "ff/internal/scheduled_change/entity"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository is a mock of ScheduledChangeRepository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) AddScheduledChange(scheduledChange model.ScheduledChange) error {
+	args := m.Called(scheduledChange)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetScheduledChanges(filters model.ScheduledChangeFilters) ([]model.ScheduledChange, error) {
+	args := m.Called(filters)
+	return args.Get(0).([]model.ScheduledChange), args.Error(1)
+}
+
+func (m *MockRepository) ClaimDueScheduledChanges(now time.Time, lockedUntil time.Time) ([]model.ScheduledChange, error) {
+	args := m.Called(now, lockedUntil)
+	return args.Get(0).([]model.ScheduledChange), args.Error(1)
+}
+
+func (m *MockRepository) CancelScheduledChange(featureFlagId uint, id uint) error {
+	args := m.Called(featureFlagId, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateScheduledChangeStatus(id uint, status string, appliedAt *time.Time, errorMessage string) error {
+	args := m.Called(id, status, appliedAt, errorMessage)
+	return args.Error(0)
+}
+
+// MockFeatureFlagService is a mock of FeatureFlagService
+type MockFeatureFlagService struct {
+	mock.Mock
+}
+
+func (m *MockFeatureFlagService) GetFeatureFlag(pagination model.Pagination, filters featureFlagEntity.FeatureFlagFilters) ([]featureFlagEntity.FeatureFlagResponse, int64, error) {
+	args := m.Called(pagination, filters)
+	return args.Get(0).([]featureFlagEntity.FeatureFlagResponse), int64(args.Get(1).(int)), args.Error(2)
+}
+
+func (m *MockFeatureFlagService) UpdateFeatureFlagById(id uint, request featureFlagEntity.UpdateFeatureFlag) error {
+	args := m.Called(id, request)
+	return args.Error(0)
+}
+
+var featureFlagOnDB = featureFlagEntity.FeatureFlagResponse{
+	ID:                "1",
+	Name:              "TEST_FLAG",
+	Description:       "Test Description",
+	IsActive:          false,
+	IsGlobal:          false,
+	ExpirationDate:    "2030-01-01",
+	RolloutPercentage: 10,
+	Version:           4,
+}
+
+func loadTestService() (*ScheduledChangeService, *MockRepository, *MockFeatureFlagService) {
+	mockRepo := new(MockRepository)
+	mockFeatureFlagService := new(MockFeatureFlagService)
+	logger := zerolog.New(os.Stdout)
+
+	return LoadService(mockRepo, mockFeatureFlagService, &logger), mockRepo, mockFeatureFlagService
+}
+
+// Create Scheduled Change Tests Cases
+func TestCreateScheduledChange(t *testing.T) {
+	t.Run("Store the execution time in UTC", func(t *testing.T) {
+		service, mockRepo, mockFeatureFlagService := loadTestService()
+
+		mockFeatureFlagService.On("GetFeatureFlag", mock.Anything, featureFlagEntity.FeatureFlagFilters{ID: 1}).Return([]featureFlagEntity.FeatureFlagResponse{featureFlagOnDB}, 1, nil)
+		mockRepo.On("AddScheduledChange", mock.MatchedBy(func(sc model.ScheduledChange) bool {
+			return sc.ExecuteAt.Equal(time.Date(2099, 1, 1, 3, 0, 0, 0, time.UTC)) &&
+				sc.Timezone == "America/Sao_Paulo" &&
+				sc.Status == scheduledChangeEntity.StatusPending &&
+				sc.PersonID == 7
+		})).Return(nil)
+
+		err := service.CreateScheduledChange(1, scheduledChangeEntity.ScheduledChange{
+			Operation: scheduledChangeEntity.OperationActivate,
+			ExecuteAt: "2099-01-01T00:00",
+			Timezone:  "America/Sao_Paulo",
+		}, 7)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	testCases := []struct {
+		name          string
+		request       scheduledChangeEntity.ScheduledChange
+		expectedError string
+	}{
+		{
+			name:          "Unknown operation",
+			request:       scheduledChangeEntity.ScheduledChange{Operation: "delete", ExecuteAt: "2099-01-01T00:00"},
+			expectedError: "Operation|Operation must be activate, deactivate, set_global or change_rollout",
+		},
+		{
+			name:          "Invalid rollout percentage",
+			request:       scheduledChangeEntity.ScheduledChange{Operation: scheduledChangeEntity.OperationChangeRollout, RolloutPercentage: 101, ExecuteAt: "2099-01-01T00:00"},
+			expectedError: "RolloutPercentage|Rollout percentage must be between 0 and 100",
+		},
+		{
+			name:          "Invalid timezone",
+			request:       scheduledChangeEntity.ScheduledChange{Operation: scheduledChangeEntity.OperationActivate, ExecuteAt: "2099-01-01T00:00", Timezone: "Mars/Olympus"},
+			expectedError: "Timezone|Timezone must be a valid IANA timezone (e.g. America/Sao_Paulo)",
+		},
+		{
+			name:          "Invalid execution time",
+			request:       scheduledChangeEntity.ScheduledChange{Operation: scheduledChangeEntity.OperationActivate, ExecuteAt: "01/01/2099"},
+			expectedError: "ExecuteAt|Execution time must be in YYYY-MM-DDTHH:MM format",
+		},
+		{
+			name:          "Execution time in the past",
+			request:       scheduledChangeEntity.ScheduledChange{Operation: scheduledChangeEntity.OperationActivate, ExecuteAt: "2000-01-01T00:00"},
+			expectedError: "ExecuteAt|Execution time must be in the future",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, mockRepo, _ := loadTestService()
+
+			err := service.CreateScheduledChange(1, tc.request, 7)
+
+			assert.EqualError(t, err, tc.expectedError)
+			mockRepo.AssertNotCalled(t, "AddScheduledChange", mock.Anything)
+		})
+	}
+
+	t.Run("Feature flag not found", func(t *testing.T) {
+		service, mockRepo, mockFeatureFlagService := loadTestService()
+
+		mockFeatureFlagService.On("GetFeatureFlag", mock.Anything, featureFlagEntity.FeatureFlagFilters{ID: 2}).Return([]featureFlagEntity.FeatureFlagResponse{}, 0, nil)
+
+		err := service.CreateScheduledChange(2, scheduledChangeEntity.ScheduledChange{
+			Operation: scheduledChangeEntity.OperationActivate,
+			ExecuteAt: "2099-01-01T00:00",
+		}, 7)
+
+		assert.EqualError(t, err, "feature flag not found")
+		mockRepo.AssertNotCalled(t, "AddScheduledChange", mock.Anything)
+	})
+}
+
+// Get Scheduled Changes Tests Cases
+func TestGetScheduledChanges(t *testing.T) {
+	t.Run("Scoped to the project of the flag", func(t *testing.T) {
+		service, mockRepo, mockFeatureFlagService := loadTestService()
+
+		mockFeatureFlagService.On("GetFeatureFlag", mock.Anything, featureFlagEntity.FeatureFlagFilters{ID: 1, ProjectID: 3}).Return([]featureFlagEntity.FeatureFlagResponse{featureFlagOnDB}, 1, nil)
+		mockRepo.On("GetScheduledChanges", model.ScheduledChangeFilters{FeatureFlagID: 1, Status: scheduledChangeEntity.StatusPending}).Return([]model.ScheduledChange{
+			{ID: 1, FeatureFlagID: 1, Operation: scheduledChangeEntity.OperationActivate, Timezone: "UTC"},
+		}, nil)
+
+		scheduledChanges, err := service.GetScheduledChanges(1, 3, scheduledChangeEntity.ScheduledChangeFilters{Status: scheduledChangeEntity.StatusPending})
+
+		assert.NoError(t, err)
+		assert.Len(t, scheduledChanges, 1)
+	})
+
+	t.Run("Feature flag of another project", func(t *testing.T) {
+		service, mockRepo, mockFeatureFlagService := loadTestService()
+
+		mockFeatureFlagService.On("GetFeatureFlag", mock.Anything, featureFlagEntity.FeatureFlagFilters{ID: 1, ProjectID: 4}).Return([]featureFlagEntity.FeatureFlagResponse{}, 0, nil)
+
+		_, err := service.GetScheduledChanges(1, 4, scheduledChangeEntity.ScheduledChangeFilters{})
+
+		assert.EqualError(t, err, "feature flag not found")
+		mockRepo.AssertNotCalled(t, "GetScheduledChanges", mock.Anything)
+	})
+}
+
+// Cancel Scheduled Change Tests Cases
+func TestCancelScheduledChange(t *testing.T) {
+	t.Run("Cancel a pending change", func(t *testing.T) {
+		service, mockRepo, mockFeatureFlagService := loadTestService()
+
+		mockFeatureFlagService.On("GetFeatureFlag", mock.Anything, featureFlagEntity.FeatureFlagFilters{ID: 1, ProjectID: 3}).Return([]featureFlagEntity.FeatureFlagResponse{featureFlagOnDB}, 1, nil)
+		mockRepo.On("CancelScheduledChange", uint(1), uint(5)).Return(nil)
+
+		err := service.CancelScheduledChange(1, 3, 5)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Feature flag of another project", func(t *testing.T) {
+		service, mockRepo, mockFeatureFlagService := loadTestService()
+
+		mockFeatureFlagService.On("GetFeatureFlag", mock.Anything, featureFlagEntity.FeatureFlagFilters{ID: 1, ProjectID: 4}).Return([]featureFlagEntity.FeatureFlagResponse{}, 0, nil)
+
+		err := service.CancelScheduledChange(1, 4, 5)
+
+		assert.EqualError(t, err, "feature flag not found")
+		mockRepo.AssertNotCalled(t, "CancelScheduledChange", mock.Anything, mock.Anything)
+	})
+}
+
+// Apply Scheduled Changes Tests Cases
+func TestApplyScheduledChanges(t *testing.T) {
+	now := time.Date(2024, 10, 10, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name            string
+		scheduledChange model.ScheduledChange
+		expectedUpdate  featureFlagEntity.UpdateFeatureFlag
+	}{
+		{
+			name:            "Activate",
+			scheduledChange: model.ScheduledChange{ID: 1, FeatureFlagID: 1, Operation: scheduledChangeEntity.OperationActivate},
+			expectedUpdate:  featureFlagEntity.UpdateFeatureFlag{Version: 4, Description: "Test Description", IsActive: true, ExpirationDate: "2030-01-01", RolloutPercentage: 10},
+		},
+		{
+			name:            "Set global",
+			scheduledChange: model.ScheduledChange{ID: 1, FeatureFlagID: 1, Operation: scheduledChangeEntity.OperationSetGlobal, IsGlobal: true},
+			expectedUpdate:  featureFlagEntity.UpdateFeatureFlag{Version: 4, Description: "Test Description", IsGlobal: true, ExpirationDate: "2030-01-01", RolloutPercentage: 10},
+		},
+		{
+			name:            "Change rollout",
+			scheduledChange: model.ScheduledChange{ID: 1, FeatureFlagID: 1, Operation: scheduledChangeEntity.OperationChangeRollout, RolloutPercentage: 50},
+			expectedUpdate:  featureFlagEntity.UpdateFeatureFlag{Version: 4, Description: "Test Description", ExpirationDate: "2030-01-01", RolloutPercentage: 50},
+		},
+		{
+			name:            "Forced deactivate",
+			scheduledChange: model.ScheduledChange{ID: 1, FeatureFlagID: 1, Operation: scheduledChangeEntity.OperationDeactivate, Force: true},
+			expectedUpdate:  featureFlagEntity.UpdateFeatureFlag{Version: 4, Description: "Test Description", ExpirationDate: "2030-01-01", RolloutPercentage: 10, Force: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, mockRepo, mockFeatureFlagService := loadTestService()
+
+			mockRepo.On("ClaimDueScheduledChanges", now, now.Add(claimLease)).Return([]model.ScheduledChange{tc.scheduledChange}, nil)
+			mockFeatureFlagService.On("GetFeatureFlag", mock.Anything, featureFlagEntity.FeatureFlagFilters{ID: 1}).Return([]featureFlagEntity.FeatureFlagResponse{featureFlagOnDB}, 1, nil)
+			mockFeatureFlagService.On("UpdateFeatureFlagById", uint(1), tc.expectedUpdate).Return(nil)
+			mockRepo.On("UpdateScheduledChangeStatus", uint(1), scheduledChangeEntity.StatusApplied, &now, "").Return(nil)
+
+			applied, err := service.ApplyScheduledChanges(now)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, applied)
+			mockRepo.AssertExpectations(t)
+			mockFeatureFlagService.AssertExpectations(t)
+		})
+	}
+
+	t.Run("A failing change is marked as failed and the others are applied", func(t *testing.T) {
+		service, mockRepo, mockFeatureFlagService := loadTestService()
+
+		mockRepo.On("ClaimDueScheduledChanges", now, now.Add(claimLease)).Return([]model.ScheduledChange{
+			{ID: 1, FeatureFlagID: 2, Operation: scheduledChangeEntity.OperationActivate},
+			{ID: 2, FeatureFlagID: 1, Operation: scheduledChangeEntity.OperationDeactivate},
+		}, nil)
+		mockFeatureFlagService.On("GetFeatureFlag", mock.Anything, featureFlagEntity.FeatureFlagFilters{ID: 2}).Return([]featureFlagEntity.FeatureFlagResponse{}, 0, nil)
+		mockFeatureFlagService.On("GetFeatureFlag", mock.Anything, featureFlagEntity.FeatureFlagFilters{ID: 1}).Return([]featureFlagEntity.FeatureFlagResponse{featureFlagOnDB}, 1, nil)
+		mockFeatureFlagService.On("UpdateFeatureFlagById", uint(1), mock.Anything).Return(errors.New("no feature flag updated"))
+		mockRepo.On("UpdateScheduledChangeStatus", uint(1), scheduledChangeEntity.StatusFailed, (*time.Time)(nil), "feature flag not found").Return(nil)
+		mockRepo.On("UpdateScheduledChangeStatus", uint(2), scheduledChangeEntity.StatusApplied, &now, "").Return(nil)
+
+		applied, err := service.ApplyScheduledChanges(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, applied)
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,34 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type Job func(now time.Time) error
+
+// Every runs the job right away and then on every interval until the context is done,
+// a failing run is logged and retried on the next tick
+func Every(ctx context.Context, interval time.Duration, name string, logger *zerolog.Logger, job Job) {
+	run := func(now time.Time) {
+		if err := job(now); err != nil {
+			logger.Error().Err(err).Str("job", name).Msg("Scheduled job failed")
+		}
+	}
+
+	run(time.Now())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			run(now)
+		}
+	}
+}
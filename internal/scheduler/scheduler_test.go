@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvery(t *testing.T) {
+	t.Run("Runs right away and on every tick until cancelled", func(t *testing.T) {
+		logger := zerolog.New(os.Stdout)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var runs int32
+		done := make(chan struct{})
+		go func() {
+			Every(ctx, 10*time.Millisecond, "test", &logger, func(now time.Time) error {
+				atomic.AddInt32(&runs, 1)
+				return errors.New("failing runs are retried")
+			})
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&runs) >= 3 }, time.Second, 5*time.Millisecond)
+
+		cancel()
+		<-done
+	})
+}
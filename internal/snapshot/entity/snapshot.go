@@ -0,0 +1,36 @@
+package entity
+
+import ff_entity "ff/internal/feature_flag/entity"
+
+// Snapshot is every flag of a project in an environment, with its targeting, in one document.
+// The version grows on every change, a snapshot with the same version has the same flags
+type Snapshot struct {
+	Version      uint          `json:"version"`
+	ProjectID    uint          `json:"projectId"`
+	Environment  string        `json:"environment"`
+	FeatureFlags []FeatureFlag `json:"featureFlags"`
+}
+
+type FeatureFlag struct {
+	ID                uint                     `json:"id"`
+	ProjectID         uint                     `json:"projectId"`
+	Name              string                   `json:"name"`
+	IsActive          bool                     `json:"isActive"`
+	IsGlobal          bool                     `json:"isGlobal"`
+	ExpirationDate    string                   `json:"expirationDate"`
+	RolloutPercentage int                      `json:"rolloutPercentage"`
+	Type              string                   `json:"type"`
+	DefaultVariant    string                   `json:"defaultVariant"`
+	Rules             []ff_entity.Rule         `json:"rules"`
+	Variants          []ff_entity.Variant      `json:"variants"`
+	Prerequisites     []ff_entity.Prerequisite `json:"prerequisites"`
+	Assignments       []Assignment             `json:"assignments"`
+	// SegmentPersonIDs are the people enabled through one of their segments
+	SegmentPersonIDs []uint `json:"segmentPersonIds"`
+}
+
+type Assignment struct {
+	PersonID uint   `json:"personId"`
+	Kind     string `json:"kind"`
+	Variant  string `json:"variant,omitempty"`
+}
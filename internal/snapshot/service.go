@@ -0,0 +1,128 @@
+package snapshot
+
+import (
+	"errors"
+
+	"ff/internal/db/model"
+	featureflag "ff/internal/feature_flag"
+	snapshotEntity "ff/internal/snapshot/entity"
+
+	"github.com/rs/zerolog"
+)
+
+type SnapshotRepository interface {
+	GetFeatureFlagSnapshot(projectId uint, environment string) (model.FeatureFlagSnapshot, error)
+	GetFeatureFlagSnapshotById(featureFlagId uint, environment string) (model.FeatureFlagSnapshot, error)
+	GetSnapshotVersion(projectId uint, environment string) (uint, error)
+}
+
+type SnapshotService struct {
+	Repository SnapshotRepository
+	Logger     *zerolog.Logger
+}
+
+func LoadService(r SnapshotRepository, l *zerolog.Logger) *SnapshotService {
+	return &SnapshotService{
+		Logger:     l,
+		Repository: r,
+	}
+}
+
+func (ss *SnapshotService) GetSnapshot(projectId uint, environment string) (snapshotEntity.Snapshot, error) {
+	ss.Logger.Info().Msg("Getting Feature Flag snapshot")
+
+	if environment == "" {
+		environment = model.DefaultEnvironment
+	}
+
+	// read before the flags, a change in between is served with the older version
+	// and the next poll fetches it again
+	version, err := ss.Repository.GetSnapshotVersion(projectId, environment)
+	if err != nil {
+		return snapshotEntity.Snapshot{}, err
+	}
+
+	featureFlagSnapshot, err := ss.Repository.GetFeatureFlagSnapshot(projectId, environment)
+	if err != nil {
+		return snapshotEntity.Snapshot{}, err
+	}
+
+	snapshot := snapshotFromModel(projectId, environment, featureFlagSnapshot)
+	snapshot.Version = version
+
+	return snapshot, nil
+}
+
+// GetSnapshotVersion is the version the next snapshot of the project in the environment would
+// have, it is cheap enough to check on every poll before building the snapshot
+func (ss *SnapshotService) GetSnapshotVersion(projectId uint, environment string) (uint, error) {
+	if environment == "" {
+		environment = model.DefaultEnvironment
+	}
+
+	return ss.Repository.GetSnapshotVersion(projectId, environment)
+}
+
+// GetFeatureFlagSnapshot returns a single flag as it is in the snapshot of the environment
+func (ss *SnapshotService) GetFeatureFlagSnapshot(featureFlagId uint, environment string) (snapshotEntity.FeatureFlag, error) {
+	ss.Logger.Info().Msg("Getting Feature Flag snapshot by id")
+
+	if environment == "" {
+		environment = model.DefaultEnvironment
+	}
+
+	featureFlagSnapshot, err := ss.Repository.GetFeatureFlagSnapshotById(featureFlagId, environment)
+	if err != nil {
+		return snapshotEntity.FeatureFlag{}, err
+	}
+
+	if len(featureFlagSnapshot.FeatureFlags) == 0 {
+		return snapshotEntity.FeatureFlag{}, errors.New("feature flag not found")
+	}
+
+	snapshot := snapshotFromModel(featureFlagSnapshot.FeatureFlags[0].ProjectID, environment, featureFlagSnapshot)
+
+	return snapshot.FeatureFlags[0], nil
+}
+
+func snapshotFromModel(projectId uint, environment string, featureFlagSnapshot model.FeatureFlagSnapshot) snapshotEntity.Snapshot {
+	assignments := make(map[uint][]snapshotEntity.Assignment)
+	for _, assignment := range featureFlagSnapshot.Assignments {
+		assignments[assignment.FeatureFlagID] = append(assignments[assignment.FeatureFlagID], snapshotEntity.Assignment{
+			PersonID: assignment.PersonID,
+			Kind:     assignment.Kind,
+			Variant:  assignment.Variant,
+		})
+	}
+
+	segmentPersonIds := make(map[uint][]uint)
+	for _, member := range featureFlagSnapshot.SegmentMembers {
+		segmentPersonIds[member.FeatureFlagID] = append(segmentPersonIds[member.FeatureFlagID], member.PersonID)
+	}
+
+	snapshot := snapshotEntity.Snapshot{
+		ProjectID:    projectId,
+		Environment:  environment,
+		FeatureFlags: []snapshotEntity.FeatureFlag{},
+	}
+	for _, ffDB := range featureFlagSnapshot.FeatureFlags {
+		snapshot.FeatureFlags = append(snapshot.FeatureFlags, snapshotEntity.FeatureFlag{
+			ID:                ffDB.ID,
+			ProjectID:         ffDB.ProjectID,
+			Name:              ffDB.Name,
+			IsActive:          ffDB.IsActive,
+			IsGlobal:          ffDB.IsGlobal,
+			ExpirationDate:    ffDB.ExpirationDate,
+			RolloutPercentage: ffDB.RolloutPercentage,
+			Type:              ffDB.Type,
+			DefaultVariant:    ffDB.DefaultVariant,
+			Rules:             featureflag.RulesFromModel(ffDB.Rules),
+			Variants:          featureflag.VariantsFromModel(ffDB.Variants),
+			Prerequisites:     featureflag.PrerequisitesFromModel(ffDB.Prerequisites),
+			Assignments:       assignments[ffDB.ID],
+			SegmentPersonIDs:  segmentPersonIds[ffDB.ID],
+		})
+	}
+
+	return snapshot
+}
@@ -0,0 +1,113 @@
+package snapshot
+
+import (
+	"ff/internal/db/model"
+	featureflag "ff/internal/feature_flag"
+	snapshotEntity "ff/internal/snapshot/entity"
+)
+
+// Store answers the lookups of an evaluation from a snapshot kept in memory, so flags can
+// be evaluated away from the database with the same semantics as the server. The project
+// and environment arguments are ignored, a snapshot already belongs to one of each
+type Store struct {
+	snapshot     snapshotEntity.Snapshot
+	featureFlags []model.FeatureFlag
+	byName       map[string]int
+}
+
+func NewStore(snapshot snapshotEntity.Snapshot) *Store {
+	store := &Store{
+		snapshot: snapshot,
+		byName:   make(map[string]int, len(snapshot.FeatureFlags)),
+	}
+
+	ids := make(map[string]uint, len(snapshot.FeatureFlags))
+	for _, featureFlag := range snapshot.FeatureFlags {
+		ids[featureFlag.Name] = featureFlag.ID
+	}
+
+	for i, featureFlag := range snapshot.FeatureFlags {
+		var prerequisites []model.Prerequisite
+		for position, prerequisite := range featureFlag.Prerequisites {
+			prerequisites = append(prerequisites, model.Prerequisite{
+				FeatureFlagID:  featureFlag.ID,
+				PrerequisiteID: ids[prerequisite.FlagName],
+				Prerequisite:   &model.FeatureFlag{ID: ids[prerequisite.FlagName], Name: prerequisite.FlagName},
+				Position:       position,
+				Variant:        prerequisite.Variant,
+			})
+		}
+
+		store.byName[featureFlag.Name] = i
+		store.featureFlags = append(store.featureFlags, model.FeatureFlag{
+			ID:                featureFlag.ID,
+			Name:              featureFlag.Name,
+			IsActive:          featureFlag.IsActive,
+			IsGlobal:          featureFlag.IsGlobal,
+			ExpirationDate:    featureFlag.ExpirationDate,
+			RolloutPercentage: featureFlag.RolloutPercentage,
+			Type:              featureFlag.Type,
+			DefaultVariant:    featureFlag.DefaultVariant,
+			ProjectID:         snapshot.ProjectID,
+			Rules:             featureflag.RulesToModel(featureFlag.Rules),
+			Variants:          featureflag.VariantsToModel(featureFlag.Variants),
+			Prerequisites:     prerequisites,
+		})
+	}
+
+	return store
+}
+
+// Snapshot returns the snapshot the store was built from
+func (s *Store) Snapshot() snapshotEntity.Snapshot {
+	return s.snapshot
+}
+
+// GetFeatureFlagByName returns an empty flag when the flag is not in the snapshot
+func (s *Store) GetFeatureFlagByName(projectId uint, name string, environment string) (model.FeatureFlag, error) {
+	i, ok := s.byName[name]
+	if !ok {
+		return model.FeatureFlag{}, nil
+	}
+
+	return s.featureFlags[i], nil
+}
+
+// GetAssignedFeatureFlagsByPersonId reports every flag with the assignments of the person,
+// as the repository does
+func (s *Store) GetAssignedFeatureFlagsByPersonId(id uint, projectId uint, environment string) ([]model.AssignedFeatureFlag, error) {
+	var featureFlags []model.AssignedFeatureFlag
+	for i, featureFlag := range s.snapshot.FeatureFlags {
+		assigned := model.AssignedFeatureFlag{
+			ID:                featureFlag.ID,
+			Name:              featureFlag.Name,
+			IsActive:          featureFlag.IsActive,
+			IsGlobal:          featureFlag.IsGlobal,
+			ExpirationDate:    featureFlag.ExpirationDate,
+			RolloutPercentage: featureFlag.RolloutPercentage,
+			Type:              featureFlag.Type,
+			DefaultVariant:    featureFlag.DefaultVariant,
+			Variants:          s.featureFlags[i].Variants,
+		}
+
+		for _, assignment := range featureFlag.Assignments {
+			if assignment.PersonID != id {
+				continue
+			}
+
+			assigned.IsAssigned = assignment.Kind != model.AssignmentExclude
+			assigned.IsExcluded = assignment.Kind == model.AssignmentExclude
+			assigned.AssignedVariant = assignment.Variant
+		}
+
+		for _, personId := range featureFlag.SegmentPersonIDs {
+			if personId == id {
+				assigned.IsAssignedViaSegment = true
+			}
+		}
+
+		featureFlags = append(featureFlags, assigned)
+	}
+
+	return featureFlags, nil
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	snapshotEntity "ff/internal/snapshot/entity"
+)
+
+const (
+	EventFeatureFlagCreated = "feature_flag.created"
+	EventFeatureFlagUpdated = "feature_flag.updated"
+	EventAssignmentApplied  = "assignment.applied"
+	EventAssignmentDeleted  = "assignment.deleted"
+	// EventFeatureFlagArchived and EventFeatureFlagDeleted remove the flag from the snapshot,
+	// the client drops it from its cache, a restored flag comes back as a new one
+	EventFeatureFlagArchived = "feature_flag.archived"
+	EventFeatureFlagRestored = "feature_flag.restored"
+	EventFeatureFlagDeleted  = "feature_flag.deleted"
+	// EventReset tells the client that the events it missed are gone, it has to fetch the snapshot again
+	EventReset = "reset"
+)
+
+// Event carries the whole flag after the change, a client replaces the flag in its
+// cache with it instead of fetching it again
+type Event struct {
+	ID          uint                        `json:"id"`
+	Type        string                      `json:"type"`
+	ProjectID   uint                        `json:"projectId"`
+	Environment string                      `json:"environment"`
+	FeatureFlag *snapshotEntity.FeatureFlag `json:"featureFlag,omitempty"`
+	CreatedAt   string                      `json:"createdAt"`
+}
@@ -0,0 +1,373 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"ff/internal/db/model"
+	environmentEntity "ff/internal/environment/entity"
+	snapshotEntity "ff/internal/snapshot/entity"
+	streamEntity "ff/internal/stream/entity"
+
+	"github.com/rs/zerolog"
+)
+
+// replayLimit is how many missed events are replayed to a reconnecting client,
+// a client further behind is told to fetch the snapshot again
+const replayLimit = 500
+
+// subscriptionBuffer is how many events a subscription holds before it is dropped as too slow
+const subscriptionBuffer = 64
+
+// dispatchWindow is how many ids behind the newest event are read again on every dispatch, the
+// ids are given when the event is stored so an event committed after a newer one is still sent
+const dispatchWindow = 100
+
+type StreamRepository interface {
+	AddFlagEvent(event model.FlagEvent) error
+	GetFlagEvents(afterId uint, limit int) ([]model.FlagEvent, error)
+	GetFlagEventBounds() (uint, uint, error)
+	DeleteFlagEventsBefore(date time.Time) error
+}
+
+type SnapshotService interface {
+	GetFeatureFlagSnapshot(featureFlagId uint, environment string) (snapshotEntity.FeatureFlag, error)
+}
+
+type EnvironmentService interface {
+	GetEnvironments() ([]environmentEntity.EnvironmentResponse, error)
+}
+
+// Subscription receives the events of a project in an environment, the channel is
+// closed when the subscription is dropped
+type Subscription struct {
+	ProjectID   uint
+	Environment string
+	events      chan streamEntity.Event
+}
+
+func (s *Subscription) Events() <-chan streamEntity.Event {
+	return s.events
+}
+
+func (s *Subscription) matches(event streamEntity.Event) bool {
+	return (s.ProjectID == 0 || s.ProjectID == event.ProjectID) && s.Environment == event.Environment
+}
+
+// StreamService stores every flag change as an event and fans the events out to the
+// subscriptions. Events go through the database so changes made by other processes
+// (the web app) reach the subscriptions too, and the event id is a resumable cursor
+type StreamService struct {
+	Repository         StreamRepository
+	SnapshotService    SnapshotService
+	EnvironmentService EnvironmentService
+	Logger             *zerolog.Logger
+
+	mu            sync.Mutex
+	subscriptions map[*Subscription]struct{}
+	wake          chan struct{}
+}
+
+func LoadService(r StreamRepository, ss SnapshotService, es EnvironmentService, l *zerolog.Logger) *StreamService {
+	return &StreamService{
+		Logger:             l,
+		Repository:         r,
+		SnapshotService:    ss,
+		EnvironmentService: es,
+		subscriptions:      make(map[*Subscription]struct{}),
+		wake:               make(chan struct{}, 1),
+	}
+}
+
+// PublishFeatureFlagChange records the flag as it is after the change, an empty environment
+// records it in every environment. Failures are logged, the change itself already happened
+func (ss *StreamService) PublishFeatureFlagChange(eventType string, featureFlagId uint, environment string) {
+	ss.Logger.Info().Str("type", eventType).Uint("featureFlagId", featureFlagId).Msg("Publishing flag event")
+
+	environments, err := ss.environments(environment)
+	if err != nil {
+		ss.Logger.Error().Err(err).Msg("Error when getting environments to publish the flag event")
+		return
+	}
+
+	for _, environment := range environments {
+		featureFlag, err := ss.SnapshotService.GetFeatureFlagSnapshot(featureFlagId, environment)
+		if err != nil {
+			ss.Logger.Error().Err(err).Uint("featureFlagId", featureFlagId).Msg("Error when getting the flag to publish")
+			continue
+		}
+
+		ss.addFlagEvent(eventType, environment, featureFlag)
+	}
+
+	ss.wakeUp()
+}
+
+// PublishFeatureFlagDeletion records the removal of the flag in every environment, the flag
+// can not be read anymore so the event only carries its id, project and name
+func (ss *StreamService) PublishFeatureFlagDeletion(featureFlagId uint, projectId uint, name string) {
+	ss.Logger.Info().Str("type", streamEntity.EventFeatureFlagDeleted).Uint("featureFlagId", featureFlagId).Msg("Publishing flag event")
+
+	environments, err := ss.environments("")
+	if err != nil {
+		ss.Logger.Error().Err(err).Msg("Error when getting environments to publish the flag event")
+		return
+	}
+
+	for _, environment := range environments {
+		ss.addFlagEvent(streamEntity.EventFeatureFlagDeleted, environment, snapshotEntity.FeatureFlag{
+			ID:        featureFlagId,
+			ProjectID: projectId,
+			Name:      name,
+		})
+	}
+
+	ss.wakeUp()
+}
+
+// environments returns the environment, or every environment when it is empty
+func (ss *StreamService) environments(environment string) ([]string, error) {
+	if environment != "" {
+		return []string{environment}, nil
+	}
+
+	environmentResponses, err := ss.EnvironmentService.GetEnvironments()
+	if err != nil {
+		return nil, err
+	}
+
+	var environments []string
+	for _, environmentResponse := range environmentResponses {
+		environments = append(environments, environmentResponse.Name)
+	}
+
+	return environments, nil
+}
+
+func (ss *StreamService) addFlagEvent(eventType string, environment string, featureFlag snapshotEntity.FeatureFlag) {
+	payload, err := json.Marshal(featureFlag)
+	if err != nil {
+		ss.Logger.Error().Err(err).Uint("featureFlagId", featureFlag.ID).Msg("Error when encoding the flag to publish")
+		return
+	}
+
+	if err := ss.Repository.AddFlagEvent(model.FlagEvent{
+		Type:          eventType,
+		ProjectID:     featureFlag.ProjectID,
+		Environment:   environment,
+		FeatureFlagID: featureFlag.ID,
+		Payload:       string(payload),
+	}); err != nil {
+		ss.Logger.Error().Err(err).Uint("featureFlagId", featureFlag.ID).Msg("Error when storing the flag event")
+	}
+}
+
+// wakeUp makes Run send the new events right away
+func (ss *StreamService) wakeUp() {
+	select {
+	case ss.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Subscribe registers a subscription and returns the events missed since lastEventId,
+// a zero lastEventId only receives new events. A client too far behind receives a reset
+// event instead, carrying the id to resume from once it fetched the snapshot again
+func (ss *StreamService) Subscribe(projectId uint, environment string, lastEventId uint) (*Subscription, []streamEntity.Event, error) {
+	if environment == "" {
+		environment = model.DefaultEnvironment
+	}
+
+	subscription := &Subscription{
+		ProjectID:   projectId,
+		Environment: environment,
+		events:      make(chan streamEntity.Event, subscriptionBuffer),
+	}
+
+	// registered before reading the missed events, so nothing falls in between
+	ss.mu.Lock()
+	ss.subscriptions[subscription] = struct{}{}
+	ss.mu.Unlock()
+
+	if lastEventId == 0 {
+		return subscription, nil, nil
+	}
+
+	events, err := ss.missedEvents(subscription, lastEventId)
+	if err != nil {
+		ss.Unsubscribe(subscription)
+		return nil, nil, err
+	}
+
+	return subscription, events, nil
+}
+
+func (ss *StreamService) Unsubscribe(subscription *Subscription) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if _, ok := ss.subscriptions[subscription]; ok {
+		delete(ss.subscriptions, subscription)
+		close(subscription.events)
+	}
+}
+
+func (ss *StreamService) missedEvents(subscription *Subscription, lastEventId uint) ([]streamEntity.Event, error) {
+	oldest, latest, err := ss.Repository.GetFlagEventBounds()
+	if err != nil {
+		return nil, err
+	}
+
+	reset := []streamEntity.Event{{
+		ID:          latest,
+		Type:        streamEntity.EventReset,
+		ProjectID:   subscription.ProjectID,
+		Environment: subscription.Environment,
+	}}
+
+	// the id is from before the events were removed, or from another database
+	if lastEventId > latest || lastEventId+1 < oldest {
+		return reset, nil
+	}
+
+	flagEvents, err := ss.Repository.GetFlagEvents(lastEventId, replayLimit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(flagEvents) > replayLimit {
+		return reset, nil
+	}
+
+	var events []streamEntity.Event
+	for _, flagEvent := range flagEvents {
+		event := eventFromModel(flagEvent)
+		if subscription.matches(event) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// Run sends the new events to the subscriptions until the context is done, it looks for
+// events on every interval and right away when this process publishes one
+func (ss *StreamService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var cursor *streamCursor
+
+	for {
+		if cursor == nil {
+			// events stored before the start were sent by the previous run
+			_, latest, err := ss.Repository.GetFlagEventBounds()
+			if err != nil {
+				ss.Logger.Error().Err(err).Msg("Error when starting the flag event stream")
+			} else {
+				cursor = &streamCursor{start: latest, latest: latest, sent: map[uint]struct{}{}}
+			}
+		} else {
+			ss.dispatch(cursor)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-ss.wake:
+		}
+	}
+}
+
+// streamCursor is where Run is in the events, the ids sent inside the window are kept so an
+// event read again is not sent twice
+type streamCursor struct {
+	start  uint
+	latest uint
+	sent   map[uint]struct{}
+}
+
+func (ss *StreamService) dispatch(cursor *streamCursor) {
+	afterId := cursor.start
+	if cursor.latest > cursor.start+dispatchWindow {
+		afterId = cursor.latest - dispatchWindow
+	}
+
+	for {
+		flagEvents, err := ss.Repository.GetFlagEvents(afterId, replayLimit)
+		if err != nil {
+			ss.Logger.Error().Err(err).Msg("Error when getting new flag events")
+			return
+		}
+
+		for _, flagEvent := range flagEvents {
+			afterId = flagEvent.ID
+			if _, ok := cursor.sent[flagEvent.ID]; ok {
+				continue
+			}
+
+			ss.send(eventFromModel(flagEvent))
+			cursor.sent[flagEvent.ID] = struct{}{}
+			if flagEvent.ID > cursor.latest {
+				cursor.latest = flagEvent.ID
+			}
+		}
+
+		if len(flagEvents) < replayLimit {
+			break
+		}
+	}
+
+	// the ids out of the window are not read again
+	for id := range cursor.sent {
+		if id+dispatchWindow <= cursor.latest {
+			delete(cursor.sent, id)
+		}
+	}
+}
+
+func (ss *StreamService) send(event streamEntity.Event) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	for subscription := range ss.subscriptions {
+		if !subscription.matches(event) {
+			continue
+		}
+
+		select {
+		case subscription.events <- event:
+		default:
+			// too slow, the client reconnects and catches up from its last event id
+			delete(ss.subscriptions, subscription)
+			close(subscription.events)
+		}
+	}
+}
+
+// PruneFlagEvents removes the events older than the retention
+func (ss *StreamService) PruneFlagEvents(now time.Time, retention time.Duration) error {
+	ss.Logger.Info().Msg("Pruning flag events")
+
+	return ss.Repository.DeleteFlagEventsBefore(now.Add(-retention))
+}
+
+func eventFromModel(flagEvent model.FlagEvent) streamEntity.Event {
+	event := streamEntity.Event{
+		ID:          flagEvent.ID,
+		Type:        flagEvent.Type,
+		ProjectID:   flagEvent.ProjectID,
+		Environment: flagEvent.Environment,
+		CreatedAt:   flagEvent.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	var featureFlag snapshotEntity.FeatureFlag
+	if err := json.Unmarshal([]byte(flagEvent.Payload), &featureFlag); err == nil {
+		event.FeatureFlag = &featureFlag
+	}
+
+	return event
+}
@@ -0,0 +1,281 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"ff/internal/db/model"
+	environmentEntity "ff/internal/environment/entity"
+	snapshotEntity "ff/internal/snapshot/entity"
+	streamEntity "ff/internal/stream/entity"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRepository is a mock of SqlRepository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) AddFlagEvent(event model.FlagEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetFlagEvents(afterId uint, limit int) ([]model.FlagEvent, error) {
+	args := m.Called(afterId, limit)
+	return args.Get(0).([]model.FlagEvent), args.Error(1)
+}
+
+func (m *MockRepository) GetFlagEventBounds() (uint, uint, error) {
+	args := m.Called()
+	return args.Get(0).(uint), args.Get(1).(uint), args.Error(2)
+}
+
+func (m *MockRepository) DeleteFlagEventsBefore(date time.Time) error {
+	args := m.Called(date)
+	return args.Error(0)
+}
+
+type MockSnapshotService struct {
+	mock.Mock
+}
+
+func (m *MockSnapshotService) GetFeatureFlagSnapshot(featureFlagId uint, environment string) (snapshotEntity.FeatureFlag, error) {
+	args := m.Called(featureFlagId, environment)
+	return args.Get(0).(snapshotEntity.FeatureFlag), args.Error(1)
+}
+
+type MockEnvironmentService struct {
+	mock.Mock
+}
+
+func (m *MockEnvironmentService) GetEnvironments() ([]environmentEntity.EnvironmentResponse, error) {
+	args := m.Called()
+	return args.Get(0).([]environmentEntity.EnvironmentResponse), args.Error(1)
+}
+
+func loadTestService() (*StreamService, *MockRepository, *MockSnapshotService, *MockEnvironmentService) {
+	mockRepo := new(MockRepository)
+	mockSnapshot := new(MockSnapshotService)
+	mockEnvironment := new(MockEnvironmentService)
+	logger := zerolog.New(os.Stdout)
+
+	return LoadService(mockRepo, mockSnapshot, mockEnvironment, &logger), mockRepo, mockSnapshot, mockEnvironment
+}
+
+func flagEvent(id uint, environment string) model.FlagEvent {
+	return model.FlagEvent{
+		ID:            id,
+		Type:          streamEntity.EventFeatureFlagUpdated,
+		ProjectID:     1,
+		Environment:   environment,
+		FeatureFlagID: 7,
+		Payload:       `{"id":7,"projectId":1,"name":"NEW_CHECKOUT","isActive":true}`,
+	}
+}
+
+func TestPublishFeatureFlagChange(t *testing.T) {
+	t.Run("Publish the flag after the change in its environment", func(t *testing.T) {
+		service, mockRepo, mockSnapshot, mockEnvironment := loadTestService()
+
+		mockSnapshot.On("GetFeatureFlagSnapshot", uint(7), "staging").Return(snapshotEntity.FeatureFlag{ID: 7, ProjectID: 1, Name: "NEW_CHECKOUT", IsActive: true}, nil)
+		mockRepo.On("AddFlagEvent", mock.MatchedBy(func(event model.FlagEvent) bool {
+			featureFlag := eventFromModel(event).FeatureFlag
+			return event.Type == streamEntity.EventAssignmentApplied && event.ProjectID == 1 && event.Environment == "staging" &&
+				featureFlag != nil && featureFlag.Name == "NEW_CHECKOUT" && featureFlag.IsActive
+		})).Return(nil)
+
+		service.PublishFeatureFlagChange(streamEntity.EventAssignmentApplied, 7, "staging")
+
+		mockRepo.AssertExpectations(t)
+		mockEnvironment.AssertNotCalled(t, "GetEnvironments")
+	})
+
+	t.Run("Publish to every environment", func(t *testing.T) {
+		service, mockRepo, mockSnapshot, mockEnvironment := loadTestService()
+
+		mockEnvironment.On("GetEnvironments").Return([]environmentEntity.EnvironmentResponse{{Name: "production"}, {Name: "staging"}}, nil)
+		mockSnapshot.On("GetFeatureFlagSnapshot", uint(7), "production").Return(snapshotEntity.FeatureFlag{ID: 7, ProjectID: 1}, nil)
+		mockSnapshot.On("GetFeatureFlagSnapshot", uint(7), "staging").Return(snapshotEntity.FeatureFlag{}, errors.New("feature flag not found"))
+		mockRepo.On("AddFlagEvent", mock.MatchedBy(func(event model.FlagEvent) bool {
+			return event.Environment == "production"
+		})).Return(nil).Once()
+
+		service.PublishFeatureFlagChange(streamEntity.EventFeatureFlagUpdated, 7, "")
+
+		mockRepo.AssertExpectations(t)
+		mockSnapshot.AssertExpectations(t)
+	})
+}
+
+func TestPublishFeatureFlagDeletion(t *testing.T) {
+	t.Run("Publish the removal to every environment without reading the flag", func(t *testing.T) {
+		service, mockRepo, mockSnapshot, mockEnvironment := loadTestService()
+
+		mockEnvironment.On("GetEnvironments").Return([]environmentEntity.EnvironmentResponse{{Name: "production"}, {Name: "staging"}}, nil)
+		mockRepo.On("AddFlagEvent", mock.MatchedBy(func(event model.FlagEvent) bool {
+			featureFlag := eventFromModel(event).FeatureFlag
+			return event.Type == streamEntity.EventFeatureFlagDeleted && event.ProjectID == 1 && event.FeatureFlagID == 7 &&
+				featureFlag != nil && featureFlag.Name == "NEW_CHECKOUT"
+		})).Return(nil).Twice()
+
+		service.PublishFeatureFlagDeletion(7, 1, "NEW_CHECKOUT")
+
+		mockRepo.AssertExpectations(t)
+		mockSnapshot.AssertNotCalled(t, "GetFeatureFlagSnapshot", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Run("Without a last event id nothing is replayed", func(t *testing.T) {
+		service, mockRepo, _, _ := loadTestService()
+
+		subscription, missed, err := service.Subscribe(1, "", 0)
+
+		require.NoError(t, err)
+		assert.Empty(t, missed)
+		assert.Equal(t, model.DefaultEnvironment, subscription.Environment)
+		mockRepo.AssertNotCalled(t, "GetFlagEvents", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Replay the missed events of the environment", func(t *testing.T) {
+		service, mockRepo, _, _ := loadTestService()
+
+		mockRepo.On("GetFlagEventBounds").Return(uint(1), uint(4), nil)
+		mockRepo.On("GetFlagEvents", uint(2), replayLimit+1).Return([]model.FlagEvent{flagEvent(3, "staging"), flagEvent(4, model.DefaultEnvironment)}, nil)
+
+		_, missed, err := service.Subscribe(1, model.DefaultEnvironment, 2)
+
+		require.NoError(t, err)
+		require.Equal(t, 1, len(missed))
+		assert.Equal(t, uint(4), missed[0].ID)
+		require.NotNil(t, missed[0].FeatureFlag)
+		assert.Equal(t, "NEW_CHECKOUT", missed[0].FeatureFlag.Name)
+	})
+
+	t.Run("Reset when the missed events were removed", func(t *testing.T) {
+		service, mockRepo, _, _ := loadTestService()
+
+		mockRepo.On("GetFlagEventBounds").Return(uint(10), uint(12), nil)
+
+		_, missed, err := service.Subscribe(1, model.DefaultEnvironment, 2)
+
+		require.NoError(t, err)
+		require.Equal(t, 1, len(missed))
+		assert.Equal(t, streamEntity.EventReset, missed[0].Type)
+		assert.Equal(t, uint(12), missed[0].ID)
+	})
+
+	t.Run("Reset for an unknown id", func(t *testing.T) {
+		service, mockRepo, _, _ := loadTestService()
+
+		mockRepo.On("GetFlagEventBounds").Return(uint(1), uint(5), nil)
+
+		_, missed, err := service.Subscribe(1, model.DefaultEnvironment, 50)
+
+		require.NoError(t, err)
+		require.Equal(t, 1, len(missed))
+		assert.Equal(t, streamEntity.EventReset, missed[0].Type)
+	})
+
+	t.Run("Failing replay drops the subscription", func(t *testing.T) {
+		service, mockRepo, _, _ := loadTestService()
+
+		mockRepo.On("GetFlagEventBounds").Return(uint(0), uint(0), errors.New("error when getting flag event bounds"))
+
+		subscription, _, err := service.Subscribe(1, model.DefaultEnvironment, 2)
+
+		assert.Error(t, err)
+		assert.Nil(t, subscription)
+		assert.Empty(t, service.subscriptions)
+	})
+}
+
+func TestRun(t *testing.T) {
+	t.Run("New events reach the matching subscriptions", func(t *testing.T) {
+		service, mockRepo, _, _ := loadTestService()
+
+		mockRepo.On("GetFlagEventBounds").Return(uint(2), uint(2), nil)
+		mockRepo.On("GetFlagEvents", uint(2), replayLimit).Return([]model.FlagEvent{flagEvent(3, "staging"), flagEvent(4, model.DefaultEnvironment)}, nil)
+
+		production, _, err := service.Subscribe(1, model.DefaultEnvironment, 0)
+		require.NoError(t, err)
+		otherProject, _, err := service.Subscribe(2, model.DefaultEnvironment, 0)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go service.Run(ctx, 10*time.Millisecond)
+
+		select {
+		case event := <-production.Events():
+			assert.Equal(t, uint(4), event.ID)
+		case <-time.After(time.Second):
+			t.Fatal("no event received")
+		}
+
+		assert.Empty(t, otherProject.Events())
+	})
+
+	t.Run("Event stored after a newer one is sent once", func(t *testing.T) {
+		service, mockRepo, _, _ := loadTestService()
+
+		mockRepo.On("GetFlagEvents", uint(2), replayLimit).Return([]model.FlagEvent{flagEvent(4, model.DefaultEnvironment)}, nil).Once()
+		mockRepo.On("GetFlagEvents", uint(2), replayLimit).Return([]model.FlagEvent{flagEvent(3, model.DefaultEnvironment), flagEvent(4, model.DefaultEnvironment)}, nil).Once()
+		mockRepo.On("GetFlagEvents", uint(2), replayLimit).Return([]model.FlagEvent{flagEvent(3, model.DefaultEnvironment), flagEvent(4, model.DefaultEnvironment)}, nil).Once()
+
+		subscription, _, err := service.Subscribe(1, model.DefaultEnvironment, 0)
+		require.NoError(t, err)
+
+		cursor := &streamCursor{start: 2, latest: 2, sent: map[uint]struct{}{}}
+		service.dispatch(cursor)
+		service.dispatch(cursor)
+		service.dispatch(cursor)
+
+		assert.Equal(t, uint(4), (<-subscription.Events()).ID)
+		assert.Equal(t, uint(3), (<-subscription.Events()).ID)
+		assert.Empty(t, subscription.Events())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Window follows the newest event", func(t *testing.T) {
+		service, mockRepo, _, _ := loadTestService()
+
+		mockRepo.On("GetFlagEvents", uint(2), replayLimit).Return([]model.FlagEvent{flagEvent(3, model.DefaultEnvironment), flagEvent(2+dispatchWindow+5, model.DefaultEnvironment)}, nil).Once()
+		mockRepo.On("GetFlagEvents", uint(7), replayLimit).Return([]model.FlagEvent{flagEvent(2+dispatchWindow+5, model.DefaultEnvironment)}, nil).Once()
+
+		cursor := &streamCursor{start: 2, latest: 2, sent: map[uint]struct{}{}}
+		service.dispatch(cursor)
+		service.dispatch(cursor)
+
+		assert.Equal(t, uint(2+dispatchWindow+5), cursor.latest)
+		assert.Equal(t, map[uint]struct{}{2 + dispatchWindow + 5: {}}, cursor.sent)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Slow subscriptions are dropped", func(t *testing.T) {
+		service, _, _, _ := loadTestService()
+
+		subscription, _, err := service.Subscribe(1, model.DefaultEnvironment, 0)
+		require.NoError(t, err)
+
+		for i := 0; i <= subscriptionBuffer; i++ {
+			service.send(streamEntity.Event{ID: uint(i + 1), ProjectID: 1, Environment: model.DefaultEnvironment})
+		}
+
+		received := 0
+		for range subscription.Events() {
+			received++
+		}
+
+		assert.Equal(t, subscriptionBuffer, received)
+		assert.Empty(t, service.subscriptions)
+	})
+}
@@ -0,0 +1,116 @@
+package targeting
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	ff_entity "ff/internal/feature_flag/entity"
+)
+
+// MatchRules returns the first rule matching the attributes, rules are evaluated in order
+func MatchRules(rules []ff_entity.Rule, attributes map[string]interface{}) (ff_entity.Rule, bool) {
+	for _, rule := range rules {
+		if Match(rule, attributes) {
+			return rule, true
+		}
+	}
+
+	return ff_entity.Rule{}, false
+}
+
+// Match reports whether the attribute referenced by the rule satisfies its operator,
+// a missing attribute never matches
+func Match(rule ff_entity.Rule, attributes map[string]interface{}) bool {
+	attribute, ok := attributes[rule.Attribute]
+	if !ok || attribute == nil {
+		return false
+	}
+
+	value := toString(attribute)
+
+	switch rule.Operator {
+	case ff_entity.OperatorEquals:
+		return value == rule.Values[0]
+	case ff_entity.OperatorIn:
+		return anyValue(rule.Values, func(v string) bool { return value == v })
+	case ff_entity.OperatorContains:
+		return anyValue(rule.Values, func(v string) bool { return strings.Contains(value, v) })
+	case ff_entity.OperatorStartsWith:
+		return anyValue(rule.Values, func(v string) bool { return strings.HasPrefix(value, v) })
+	case ff_entity.OperatorEndsWith:
+		return anyValue(rule.Values, func(v string) bool { return strings.HasSuffix(value, v) })
+	case ff_entity.OperatorRegex:
+		return anyValue(rule.Values, func(v string) bool {
+			matched, err := regexp.MatchString(v, value)
+			return err == nil && matched
+		})
+	case ff_entity.OperatorGreater, ff_entity.OperatorGreaterEq, ff_entity.OperatorLess, ff_entity.OperatorLessEq:
+		return compareNumbers(rule.Operator, value, rule.Values[0])
+	case ff_entity.OperatorBefore, ff_entity.OperatorAfter:
+		return compareDates(rule.Operator, value, rule.Values[0])
+	}
+
+	return false
+}
+
+func toString(attribute interface{}) string {
+	if date, ok := attribute.(time.Time); ok {
+		return date.Format(time.RFC3339)
+	}
+
+	return fmt.Sprint(attribute)
+}
+
+func anyValue(values []string, match func(string) bool) bool {
+	for _, v := range values {
+		if match(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func compareNumbers(operator, value, expected string) bool {
+	left, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+
+	right, err := strconv.ParseFloat(expected, 64)
+	if err != nil {
+		return false
+	}
+
+	switch operator {
+	case ff_entity.OperatorGreater:
+		return left > right
+	case ff_entity.OperatorGreaterEq:
+		return left >= right
+	case ff_entity.OperatorLess:
+		return left < right
+	default:
+		return left <= right
+	}
+}
+
+func compareDates(operator, value, expected string) bool {
+	left, err := ff_entity.ParseDate(value)
+	if err != nil {
+		return false
+	}
+
+	right, err := ff_entity.ParseDate(expected)
+	if err != nil {
+		return false
+	}
+
+	if operator == ff_entity.OperatorBefore {
+		return left.Before(right)
+	}
+
+	return left.After(right)
+}
@@ -0,0 +1,97 @@
+package targeting
+
+import (
+	"testing"
+	"time"
+
+	ff_entity "ff/internal/feature_flag/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	attributes := map[string]interface{}{
+		"email":     "jane@ourcompany.com",
+		"country":   "BR",
+		"plan":      "enterprise",
+		"seats":     float64(150),
+		"createdAt": "2024-03-10",
+		"signedAt":  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	testCases := []struct {
+		name    string
+		rule    ff_entity.Rule
+		matches bool
+	}{
+		{"equals", ff_entity.Rule{Attribute: "plan", Operator: ff_entity.OperatorEquals, Values: []string{"enterprise"}}, true},
+		{"equals mismatch", ff_entity.Rule{Attribute: "plan", Operator: ff_entity.OperatorEquals, Values: []string{"free"}}, false},
+		{"in list", ff_entity.Rule{Attribute: "country", Operator: ff_entity.OperatorIn, Values: []string{"BR", "PT"}}, true},
+		{"not in list", ff_entity.Rule{Attribute: "country", Operator: ff_entity.OperatorIn, Values: []string{"US"}}, false},
+		{"contains", ff_entity.Rule{Attribute: "email", Operator: ff_entity.OperatorContains, Values: []string{"@ourcompany"}}, true},
+		{"starts with", ff_entity.Rule{Attribute: "email", Operator: ff_entity.OperatorStartsWith, Values: []string{"jane"}}, true},
+		{"ends with", ff_entity.Rule{Attribute: "email", Operator: ff_entity.OperatorEndsWith, Values: []string{"@ourcompany.com"}}, true},
+		{"regex", ff_entity.Rule{Attribute: "email", Operator: ff_entity.OperatorRegex, Values: []string{`^[a-z]+@ourcompany\.com$`}}, true},
+		{"greater than", ff_entity.Rule{Attribute: "seats", Operator: ff_entity.OperatorGreater, Values: []string{"100"}}, true},
+		{"less or equal", ff_entity.Rule{Attribute: "seats", Operator: ff_entity.OperatorLessEq, Values: []string{"150"}}, true},
+		{"less than", ff_entity.Rule{Attribute: "seats", Operator: ff_entity.OperatorLess, Values: []string{"150"}}, false},
+		{"numeric on text", ff_entity.Rule{Attribute: "plan", Operator: ff_entity.OperatorGreater, Values: []string{"1"}}, false},
+		{"before", ff_entity.Rule{Attribute: "createdAt", Operator: ff_entity.OperatorBefore, Values: []string{"2024-04-01"}}, true},
+		{"after", ff_entity.Rule{Attribute: "createdAt", Operator: ff_entity.OperatorAfter, Values: []string{"2024-04-01"}}, false},
+		{"after with time attribute", ff_entity.Rule{Attribute: "signedAt", Operator: ff_entity.OperatorAfter, Values: []string{"2023-12-31"}}, true},
+		{"missing attribute", ff_entity.Rule{Attribute: "age", Operator: ff_entity.OperatorEquals, Values: []string{"1"}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.matches, Match(tc.rule, attributes))
+		})
+	}
+}
+
+func TestMatchRules(t *testing.T) {
+	rules := []ff_entity.Rule{
+		{Attribute: "country", Operator: ff_entity.OperatorIn, Values: []string{"US"}, Result: false},
+		{Attribute: "email", Operator: ff_entity.OperatorEndsWith, Values: []string{"@ourcompany.com"}, Result: true},
+		{Attribute: "plan", Operator: ff_entity.OperatorEquals, Values: []string{"enterprise"}, Result: false},
+	}
+
+	t.Run("First matching rule wins", func(t *testing.T) {
+		rule, ok := MatchRules(rules, map[string]interface{}{"email": "jane@ourcompany.com", "plan": "enterprise"})
+		assert.True(t, ok)
+		assert.True(t, rule.Result)
+		assert.Equal(t, "email", rule.Attribute)
+	})
+
+	t.Run("No rule matches", func(t *testing.T) {
+		_, ok := MatchRules(rules, map[string]interface{}{"country": "BR"})
+		assert.False(t, ok)
+	})
+}
+
+func TestRuleValidate(t *testing.T) {
+	testCases := []struct {
+		name string
+		rule ff_entity.Rule
+		err  string
+	}{
+		{"valid", ff_entity.Rule{Attribute: "plan", Operator: ff_entity.OperatorEquals, Values: []string{"enterprise"}}, ""},
+		{"missing attribute", ff_entity.Rule{Operator: ff_entity.OperatorEquals, Values: []string{"a"}}, "Rules|Rule attribute is required"},
+		{"missing values", ff_entity.Rule{Attribute: "plan", Operator: ff_entity.OperatorEquals}, "Rules|Rule values are required"},
+		{"invalid operator", ff_entity.Rule{Attribute: "plan", Operator: "like", Values: []string{"a"}}, "Rules|Rule operator is invalid"},
+		{"invalid regex", ff_entity.Rule{Attribute: "email", Operator: ff_entity.OperatorRegex, Values: []string{"("}}, "Rules|Rule value must be a valid regular expression"},
+		{"invalid number", ff_entity.Rule{Attribute: "seats", Operator: ff_entity.OperatorGreater, Values: []string{"ten"}}, "Rules|Numeric rule value must be a number"},
+		{"invalid date", ff_entity.Rule{Attribute: "createdAt", Operator: ff_entity.OperatorBefore, Values: []string{"10/10/2024"}}, "Rules|Date rule value must be in YYYY-MM-DD or RFC3339 format"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.Validate()
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package entity
+
+import (
+	"errors"
+	"net/url"
+)
+
+// events sent to the webhooks, the status and global toggles are sent next to the update
+// that caused them so a receiver can subscribe to them only
+const (
+	EventFeatureFlagCreated       = "feature_flag.created"
+	EventFeatureFlagUpdated       = "feature_flag.updated"
+	EventFeatureFlagStatusToggled = "feature_flag.status_toggled"
+	EventFeatureFlagGlobalToggled = "feature_flag.global_toggled"
+	EventFeatureFlagArchived      = "feature_flag.archived"
+	EventFeatureFlagRestored      = "feature_flag.restored"
+	EventFeatureFlagDeleted       = "feature_flag.deleted"
+	EventAssignmentApplied        = "assignment.applied"
+	EventAssignmentDeleted        = "assignment.deleted"
+)
+
+var EventTypes = []string{
+	EventFeatureFlagCreated,
+	EventFeatureFlagUpdated,
+	EventFeatureFlagStatusToggled,
+	EventFeatureFlagGlobalToggled,
+	EventFeatureFlagArchived,
+	EventFeatureFlagRestored,
+	EventFeatureFlagDeleted,
+	EventAssignmentApplied,
+	EventAssignmentDeleted,
+}
+
+type Webhook struct {
+	URL string `json:"url"`
+	// Secret signs the payloads, one is generated when it is empty
+	Secret string `json:"secret"`
+	// EventTypes are the events sent to the webhook, every event when empty
+	EventTypes []string `json:"eventTypes"`
+	IsActive   *bool    `json:"isActive"`
+}
+
+func (w *Webhook) Validate() error {
+	if w.URL == "" {
+		return errors.New("URL|URL is required")
+	}
+
+	parsed, err := url.Parse(w.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("URL|URL must be an http or https address")
+	}
+
+	if len(w.URL) > 512 {
+		return errors.New("URL|URL must have at most 512 characters")
+	}
+
+	if len(w.Secret) > 128 {
+		return errors.New("Secret|Secret must have at most 128 characters")
+	}
+
+	for _, eventType := range w.EventTypes {
+		if !IsEventType(eventType) {
+			return errors.New("EventTypes|Unknown event type " + eventType)
+		}
+	}
+
+	return nil
+}
+
+func IsEventType(eventType string) bool {
+	for _, known := range EventTypes {
+		if eventType == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+type WebhookResponse struct {
+	ID         uint     `json:"id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	IsActive   bool     `json:"isActive"`
+	// Secret is only returned when the webhook is created
+	Secret    string `json:"secret,omitempty"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type DeliveryResponse struct {
+	ID            uint              `json:"id"`
+	EventType     string            `json:"eventType"`
+	Payload       string            `json:"payload"`
+	Status        string            `json:"status"`
+	Attempts      []AttemptResponse `json:"attempts"`
+	NextAttemptAt string            `json:"nextAttemptAt,omitempty"`
+	CreatedAt     string            `json:"createdAt"`
+}
+
+type AttemptResponse struct {
+	StatusCode int    `json:"statusCode"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+// Change is a change to notify, before and after are the changed object on each side of
+// it, nil for the side where it does not exist
+type Change struct {
+	Event         string
+	ProjectID     uint
+	Environment   string
+	FeatureFlagID uint
+	Before        interface{}
+	After         interface{}
+}
+
+// Payload is the body sent to the webhooks
+type Payload struct {
+	Event         string      `json:"event"`
+	ProjectID     uint        `json:"projectId"`
+	Environment   string      `json:"environment"`
+	FeatureFlagID uint        `json:"featureFlagId"`
+	Before        interface{} `json:"before"`
+	After         interface{} `json:"after"`
+	OccurredAt    string      `json:"occurredAt"`
+}
@@ -0,0 +1,420 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ff/internal/db/model"
+	webhookEntity "ff/internal/webhook/entity"
+
+	"github.com/rs/zerolog"
+)
+
+// headers sent with every delivery, the signature is the HMAC-SHA256 of the body with
+// the secret of the webhook, in hex and prefixed by "sha256="
+const (
+	EventHeader     = "X-FF-Event"
+	DeliveryHeader  = "X-FF-Delivery"
+	SignatureHeader = "X-FF-Signature"
+)
+
+const (
+	DefaultMaxAttempts = 6
+	DefaultBaseBackoff = 30 * time.Second
+	DefaultMaxBackoff  = time.Hour
+	DefaultTimeout     = 10 * time.Second
+	DefaultConcurrency = 8
+	// DefaultClaimLease outlasts a batch sent to receivers that all time out
+	DefaultClaimLease = 5 * time.Minute
+
+	// deliveryBatch is how many due deliveries are sent on each run
+	deliveryBatch = 100
+)
+
+type WebhookRepository interface {
+	AddWebhook(webhook model.Webhook) (model.Webhook, error)
+	GetWebhooks(projectId uint) ([]model.Webhook, error)
+	GetWebhook(projectId uint, id uint) (model.Webhook, error)
+	UpdateWebhook(webhook model.Webhook) error
+	DeleteWebhook(projectId uint, id uint) error
+	AddWebhookDeliveries(deliveries []model.WebhookDelivery) error
+	ClaimDueWebhookDeliveries(now time.Time, limit int, lockedUntil time.Time) ([]model.WebhookDelivery, error)
+	GetWebhookDeliveries(webhookId uint, pagination model.Pagination) ([]model.WebhookDelivery, int64, error)
+	RecordWebhookAttempt(delivery model.WebhookDelivery, attempt model.WebhookAttempt) error
+	GetFeatureFlagProjectId(featureFlagId uint) (uint, error)
+}
+
+type WebhookService struct {
+	Repository WebhookRepository
+	HTTPClient *http.Client
+	// a failed delivery is retried after BaseBackoff, doubling on each attempt up to
+	// MaxBackoff, and given up after MaxAttempts
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// the claimed deliveries are sent by up to Concurrency requests at a time, the claim
+	// keeps the other replicas away for ClaimLease
+	Concurrency int
+	ClaimLease  time.Duration
+	Logger      *zerolog.Logger
+}
+
+func LoadService(r WebhookRepository, l *zerolog.Logger) *WebhookService {
+	return &WebhookService{
+		Logger:      l,
+		Repository:  r,
+		HTTPClient:  &http.Client{Timeout: DefaultTimeout},
+		MaxAttempts: DefaultMaxAttempts,
+		BaseBackoff: DefaultBaseBackoff,
+		MaxBackoff:  DefaultMaxBackoff,
+		Concurrency: DefaultConcurrency,
+		ClaimLease:  DefaultClaimLease,
+	}
+}
+
+// CreateWebhook returns the secret of the webhook, it can not be read again afterwards
+func (ws *WebhookService) CreateWebhook(projectId uint, request webhookEntity.Webhook) (webhookEntity.WebhookResponse, error) {
+	ws.Logger.Info().Msg("Creating a new Webhook")
+
+	if err := request.Validate(); err != nil {
+		return webhookEntity.WebhookResponse{}, errors.New(err.Error())
+	}
+
+	if request.Secret == "" {
+		secret := make([]byte, 24)
+		if _, err := rand.Read(secret); err != nil {
+			return webhookEntity.WebhookResponse{}, err
+		}
+		request.Secret = hex.EncodeToString(secret)
+	}
+
+	isActive := true
+	if request.IsActive != nil {
+		isActive = *request.IsActive
+	}
+
+	webhook, err := ws.Repository.AddWebhook(model.Webhook{
+		ProjectID:  projectId,
+		URL:        request.URL,
+		Secret:     request.Secret,
+		EventTypes: strings.Join(request.EventTypes, ","),
+		IsActive:   isActive,
+	})
+	if err != nil {
+		return webhookEntity.WebhookResponse{}, err
+	}
+
+	response := webhookFromModel(webhook)
+	response.Secret = request.Secret
+
+	return response, nil
+}
+
+func (ws *WebhookService) GetWebhooks(projectId uint) ([]webhookEntity.WebhookResponse, error) {
+	ws.Logger.Info().Msg("Getting Webhooks")
+
+	webhooks, err := ws.Repository.GetWebhooks(projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhookResponses []webhookEntity.WebhookResponse
+	for _, webhook := range webhooks {
+		webhookResponses = append(webhookResponses, webhookFromModel(webhook))
+	}
+
+	return webhookResponses, nil
+}
+
+// UpdateWebhook keeps the current secret when none is sent
+func (ws *WebhookService) UpdateWebhook(projectId uint, id uint, request webhookEntity.Webhook) error {
+	ws.Logger.Info().Msg("Updating a Webhook")
+
+	if err := request.Validate(); err != nil {
+		return errors.New(err.Error())
+	}
+
+	webhook, err := ws.Repository.GetWebhook(projectId, id)
+	if err != nil {
+		return err
+	}
+
+	if webhook.ID == 0 {
+		return errors.New("webhook not found")
+	}
+
+	webhook.URL = request.URL
+	webhook.EventTypes = strings.Join(request.EventTypes, ",")
+	if request.Secret != "" {
+		webhook.Secret = request.Secret
+	}
+	if request.IsActive != nil {
+		webhook.IsActive = *request.IsActive
+	}
+
+	return ws.Repository.UpdateWebhook(webhook)
+}
+
+func (ws *WebhookService) DeleteWebhook(projectId uint, id uint) error {
+	ws.Logger.Info().Msg("Deleting a Webhook")
+
+	return ws.Repository.DeleteWebhook(projectId, id)
+}
+
+func (ws *WebhookService) GetWebhookDeliveries(projectId uint, id uint, pagination model.Pagination) ([]webhookEntity.DeliveryResponse, int64, error) {
+	ws.Logger.Info().Msg("Getting Webhook deliveries")
+
+	webhook, err := ws.Repository.GetWebhook(projectId, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if webhook.ID == 0 {
+		return nil, 0, errors.New("webhook not found")
+	}
+
+	deliveries, totalCount, err := ws.Repository.GetWebhookDeliveries(id, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var deliveryResponses []webhookEntity.DeliveryResponse
+	for _, delivery := range deliveries {
+		response := webhookEntity.DeliveryResponse{
+			ID:        delivery.ID,
+			EventType: delivery.EventType,
+			Payload:   delivery.Payload,
+			Status:    delivery.Status,
+			Attempts:  []webhookEntity.AttemptResponse{},
+			CreatedAt: delivery.CreatedAt.Format("2006-01-02 15:04:05"),
+		}
+
+		if delivery.Status == model.WebhookDeliveryPending {
+			response.NextAttemptAt = delivery.NextAttemptAt.Format("2006-01-02 15:04:05")
+		}
+
+		for _, attempt := range delivery.AttemptLogs {
+			response.Attempts = append(response.Attempts, webhookEntity.AttemptResponse{
+				StatusCode: attempt.StatusCode,
+				Error:      attempt.Error,
+				DurationMs: attempt.DurationMs,
+				CreatedAt:  attempt.CreatedAt.Format("2006-01-02 15:04:05"),
+			})
+		}
+
+		deliveryResponses = append(deliveryResponses, response)
+	}
+
+	return deliveryResponses, totalCount, nil
+}
+
+// NotifyChange queues a delivery of the change to every active webhook of the project
+// listening to the event, they are sent by DeliverWebhooks. A failure is only logged,
+// the change itself is already done
+func (ws *WebhookService) NotifyChange(change webhookEntity.Change) {
+	if err := ws.notifyChange(change, time.Now()); err != nil {
+		ws.Logger.Error().Err(err).Str("event", change.Event).Uint("featureFlagId", change.FeatureFlagID).Msg("Error when queuing webhook deliveries")
+	}
+}
+
+func (ws *WebhookService) notifyChange(change webhookEntity.Change, now time.Time) error {
+	// assignments do not know the project of their flag
+	if change.ProjectID == 0 {
+		projectId, err := ws.Repository.GetFeatureFlagProjectId(change.FeatureFlagID)
+		if err != nil {
+			return err
+		}
+		change.ProjectID = projectId
+	}
+
+	webhooks, err := ws.Repository.GetWebhooks(change.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	environment := change.Environment
+	if environment == "" {
+		environment = model.DefaultEnvironment
+	}
+
+	payload, err := json.Marshal(webhookEntity.Payload{
+		Event:         change.Event,
+		ProjectID:     change.ProjectID,
+		Environment:   environment,
+		FeatureFlagID: change.FeatureFlagID,
+		Before:        change.Before,
+		After:         change.After,
+		OccurredAt:    now.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	var deliveries []model.WebhookDelivery
+	for _, webhook := range webhooks {
+		if !webhook.IsActive || !listensTo(webhook, change.Event) {
+			continue
+		}
+
+		deliveries = append(deliveries, model.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventType:     change.Event,
+			Payload:       string(payload),
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: now,
+		})
+	}
+
+	return ws.Repository.AddWebhookDeliveries(deliveries)
+}
+
+// DeliverWebhooks claims and sends the deliveries due at now, a failed one is scheduled again with
+// an exponential backoff until it runs out of attempts
+func (ws *WebhookService) DeliverWebhooks(now time.Time) error {
+	deliveries, err := ws.Repository.ClaimDueWebhookDeliveries(now, deliveryBatch, now.Add(ws.ClaimLease))
+	if err != nil {
+		return err
+	}
+
+	// a slow receiver only holds one of the requests, the others keep going
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, ws.Concurrency)
+	errs := make(chan error, len(deliveries))
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(delivery model.WebhookDelivery) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			errs <- ws.deliver(delivery, now)
+		}(delivery)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deliver sends one delivery and records the attempt, which releases the claim
+func (ws *WebhookService) deliver(delivery model.WebhookDelivery, now time.Time) error {
+	attempt := ws.send(delivery)
+
+	delivery.Attempts++
+	switch {
+	case attempt.Error == "":
+		delivery.Status = model.WebhookDeliverySucceeded
+	case delivery.Attempts >= ws.MaxAttempts:
+		delivery.Status = model.WebhookDeliveryFailed
+		ws.Logger.Warn().Uint("deliveryId", delivery.ID).Str("error", attempt.Error).Msg("Giving up on the webhook delivery")
+	default:
+		delivery.NextAttemptAt = now.Add(ws.backoff(delivery.Attempts))
+	}
+
+	return ws.Repository.RecordWebhookAttempt(delivery, attempt)
+}
+
+// send posts the payload, any answer but a 2xx is a failed attempt
+func (ws *WebhookService) send(delivery model.WebhookDelivery) model.WebhookAttempt {
+	// a delivery left behind by a removed webhook has nowhere to go
+	if delivery.Webhook == nil {
+		return model.WebhookAttempt{Error: "webhook not found"}
+	}
+
+	request, err := http.NewRequest(http.MethodPost, delivery.Webhook.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return model.WebhookAttempt{Error: err.Error()}
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventHeader, delivery.EventType)
+	request.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	request.Header.Set(SignatureHeader, Sign(delivery.Webhook.Secret, []byte(delivery.Payload)))
+
+	start := time.Now()
+	response, err := ws.HTTPClient.Do(request)
+	attempt := model.WebhookAttempt{DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64*1024))
+
+	attempt.StatusCode = response.StatusCode
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("unexpected status %d", response.StatusCode)
+	}
+
+	return attempt
+}
+
+func (ws *WebhookService) backoff(attempts int) time.Duration {
+	backoff := ws.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= ws.MaxBackoff {
+			return ws.MaxBackoff
+		}
+	}
+
+	return backoff
+}
+
+// Sign returns the signature header value of the body, receivers compute it with their
+// copy of the secret and compare
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func listensTo(webhook model.Webhook, event string) bool {
+	if webhook.EventTypes == "" {
+		return true
+	}
+
+	for _, eventType := range strings.Split(webhook.EventTypes, ",") {
+		if eventType == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func webhookFromModel(webhook model.Webhook) webhookEntity.WebhookResponse {
+	eventTypes := []string{}
+	if webhook.EventTypes != "" {
+		eventTypes = strings.Split(webhook.EventTypes, ",")
+	}
+
+	return webhookEntity.WebhookResponse{
+		ID:         webhook.ID,
+		URL:        webhook.URL,
+		EventTypes: eventTypes,
+		IsActive:   webhook.IsActive,
+		CreatedAt:  webhook.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:  webhook.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,330 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ff/internal/db/model"
+	webhookEntity "ff/internal/webhook/entity"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRepository is a mock of SqlRepository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) AddWebhook(webhook model.Webhook) (model.Webhook, error) {
+	args := m.Called(webhook)
+	return args.Get(0).(model.Webhook), args.Error(1)
+}
+
+func (m *MockRepository) GetWebhooks(projectId uint) ([]model.Webhook, error) {
+	args := m.Called(projectId)
+	return args.Get(0).([]model.Webhook), args.Error(1)
+}
+
+func (m *MockRepository) GetWebhook(projectId uint, id uint) (model.Webhook, error) {
+	args := m.Called(projectId, id)
+	return args.Get(0).(model.Webhook), args.Error(1)
+}
+
+func (m *MockRepository) UpdateWebhook(webhook model.Webhook) error {
+	args := m.Called(webhook)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteWebhook(projectId uint, id uint) error {
+	args := m.Called(projectId, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AddWebhookDeliveries(deliveries []model.WebhookDelivery) error {
+	args := m.Called(deliveries)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ClaimDueWebhookDeliveries(now time.Time, limit int, lockedUntil time.Time) ([]model.WebhookDelivery, error) {
+	args := m.Called(now, limit, lockedUntil)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockRepository) GetWebhookDeliveries(webhookId uint, pagination model.Pagination) ([]model.WebhookDelivery, int64, error) {
+	args := m.Called(webhookId, pagination)
+	return args.Get(0).([]model.WebhookDelivery), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRepository) RecordWebhookAttempt(delivery model.WebhookDelivery, attempt model.WebhookAttempt) error {
+	args := m.Called(delivery, attempt)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetFeatureFlagProjectId(featureFlagId uint) (uint, error) {
+	args := m.Called(featureFlagId)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func loadTestService() (*WebhookService, *MockRepository) {
+	mockRepo := new(MockRepository)
+	logger := zerolog.New(os.Stdout)
+
+	return LoadService(mockRepo, &logger), mockRepo
+}
+
+func TestCreateWebhook(t *testing.T) {
+	t.Run("Generate a secret when none is sent", func(t *testing.T) {
+		service, mockRepo := loadTestService()
+
+		mockRepo.On("AddWebhook", mock.MatchedBy(func(webhook model.Webhook) bool {
+			return webhook.ProjectID == 1 && len(webhook.Secret) == 48 && webhook.EventTypes == "feature_flag.created,assignment.applied" && webhook.IsActive
+		})).Return(model.Webhook{ID: 3, URL: "https://hooks.example.com", EventTypes: "feature_flag.created,assignment.applied", IsActive: true}, nil)
+
+		webhook, err := service.CreateWebhook(1, webhookEntity.Webhook{
+			URL:        "https://hooks.example.com",
+			EventTypes: []string{webhookEntity.EventFeatureFlagCreated, webhookEntity.EventAssignmentApplied},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), webhook.ID)
+		assert.Equal(t, 48, len(webhook.Secret))
+		assert.Equal(t, []string{webhookEntity.EventFeatureFlagCreated, webhookEntity.EventAssignmentApplied}, webhook.EventTypes)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		tests := []struct {
+			request webhookEntity.Webhook
+			err     string
+		}{
+			{request: webhookEntity.Webhook{}, err: "URL|URL is required"},
+			{request: webhookEntity.Webhook{URL: "ftp://hooks.example.com"}, err: "URL|URL must be an http or https address"},
+			{request: webhookEntity.Webhook{URL: "https://hooks.example.com", EventTypes: []string{"flag.deleted"}}, err: "EventTypes|Unknown event type flag.deleted"},
+		}
+
+		for _, tt := range tests {
+			service, mockRepo := loadTestService()
+
+			_, err := service.CreateWebhook(1, tt.request)
+
+			assert.EqualError(t, err, tt.err)
+			mockRepo.AssertNotCalled(t, "AddWebhook", mock.Anything)
+		}
+	})
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	t.Run("Keep the secret when none is sent", func(t *testing.T) {
+		service, mockRepo := loadTestService()
+
+		isActive := false
+		mockRepo.On("GetWebhook", uint(1), uint(3)).Return(model.Webhook{ID: 3, ProjectID: 1, Secret: "s3cr3t", IsActive: true}, nil)
+		mockRepo.On("UpdateWebhook", model.Webhook{ID: 3, ProjectID: 1, URL: "https://hooks.example.com/v2", Secret: "s3cr3t", IsActive: false}).Return(nil)
+
+		err := service.UpdateWebhook(1, 3, webhookEntity.Webhook{URL: "https://hooks.example.com/v2", IsActive: &isActive})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Webhook of another project", func(t *testing.T) {
+		service, mockRepo := loadTestService()
+
+		mockRepo.On("GetWebhook", uint(2), uint(3)).Return(model.Webhook{}, nil)
+
+		err := service.UpdateWebhook(2, 3, webhookEntity.Webhook{URL: "https://hooks.example.com"})
+
+		assert.EqualError(t, err, "webhook not found")
+		mockRepo.AssertNotCalled(t, "UpdateWebhook", mock.Anything)
+	})
+}
+
+func TestNotifyChange(t *testing.T) {
+	t.Run("Queue a delivery for the webhooks listening to the event", func(t *testing.T) {
+		service, mockRepo := loadTestService()
+
+		mockRepo.On("GetFeatureFlagProjectId", uint(5)).Return(uint(1), nil)
+		mockRepo.On("GetWebhooks", uint(1)).Return([]model.Webhook{
+			{ID: 1, IsActive: true},
+			{ID: 2, IsActive: true, EventTypes: "assignment.applied,assignment.deleted"},
+			{ID: 3, IsActive: true, EventTypes: "feature_flag.created"},
+			{ID: 4, IsActive: false},
+		}, nil)
+		mockRepo.On("AddWebhookDeliveries", mock.MatchedBy(func(deliveries []model.WebhookDelivery) bool {
+			if len(deliveries) != 2 || deliveries[0].WebhookID != 1 || deliveries[1].WebhookID != 2 {
+				return false
+			}
+
+			var payload map[string]interface{}
+			if err := json.Unmarshal([]byte(deliveries[0].Payload), &payload); err != nil {
+				return false
+			}
+
+			return deliveries[0].Status == model.WebhookDeliveryPending && payload["event"] == webhookEntity.EventAssignmentApplied &&
+				payload["projectId"] == float64(1) && payload["environment"] == model.DefaultEnvironment &&
+				payload["before"] == nil && payload["after"].(map[string]interface{})["personId"] == float64(7)
+		})).Return(nil)
+
+		service.NotifyChange(webhookEntity.Change{
+			Event:         webhookEntity.EventAssignmentApplied,
+			FeatureFlagID: 5,
+			After:         map[string]interface{}{"personId": 7},
+		})
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failures are only logged", func(t *testing.T) {
+		service, mockRepo := loadTestService()
+
+		mockRepo.On("GetWebhooks", uint(1)).Return([]model.Webhook{}, errors.New("error when getting webhooks"))
+
+		service.NotifyChange(webhookEntity.Change{Event: webhookEntity.EventFeatureFlagUpdated, ProjectID: 1, FeatureFlagID: 5})
+
+		mockRepo.AssertNotCalled(t, "AddWebhookDeliveries", mock.Anything)
+	})
+}
+
+func TestDeliverWebhooks(t *testing.T) {
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Signed payload is delivered", func(t *testing.T) {
+		service, mockRepo := loadTestService()
+
+		var received atomic.Int32
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+
+			assert.Equal(t, Sign("s3cr3t", body), r.Header.Get(SignatureHeader))
+			assert.Equal(t, webhookEntity.EventFeatureFlagUpdated, r.Header.Get(EventHeader))
+			assert.Equal(t, "9", r.Header.Get(DeliveryHeader))
+			assert.JSONEq(t, `{"event":"feature_flag.updated"}`, string(body))
+
+			received.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer receiver.Close()
+
+		delivery := model.WebhookDelivery{
+			ID:        9,
+			Webhook:   &model.Webhook{ID: 1, URL: receiver.URL, Secret: "s3cr3t"},
+			EventType: webhookEntity.EventFeatureFlagUpdated,
+			Payload:   `{"event":"feature_flag.updated"}`,
+			Status:    model.WebhookDeliveryPending,
+		}
+		mockRepo.On("ClaimDueWebhookDeliveries", now, deliveryBatch, now.Add(DefaultClaimLease)).Return([]model.WebhookDelivery{delivery}, nil)
+		mockRepo.On("RecordWebhookAttempt", mock.MatchedBy(func(delivery model.WebhookDelivery) bool {
+			return delivery.Status == model.WebhookDeliverySucceeded && delivery.Attempts == 1
+		}), mock.MatchedBy(func(attempt model.WebhookAttempt) bool {
+			return attempt.StatusCode == http.StatusNoContent && attempt.Error == ""
+		})).Return(nil)
+
+		require.NoError(t, service.DeliverWebhooks(now))
+
+		assert.Equal(t, int32(1), received.Load())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed delivery is retried with an exponential backoff", func(t *testing.T) {
+		service, mockRepo := loadTestService()
+
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer receiver.Close()
+
+		delivery := model.WebhookDelivery{ID: 9, Webhook: &model.Webhook{URL: receiver.URL}, Status: model.WebhookDeliveryPending, Attempts: 2}
+		mockRepo.On("ClaimDueWebhookDeliveries", now, deliveryBatch, now.Add(DefaultClaimLease)).Return([]model.WebhookDelivery{delivery}, nil)
+		mockRepo.On("RecordWebhookAttempt", mock.MatchedBy(func(delivery model.WebhookDelivery) bool {
+			return delivery.Status == model.WebhookDeliveryPending && delivery.Attempts == 3 &&
+				delivery.NextAttemptAt.Equal(now.Add(4*DefaultBaseBackoff))
+		}), mock.MatchedBy(func(attempt model.WebhookAttempt) bool {
+			return attempt.StatusCode == http.StatusBadGateway && attempt.Error == "unexpected status 502"
+		})).Return(nil)
+
+		require.NoError(t, service.DeliverWebhooks(now))
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Give up after the last attempt", func(t *testing.T) {
+		service, mockRepo := loadTestService()
+
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer receiver.Close()
+
+		delivery := model.WebhookDelivery{ID: 9, Webhook: &model.Webhook{URL: receiver.URL}, Status: model.WebhookDeliveryPending, Attempts: DefaultMaxAttempts - 1}
+		mockRepo.On("ClaimDueWebhookDeliveries", now, deliveryBatch, now.Add(DefaultClaimLease)).Return([]model.WebhookDelivery{delivery}, nil)
+		mockRepo.On("RecordWebhookAttempt", mock.MatchedBy(func(delivery model.WebhookDelivery) bool {
+			return delivery.Status == model.WebhookDeliveryFailed && delivery.Attempts == DefaultMaxAttempts
+		}), mock.Anything).Return(nil)
+
+		require.NoError(t, service.DeliverWebhooks(now))
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Deliveries are sent concurrently up to the limit", func(t *testing.T) {
+		service, mockRepo := loadTestService()
+		service.Concurrency = 3
+
+		var sending, most atomic.Int32
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := sending.Add(1)
+			for {
+				previous := most.Load()
+				if current <= previous || most.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			sending.Add(-1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer receiver.Close()
+
+		var deliveries []model.WebhookDelivery
+		for id := uint(1); id <= 9; id++ {
+			deliveries = append(deliveries, model.WebhookDelivery{ID: id, Webhook: &model.Webhook{URL: receiver.URL}, Status: model.WebhookDeliveryPending})
+		}
+		mockRepo.On("ClaimDueWebhookDeliveries", now, deliveryBatch, now.Add(DefaultClaimLease)).Return(deliveries, nil)
+		mockRepo.On("RecordWebhookAttempt", mock.MatchedBy(func(delivery model.WebhookDelivery) bool {
+			return delivery.Status == model.WebhookDeliverySucceeded
+		}), mock.Anything).Return(nil).Times(len(deliveries))
+
+		require.NoError(t, service.DeliverWebhooks(now))
+
+		assert.Equal(t, int32(3), most.Load())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed record is returned", func(t *testing.T) {
+		service, mockRepo := loadTestService()
+
+		delivery := model.WebhookDelivery{ID: 9, Status: model.WebhookDeliveryPending}
+		mockRepo.On("ClaimDueWebhookDeliveries", now, deliveryBatch, now.Add(DefaultClaimLease)).Return([]model.WebhookDelivery{delivery}, nil)
+		mockRepo.On("RecordWebhookAttempt", mock.Anything, mock.Anything).Return(errors.New("error when recording webhook attempt"))
+
+		assert.EqualError(t, service.DeliverWebhooks(now), "error when recording webhook attempt")
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	service, _ := loadTestService()
+
+	assert.Equal(t, 30*time.Second, service.backoff(1))
+	assert.Equal(t, time.Minute, service.backoff(2))
+	assert.Equal(t, 8*time.Minute, service.backoff(5))
+	assert.Equal(t, DefaultMaxBackoff, service.backoff(20))
+}
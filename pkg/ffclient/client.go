@@ -0,0 +1,314 @@
+// Package ffclient is the Go client of the feature flag service. It polls the flag
+// snapshot of a project, keeps it in memory and evaluates flags locally with the same
+// semantics as the server, so a consumer keeps working while the service is unavailable
+package ffclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	evaluation "ff/internal/evaluation"
+	evaluationEntity "ff/internal/evaluation/entity"
+	ff_entity "ff/internal/feature_flag/entity"
+	"ff/internal/snapshot"
+	snapshotEntity "ff/internal/snapshot/entity"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	DefaultPollInterval = 30 * time.Second
+	DefaultTimeout      = 5 * time.Second
+
+	// ReasonFallback is reported when the flag is answered from the configured defaults,
+	// before the first snapshot is loaded or when the flag is not in the snapshot
+	ReasonFallback = "FALLBACK"
+
+	snapshotPath      = "/api/feature-flags/v1/snapshot"
+	apiKeyHeader      = "X-Api-Key"
+	projectHeader     = "X-Project"
+	environmentHeader = "X-Environment"
+)
+
+type Config struct {
+	// BaseURL is where the service is served, e.g. http://localhost:9696
+	BaseURL string
+	// ApiKey authenticates the client and selects the project of the key
+	ApiKey string
+	// Cookie authenticates the client as a person, used when there is no api key
+	Cookie string
+	// Project is the project key, the default project when empty
+	Project string
+	// Environment is the environment name, the default environment when empty
+	Environment  string
+	PollInterval time.Duration
+	HTTPClient   *http.Client
+	// Defaults and DefaultVariants are served for flags missing from the snapshot
+	// and while no snapshot could be loaded yet
+	Defaults        map[string]bool
+	DefaultVariants map[string]string
+	Logger          *zerolog.Logger
+}
+
+// Person is who the flags are evaluated for, targeting rules read the attributes
+type Person struct {
+	ID         uint
+	Email      string
+	Attributes map[string]interface{}
+}
+
+type Client struct {
+	config     Config
+	httpClient *http.Client
+	logger     *zerolog.Logger
+
+	mu        sync.RWMutex
+	store     *snapshot.Store
+	evaluator *evaluation.EvaluationService
+	updatedAt time.Time
+	etag      string
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func New(config Config) (*Client, error) {
+	if config.BaseURL == "" {
+		return nil, errors.New("base url is required")
+	}
+
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	logger := config.Logger
+	if logger == nil {
+		nop := zerolog.Nop()
+		logger = &nop
+	}
+
+	return &Client{
+		config:     config,
+		httpClient: httpClient,
+		logger:     logger,
+	}, nil
+}
+
+// Start loads the first snapshot and keeps polling in the background until Close is
+// called or the context is done. A failing first load is returned, but the client is
+// still usable with its defaults and keeps trying on every poll
+func (c *Client) Start(ctx context.Context) error {
+	err := c.Refresh(ctx)
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(c.config.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-pollCtx.Done():
+				return
+			case <-ticker.C:
+				if err := c.Refresh(pollCtx); err != nil {
+					c.logger.Error().Err(err).Msg("Keeping the last known flags")
+				}
+			}
+		}
+	}()
+
+	return err
+}
+
+// Close stops the polling started by Start
+func (c *Client) Close() {
+	if c.cancel == nil {
+		return
+	}
+
+	c.cancel()
+	<-c.done
+}
+
+// Refresh fetches the snapshot once, the last known one is kept when it fails
+func (c *Client) Refresh(ctx context.Context) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.BaseURL+snapshotPath, nil)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Accept", "application/json")
+	if c.config.ApiKey != "" {
+		request.Header.Set(apiKeyHeader, c.config.ApiKey)
+	} else if c.config.Cookie != "" {
+		request.Header.Set("Cookie", c.config.Cookie)
+	}
+	if c.config.Project != "" {
+		request.Header.Set(projectHeader, c.config.Project)
+	}
+	if c.config.Environment != "" {
+		request.Header.Set(environmentHeader, c.config.Environment)
+	}
+
+	// unchanged snapshots are answered with 304, the transport asks for gzip on its own
+	c.mu.RLock()
+	etag := c.etag
+	c.mu.RUnlock()
+	if etag != "" {
+		request.Header.Set("If-None-Match", etag)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("error when fetching the flag snapshot: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotModified {
+		c.mu.Lock()
+		c.updatedAt = time.Now()
+		c.mu.Unlock()
+
+		return nil
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("error when fetching the flag snapshot: unexpected status %d", response.StatusCode)
+	}
+
+	var flagSnapshot snapshotEntity.Snapshot
+	if err := json.NewDecoder(response.Body).Decode(&flagSnapshot); err != nil {
+		return fmt.Errorf("error when decoding the flag snapshot: %w", err)
+	}
+
+	c.setSnapshot(flagSnapshot, response.Header.Get("ETag"))
+
+	return nil
+}
+
+func (c *Client) setSnapshot(flagSnapshot snapshotEntity.Snapshot, etag string) {
+	store := snapshot.NewStore(flagSnapshot)
+	// evaluations happen on every request of the consumer, they are not logged
+	nop := zerolog.Nop()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.store = store
+	c.evaluator = evaluation.LoadService(store, store, &nop)
+	c.updatedAt = time.Now()
+	c.etag = etag
+}
+
+// UpdatedAt is when the snapshot in use was loaded, zero while none was
+func (c *Client) UpdatedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.updatedAt
+}
+
+// Snapshot returns the snapshot in use, false while none was loaded
+func (c *Client) Snapshot() (snapshotEntity.Snapshot, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.store == nil {
+		return snapshotEntity.Snapshot{}, false
+	}
+
+	return c.store.Snapshot(), true
+}
+
+// Evaluate evaluates the flag for the person against the last known snapshot
+func (c *Client) Evaluate(ctx context.Context, flag string, person Person) (evaluationEntity.EvaluationResponse, error) {
+	c.mu.RLock()
+	evaluator := c.evaluator
+	c.mu.RUnlock()
+
+	if evaluator == nil {
+		return c.fallback(flag), nil
+	}
+
+	response, err := evaluator.Evaluate(evaluationEntity.EvaluationRequest{
+		FlagName: flag,
+		Context: evaluationEntity.EvaluationContext{
+			PersonID:   person.ID,
+			Email:      person.Email,
+			Attributes: person.Attributes,
+		},
+	})
+	if err != nil {
+		return c.fallback(flag), err
+	}
+
+	if response.Reason == evaluationEntity.ReasonNotFound {
+		return c.fallback(flag), nil
+	}
+
+	return response, nil
+}
+
+// FlagType returns the type of the flag in the last known snapshot, false when the
+// flag is not in it or no snapshot was loaded yet
+func (c *Client) FlagType(flag string) (string, bool) {
+	c.mu.RLock()
+	store := c.store
+	c.mu.RUnlock()
+
+	if store == nil {
+		return "", false
+	}
+
+	featureFlag, _ := store.GetFeatureFlagByName(0, flag, "")
+	if featureFlag.ID == 0 {
+		return "", false
+	}
+
+	if featureFlag.Type == "" {
+		return ff_entity.TypeBoolean, true
+	}
+
+	return featureFlag.Type, true
+}
+
+// IsEnabled reports whether the flag is on for the person
+func (c *Client) IsEnabled(ctx context.Context, flag string, person Person) bool {
+	response, _ := c.Evaluate(ctx, flag, person)
+
+	return response.Value
+}
+
+// Variant returns the variant served to the person and its value decoded to the flag type
+func (c *Client) Variant(ctx context.Context, flag string, person Person) (string, interface{}) {
+	response, _ := c.Evaluate(ctx, flag, person)
+
+	return response.Variant, response.VariantValue
+}
+
+func (c *Client) fallback(flag string) evaluationEntity.EvaluationResponse {
+	return evaluationEntity.EvaluationResponse{
+		FlagName: flag,
+		Value:    c.config.Defaults[flag],
+		Reason:   ReasonFallback,
+		Variant:  c.config.DefaultVariants[flag],
+	}
+}
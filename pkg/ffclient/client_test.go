@@ -0,0 +1,189 @@
+package ffclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	evaluationEntity "ff/internal/evaluation/entity"
+	ff_entity "ff/internal/feature_flag/entity"
+	snapshotEntity "ff/internal/snapshot/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSnapshot = snapshotEntity.Snapshot{
+	ProjectID:   1,
+	Environment: "production",
+	FeatureFlags: []snapshotEntity.FeatureFlag{
+		{
+			ID:          1,
+			Name:        "NEW_CHECKOUT",
+			IsActive:    true,
+			IsGlobal:    true,
+			Assignments: []snapshotEntity.Assignment{{PersonID: 7, Kind: "exclude"}},
+		},
+		{
+			ID:            2,
+			Name:          "NEW_CHECKOUT_PAYPAL",
+			IsActive:      true,
+			Assignments:   []snapshotEntity.Assignment{{PersonID: 1, Kind: "include"}, {PersonID: 7, Kind: "include"}},
+			Prerequisites: []ff_entity.Prerequisite{{FlagName: "NEW_CHECKOUT"}},
+		},
+		{
+			ID:               3,
+			Name:             "BETA_DASHBOARD",
+			IsActive:         true,
+			SegmentPersonIDs: []uint{2},
+			Rules: []ff_entity.Rule{
+				{Attribute: "email", Operator: ff_entity.OperatorEndsWith, Values: []string{"@ourcompany.com"}, Result: true},
+			},
+		},
+		{
+			ID:             4,
+			Name:           "BUTTON_COLOR",
+			IsActive:       true,
+			Type:           ff_entity.TypeString,
+			DefaultVariant: "blue",
+			Variants:       []ff_entity.Variant{{Name: "blue", Value: "#00f", Weight: 0}, {Name: "green", Value: "#0f0", Weight: 100}},
+			Assignments:    []snapshotEntity.Assignment{{PersonID: 1, Kind: "include", Variant: "blue"}},
+			IsGlobal:       true,
+		},
+	},
+}
+
+// newSnapshotServer is a stand-in of the snapshot route, failing while down is set
+func newSnapshotServer(t *testing.T, down *atomic.Bool, requests *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		assert.Equal(t, snapshotPath, r.URL.Path)
+		assert.Equal(t, "ffk_test", r.Header.Get(apiKeyHeader))
+		assert.Equal(t, "staging", r.Header.Get(environmentHeader))
+
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		etag := `W/"1-staging-1"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(testSnapshot)
+	}))
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Evaluates the snapshot locally", func(t *testing.T) {
+		var down atomic.Bool
+		var requests atomic.Int32
+		server := newSnapshotServer(t, &down, &requests)
+		defer server.Close()
+
+		client, err := New(Config{BaseURL: server.URL, ApiKey: "ffk_test", Environment: "staging"})
+		require.NoError(t, err)
+		require.NoError(t, client.Refresh(ctx))
+
+		assert.True(t, client.IsEnabled(ctx, "NEW_CHECKOUT", Person{ID: 1}))
+		assert.False(t, client.IsEnabled(ctx, "NEW_CHECKOUT", Person{ID: 7}), "exclusions win over global")
+
+		assert.True(t, client.IsEnabled(ctx, "NEW_CHECKOUT_PAYPAL", Person{ID: 1}))
+		response, err := client.Evaluate(ctx, "NEW_CHECKOUT_PAYPAL", Person{ID: 7})
+		require.NoError(t, err)
+		assert.False(t, response.Value)
+		assert.Equal(t, evaluationEntity.ReasonPrerequisite, response.Reason)
+
+		assert.True(t, client.IsEnabled(ctx, "BETA_DASHBOARD", Person{ID: 2}))
+		assert.True(t, client.IsEnabled(ctx, "BETA_DASHBOARD", Person{ID: 3, Email: "jane@ourcompany.com"}))
+		assert.False(t, client.IsEnabled(ctx, "BETA_DASHBOARD", Person{ID: 3, Email: "john@competitor.com"}))
+
+		variant, value := client.Variant(ctx, "BUTTON_COLOR", Person{ID: 1})
+		assert.Equal(t, "blue", variant)
+		assert.Equal(t, "#00f", value)
+		variant, _ = client.Variant(ctx, "BUTTON_COLOR", Person{ID: 2})
+		assert.Equal(t, "green", variant)
+	})
+
+	t.Run("Serves the defaults until a snapshot is loaded", func(t *testing.T) {
+		var down atomic.Bool
+		var requests atomic.Int32
+		down.Store(true)
+		server := newSnapshotServer(t, &down, &requests)
+		defer server.Close()
+
+		client, err := New(Config{
+			BaseURL:         server.URL,
+			ApiKey:          "ffk_test",
+			Environment:     "staging",
+			Defaults:        map[string]bool{"NEW_CHECKOUT": true},
+			DefaultVariants: map[string]string{"BUTTON_COLOR": "blue"},
+		})
+		require.NoError(t, err)
+		assert.Error(t, client.Refresh(ctx))
+
+		response, err := client.Evaluate(ctx, "NEW_CHECKOUT", Person{ID: 7})
+		require.NoError(t, err)
+		assert.True(t, response.Value)
+		assert.Equal(t, ReasonFallback, response.Reason)
+
+		variant, _ := client.Variant(ctx, "BUTTON_COLOR", Person{ID: 1})
+		assert.Equal(t, "blue", variant)
+		assert.False(t, client.IsEnabled(ctx, "UNKNOWN_FLAG", Person{ID: 1}))
+		assert.True(t, client.UpdatedAt().IsZero())
+	})
+
+	t.Run("Keeps the last known snapshot when the server is down", func(t *testing.T) {
+		var down atomic.Bool
+		var requests atomic.Int32
+		server := newSnapshotServer(t, &down, &requests)
+		defer server.Close()
+
+		client, err := New(Config{BaseURL: server.URL, ApiKey: "ffk_test", Environment: "staging", PollInterval: 10 * time.Millisecond})
+		require.NoError(t, err)
+		require.NoError(t, client.Start(ctx))
+		defer client.Close()
+
+		down.Store(true)
+		assert.Eventually(t, func() bool { return requests.Load() >= 3 }, time.Second, 5*time.Millisecond)
+
+		assert.True(t, client.IsEnabled(ctx, "NEW_CHECKOUT", Person{ID: 1}))
+		assert.False(t, client.IsEnabled(ctx, "NEW_CHECKOUT", Person{ID: 7}))
+		assert.False(t, client.UpdatedAt().IsZero())
+	})
+
+	t.Run("Unchanged snapshot is not loaded again", func(t *testing.T) {
+		var down atomic.Bool
+		var requests atomic.Int32
+		server := newSnapshotServer(t, &down, &requests)
+		defer server.Close()
+
+		client, err := New(Config{BaseURL: server.URL, ApiKey: "ffk_test", Environment: "staging"})
+		require.NoError(t, err)
+		require.NoError(t, client.Refresh(ctx))
+		loadedAt := client.UpdatedAt()
+
+		require.NoError(t, client.Refresh(ctx))
+
+		assert.Equal(t, int32(2), requests.Load())
+		assert.True(t, client.UpdatedAt().After(loadedAt))
+		assert.True(t, client.IsEnabled(ctx, "NEW_CHECKOUT", Person{ID: 1}))
+	})
+
+	t.Run("Base url is required", func(t *testing.T) {
+		_, err := New(Config{})
+
+		assert.EqualError(t, err, "base url is required")
+	})
+}
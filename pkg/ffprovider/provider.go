@@ -0,0 +1,213 @@
+// Package ffprovider is the OpenFeature provider of the feature flag service. It resolves
+// flags with the Go client (ffclient), locally against the polled snapshot, so teams use
+// the OpenFeature API without depending on the routes of the service
+package ffprovider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	evaluationEntity "ff/internal/evaluation/entity"
+	ff_entity "ff/internal/feature_flag/entity"
+	"ff/pkg/ffclient"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const (
+	ProviderName = "ff"
+
+	// EmailKey is the evaluation context key read as the email of the person, every
+	// other key but the targeting key is passed to the targeting rules as an attribute
+	EmailKey = "email"
+
+	// MetadataReason is the flag metadata key with the reason reported by the service
+	MetadataReason = "reason"
+)
+
+type Provider struct {
+	client *ffclient.Client
+}
+
+// New builds the provider and its client, the client is started by Init, which
+// openfeature.SetProviderAndWait calls, and stopped by Shutdown
+func New(config ffclient.Config) (*Provider, error) {
+	client, err := ffclient.New(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Provider{client: client}, nil
+}
+
+func (p *Provider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{Name: ProviderName}
+}
+
+func (p *Provider) Hooks() []openfeature.Hook {
+	return []openfeature.Hook{}
+}
+
+// Init loads the first snapshot, a failure leaves the provider in the error state but
+// the client keeps polling in the background
+func (p *Provider) Init(evaluationContext openfeature.EvaluationContext) error {
+	return p.client.Start(context.Background())
+}
+
+func (p *Provider) Shutdown() {
+	p.client.Close()
+}
+
+func (p *Provider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, flatCtx openfeature.FlattenedContext) openfeature.BoolResolutionDetail {
+	response, detail := p.resolve(ctx, flag, ff_entity.TypeBoolean, flatCtx)
+	if detail.Error() != nil {
+		return openfeature.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	return openfeature.BoolResolutionDetail{Value: response.Value, ProviderResolutionDetail: detail}
+}
+
+func (p *Provider) StringEvaluation(ctx context.Context, flag string, defaultValue string, flatCtx openfeature.FlattenedContext) openfeature.StringResolutionDetail {
+	response, detail := p.resolve(ctx, flag, ff_entity.TypeString, flatCtx)
+	if detail.Error() != nil {
+		return openfeature.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	value, ok := response.VariantValue.(string)
+	if !ok {
+		return openfeature.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	return openfeature.StringResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+func (p *Provider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, flatCtx openfeature.FlattenedContext) openfeature.FloatResolutionDetail {
+	response, detail := p.resolve(ctx, flag, ff_entity.TypeNumber, flatCtx)
+	if detail.Error() != nil {
+		return openfeature.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	value, ok := response.VariantValue.(float64)
+	if !ok {
+		return openfeature.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	return openfeature.FloatResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// IntEvaluation resolves number flags, a variant with a fraction is a type mismatch
+func (p *Provider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, flatCtx openfeature.FlattenedContext) openfeature.IntResolutionDetail {
+	response, detail := p.resolve(ctx, flag, ff_entity.TypeNumber, flatCtx)
+	if detail.Error() != nil {
+		return openfeature.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	value, ok := response.VariantValue.(float64)
+	if !ok {
+		return openfeature.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	if value != math.Trunc(value) {
+		return openfeature.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: errorDetail(
+			openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("variant value %v of %s is not an integer", value, flag)),
+		)}
+	}
+
+	return openfeature.IntResolutionDetail{Value: int64(value), ProviderResolutionDetail: detail}
+}
+
+func (p *Provider) ObjectEvaluation(ctx context.Context, flag string, defaultValue any, flatCtx openfeature.FlattenedContext) openfeature.InterfaceResolutionDetail {
+	response, detail := p.resolve(ctx, flag, ff_entity.TypeJSON, flatCtx)
+	if detail.Error() != nil || response.VariantValue == nil {
+		return openfeature.InterfaceResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+
+	return openfeature.InterfaceResolutionDetail{Value: response.VariantValue, ProviderResolutionDetail: detail}
+}
+
+// resolve evaluates the flag once it is known to have the expected type, the resolution
+// error of the detail is set when the default value has to be served
+func (p *Provider) resolve(ctx context.Context, flag string, flagType string, flatCtx openfeature.FlattenedContext) (evaluationEntity.EvaluationResponse, openfeature.ProviderResolutionDetail) {
+	if p.client.UpdatedAt().IsZero() {
+		return evaluationEntity.EvaluationResponse{}, errorDetail(openfeature.NewProviderNotReadyResolutionError("no flag snapshot was loaded yet"))
+	}
+
+	actualType, ok := p.client.FlagType(flag)
+	if !ok {
+		return evaluationEntity.EvaluationResponse{}, errorDetail(openfeature.NewFlagNotFoundResolutionError(fmt.Sprintf("flag %s not found", flag)))
+	}
+
+	if actualType != flagType {
+		return evaluationEntity.EvaluationResponse{}, errorDetail(openfeature.NewTypeMismatchResolutionError(fmt.Sprintf("flag %s is a %s flag, not %s", flag, actualType, flagType)))
+	}
+
+	person, err := personFromContext(flatCtx)
+	if err != nil {
+		return evaluationEntity.EvaluationResponse{}, errorDetail(openfeature.NewInvalidContextResolutionError(err.Error()))
+	}
+
+	response, err := p.client.Evaluate(ctx, flag, person)
+	if err != nil {
+		return evaluationEntity.EvaluationResponse{}, errorDetail(openfeature.NewGeneralResolutionError(err.Error()))
+	}
+
+	return response, openfeature.ProviderResolutionDetail{
+		Reason:       reasonFromEvaluation(response.Reason),
+		Variant:      response.Variant,
+		FlagMetadata: openfeature.FlagMetadata{MetadataReason: response.Reason},
+	}
+}
+
+// personFromContext maps the targeting key to the person id, people are identified by
+// their numeric id. Without a targeting key the person is anonymous and only the rules apply
+func personFromContext(flatCtx openfeature.FlattenedContext) (ffclient.Person, error) {
+	person := ffclient.Person{Attributes: map[string]interface{}{}}
+
+	for key, value := range flatCtx {
+		switch key {
+		case openfeature.TargetingKey:
+			targetingKey := fmt.Sprint(value)
+			if targetingKey == "" {
+				continue
+			}
+
+			id, err := strconv.ParseUint(targetingKey, 10, 64)
+			if err != nil {
+				return ffclient.Person{}, fmt.Errorf("targeting key %s is not a person id", targetingKey)
+			}
+			person.ID = uint(id)
+		case EmailKey:
+			person.Email = fmt.Sprint(value)
+		default:
+			person.Attributes[key] = value
+		}
+	}
+
+	return person, nil
+}
+
+func reasonFromEvaluation(reason string) openfeature.Reason {
+	switch reason {
+	case evaluationEntity.ReasonFlagInactive, evaluationEntity.ReasonExpired, evaluationEntity.ReasonPrerequisite:
+		return openfeature.DisabledReason
+	case evaluationEntity.ReasonAssigned, evaluationEntity.ReasonExcluded, evaluationEntity.ReasonSegment, evaluationEntity.ReasonTargeting:
+		return openfeature.TargetingMatchReason
+	case evaluationEntity.ReasonGlobal:
+		return openfeature.StaticReason
+	case evaluationEntity.ReasonRollout:
+		return openfeature.SplitReason
+	case evaluationEntity.ReasonDefault:
+		return openfeature.DefaultReason
+	}
+
+	return openfeature.UnknownReason
+}
+
+func errorDetail(resolutionError openfeature.ResolutionError) openfeature.ProviderResolutionDetail {
+	return openfeature.ProviderResolutionDetail{
+		ResolutionError: resolutionError,
+		Reason:          openfeature.ErrorReason,
+	}
+}
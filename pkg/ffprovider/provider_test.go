@@ -0,0 +1,188 @@
+package ffprovider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ff_entity "ff/internal/feature_flag/entity"
+	snapshotEntity "ff/internal/snapshot/entity"
+	"ff/pkg/ffclient"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSnapshot = snapshotEntity.Snapshot{
+	ProjectID:   1,
+	Environment: "production",
+	FeatureFlags: []snapshotEntity.FeatureFlag{
+		{
+			ID:          1,
+			Name:        "NEW_CHECKOUT",
+			IsActive:    true,
+			IsGlobal:    true,
+			Assignments: []snapshotEntity.Assignment{{PersonID: 7, Kind: "exclude"}},
+		},
+		{
+			ID:       2,
+			Name:     "BRAZIL_ONLY",
+			IsActive: true,
+			Rules: []ff_entity.Rule{
+				{Attribute: "country", Operator: ff_entity.OperatorEquals, Values: []string{"BR"}, Result: true},
+			},
+		},
+		{
+			ID:             3,
+			Name:           "BUTTON_COLOR",
+			IsActive:       true,
+			IsGlobal:       true,
+			Type:           ff_entity.TypeString,
+			DefaultVariant: "blue",
+			Variants:       []ff_entity.Variant{{Name: "blue", Value: "#00f", Weight: 0}, {Name: "green", Value: "#0f0", Weight: 100}},
+		},
+		{
+			ID:             4,
+			Name:           "PAGE_SIZE",
+			IsActive:       true,
+			IsGlobal:       true,
+			Type:           ff_entity.TypeNumber,
+			DefaultVariant: "small",
+			Variants:       []ff_entity.Variant{{Name: "small", Value: "10", Weight: 0}, {Name: "large", Value: "50", Weight: 100}},
+		},
+		{
+			ID:             5,
+			Name:           "DISCOUNT",
+			IsActive:       true,
+			IsGlobal:       true,
+			Type:           ff_entity.TypeNumber,
+			DefaultVariant: "half",
+			Variants:       []ff_entity.Variant{{Name: "half", Value: "0.5", Weight: 100}},
+		},
+		{
+			ID:             6,
+			Name:           "THEME",
+			IsActive:       false,
+			Type:           ff_entity.TypeJSON,
+			DefaultVariant: "light",
+			Variants:       []ff_entity.Variant{{Name: "light", Value: `{"background":"#fff"}`, Weight: 100}},
+		},
+	},
+}
+
+func newTestProvider(t *testing.T) *Provider {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(testSnapshot)
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := New(ffclient.Config{BaseURL: server.URL, ApiKey: "ffk_test"})
+	require.NoError(t, err)
+
+	return provider
+}
+
+func TestProvider(t *testing.T) {
+	ctx := context.Background()
+
+	provider := newTestProvider(t)
+	require.NoError(t, provider.Init(openfeature.EvaluationContext{}))
+	defer provider.Shutdown()
+
+	t.Run("Boolean resolution with the person of the context", func(t *testing.T) {
+		detail := provider.BooleanEvaluation(ctx, "NEW_CHECKOUT", false, openfeature.FlattenedContext{openfeature.TargetingKey: "1"})
+		assert.NoError(t, detail.Error())
+		assert.True(t, detail.Value)
+		assert.Equal(t, openfeature.StaticReason, detail.Reason)
+		assert.Equal(t, "GLOBAL", detail.FlagMetadata[MetadataReason])
+
+		detail = provider.BooleanEvaluation(ctx, "NEW_CHECKOUT", true, openfeature.FlattenedContext{openfeature.TargetingKey: "7"})
+		assert.NoError(t, detail.Error())
+		assert.False(t, detail.Value)
+		assert.Equal(t, openfeature.TargetingMatchReason, detail.Reason)
+	})
+
+	t.Run("Attributes of the context reach the targeting rules", func(t *testing.T) {
+		detail := provider.BooleanEvaluation(ctx, "BRAZIL_ONLY", false, openfeature.FlattenedContext{"country": "BR"})
+		assert.True(t, detail.Value)
+		assert.Equal(t, openfeature.TargetingMatchReason, detail.Reason)
+
+		detail = provider.BooleanEvaluation(ctx, "BRAZIL_ONLY", true, openfeature.FlattenedContext{"country": "AR"})
+		assert.NoError(t, detail.Error())
+		assert.False(t, detail.Value)
+		assert.Equal(t, openfeature.DefaultReason, detail.Reason)
+	})
+
+	t.Run("String, number and object resolution", func(t *testing.T) {
+		stringDetail := provider.StringEvaluation(ctx, "BUTTON_COLOR", "#000", openfeature.FlattenedContext{openfeature.TargetingKey: "1"})
+		assert.Equal(t, "#0f0", stringDetail.Value)
+		assert.Equal(t, "green", stringDetail.Variant)
+
+		floatDetail := provider.FloatEvaluation(ctx, "DISCOUNT", 0, openfeature.FlattenedContext{})
+		assert.Equal(t, 0.5, floatDetail.Value)
+
+		intDetail := provider.IntEvaluation(ctx, "PAGE_SIZE", 20, openfeature.FlattenedContext{openfeature.TargetingKey: "1"})
+		assert.NoError(t, intDetail.Error())
+		assert.Equal(t, int64(50), intDetail.Value)
+
+		objectDetail := provider.ObjectEvaluation(ctx, "THEME", nil, openfeature.FlattenedContext{})
+		assert.Equal(t, map[string]interface{}{"background": "#fff"}, objectDetail.Value)
+		assert.Equal(t, openfeature.DisabledReason, objectDetail.Reason)
+	})
+
+	t.Run("Flag not found", func(t *testing.T) {
+		detail := provider.BooleanEvaluation(ctx, "UNKNOWN_FLAG", true, openfeature.FlattenedContext{})
+		assert.True(t, detail.Value)
+		assert.Equal(t, openfeature.FlagNotFoundCode, detail.ResolutionDetail().ErrorCode)
+		assert.Equal(t, openfeature.ErrorReason, detail.Reason)
+	})
+
+	t.Run("Type mismatch", func(t *testing.T) {
+		detail := provider.StringEvaluation(ctx, "NEW_CHECKOUT", "off", openfeature.FlattenedContext{})
+		assert.Equal(t, "off", detail.Value)
+		assert.Equal(t, openfeature.TypeMismatchCode, detail.ResolutionDetail().ErrorCode)
+
+		intDetail := provider.IntEvaluation(ctx, "DISCOUNT", 1, openfeature.FlattenedContext{})
+		assert.Equal(t, int64(1), intDetail.Value)
+		assert.Equal(t, openfeature.TypeMismatchCode, intDetail.ResolutionDetail().ErrorCode)
+	})
+
+	t.Run("Targeting key must be a person id", func(t *testing.T) {
+		detail := provider.BooleanEvaluation(ctx, "NEW_CHECKOUT", false, openfeature.FlattenedContext{openfeature.TargetingKey: "jane"})
+		assert.False(t, detail.Value)
+		assert.Equal(t, openfeature.InvalidContextCode, detail.ResolutionDetail().ErrorCode)
+	})
+}
+
+func TestProviderNotReady(t *testing.T) {
+	provider := newTestProvider(t)
+
+	detail := provider.BooleanEvaluation(context.Background(), "NEW_CHECKOUT", true, openfeature.FlattenedContext{})
+
+	assert.True(t, detail.Value)
+	assert.Equal(t, openfeature.ProviderNotReadyCode, detail.ResolutionDetail().ErrorCode)
+}
+
+func TestOpenFeatureClient(t *testing.T) {
+	provider := newTestProvider(t)
+	require.NoError(t, openfeature.SetNamedProviderAndWait("ffprovider-test", provider))
+	defer openfeature.Shutdown()
+
+	client := openfeature.NewClient("ffprovider-test")
+	evaluationContext := openfeature.NewEvaluationContext("7", map[string]interface{}{"country": "BR"})
+
+	enabled, err := client.BooleanValue(context.Background(), "NEW_CHECKOUT", true, evaluationContext)
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+
+	enabled, err = client.BooleanValue(context.Background(), "BRAZIL_ONLY", false, evaluationContext)
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+
+	_, err = client.BooleanValue(context.Background(), "UNKNOWN_FLAG", false, evaluationContext)
+	assert.Error(t, err)
+}
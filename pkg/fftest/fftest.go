@@ -0,0 +1,379 @@
+// Package fftest is an in-memory fake of the feature flag service for the unit tests of
+// its consumers. Flags are set per test, the store answers the same questions as the
+// service and can be served over httptest to code calling the real routes:
+//
+//	store := fftest.New()
+//	store.Enable("NEW_CHECKOUT")
+//	store.DisableFor("NEW_CHECKOUT", 7)
+//	store.SetVariant("CHECKOUT_COLOR", "blue", "#00f")
+//
+//	server := store.Server(t)
+//	app := newApp(server.URL)
+//	...
+//	store.AssertEvaluated(t, "NEW_CHECKOUT", 7)
+package fftest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	evaluationEntity "ff/internal/evaluation/entity"
+	p_entity "ff/internal/person/entity"
+)
+
+// scopedPrefixes are the prefixes the service serves its routes under, the fake answers
+// the same flags whatever the project and the environment are
+var scopedPrefixes = []string{
+	"/v1",
+	"/v1/environments/{environment}",
+	"/v1/projects/{project}",
+	"/v1/projects/{project}/environments/{environment}",
+}
+
+// Evaluation is a flag asked for a person, through Evaluate or the evaluate route
+type Evaluation struct {
+	FlagName string
+	PersonID uint
+	Value    bool
+}
+
+type state struct {
+	isActive     bool
+	variant      string
+	variantValue interface{}
+}
+
+type flag struct {
+	id       uint
+	name     string
+	fallback state
+	people   map[uint]state
+}
+
+type Store struct {
+	mu          sync.Mutex
+	flags       map[string]*flag
+	evaluations []Evaluation
+	fetched     []uint
+}
+
+func New() *Store {
+	return &Store{
+		flags: make(map[string]*flag),
+	}
+}
+
+// Enable turns the flag on for everyone, the people set with DisableFor stay off
+func (s *Store) Enable(name string) {
+	s.update(name, func(f *flag) {
+		f.fallback.isActive = true
+	})
+}
+
+// Disable turns the flag off for everyone, the people set with EnableFor stay on
+func (s *Store) Disable(name string) {
+	s.update(name, func(f *flag) {
+		f.fallback.isActive = false
+	})
+}
+
+// EnableFor turns the flag on for the people only
+func (s *Store) EnableFor(name string, personIds ...uint) {
+	s.update(name, func(f *flag) {
+		for _, personId := range personIds {
+			personState := f.stateOf(personId)
+			personState.isActive = true
+			f.people[personId] = personState
+		}
+	})
+}
+
+// DisableFor turns the flag off for the people only
+func (s *Store) DisableFor(name string, personIds ...uint) {
+	s.update(name, func(f *flag) {
+		for _, personId := range personIds {
+			personState := f.stateOf(personId)
+			personState.isActive = false
+			f.people[personId] = personState
+		}
+	})
+}
+
+// SetVariant turns the flag on for everyone and serves the variant to them
+func (s *Store) SetVariant(name string, variant string, value interface{}) {
+	s.update(name, func(f *flag) {
+		f.fallback = state{isActive: true, variant: variant, variantValue: value}
+	})
+}
+
+// SetVariantFor turns the flag on for the people only and serves the variant to them
+func (s *Store) SetVariantFor(name string, variant string, value interface{}, personIds ...uint) {
+	s.update(name, func(f *flag) {
+		for _, personId := range personIds {
+			f.people[personId] = state{isActive: true, variant: variant, variantValue: value}
+		}
+	})
+}
+
+// Reset removes every flag and forgets the evaluations
+func (s *Store) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flags = make(map[string]*flag)
+	s.evaluations = nil
+	s.fetched = nil
+}
+
+func (s *Store) update(name string, change func(f *flag)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.flags[name]
+	if !ok {
+		f = &flag{id: uint(len(s.flags) + 1), name: name, people: make(map[uint]state)}
+		s.flags[name] = f
+	}
+
+	change(f)
+}
+
+// stateOf is the state of the flag for the person, what everyone has when nothing was set for them
+func (f *flag) stateOf(personId uint) state {
+	if personState, ok := f.people[personId]; ok {
+		return personState
+	}
+
+	return f.fallback
+}
+
+// AssignedFeatureFlags answers as /v1/people/:id/assigned-feature-flags does, with the
+// flags on for the person. A flag missing is off
+func (s *Store) AssignedFeatureFlags(personId uint) []p_entity.AssignedFeatureFlagResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fetched = append(s.fetched, personId)
+
+	featureFlags := []p_entity.AssignedFeatureFlagResponse{}
+	for _, f := range s.sortedFlags() {
+		personState := f.stateOf(personId)
+		if !personState.isActive {
+			continue
+		}
+
+		featureFlags = append(featureFlags, p_entity.AssignedFeatureFlagResponse{
+			ID:           f.id,
+			Name:         f.name,
+			IsActive:     true,
+			IsAssigned:   true,
+			Variant:      personState.variant,
+			VariantValue: personState.variantValue,
+		})
+	}
+
+	return featureFlags
+}
+
+func (s *Store) sortedFlags() []*flag {
+	flags := make([]*flag, 0, len(s.flags))
+	for _, f := range s.flags {
+		flags = append(flags, f)
+	}
+
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].id < flags[j].id
+	})
+
+	return flags
+}
+
+// Evaluate answers as /v1/evaluate does and records the evaluation
+func (s *Store) Evaluate(name string, personId uint) evaluationEntity.EvaluationResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	response := evaluationEntity.EvaluationResponse{FlagName: name}
+
+	f, ok := s.flags[name]
+	if !ok {
+		response.Reason = evaluationEntity.ReasonNotFound
+	} else {
+		personState, forPerson := f.people[personId]
+		if !forPerson {
+			personState = f.fallback
+		}
+
+		response.Value = personState.isActive
+		switch {
+		case personState.isActive && forPerson:
+			response.Reason = evaluationEntity.ReasonAssigned
+		case personState.isActive:
+			response.Reason = evaluationEntity.ReasonGlobal
+		case forPerson:
+			response.Reason = evaluationEntity.ReasonExcluded
+		default:
+			response.Reason = evaluationEntity.ReasonFlagInactive
+		}
+
+		if personState.isActive {
+			response.Variant = personState.variant
+			response.VariantValue = personState.variantValue
+		}
+	}
+
+	s.evaluations = append(s.evaluations, Evaluation{FlagName: name, PersonID: personId, Value: response.Value})
+
+	return response
+}
+
+// IsEnabled reports whether the flag is on for the person and records the evaluation
+func (s *Store) IsEnabled(name string, personId uint) bool {
+	return s.Evaluate(name, personId).Value
+}
+
+// Variant returns the variant served to the person and records the evaluation
+func (s *Store) Variant(name string, personId uint) (string, interface{}) {
+	response := s.Evaluate(name, personId)
+
+	return response.Variant, response.VariantValue
+}
+
+// Evaluations are the evaluations recorded so far, in order
+func (s *Store) Evaluations() []Evaluation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Evaluation{}, s.evaluations...)
+}
+
+// AssertEvaluated fails the test unless the flag was evaluated, for every one of the
+// people when some are given
+func (s *Store) AssertEvaluated(t testing.TB, name string, personIds ...uint) bool {
+	t.Helper()
+
+	evaluations := s.Evaluations()
+
+	if len(personIds) == 0 {
+		for _, evaluation := range evaluations {
+			if evaluation.FlagName == name {
+				return true
+			}
+		}
+
+		t.Errorf("fftest: flag %q was not evaluated", name)
+		return false
+	}
+
+	ok := true
+	for _, personId := range personIds {
+		found := false
+		for _, evaluation := range evaluations {
+			if evaluation.FlagName == name && evaluation.PersonID == personId {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t.Errorf("fftest: flag %q was not evaluated for person %d", name, personId)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// AssertNotEvaluated fails the test when the flag was evaluated for anyone
+func (s *Store) AssertNotEvaluated(t testing.TB, name string) bool {
+	t.Helper()
+
+	for _, evaluation := range s.Evaluations() {
+		if evaluation.FlagName == name {
+			t.Errorf("fftest: flag %q was evaluated for person %d", name, evaluation.PersonID)
+			return false
+		}
+	}
+
+	return true
+}
+
+// AssertFetched fails the test unless the flags of the person were fetched from the
+// assigned-feature-flags route or AssignedFeatureFlags
+func (s *Store) AssertFetched(t testing.TB, personId uint) bool {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, fetched := range s.fetched {
+		if fetched == personId {
+			return true
+		}
+	}
+
+	t.Errorf("fftest: flags of person %d were not fetched", personId)
+	return false
+}
+
+// Handler serves the consumer routes of the service from the store, under every prefix
+// the service has. Authentication headers are accepted but not checked
+func (s *Store) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	for _, prefix := range scopedPrefixes {
+		mux.HandleFunc("GET /api/feature-flags"+prefix+"/people/{id}/assigned-feature-flags", s.assignedFeatureFlagsHandler)
+		mux.HandleFunc("POST /api/feature-flags"+prefix+"/evaluate", s.evaluateHandler)
+	}
+
+	return mux
+}
+
+// Server serves the store over httptest until the end of the test
+func (s *Store) Server(t testing.TB) *httptest.Server {
+	server := httptest.NewServer(s.Handler())
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func (s *Store) assignedFeatureFlagsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "person id is not a number"})
+		return
+	}
+
+	featureFlags := s.AssignedFeatureFlags(uint(id))
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"items": featureFlags,
+		"total": len(featureFlags),
+	})
+}
+
+func (s *Store) evaluateHandler(w http.ResponseWriter, r *http.Request) {
+	var request evaluationEntity.EvaluationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.Evaluate(request.FlagName, request.Context.PersonID))
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,153 @@
+package fftest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"ff/api/middlewares/flags"
+	evaluationEntity "ff/internal/evaluation/entity"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder keeps the failures of an assertion instead of failing the test
+type recorder struct {
+	testing.TB
+	errors int
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors++
+}
+
+func TestStore(t *testing.T) {
+	store := New()
+	store.Enable("NEW_CHECKOUT")
+	store.DisableFor("NEW_CHECKOUT", 7)
+	store.EnableFor("BETA", 7)
+	store.SetVariant("CHECKOUT_COLOR", "blue", "#00f")
+	store.SetVariantFor("CHECKOUT_COLOR", "red", "#f00", 7)
+
+	t.Run("Flags of a person", func(t *testing.T) {
+		names := map[string]string{}
+		for _, featureFlag := range store.AssignedFeatureFlags(7) {
+			names[featureFlag.Name] = featureFlag.Variant
+		}
+		assert.Equal(t, map[string]string{"BETA": "", "CHECKOUT_COLOR": "red"}, names)
+
+		names = map[string]string{}
+		for _, featureFlag := range store.AssignedFeatureFlags(8) {
+			names[featureFlag.Name] = featureFlag.Variant
+		}
+		assert.Equal(t, map[string]string{"NEW_CHECKOUT": "", "CHECKOUT_COLOR": "blue"}, names)
+	})
+
+	t.Run("Evaluations", func(t *testing.T) {
+		tests := []struct {
+			flag     string
+			personId uint
+			value    bool
+			reason   string
+		}{
+			{flag: "NEW_CHECKOUT", personId: 8, value: true, reason: evaluationEntity.ReasonGlobal},
+			{flag: "NEW_CHECKOUT", personId: 7, value: false, reason: evaluationEntity.ReasonExcluded},
+			{flag: "BETA", personId: 7, value: true, reason: evaluationEntity.ReasonAssigned},
+			{flag: "BETA", personId: 8, value: false, reason: evaluationEntity.ReasonFlagInactive},
+			{flag: "UNKNOWN", personId: 8, value: false, reason: evaluationEntity.ReasonNotFound},
+		}
+
+		for _, tt := range tests {
+			response := store.Evaluate(tt.flag, tt.personId)
+			assert.Equal(t, tt.value, response.Value, tt.flag)
+			assert.Equal(t, tt.reason, response.Reason, tt.flag)
+		}
+
+		variant, value := store.Variant("CHECKOUT_COLOR", 8)
+		assert.Equal(t, "blue", variant)
+		assert.Equal(t, "#00f", value)
+	})
+
+	t.Run("Assertions", func(t *testing.T) {
+		assert.True(t, store.AssertEvaluated(t, "BETA", 7, 8))
+		assert.True(t, store.AssertNotEvaluated(t, "NEVER"))
+		assert.True(t, store.AssertFetched(t, 7))
+
+		failing := &recorder{TB: t}
+		assert.False(t, store.AssertEvaluated(failing, "BETA", 9))
+		assert.False(t, store.AssertNotEvaluated(failing, "BETA"))
+		assert.False(t, store.AssertFetched(failing, 9))
+		assert.Equal(t, 3, failing.errors)
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		store.Reset()
+
+		assert.Empty(t, store.AssignedFeatureFlags(8))
+		assert.Equal(t, 0, len(store.Evaluations()))
+	})
+}
+
+func TestServer(t *testing.T) {
+	store := New()
+	store.EnableFor("NEW_CHECKOUT", 7)
+	server := store.Server(t)
+
+	t.Run("Assigned feature flags under every prefix", func(t *testing.T) {
+		for _, path := range []string{
+			"/api/feature-flags/v1/people/7/assigned-feature-flags",
+			"/api/feature-flags/v1/projects/mobile/environments/staging/people/7/assigned-feature-flags",
+		} {
+			response, err := http.Get(server.URL + path)
+			require.NoError(t, err)
+
+			var body struct {
+				Items []map[string]interface{} `json:"items"`
+				Total int                      `json:"total"`
+			}
+			require.NoError(t, json.NewDecoder(response.Body).Decode(&body))
+			response.Body.Close()
+
+			assert.Equal(t, 1, body.Total)
+			assert.Equal(t, "NEW_CHECKOUT", body.Items[0]["name"])
+		}
+	})
+
+	t.Run("Evaluate", func(t *testing.T) {
+		request, _ := json.Marshal(map[string]interface{}{"flagName": "NEW_CHECKOUT", "context": map[string]interface{}{"personId": 7}})
+		response, err := http.Post(server.URL+"/api/feature-flags/v1/evaluate", "application/json", bytes.NewReader(request))
+		require.NoError(t, err)
+		defer response.Body.Close()
+
+		var evaluation evaluationEntity.EvaluationResponse
+		require.NoError(t, json.NewDecoder(response.Body).Decode(&evaluation))
+		assert.True(t, evaluation.Value)
+
+		store.AssertEvaluated(t, "NEW_CHECKOUT", 7)
+	})
+
+	t.Run("Backs the flags middleware", func(t *testing.T) {
+		e := echo.New()
+		e.Use(flags.Middleware(flags.Config{
+			BaseURL: server.URL,
+			PersonID: func(c echo.Context) (uint, error) {
+				return 7, nil
+			},
+		}))
+		e.GET("/checkout", func(c echo.Context) error {
+			return c.String(http.StatusOK, "new checkout")
+		}, flags.Require("NEW_CHECKOUT"))
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/checkout", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		store.AssertFetched(t, 7)
+	})
+}
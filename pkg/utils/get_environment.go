@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"ff/internal/db/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetEnvironment returns the environment resolved by the environment middleware
+func GetEnvironment(c echo.Context) string {
+	environment, ok := c.Get("environment").(string)
+	if !ok || environment == "" {
+		return model.DefaultEnvironment
+	}
+
+	return environment
+}
@@ -0,0 +1,12 @@
+package utils
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+// GetProject returns the id of the project resolved by the project middleware
+func GetProject(c echo.Context) uint {
+	projectId, _ := c.Get("project").(uint)
+
+	return projectId
+}
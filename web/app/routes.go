@@ -6,8 +6,15 @@ import (
 	"ff/config/database"
 	assignment "ff/internal/assignment"
 	"ff/internal/db/mysql"
+	"ff/internal/environment"
 	featureflag "ff/internal/feature_flag"
 	person "ff/internal/person"
+	project "ff/internal/project"
+	"ff/internal/revision"
+	scheduledchange "ff/internal/scheduled_change"
+	"ff/internal/snapshot"
+	"ff/internal/stream"
+	"ff/internal/webhook"
 	handler "ff/web/handlers"
 	"fmt"
 	"net/http"
@@ -18,24 +25,50 @@ import (
 	"github.com/rs/zerolog"
 )
 
-func loadServices() (*featureflag.FeatureFlagService, *assignment.AssignmentService, *person.PeopleService) {
+func loadServices() (*featureflag.FeatureFlagService, *assignment.AssignmentService, *person.PeopleService, *scheduledchange.ScheduledChangeService, *environment.EnvironmentService, *project.ProjectService) {
 	logger := zerolog.New(os.Stdout)
 
 	config.LoadAppConfig(&logger)
 	ddb := database.DDB{Logger: &logger}
 
-	db := ddb.Connect(config.AppConfig.ConnectionString)
-	ddb.RunMigrations(db)
+	db := ddb.Connect(config.AppConfig.DatabaseDriver, config.AppConfig.ConnectionString)
+	ddb.CheckMigrations(db, config.AppConfig.DatabaseDriver)
 
 	featureFlagRepository := mysql.NewSqlFeatureFlagRepository(db, &logger)
 	assignmentRepository := mysql.NewSqlAssignmentRepository(db, &logger)
 	peopleRepository := mysql.NewSqlPersonRepository(db, &logger)
+	scheduledChangeRepository := mysql.NewSqlScheduledChangeRepository(db, &logger)
+	environmentRepository := mysql.NewSqlEnvironmentRepository(db, &logger)
+	projectRepository := mysql.NewSqlProjectRepository(db, &logger)
+	snapshotRepository := mysql.NewSqlSnapshotRepository(db, &logger)
+	streamRepository := mysql.NewSqlStreamRepository(db, &logger)
+	webhookRepository := mysql.NewSqlWebhookRepository(db, &logger)
+	revisionRepository := mysql.NewSqlRevisionRepository(db, &logger)
 
 	featureFlagService := featureflag.LoadService(featureFlagRepository, &logger)
 	assignmentService := assignment.LoadService(assignmentRepository, &logger)
 	personService := person.LoadService(peopleRepository, &logger)
-
-	return featureFlagService, assignmentService, personService
+	scheduledChangeService := scheduledchange.LoadService(scheduledChangeRepository, featureFlagService, &logger)
+	environmentService := environment.LoadService(environmentRepository, &logger)
+	projectService := project.LoadService(projectRepository, &logger)
+	snapshotService := snapshot.LoadService(snapshotRepository, &logger)
+
+	// changes made here are streamed by the api, it reads the events from the database
+	streamService := stream.LoadService(streamRepository, snapshotService, environmentService, &logger)
+	featureFlagService.Publisher = streamService
+	assignmentService.Publisher = streamService
+
+	// the deliveries are queued here and sent by the api
+	webhookService := webhook.LoadService(webhookRepository, &logger)
+	featureFlagService.Notifier = webhookService
+	assignmentService.Notifier = webhookService
+
+	// and kept as a revision of the flag, the api lists them and rolls the flag back
+	revisionService := revision.LoadService(revisionRepository, featureFlagService, &logger)
+	featureFlagService.Recorder = revisionService
+	assignmentService.Recorder = revisionService
+
+	return featureFlagService, assignmentService, personService, scheduledChangeService, environmentService, projectService
 }
 
 // const COOKIE_TEST = "HEEEEY FILL ME UP"
@@ -61,7 +94,7 @@ func TEST_AUTH(next echo.HandlerFunc) echo.HandlerFunc {
 }
 
 func setupRoutes(e *echo.Echo) {
-	featureFlagService, assignmentService, personService := loadServices()
+	featureFlagService, assignmentService, personService, scheduledChangeService, environmentService, projectService := loadServices()
 	ffh := handler.FeatureFlagHandler{
 		FeatureFlagService: featureFlagService,
 	}
@@ -70,14 +103,23 @@ func setupRoutes(e *echo.Echo) {
 		PersonService:      personService,
 		FeatureFlagService: featureFlagService,
 	}
+	sch := handler.ScheduledChangeHandler{
+		ScheduledChangeService: scheduledChangeService,
+	}
+	eh := handler.EnvironmentHandler{
+		EnvironmentService: environmentService,
+	}
+	ph := handler.ProjectHandler{
+		ProjectService: projectService,
+	}
 	ch := handler.ComponentHandler{}
 
 	e.GET("/", func(c echo.Context) error {
 		// in this case, "/"  will be the same of "/feature-flags"
 		return ffh.GetFeatureFlagList(c)
-	}, TEST_AUTH)
+	}, TEST_AUTH, ph.LoadProject)
 
-	g := e.Group(("/feature-flags"), TEST_AUTH, middlewares.ValidateCookie)
+	g := e.Group(("/feature-flags"), TEST_AUTH, middlewares.ValidateCookie, ph.LoadProject)
 
 	//! Pages
 	g.GET("", ffh.GetFeatureFlagList)
@@ -92,17 +134,38 @@ func setupRoutes(e *echo.Echo) {
 	g.PUT("/:id", ffh.UpdateFeatureFlag)
 	g.GET("/filters", ffh.GetFeatureFlagListFiltered)
 	g.PUT("/status/:id", ffh.UpdateFeatureFlagStatus)
+	g.PUT("/archive/:id", ffh.ArchiveFeatureFlag)
+	g.PUT("/restore/:id", ffh.RestoreFeatureFlag)
+	g.DELETE("/:id", ffh.DeleteFeatureFlag)
 
 	//* assignment handlers
 	g.GET("/:feature-flag-id/assignments/filters", ah.GetPeopleListToAssignFiltered)
 	g.PUT("/:feature-flag-id/assignments/:id", ah.UpdateAssignment)
 	g.PUT("/:feature-flag-id/global", ah.SetFeatureFlagToGlobal)
 
+	//* scheduled change handlers
+	g.DELETE("/:feature-flag-id/scheduled-changes/:id", sch.CancelScheduledChange)
+
+	//* environment handlers
+	g.PUT("/environment", eh.SetEnvironment)
+
+	//* project handlers
+	g.PUT("/project", ph.SetProject)
+
 	//! Specific components updated by event
 	//* is_global_event
 	g.GET("/:feature-flag-id/component/set-global-button", ah.GetGlobalButtonSetup)
 	g.GET("/:feature-flag-id/component/show-only-assigned-people", ah.GetShowOnlyAssignedPeopleFilter)
 
+	//* edit modal
+	g.GET("/:feature-flag-id/component/scheduled-changes", sch.GetScheduledChangeList)
+
+	//* header
+	g.GET("/component/environment-switcher", eh.GetEnvironmentSwitcher)
+
+	//* feature flag list
+	g.GET("/component/project-selector", ph.GetProjectSelector)
+
 	//* create_feature_flag_event
 	// g.GET("/component/header", ch.GetHeader)
 
@@ -10,6 +10,7 @@ import templruntime "github.com/a-h/templ/runtime"
 
 import (
 	ff_entity "ff/internal/feature_flag/entity"
+	"strconv"
 )
 
 func FeatureFlagFilters() templ.Component {
@@ -33,7 +34,7 @@ func FeatureFlagFilters() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<div id=\"feature_flags_filters\" class=\"py-4\"><div class=\"flex justify-between\"><div><!-- TODO: trigger after typing --><input type=\"text\" id=\"feature_flag_name\" name=\"name\" placeholder=\"Enter Feature Flag Name\" class=\"w-64 border-1 bg-transparent ring-1 ring-inset ring-gray-300 py-1.5 text-gray-900 placeholder:text-gray-400 focus:ring-0 pl-4 feature_flag_filters\" hx-trigger=\"input changed delay:500ms, search\" hx-get=\"/feature-flags/filters\" hx-target=\"#feature_flag_table\" hx-include=\".feature_flag_filters\" hx-swap=\"outerHTML swap:100ms\"></div><fieldset><div class=\"mt-2 inline-block align-middle\"><div class=\"flex gap-x-2\"><div class=\"flex h-6 items-center\"><input id=\"feature_flag_status\" name=\"isActive\" type=\"checkbox\" class=\"h-5 w-5 rounded  accent-indigo-900 feature_flag_filters\" hx-get=\"/feature-flags/filters\" hx-target=\"#feature_flag_table\" hx-include=\".feature_flag_filters\" hx-swap=\"outerHTML swap:100ms\" hx-trigger=\"click\"></div><div class=\"text-sm leading-6\"><label for=\"feature_flag_status\" class=\"font-medium text-gray-900\">Show Active Flags</label></div></div></div></fieldset></div></div>")
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<div id=\"feature_flags_filters\" class=\"py-4\"><div class=\"flex justify-between\"><div class=\"flex items-center\"><div hx-get=\"/feature-flags/component/project-selector\" hx-trigger=\"load\" hx-swap=\"outerHTML\"></div><!-- TODO: trigger after typing --><input type=\"text\" id=\"feature_flag_name\" name=\"name\" placeholder=\"Enter Feature Flag Name\" class=\"w-64 border-1 bg-transparent ring-1 ring-inset ring-gray-300 py-1.5 text-gray-900 placeholder:text-gray-400 focus:ring-0 pl-4 feature_flag_filters\" hx-trigger=\"input changed delay:500ms, search\" hx-get=\"/feature-flags/filters\" hx-target=\"#feature_flag_table\" hx-include=\".feature_flag_filters\" hx-swap=\"outerHTML swap:100ms\"></div><fieldset><div class=\"mt-2 inline-block align-middle\"><div class=\"flex gap-x-2\"><div class=\"flex h-6 items-center\"><input id=\"feature_flag_status\" name=\"isActive\" type=\"checkbox\" class=\"h-5 w-5 rounded  accent-indigo-900 feature_flag_filters\" hx-get=\"/feature-flags/filters\" hx-target=\"#feature_flag_table\" hx-include=\".feature_flag_filters\" hx-swap=\"outerHTML swap:100ms\" hx-trigger=\"click\"></div><div class=\"text-sm leading-6\"><label for=\"feature_flag_status\" class=\"font-medium text-gray-900\">Show Active Flags</label></div></div></div><div class=\"mt-2 ml-4 inline-block align-middle\"><div class=\"flex gap-x-2\"><div class=\"flex h-6 items-center\"><input id=\"feature_flag_archived\" name=\"archived\" type=\"checkbox\" class=\"h-5 w-5 rounded  accent-indigo-900 feature_flag_filters\" hx-get=\"/feature-flags/filters\" hx-target=\"#feature_flag_table\" hx-include=\".feature_flag_filters\" hx-swap=\"outerHTML swap:100ms\" hx-trigger=\"click\"></div><div class=\"text-sm leading-6\"><label for=\"feature_flag_archived\" class=\"font-medium text-gray-900\">Show Archived Flags</label></div></div></div></fieldset></div></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -69,7 +70,7 @@ func FeatureFlagLine(featureFlag ff_entity.FeatureFlagResponse) templ.Component
 		var templ_7745c5c3_Var3 string
 		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs("feature_flag_id_" + featureFlag.ID)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 38, Col: 44}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 53, Col: 44}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 		if templ_7745c5c3_Err != nil {
@@ -82,7 +83,7 @@ func FeatureFlagLine(featureFlag ff_entity.FeatureFlagResponse) templ.Component
 		var templ_7745c5c3_Var4 string
 		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(featureFlag.ID)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 39, Col: 51}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 54, Col: 51}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 		if templ_7745c5c3_Err != nil {
@@ -95,7 +96,7 @@ func FeatureFlagLine(featureFlag ff_entity.FeatureFlagResponse) templ.Component
 		var templ_7745c5c3_Var5 string
 		templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(featureFlag.Name)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 40, Col: 62}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 55, Col: 62}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 		if templ_7745c5c3_Err != nil {
@@ -108,7 +109,7 @@ func FeatureFlagLine(featureFlag ff_entity.FeatureFlagResponse) templ.Component
 		var templ_7745c5c3_Var6 string
 		templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(featureFlag.Description)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 41, Col: 69}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 56, Col: 69}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
 		if templ_7745c5c3_Err != nil {
@@ -118,7 +119,12 @@ func FeatureFlagLine(featureFlag ff_entity.FeatureFlagResponse) templ.Component
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		if featureFlag.IsActive {
+		if featureFlag.ArchivedAt != "" {
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<div class=\"inline-block align-baseline\"><i class=\"fa-solid fa-box-archive\" style=\"color: #8f8f8f;\"></i> <span class=\"ml-1\">Archived</span></div>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		} else if featureFlag.IsActive {
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<div class=\"inline-block align-baseline cursor-pointer\" hx-put=\"")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
@@ -126,13 +132,26 @@ func FeatureFlagLine(featureFlag ff_entity.FeatureFlagResponse) templ.Component
 			var templ_7745c5c3_Var7 string
 			templ_7745c5c3_Var7, templ_7745c5c3_Err = templ.JoinStringErrs("/feature-flags/status/" + featureFlag.ID)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 44, Col: 110}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 64, Col: 110}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var7))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" hx-target=\"#feature_flag_table\" hx-swap=\"outerHTML swap:300ms\" hx-include=\"[name=&#39;name&#39;],[name=&#39;isActive&#39;]\"><i class=\"fa-solid fa-check\" style=\"color: #63E6BE;\"></i> <span class=\"ml-1\">Active</span></div>")
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" hx-vals=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var8 string
+			templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(`{"version": "` + strconv.FormatUint(uint64(featureFlag.Version), 10) + `"}`)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 65, Col: 92}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" hx-target=\"#feature_flag_table\" hx-swap=\"outerHTML swap:300ms\" hx-include=\"[name=&#39;name&#39;],[name=&#39;isActive&#39;],[name=&#39;archived&#39;]\"><i class=\"fa-solid fa-check\" style=\"color: #63E6BE;\"></i> <span class=\"ml-1\">Active</span></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -141,16 +160,29 @@ func FeatureFlagLine(featureFlag ff_entity.FeatureFlagResponse) templ.Component
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			var templ_7745c5c3_Var8 string
-			templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs("/feature-flags/status/" + featureFlag.ID)
+			var templ_7745c5c3_Var9 string
+			templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs("/feature-flags/status/" + featureFlag.ID)
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 50, Col: 110}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 71, Col: 110}
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" hx-target=\"#feature_flag_table\" hx-swap=\"outerHTML swap:300ms\" hx-include=\"[name=&#39;name&#39;],[name=&#39;isActive&#39;]\"><i class=\"fa-solid fa-circle-xmark\" style=\"color: #ff0000;\"></i> <span class=\"ml-1\">Inactive</span></div>")
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" hx-vals=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var10 string
+			templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs(`{"version": "` + strconv.FormatUint(uint64(featureFlag.Version), 10) + `"}`)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 72, Col: 92}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" hx-target=\"#feature_flag_table\" hx-swap=\"outerHTML swap:300ms\" hx-include=\"[name=&#39;name&#39;],[name=&#39;isActive&#39;],[name=&#39;archived&#39;]\"><i class=\"fa-solid fa-circle-xmark\" style=\"color: #ff0000;\"></i> <span class=\"ml-1\">Inactive</span></div>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -159,55 +191,122 @@ func FeatureFlagLine(featureFlag ff_entity.FeatureFlagResponse) templ.Component
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var9 string
-		templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(featureFlag.ExpirationDate)
-		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 57, Col: 63}
-		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
-		if templ_7745c5c3_Err != nil {
-			return templ_7745c5c3_Err
-		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("</td><td class=\"table-cell px-2 py-2 flex justify-center items-center\"><div class=\"text-center\"><!-- Edit --><i class=\"fa-regular fa-pen-to-square cursor-pointer\" style=\"color: #8f8f8f;\" hx-get=\"")
-		if templ_7745c5c3_Err != nil {
-			return templ_7745c5c3_Err
-		}
-		var templ_7745c5c3_Var10 string
-		templ_7745c5c3_Var10, templ_7745c5c3_Err = templ.JoinStringErrs("/feature-flags/form/create-or-update?id=" + featureFlag.ID)
-		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 62, Col: 76}
-		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var10))
-		if templ_7745c5c3_Err != nil {
-			return templ_7745c5c3_Err
-		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" hx-target=\"body\" hx-swap=\"beforeend\"></i><!-- Assignment --><i hx-get=\"")
-		if templ_7745c5c3_Err != nil {
-			return templ_7745c5c3_Err
-		}
 		var templ_7745c5c3_Var11 string
-		templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs("/feature-flags/" + featureFlag.ID + "/assignments")
+		templ_7745c5c3_Var11, templ_7745c5c3_Err = templ.JoinStringErrs(featureFlag.ExpirationDate)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 64, Col: 69}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 79, Col: 63}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var11))
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" hx-swap=\"outerHTML swap:100ms\" hx-target=\"body\" hx-replace-url=\"")
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("</td><td class=\"table-cell px-2 py-2 flex justify-center items-center\"><div class=\"text-center\">")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		var templ_7745c5c3_Var12 string
-		templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs("/feature-flags/" + featureFlag.ID + "/assignments")
-		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 65, Col: 76}
-		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
-		if templ_7745c5c3_Err != nil {
-			return templ_7745c5c3_Err
+		if featureFlag.ArchivedAt != "" {
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<!-- Restore --> <i class=\"fa-solid fa-box-open cursor-pointer\" style=\"color: #8f8f8f;\" hx-put=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var12 string
+			templ_7745c5c3_Var12, templ_7745c5c3_Err = templ.JoinStringErrs("/feature-flags/restore/" + featureFlag.ID)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 85, Col: 59}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var12))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" hx-target=\"#feature_flag_table\" hx-swap=\"outerHTML swap:300ms\" hx-include=\"[name=&#39;name&#39;],[name=&#39;isActive&#39;],[name=&#39;archived&#39;]\"></i><!-- Delete --> <i class=\"fa-solid fa-trash ml-3 cursor-pointer\" style=\"color: #ff0000;\" hx-delete=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var13 string
+			templ_7745c5c3_Var13, templ_7745c5c3_Err = templ.JoinStringErrs("/feature-flags/" + featureFlag.ID)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 89, Col: 54}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var13))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" hx-target=\"#feature_flag_table\" hx-swap=\"outerHTML swap:300ms\" hx-include=\"[name=&#39;name&#39;],[name=&#39;isActive&#39;],[name=&#39;archived&#39;]\" hx-confirm=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var14 string
+			templ_7745c5c3_Var14, templ_7745c5c3_Err = templ.JoinStringErrs("Delete " + featureFlag.Name + " and its assignments for good?")
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 91, Col: 84}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var14))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\"></i>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		} else {
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<!-- Edit --> <i class=\"fa-regular fa-pen-to-square cursor-pointer\" style=\"color: #8f8f8f;\" hx-get=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var15 string
+			templ_7745c5c3_Var15, templ_7745c5c3_Err = templ.JoinStringErrs("/feature-flags/form/create-or-update?id=" + featureFlag.ID)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 95, Col: 76}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var15))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" hx-target=\"body\" hx-swap=\"beforeend\"></i><!-- Assignment --> <i hx-get=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var16 string
+			templ_7745c5c3_Var16, templ_7745c5c3_Err = templ.JoinStringErrs("/feature-flags/" + featureFlag.ID + "/assignments")
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 97, Col: 69}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var16))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" hx-swap=\"outerHTML swap:100ms\" hx-target=\"body\" hx-replace-url=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var17 string
+			templ_7745c5c3_Var17, templ_7745c5c3_Err = templ.JoinStringErrs("/feature-flags/" + featureFlag.ID + "/assignments")
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 98, Col: 76}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var17))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" class=\"fa-solid fa-user-plus ml-3 cursor-pointer\" style=\"color: #cfa920;\"></i><!-- Archive --> <i class=\"fa-solid fa-box-archive ml-3 cursor-pointer\" style=\"color: #8f8f8f;\" hx-put=\"")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			var templ_7745c5c3_Var18 string
+			templ_7745c5c3_Var18, templ_7745c5c3_Err = templ.JoinStringErrs("/feature-flags/archive/" + featureFlag.ID)
+			if templ_7745c5c3_Err != nil {
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/components/feature_flag_list.templ`, Line: 102, Col: 59}
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var18))
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" hx-target=\"#feature_flag_table\" hx-swap=\"outerHTML swap:300ms\" hx-include=\"[name=&#39;name&#39;],[name=&#39;isActive&#39;],[name=&#39;archived&#39;]\"></i>")
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("\" class=\"fa-solid fa-user-plus ml-3 cursor-pointer\" style=\"color: #cfa920;\"></i></div></td></tr>")
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("</div></td></tr>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -231,9 +330,9 @@ func FeatureFlagTable(featureFlags []ff_entity.FeatureFlagResponse) templ.Compon
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var13 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var13 == nil {
-			templ_7745c5c3_Var13 = templ.NopComponent
+		templ_7745c5c3_Var19 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var19 == nil {
+			templ_7745c5c3_Var19 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<tbody id=\"feature_flag_table\" class=\"table-row-group\" hx-trigger=\"refresh_ff_list_event from:body\" hx-swap=\"outerHTML\" hx-get=\"/feature-flags\" hx-select=\"#feature_flag_table\">")
@@ -270,9 +369,9 @@ func FeatureFlagList(featureFlags []ff_entity.FeatureFlagResponse) templ.Compone
 			}()
 		}
 		ctx = templ.InitializeContext(ctx)
-		templ_7745c5c3_Var14 := templ.GetChildren(ctx)
-		if templ_7745c5c3_Var14 == nil {
-			templ_7745c5c3_Var14 = templ.NopComponent
+		templ_7745c5c3_Var20 := templ.GetChildren(ctx)
+		if templ_7745c5c3_Var20 == nil {
+			templ_7745c5c3_Var20 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<div id=\"feature_flag_list\" class=\"\">")
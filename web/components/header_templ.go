@@ -29,7 +29,7 @@ func Header() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<header id=\"header-actions\" class=\"flex justify-between\"><h1 class=\"text-2xl cursor-pointer\" hx-get=\"/feature-flags\" hx-target=\"body\" hx-swap=\"swap:200ms\" hx-replace-url=\"/feature-flags\">HTMX Feature Flags Demo Templ</h1>")
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("<header id=\"header-actions\" class=\"flex justify-between\"><h1 class=\"text-2xl cursor-pointer\" hx-get=\"/feature-flags\" hx-target=\"body\" hx-swap=\"swap:200ms\" hx-replace-url=\"/feature-flags\">HTMX Feature Flags Demo Templ</h1><div class=\"flex items-center gap-x-4\"><div hx-get=\"/feature-flags/component/environment-switcher\" hx-trigger=\"load\" hx-swap=\"outerHTML\"></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -37,7 +37,7 @@ func Header() templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("</header>")
+		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString("</div></header>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
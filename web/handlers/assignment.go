@@ -20,11 +20,12 @@ import (
 type AssignmentService interface {
 	ApplyAssignment(request a_entity.Assignment, personId uint) error
 	DeleteAssignment(request a_entity.Assignment, personId uint) error
+	CycleAssignment(request a_entity.Assignment, personId uint) (string, error)
 }
 
 type PersonService interface {
 	GetPeopleAssignmentByFeatureFlag(pagination model.Pagination, filters p_entity.PersonFilters) ([]p_entity.PersonWithAssignmentResponse, int64, error)
-	GetAssignedFeatureFlagsByPersonId(id uint) ([]p_entity.AssignedFeatureFlagResponse, error)
+	GetAssignedFeatureFlagsByPersonId(id uint, projectId uint, environment string) ([]p_entity.AssignedFeatureFlagResponse, error)
 }
 
 type AssignmentHandler struct {
@@ -33,17 +34,6 @@ type AssignmentHandler struct {
 	FeatureFlagService FeatureFlagService
 }
 
-func FindPersonByID(id int, assignments *[]p_entity.PersonWithAssignmentResponse) p_entity.PersonWithAssignmentResponse {
-	var assignment p_entity.PersonWithAssignmentResponse
-	for _, p := range *assignments {
-		if p.ID == strconv.Itoa(id) {
-			assignment = p
-		}
-	}
-
-	return assignment
-}
-
 func (ah *AssignmentHandler) GetPeopleListToAssign(c echo.Context) error {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -56,7 +46,9 @@ func (ah *AssignmentHandler) GetPeopleListToAssign(c echo.Context) error {
 		Page:  1,
 		Limit: 1,
 	}, ff_entity.FeatureFlagFilters{
-		ID: uint(id),
+		ID:          uint(id),
+		Environment: utils.GetEnvironment(c),
+		ProjectID:   utils.GetProject(c),
 	})
 	if total == 0 {
 		c.Response().Header().Add("HX-Replace-Url", "/404")
@@ -68,6 +60,7 @@ func (ah *AssignmentHandler) GetPeopleListToAssign(c echo.Context) error {
 		Limit: 500,
 	}, p_entity.PersonFilters{
 		FeatureFlagID: uint(id),
+		Environment:   utils.GetEnvironment(c),
 	})
 	if err != nil {
 		c.Response().Header().Add("HX-Replace-Url", "/error")
@@ -95,6 +88,7 @@ func (ah *AssignmentHandler) GetPeopleListToAssignFiltered(c echo.Context) error
 	filters := p_entity.PersonFilters{
 		FeatureFlagID: uint(id),
 		Name:          name,
+		Environment:   utils.GetEnvironment(c),
 	}
 
 	if isAssignedStr == "on" {
@@ -114,7 +108,9 @@ func (ah *AssignmentHandler) GetPeopleListToAssignFiltered(c echo.Context) error
 		Page:  1,
 		Limit: 1,
 	}, ff_entity.FeatureFlagFilters{
-		ID: uint(id),
+		ID:          uint(id),
+		Environment: utils.GetEnvironment(c),
+		ProjectID:   utils.GetProject(c),
 	})
 
 	return utils.Render(c, http.StatusOK, components.AssignmentTable(assignments, featureFlags[0]))
@@ -135,41 +131,28 @@ func (ah *AssignmentHandler) UpdateAssignment(c echo.Context) error {
 
 	authInfo := c.Get("auth_info").(auth.AuthUserResponse)
 
-	assignments, total, err := ah.PersonService.GetPeopleAssignmentByFeatureFlag(model.Pagination{
-		Page:  1,
-		Limit: 10000,
-	}, p_entity.PersonFilters{
+	// each click moves the person to the next state: not assigned, assigned, excluded
+	_, err = ah.AssignmentService.CycleAssignment(a_entity.Assignment{
+		PersonID:      uint(personId),
 		FeatureFlagID: uint(featureFlagId),
-	})
+		Environment:   utils.GetEnvironment(c),
+		ProjectID:     utils.GetProject(c),
+	}, uint(authInfo.PersonID))
 	if err != nil {
-		return errors.New("Something goes wrong when attempting to get the assignment list")
-	}
-	if total == 0 {
-		return errors.New("Feature Flag ID is invalid")
+		return utils.ErrorMessage(c, err.Error())
 	}
 
-	personAssignment := FindPersonByID(personId, &assignments)
-
-	if personAssignment.IsAssigned {
-		ah.AssignmentService.DeleteAssignment(a_entity.Assignment{
-			PersonID:      uint(personId),
-			FeatureFlagID: uint(featureFlagId),
-		}, uint(authInfo.PersonID))
-	} else {
-		ah.AssignmentService.ApplyAssignment(a_entity.Assignment{
-			PersonID:      uint(personId),
-			FeatureFlagID: uint(featureFlagId),
-		}, uint(authInfo.PersonID))
-	}
-
-	personAssignment.IsAssigned = !personAssignment.IsAssigned
-
 	featureFlags, _, err := ah.FeatureFlagService.GetFeatureFlag(model.Pagination{
 		Page:  1,
 		Limit: 1,
 	}, ff_entity.FeatureFlagFilters{
-		ID: uint(featureFlagId),
+		ID:          uint(featureFlagId),
+		Environment: utils.GetEnvironment(c),
+		ProjectID:   utils.GetProject(c),
 	})
+	if err != nil || len(featureFlags) == 0 {
+		return utils.ErrorMessage(c, "Feature Flag not found")
+	}
 
 	name := c.FormValue("name")
 	isAssignedStr := c.FormValue("isAssigned")
@@ -177,6 +160,7 @@ func (ah *AssignmentHandler) UpdateAssignment(c echo.Context) error {
 	filters := p_entity.PersonFilters{
 		FeatureFlagID: uint(featureFlagId),
 		Name:          name,
+		Environment:   utils.GetEnvironment(c),
 	}
 
 	if isAssignedStr == "on" {
@@ -184,10 +168,13 @@ func (ah *AssignmentHandler) UpdateAssignment(c echo.Context) error {
 		filters.IsAssigned = &isAssigned
 	}
 
-	assignmentsToShow, _, _ := ah.PersonService.GetPeopleAssignmentByFeatureFlag(model.Pagination{
+	assignmentsToShow, _, err := ah.PersonService.GetPeopleAssignmentByFeatureFlag(model.Pagination{
 		Page:  1,
 		Limit: 10000,
 	}, filters)
+	if err != nil {
+		return utils.ErrorMessage(c, "Something goes wrong when attempting to get the assignment list")
+	}
 
 	return utils.Render(c, http.StatusOK, components.AssignmentTable(assignmentsToShow, featureFlags[0]))
 }
@@ -199,13 +186,22 @@ func (ah *AssignmentHandler) SetFeatureFlagToGlobal(c echo.Context) error {
 		return errors.New("Feature flag id is invalid (not a number)")
 	}
 
-	// authInfo := c.Get("auth_info").(auth.AuthUserResponse)
+	authInfo := c.Get("auth_info").(auth.AuthUserResponse)
+
+	// the switch is based on the version the button was rendered with, without it the change can not be checked
+	version, err := strconv.Atoi(c.FormValue("version"))
+	if err != nil || version <= 0 {
+		c.Response().Header().Add("HX-Trigger", "is_global_event")
+		return utils.ErrorMessage(c, "feature flag version is missing, the button was reloaded")
+	}
 
 	featureFlags, total, err := ah.FeatureFlagService.GetFeatureFlag(model.Pagination{
 		Page:  1,
 		Limit: 1,
 	}, ff_entity.FeatureFlagFilters{
-		ID: uint(featureFlagId),
+		ID:          uint(featureFlagId),
+		Environment: utils.GetEnvironment(c),
+		ProjectID:   utils.GetProject(c),
 	})
 	if err != nil {
 		return errors.New("Something goes wrong when attempting to get the assignment list")
@@ -215,11 +211,21 @@ func (ah *AssignmentHandler) SetFeatureFlagToGlobal(c echo.Context) error {
 	}
 
 	if err := ah.FeatureFlagService.UpdateFeatureFlagById(uint(featureFlagId), ff_entity.UpdateFeatureFlag{
-		Description:    featureFlags[0].Description,
-		IsActive:       featureFlags[0].IsActive,
-		IsGlobal:       !featureFlags[0].IsGlobal,
-		ExpirationDate: featureFlags[0].ExpirationDate,
+		Description:       featureFlags[0].Description,
+		IsActive:          featureFlags[0].IsActive,
+		IsGlobal:          !featureFlags[0].IsGlobal,
+		ExpirationDate:    featureFlags[0].ExpirationDate,
+		RolloutPercentage: featureFlags[0].RolloutPercentage,
+		Environment:       utils.GetEnvironment(c),
+		ProjectID:         utils.GetProject(c),
+		Version:           uint(version),
+		PersonID:          uint(authInfo.PersonID),
 	}); err != nil {
+		// the button is out of date, it is reloaded to show the change made by someone else
+		if err.Error() == "feature flag was changed by someone else" {
+			c.Response().Header().Add("HX-Trigger", "is_global_event")
+			return utils.ErrorMessage(c, err.Error())
+		}
 		return errors.New("Something goes wrong when attempting to update the feature flag global")
 	}
 
@@ -231,6 +237,7 @@ func (ah *AssignmentHandler) SetFeatureFlagToGlobal(c echo.Context) error {
 	filters := p_entity.PersonFilters{
 		FeatureFlagID: uint(featureFlagId),
 		Name:          name,
+		Environment:   utils.GetEnvironment(c),
 	}
 
 	if isAssignedStr == "on" {
@@ -238,10 +245,13 @@ func (ah *AssignmentHandler) SetFeatureFlagToGlobal(c echo.Context) error {
 		filters.IsAssigned = &isAssigned
 	}
 
-	assignmentsToShow, _, _ := ah.PersonService.GetPeopleAssignmentByFeatureFlag(model.Pagination{
+	assignmentsToShow, _, err := ah.PersonService.GetPeopleAssignmentByFeatureFlag(model.Pagination{
 		Page:  1,
 		Limit: 10000,
 	}, filters)
+	if err != nil {
+		return utils.ErrorMessage(c, "Something goes wrong when attempting to get the assignment list")
+	}
 
 	// c.Response().Header().Add("HX-Trigger-After-Swap", `{"isGlobal":{"target":"#is_global_button"}}`)
 	c.Response().Header().Add("HX-Trigger-After-Swap", "is_global_event")
@@ -264,7 +274,9 @@ func (ah *AssignmentHandler) GetGlobalButtonSetup(c echo.Context) error {
 		Page:  1,
 		Limit: 1,
 	}, ff_entity.FeatureFlagFilters{
-		ID: uint(featureFlagId),
+		ID:          uint(featureFlagId),
+		Environment: utils.GetEnvironment(c),
+		ProjectID:   utils.GetProject(c),
 	})
 	if err != nil {
 		return errors.New("Something goes wrong when attempting to get the feature flag list")
@@ -293,7 +305,9 @@ func (ah *AssignmentHandler) GetShowOnlyAssignedPeopleFilter(c echo.Context) err
 		Page:  1,
 		Limit: 1,
 	}, ff_entity.FeatureFlagFilters{
-		ID: uint(featureFlagId),
+		ID:          uint(featureFlagId),
+		Environment: utils.GetEnvironment(c),
+		ProjectID:   utils.GetProject(c),
 	})
 	if err != nil {
 		return errors.New("Something goes wrong when attempting to get the feature flag list")
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	env_entity "ff/internal/environment/entity"
+	"ff/web/components"
+	"ff/web/utils"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type EnvironmentService interface {
+	GetEnvironments() ([]env_entity.EnvironmentResponse, error)
+	EnvironmentExists(name string) (bool, error)
+}
+
+type EnvironmentHandler struct {
+	EnvironmentService EnvironmentService
+}
+
+func (eh *EnvironmentHandler) GetEnvironmentSwitcher(c echo.Context) error {
+	environments, err := eh.EnvironmentService.GetEnvironments()
+	if err != nil {
+		return utils.ErrorMessage(c, "something goes wrong when attempting to get the environments")
+	}
+
+	return utils.Render(c, http.StatusOK, components.EnvironmentSwitcher(environments, utils.GetEnvironment(c)))
+}
+
+// SetEnvironment keeps the chosen environment in a cookie and reloads the page,
+// every list and form is then shown with the state of that environment
+func (eh *EnvironmentHandler) SetEnvironment(c echo.Context) error {
+	environment := c.FormValue("environment")
+
+	exists, err := eh.EnvironmentService.EnvironmentExists(environment)
+	if err != nil {
+		return utils.ErrorMessage(c, "something goes wrong when attempting to get the environments")
+	}
+	if !exists {
+		return utils.ErrorMessage(c, "environment not found")
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     utils.EnvironmentCookie,
+		Value:    environment,
+		Path:     "/",
+		HttpOnly: true,
+	})
+
+	c.Response().Header().Add("HX-Refresh", "true")
+	return c.NoContent(http.StatusOK)
+}
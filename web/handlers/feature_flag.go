@@ -31,6 +31,9 @@ type FeatureFlagService interface {
 	CreateFeatureFlag(request ff_entity.FeatureFlag, personId uint) error
 	GetFeatureFlag(pagination model.Pagination, filters ff_entity.FeatureFlagFilters) ([]ff_entity.FeatureFlagResponse, int64, error)
 	UpdateFeatureFlagById(id uint, request ff_entity.UpdateFeatureFlag) error
+	ArchiveFeatureFlag(id uint, request ff_entity.ArchiveFeatureFlag) error
+	RestoreFeatureFlag(id uint, projectId uint, personId uint) error
+	DeleteFeatureFlag(id uint, projectId uint, personId uint) error
 }
 
 type FeatureFlagHandler struct {
@@ -45,7 +48,10 @@ func (ffh *FeatureFlagHandler) GetFeatureFlagList(c echo.Context) error {
 	featureFlags, _, err := ffh.FeatureFlagService.GetFeatureFlag(model.Pagination{
 		Page:  1,
 		Limit: 100,
-	}, ff_entity.FeatureFlagFilters{})
+	}, ff_entity.FeatureFlagFilters{
+		Environment: utils.GetEnvironment(c),
+		ProjectID:   utils.GetProject(c),
+	})
 
 	if err != nil {
 		return utils.ErrorMessage(c, "something goes wrong when attempting to get the feature flag list")
@@ -75,7 +81,9 @@ func (ffh *FeatureFlagHandler) GetCreateOrUpdateFeatureFlag(c echo.Context) erro
 		Page:  1,
 		Limit: 1,
 	}, ff_entity.FeatureFlagFilters{
-		ID: uint(id),
+		ID:          uint(id),
+		Environment: utils.GetEnvironment(c),
+		ProjectID:   utils.GetProject(c),
 	})
 	if err != nil {
 		c.Response().Header().Add("HX-Replace-Url", "/error")
@@ -89,11 +97,15 @@ func (ffh *FeatureFlagHandler) GetCreateOrUpdateFeatureFlag(c echo.Context) erro
 func (ffh *FeatureFlagHandler) GetFeatureFlagListFiltered(c echo.Context) error {
 	name := c.QueryParams().Get("name")
 	isActiveStr := c.QueryParams().Get("isActive")
+	archivedStr := c.QueryParams().Get("archived")
 
 	// TODO: user ffh.Service
 
 	filters := ff_entity.FeatureFlagFilters{
-		Name: name,
+		Name:        name,
+		Environment: utils.GetEnvironment(c),
+		ProjectID:   utils.GetProject(c),
+		Archived:    archivedStr == "on",
 	}
 
 	if isActiveStr == "on" {
@@ -122,11 +134,20 @@ func (ffh *FeatureFlagHandler) UpdateFeatureFlagStatus(c echo.Context) error {
 		return utils.ErrorMessage(c, "feature flag id is invalid (not a number)")
 	}
 
+	// the toggle is based on the version the list was rendered with, without it the change can not be checked
+	version, err := strconv.Atoi(c.FormValue("version"))
+	if err != nil || version <= 0 {
+		c.Response().Header().Add("HX-Trigger", "refresh_ff_list_event")
+		return utils.ErrorMessage(c, "feature flag version is missing, the list was reloaded")
+	}
+
 	featureFlags, total, err := ffh.FeatureFlagService.GetFeatureFlag(model.Pagination{
 		Page:  1,
 		Limit: 100,
 	}, ff_entity.FeatureFlagFilters{
-		ID: uint(id),
+		ID:          uint(id),
+		Environment: utils.GetEnvironment(c),
+		ProjectID:   utils.GetProject(c),
 	})
 	if err != nil {
 		return utils.ErrorMessage(c, "something goes wrong when attempting to get the feature flag list")
@@ -138,24 +159,105 @@ func (ffh *FeatureFlagHandler) UpdateFeatureFlagStatus(c echo.Context) error {
 	selectedFeatureFlag := FindFeatureFlagByID(id, &featureFlags)
 	selectedFeatureFlag.IsActive = !selectedFeatureFlag.IsActive
 
+	authInfo := c.Get("auth_info").(auth.AuthUserResponse)
+
 	// TODO: check if selectedFeatureFlag exists
 	requestToUpdate := ff_entity.UpdateFeatureFlag{
-		Description:    selectedFeatureFlag.Description,
-		IsActive:       selectedFeatureFlag.IsActive,
-		IsGlobal:       selectedFeatureFlag.IsGlobal,
-		ExpirationDate: selectedFeatureFlag.ExpirationDate,
+		Description:       selectedFeatureFlag.Description,
+		IsActive:          selectedFeatureFlag.IsActive,
+		IsGlobal:          selectedFeatureFlag.IsGlobal,
+		ExpirationDate:    selectedFeatureFlag.ExpirationDate,
+		RolloutPercentage: selectedFeatureFlag.RolloutPercentage,
+		Environment:       utils.GetEnvironment(c),
+		ProjectID:         utils.GetProject(c),
+		Version:           uint(version),
+		PersonID:          uint(authInfo.PersonID),
 	}
 
 	err = ffh.FeatureFlagService.UpdateFeatureFlagById(uint(id), requestToUpdate)
 	if err != nil {
+		// the list is out of date, it is reloaded to show the change made by someone else
+		if err.Error() == "feature flag was changed by someone else" {
+			c.Response().Header().Add("HX-Trigger", "refresh_ff_list_event")
+			return utils.ErrorMessage(c, err.Error())
+		}
+		// flags depending on this one have to be handled before deactivating it
+		if strings.HasPrefix(err.Error(), "feature flag is a prerequisite of") {
+			return utils.ErrorMessage(c, err.Error())
+		}
 		return utils.ErrorMessage(c, "something goes wrong when attempting to update the feature flag")
 	}
 
+	return ffh.renderFeatureFlagTable(c)
+}
+
+func (ffh *FeatureFlagHandler) ArchiveFeatureFlag(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.ErrorMessage(c, "feature flag id is invalid (not a number)")
+	}
+
+	authInfo := c.Get("auth_info").(auth.AuthUserResponse)
+
+	err = ffh.FeatureFlagService.ArchiveFeatureFlag(uint(id), ff_entity.ArchiveFeatureFlag{
+		ProjectID: utils.GetProject(c),
+		PersonID:  uint(authInfo.PersonID),
+	})
+	if err != nil {
+		// flags depending on this one have to be handled before archiving it
+		if strings.HasPrefix(err.Error(), "feature flag is a prerequisite of") {
+			return utils.ErrorMessage(c, err.Error())
+		}
+		return utils.ErrorMessage(c, "something goes wrong when attempting to archive the feature flag")
+	}
+
+	return ffh.renderFeatureFlagTable(c)
+}
+
+func (ffh *FeatureFlagHandler) RestoreFeatureFlag(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.ErrorMessage(c, "feature flag id is invalid (not a number)")
+	}
+
+	authInfo := c.Get("auth_info").(auth.AuthUserResponse)
+
+	if err := ffh.FeatureFlagService.RestoreFeatureFlag(uint(id), utils.GetProject(c), uint(authInfo.PersonID)); err != nil {
+		return utils.ErrorMessage(c, "something goes wrong when attempting to restore the feature flag")
+	}
+
+	return ffh.renderFeatureFlagTable(c)
+}
+
+func (ffh *FeatureFlagHandler) DeleteFeatureFlag(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.ErrorMessage(c, "feature flag id is invalid (not a number)")
+	}
+
+	authInfo := c.Get("auth_info").(auth.AuthUserResponse)
+
+	if err := ffh.FeatureFlagService.DeleteFeatureFlag(uint(id), utils.GetProject(c), uint(authInfo.PersonID)); err != nil {
+		if err.Error() == "feature flag must be archived before it is deleted" {
+			return utils.ErrorMessage(c, err.Error())
+		}
+		return utils.ErrorMessage(c, "something goes wrong when attempting to delete the feature flag")
+	}
+
+	return ffh.renderFeatureFlagTable(c)
+}
+
+// renderFeatureFlagTable renders the table again with the filters sent along the action
+func (ffh *FeatureFlagHandler) renderFeatureFlagTable(c echo.Context) error {
 	name := c.FormValue("name")
 	isActiveStr := c.FormValue("isActive")
+	archivedStr := c.FormValue("archived")
 
 	filters := ff_entity.FeatureFlagFilters{
-		Name: name,
+		Name:        name,
+		Environment: utils.GetEnvironment(c),
+		ProjectID:   utils.GetProject(c),
+		Archived:    archivedStr == "on",
 	}
 
 	if isActiveStr == "on" {
@@ -176,14 +278,18 @@ func (ffh *FeatureFlagHandler) CreateFeatureFlag(c echo.Context) error {
 	description := strings.Trim(c.FormValue("description"), " ")
 	isActive := c.FormValue("isActive") == "on"
 	expirationDate := c.FormValue("expirationDate")
+	rolloutPercentage, _ := strconv.Atoi(c.FormValue("rolloutPercentage"))
 
 	authInfo := c.Get("auth_info").(auth.AuthUserResponse)
 
 	err := ffh.FeatureFlagService.CreateFeatureFlag(ff_entity.FeatureFlag{
-		Name:           name,
-		Description:    description,
-		IsActive:       isActive,
-		ExpirationDate: expirationDate,
+		Name:              name,
+		Description:       description,
+		IsActive:          isActive,
+		ExpirationDate:    expirationDate,
+		RolloutPercentage: rolloutPercentage,
+		Environment:       utils.GetEnvironment(c),
+		ProjectID:         utils.GetProject(c),
 	}, uint(authInfo.PersonID))
 
 	// error on feature flag creation
@@ -224,26 +330,53 @@ func (ffh *FeatureFlagHandler) UpdateFeatureFlag(c echo.Context) error {
 		return utils.ErrorMessage(c, "feature flag ID is not a valid number")
 	}
 
-	name := c.FormValue("name")
 	description := strings.Trim(c.FormValue("description"), " ")
 	isActive := c.FormValue("isActive") == "on"
 	expirationDate := c.FormValue("expirationDate")
+	rolloutPercentage, _ := strconv.Atoi(c.FormValue("rolloutPercentage"))
+	authInfo := c.Get("auth_info").(auth.AuthUserResponse)
 
-	ffOnDB, _, _ := ffh.FeatureFlagService.GetFeatureFlag(model.Pagination{
+	// the update is based on the version the form was filled with, without it the change can not be checked
+	version, err := strconv.Atoi(c.FormValue("version"))
+	if err != nil || version <= 0 {
+		return utils.ErrorMessage(c, "feature flag version is missing, reload the page and try again")
+	}
+
+	ffOnDB, _, err := ffh.FeatureFlagService.GetFeatureFlag(model.Pagination{
 		Page:  1,
 		Limit: 1,
 	}, ff_entity.FeatureFlagFilters{
-		Name: name,
+		ID:          uint(id),
+		Environment: utils.GetEnvironment(c),
+		ProjectID:   utils.GetProject(c),
 	})
+	if err != nil {
+		return utils.ErrorMessage(c, "something goes wrong when attempting to get the feature flag")
+	}
+	if len(ffOnDB) == 0 {
+		return utils.ErrorMessage(c, "Feature Flag not found")
+	}
 
 	err = ffh.FeatureFlagService.UpdateFeatureFlagById(uint(id), ff_entity.UpdateFeatureFlag{
 		// method updates all 4 fields, getting the current isGlobal value to not set false when it is true
-		Description:    description,
-		IsActive:       isActive,
-		IsGlobal:       ffOnDB[0].IsGlobal,
-		ExpirationDate: expirationDate,
+		Description:       description,
+		IsActive:          isActive,
+		IsGlobal:          ffOnDB[0].IsGlobal,
+		ExpirationDate:    expirationDate,
+		RolloutPercentage: rolloutPercentage,
+		Environment:       utils.GetEnvironment(c),
+		ProjectID:         utils.GetProject(c),
+		Version:           uint(version),
+		PersonID:          uint(authInfo.PersonID),
 	})
 
+	// the form was filled with an older version, the modal tells it and offers to reload
+	if err != nil && err.Error() == "feature flag was changed by someone else" {
+		c.Response().Header().Add("HX-Retarget", "#feature_flag_conflict")
+		c.Response().Header().Add("HX-Reswap", "outerHTML")
+		return utils.Render(c, http.StatusConflict, components.FeatureFlagConflict(ffOnDB[0]))
+	}
+
 	// error on feature flag creation
 	if err != nil && err.Error() != "no feature flag updated" {
 		ff := ffOnDB[0]
@@ -251,6 +384,7 @@ func (ffh *FeatureFlagHandler) UpdateFeatureFlag(c echo.Context) error {
 		ff.Description = description
 		ff.IsActive = isActive
 		ff.ExpirationDate = expirationDate
+		ff.RolloutPercentage = rolloutPercentage
 
 		errorType := strings.Split(err.Error(), "|")[0]
 		// errorMessage := strings.Split(err.Error(), "|")[1]
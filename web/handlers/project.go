@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"ff/internal/db/model"
+	project_entity "ff/internal/project/entity"
+	"ff/web/components"
+	"ff/web/utils"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ProjectService interface {
+	GetProjects(pagination model.Pagination, filters project_entity.ProjectFilters) ([]project_entity.ProjectResponse, int64, error)
+	GetProjectByKey(key string) (project_entity.ProjectResponse, error)
+}
+
+type ProjectHandler struct {
+	ProjectService ProjectService
+}
+
+// LoadProject resolves the project chosen in the selector, falling back to the default project
+func (ph *ProjectHandler) LoadProject(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := model.DefaultProject
+		if cookie, err := c.Cookie(utils.ProjectCookie); err == nil && cookie.Value != "" {
+			key = cookie.Value
+		}
+
+		project, err := ph.ProjectService.GetProjectByKey(key)
+		if err != nil && key != model.DefaultProject {
+			project, err = ph.ProjectService.GetProjectByKey(model.DefaultProject)
+		}
+		if err != nil {
+			return utils.ErrorMessage(c, "something goes wrong when attempting to get the project")
+		}
+
+		c.Set("project", project.ID)
+		c.Set("project_key", project.Key)
+
+		return next(c)
+	}
+}
+
+func (ph *ProjectHandler) GetProjectSelector(c echo.Context) error {
+	projects, _, err := ph.ProjectService.GetProjects(model.Pagination{
+		Page:  1,
+		Limit: 100,
+	}, project_entity.ProjectFilters{})
+	if err != nil {
+		return utils.ErrorMessage(c, "something goes wrong when attempting to get the projects")
+	}
+
+	current, _ := c.Get("project_key").(string)
+
+	return utils.Render(c, http.StatusOK, components.ProjectSelector(projects, current))
+}
+
+// SetProject keeps the chosen project in a cookie and reloads the page with its flags
+func (ph *ProjectHandler) SetProject(c echo.Context) error {
+	project, err := ph.ProjectService.GetProjectByKey(c.FormValue("project"))
+	if err != nil {
+		return utils.ErrorMessage(c, err.Error())
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     utils.ProjectCookie,
+		Value:    project.Key,
+		Path:     "/",
+		HttpOnly: true,
+	})
+
+	c.Response().Header().Add("HX-Refresh", "true")
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	sc_entity "ff/internal/scheduled_change/entity"
+	"ff/web/components"
+	"ff/web/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ScheduledChangeService interface {
+	GetScheduledChanges(featureFlagId uint, projectId uint, filters sc_entity.ScheduledChangeFilters) ([]sc_entity.ScheduledChangeResponse, error)
+	CancelScheduledChange(featureFlagId uint, projectId uint, id uint) error
+}
+
+type ScheduledChangeHandler struct {
+	ScheduledChangeService ScheduledChangeService
+}
+
+func (sch *ScheduledChangeHandler) GetScheduledChangeList(c echo.Context) error {
+	featureFlagIdStr := c.Param("feature-flag-id")
+	featureFlagId, err := strconv.Atoi(featureFlagIdStr)
+	if err != nil {
+		return utils.ErrorMessage(c, "feature flag id is invalid (not a number)")
+	}
+
+	return sch.renderPendingScheduledChanges(c, featureFlagIdStr, uint(featureFlagId))
+}
+
+func (sch *ScheduledChangeHandler) CancelScheduledChange(c echo.Context) error {
+	featureFlagIdStr := c.Param("feature-flag-id")
+	featureFlagId, err := strconv.Atoi(featureFlagIdStr)
+	if err != nil {
+		return utils.ErrorMessage(c, "feature flag id is invalid (not a number)")
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return utils.ErrorMessage(c, "scheduled change id is invalid (not a number)")
+	}
+
+	if err := sch.ScheduledChangeService.CancelScheduledChange(uint(featureFlagId), utils.GetProject(c), uint(id)); err != nil {
+		return utils.ErrorMessage(c, "something goes wrong when attempting to cancel the scheduled change")
+	}
+
+	return sch.renderPendingScheduledChanges(c, featureFlagIdStr, uint(featureFlagId))
+}
+
+func (sch *ScheduledChangeHandler) renderPendingScheduledChanges(c echo.Context, featureFlagIdStr string, featureFlagId uint) error {
+	scheduledChanges, err := sch.ScheduledChangeService.GetScheduledChanges(featureFlagId, utils.GetProject(c), sc_entity.ScheduledChangeFilters{
+		Status:      sc_entity.StatusPending,
+		Environment: utils.GetEnvironment(c),
+	})
+	if err != nil {
+		return utils.ErrorMessage(c, "something goes wrong when attempting to get the scheduled changes")
+	}
+
+	return utils.Render(c, http.StatusOK, components.ScheduledChangeList(featureFlagIdStr, scheduledChanges))
+}
@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"ff/internal/db/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// EnvironmentCookie keeps the environment chosen in the header switcher
+const EnvironmentCookie = "environment"
+
+func GetEnvironment(c echo.Context) string {
+	cookie, err := c.Cookie(EnvironmentCookie)
+	if err != nil || cookie.Value == "" {
+		return model.DefaultEnvironment
+	}
+
+	return cookie.Value
+}
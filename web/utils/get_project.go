@@ -0,0 +1,15 @@
+package utils
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+// ProjectCookie keeps the project chosen in the feature flag list
+const ProjectCookie = "project"
+
+// GetProject returns the id of the project loaded from the project cookie
+func GetProject(c echo.Context) uint {
+	projectId, _ := c.Get("project").(uint)
+
+	return projectId
+}